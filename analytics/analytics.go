@@ -0,0 +1,225 @@
+package analytics
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/criteria"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Repository computes historical analytics of work items from their
+// revisions
+type Repository interface {
+	// Burndown returns the daily burndown of the given iteration and its
+	// child iterations. The remaining effort is the sum of the values of the
+	// given numeric field, if any.
+	Burndown(ctx context.Context, itr iteration.Iteration, effortField string) (*Burndown, error)
+	// Velocity returns the work completed in the last ended iterations of the
+	// given space, oldest first.
+	Velocity(ctx context.Context, spaceID uuid.UUID, limit int, effortField string) (*VelocityReport, error)
+	// CycleTime returns the lead time, cycle time and time in state
+	// distributions of the work items of the given space that match the
+	// given filter, if any.
+	CycleTime(ctx context.Context, spaceID uuid.UUID, filter criteria.Expression) (*CycleTimeReport, error)
+	// CumulativeFlow returns the daily number of work items per state of the
+	// given space, or of the given iteration and its child iterations, that
+	// match the given filter, if any.
+	CumulativeFlow(ctx context.Context, spaceID uuid.UUID, itr *iteration.Iteration, filter criteria.Expression, from, to *time.Time) (*CumulativeFlow, error)
+}
+
+// NewRepository creates a new storage type.
+func NewRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+// GormRepository is the implementation of the storage interface for
+// analytics.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+// history holds the revisions of work items by work item ID, in
+// chronological order
+type history map[uuid.UUID][]workitem.Revision
+
+// newHistory groups the given revisions by work item
+func newHistory(revisions []workitem.Revision) history {
+	h := history{}
+	for _, r := range revisions {
+		h[r.WorkItemID] = append(h[r.WorkItemID], r)
+	}
+	for _, revs := range h {
+		sort.SliceStable(revs, func(i, j int) bool {
+			return revs[i].Time.Before(revs[j].Time)
+		})
+	}
+	return h
+}
+
+// before returns the fields of the given work item as of its last revision
+// strictly before the given time, or nil if it didn't exist at that time
+func (h history) before(id uuid.UUID, t time.Time) workitem.Fields {
+	var fields workitem.Fields
+	for _, r := range h[id] {
+		if !r.Time.Before(t) {
+			break
+		}
+		fields = r.WorkItemFields
+		if r.Type == workitem.RevisionTypeDelete {
+			fields = nil
+		}
+	}
+	return fields
+}
+
+// first returns the time of the earliest revision, or nil if there is none
+func (h history) first() *time.Time {
+	var res *time.Time
+	for _, revs := range h {
+		if len(revs) > 0 && (res == nil || revs[0].Time.Before(*res)) {
+			t := revs[0].Time
+			res = &t
+		}
+	}
+	return res
+}
+
+// inIterations returns true if the given fields place the work item in one
+// of the given iterations
+func inIterations(fields workitem.Fields, iterationIDs map[string]struct{}) bool {
+	if fields == nil {
+		return false
+	}
+	id, ok := fields[workitem.SystemIteration].(string)
+	if !ok {
+		return false
+	}
+	_, ok = iterationIDs[id]
+	return ok
+}
+
+// effort returns the numeric value of the given field, or 0 if the field is
+// not set or not numeric
+func effort(fields workitem.Fields, field string) float64 {
+	if field == "" {
+		return 0
+	}
+	switch v := fields[field].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err == nil {
+			return f
+		}
+	}
+	return 0
+}
+
+// day returns the beginning of the day of the given time in UTC
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// iterationIDs returns the IDs of the given iteration and of its descendants
+func (m *GormRepository) iterationIDs(ctx context.Context, itr iteration.Iteration) (map[string]struct{}, error) {
+	var ids []uuid.UUID
+	err := m.db.Table(itr.TableName()).
+		Where("path <@ ? AND space_id = ? AND deleted_at IS NULL", itr.Path.Convert(), itr.SpaceID).
+		Pluck("id", &ids).Error
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":          err,
+			"iteration_id": itr.ID,
+		}, "unable to load the child iterations")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	res := map[string]struct{}{itr.ID.String(): {}}
+	for _, id := range ids {
+		res[id.String()] = struct{}{}
+	}
+	return res, nil
+}
+
+// loadHistory returns the revisions of all the work items that have ever
+// been in one of the given iterations
+func (m *GormRepository) loadHistory(ctx context.Context, iterationIDs map[string]struct{}) (history, error) {
+	ids := make([]string, 0, len(iterationIDs))
+	for id := range iterationIDs {
+		ids = append(ids, id)
+	}
+	var revisions []workitem.Revision
+	err := m.db.Where(`work_item_id IN (
+			SELECT DISTINCT work_item_id FROM work_item_revisions
+			WHERE work_item_fields->>? IN (?))`, workitem.SystemIteration, ids).
+		Order("revision_time ASC").
+		Find(&revisions).Error
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":           err,
+			"iteration_ids": ids,
+		}, "unable to load the revisions of the work items of the iterations")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return newHistory(revisions), nil
+}
+
+// loadHistoryOf returns the revisions of the given work items
+func (m *GormRepository) loadHistoryOf(ctx context.Context, ids []uuid.UUID) (history, error) {
+	if len(ids) == 0 {
+		return history{}, nil
+	}
+	var revisions []workitem.Revision
+	err := m.db.Where("work_item_id IN (?)", ids).Order("revision_time ASC").Find(&revisions).Error
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err": err,
+		}, "unable to load the revisions of the work items")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return newHistory(revisions), nil
+}
+
+// matchingIDs returns the IDs of the work items of the given space that match
+// the given filter, if any
+func (m *GormRepository) matchingIDs(ctx context.Context, spaceID uuid.UUID, filter criteria.Expression) ([]uuid.UUID, error) {
+	table := workitem.WorkItemStorage{}.TableName()
+	db := m.db.Model(&workitem.WorkItemStorage{}).Where(workitem.Column(table, "space_id")+" = ?", spaceID)
+	if filter != nil {
+		where, parameters, joins, compileErrors := workitem.Compile(filter)
+		if len(compileErrors) > 0 {
+			log.Error(ctx, map[string]interface{}{
+				"err":        compileErrors,
+				"expression": filter,
+			}, "failed to compile expression")
+			return nil, errors.NewBadParameterError("filter", filter)
+		}
+		db = db.Where(where, parameters...)
+		for _, j := range joins {
+			if err := j.Validate(db); err != nil {
+				log.Error(ctx, map[string]interface{}{"expression": filter, "err": err}, "table join not valid")
+				return nil, errors.NewBadParameterError("filter", filter).Expected("valid table join")
+			}
+			db = db.Joins(j.GetJoinExpression())
+		}
+	}
+	var ids []uuid.UUID
+	if err := db.Pluck(workitem.Column(table, "id"), &ids).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":      err,
+			"space_id": spaceID,
+		}, "unable to list the matching work items")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,186 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/analytics"
+	"github.com/fabric8-services/fabric8-wit/criteria"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type analyticsBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	repo analytics.Repository
+}
+
+func TestRunAnalyticsBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &analyticsBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *analyticsBlackBoxTest) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.repo = analytics.NewRepository(s.DB)
+}
+
+func (s *analyticsBlackBoxTest) TestBurndown() {
+	now := time.Now().UTC()
+	start := now.Add(-48 * time.Hour)
+	end := now.Add(48 * time.Hour)
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.CreateWorkItemEnvironment(),
+		tf.Iterations(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.Iterations[idx].StartAt = &start
+			fxt.Iterations[idx].EndAt = &end
+			return nil
+		}),
+		tf.WorkItems(3, func(fxt *tf.TestFixture, idx int) error {
+			if idx < 2 {
+				fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[0].ID.String()
+			}
+			return nil
+		}),
+	)
+	wi := fxt.WorkItems[0]
+	wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+	_, _, err := workitem.NewWorkItemRepository(s.DB).Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[0].ID)
+	require.NoError(s.T(), err)
+
+	burndown, err := s.repo.Burndown(s.Ctx, *fxt.Iterations[0], "")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), fxt.Iterations[0].ID, burndown.IterationID)
+	require.Len(s.T(), burndown.Days, 3)
+	assert.Equal(s.T(), 0, burndown.Days[0].Total)
+	last := burndown.Days[2]
+	assert.Equal(s.T(), 2, last.Total)
+	assert.Equal(s.T(), 1, last.Closed)
+	assert.Equal(s.T(), 1, last.Open)
+	// both work items were added after the iteration started
+	require.Len(s.T(), burndown.ScopeChanges, 2)
+	for _, change := range burndown.ScopeChanges {
+		assert.True(s.T(), change.Added)
+	}
+}
+
+func (s *analyticsBlackBoxTest) TestVelocity() {
+	now := time.Now().UTC()
+	start := now.Add(-10 * 24 * time.Hour)
+	end := now.Add(-24 * time.Hour)
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.CreateWorkItemEnvironment(),
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].Fields["storypoints"] = workitem.FieldDefinition{
+				Label: "Story points",
+				Type:  workitem.SimpleType{Kind: workitem.KindFloat},
+			}
+			return nil
+		}),
+		tf.Iterations(2, func(fxt *tf.TestFixture, idx int) error {
+			// only the first iteration has ended
+			if idx == 0 {
+				fxt.Iterations[idx].StartAt = &start
+				fxt.Iterations[idx].EndAt = &end
+			}
+			return nil
+		}),
+		tf.WorkItems(2, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[0].ID.String()
+			fxt.WorkItems[idx].Fields["storypoints"] = float64(idx + 2)
+			return nil
+		}),
+	)
+	wi := fxt.WorkItems[0]
+	wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+	_, _, err := workitem.NewWorkItemRepository(s.DB).Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[0].ID)
+	require.NoError(s.T(), err)
+	// move the history of the work items into the iteration
+	err = s.DB.Exec("UPDATE work_item_revisions SET revision_time = revision_time - interval '5 days' WHERE work_item_id IN (?, ?)",
+		fxt.WorkItems[0].ID, fxt.WorkItems[1].ID).Error
+	require.NoError(s.T(), err)
+
+	report, err := s.repo.Velocity(s.Ctx, fxt.Spaces[0].ID, 5, "storypoints")
+	require.NoError(s.T(), err)
+	require.Len(s.T(), report.Iterations, 1)
+	v := report.Iterations[0]
+	assert.Equal(s.T(), fxt.Iterations[0].ID, v.IterationID)
+	assert.Equal(s.T(), 0, v.Committed)
+	assert.Equal(s.T(), 1, v.Completed)
+	assert.Equal(s.T(), 2.0, v.CompletedEffort)
+	assert.Equal(s.T(), 1.0, report.AverageCompleted)
+	assert.Equal(s.T(), 2.0, report.AverageCompletedEffort)
+}
+
+func (s *analyticsBlackBoxTest) TestCycleTimeAndCumulativeFlow() {
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.CreateWorkItemEnvironment(),
+		tf.Iterations(1),
+		tf.WorkItemTypes(2),
+		tf.WorkItems(3, func(fxt *tf.TestFixture, idx int) error {
+			if idx == 2 {
+				fxt.WorkItems[idx].Type = fxt.WorkItemTypes[1].ID
+				fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[0].ID.String()
+			}
+			return nil
+		}),
+	)
+	wir := workitem.NewWorkItemRepository(s.DB)
+	for _, state := range []string{workitem.SystemStateOpen, workitem.SystemStateClosed} {
+		wi, err := wir.LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(s.T(), err)
+		wi.Fields[workitem.SystemState] = state
+		_, _, err = wir.Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[0].ID)
+		require.NoError(s.T(), err)
+	}
+
+	s.T().Run("cycle time", func(t *testing.T) {
+		report, err := s.repo.CycleTime(s.Ctx, fxt.Spaces[0].ID, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Overall.LeadTime.Count)
+		assert.Equal(t, 1, report.Overall.CycleTime.Count)
+		assert.Equal(t, 1, report.Overall.TimeInState[workitem.SystemStateOpen].Count)
+		assert.Len(t, report.ByType, 2)
+	})
+
+	s.T().Run("cycle time with filter", func(t *testing.T) {
+		filter := criteria.Equals(criteria.Field("Type"), criteria.Literal(fxt.WorkItemTypes[1].ID.String()))
+		report, err := s.repo.CycleTime(s.Ctx, fxt.Spaces[0].ID, filter)
+		require.NoError(t, err)
+		assert.Equal(t, 0, report.Overall.LeadTime.Count)
+		require.Len(t, report.ByType, 1)
+		_, ok := report.ByType[fxt.WorkItemTypes[1].ID]
+		assert.True(t, ok)
+	})
+
+	s.T().Run("cumulative flow of the space", func(t *testing.T) {
+		flow, err := s.repo.CumulativeFlow(s.Ctx, fxt.Spaces[0].ID, nil, nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, flow.Days, 30)
+		today := flow.Days[29]
+		assert.Equal(t, 2, today.States[workitem.SystemStateNew])
+		assert.Equal(t, 1, today.States[workitem.SystemStateClosed])
+		assert.Equal(t, 0, flow.Days[0].States[workitem.SystemStateNew])
+	})
+
+	s.T().Run("cumulative flow of an iteration", func(t *testing.T) {
+		flow, err := s.repo.CumulativeFlow(s.Ctx, fxt.Spaces[0].ID, fxt.Iterations[0], nil, nil, nil)
+		require.NoError(t, err)
+		require.NotEmpty(t, flow.Days)
+		today := flow.Days[len(flow.Days)-1]
+		assert.Equal(t, 1, today.States[workitem.SystemStateNew])
+		assert.Equal(t, 0, today.States[workitem.SystemStateClosed])
+	})
+
+	s.T().Run("invalid time range", func(t *testing.T) {
+		from := time.Now()
+		to := from.AddDate(0, 0, -1)
+		_, err := s.repo.CumulativeFlow(s.Ctx, fxt.Spaces[0].ID, nil, nil, &from, &to)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,82 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBurndownAndVelocity(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	start := time.Date(2018, 3, 5, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2018, 3, 8, 18, 0, 0, 0, time.UTC)
+	itr := iteration.Iteration{ID: uuid.NewV4(), Name: "Sprint 1", StartAt: &start, EndAt: &end}
+	ids := map[string]struct{}{itr.ID.String(): {}}
+	fields := func(iterationID, state string, points float64) workitem.Fields {
+		return workitem.Fields{
+			workitem.SystemIteration: iterationID,
+			workitem.SystemState:     state,
+			"storypoints":            points,
+		}
+	}
+	rev := func(id uuid.UUID, at time.Time, typ workitem.RevisionType, f workitem.Fields) workitem.Revision {
+		return workitem.Revision{WorkItemID: id, Time: at, Type: typ, WorkItemFields: f}
+	}
+	planned, added, removed, closedBefore := uuid.NewV4(), uuid.NewV4(), uuid.NewV4(), uuid.NewV4()
+	h := newHistory([]workitem.Revision{
+		// planned before the start and closed on the second day
+		rev(planned, start.Add(-time.Hour), workitem.RevisionTypeCreate, fields(itr.ID.String(), "new", 3)),
+		rev(planned, start.Add(26*time.Hour), workitem.RevisionTypeUpdate, fields(itr.ID.String(), "closed", 3)),
+		// added on the third day
+		rev(added, start.Add(50*time.Hour), workitem.RevisionTypeCreate, fields(itr.ID.String(), "open", 5)),
+		// planned and moved to the next iteration on the second day
+		rev(removed, start.Add(-time.Hour), workitem.RevisionTypeCreate, fields(itr.ID.String(), "new", 8)),
+		rev(removed, start.Add(30*time.Hour), workitem.RevisionTypeUpdate, fields(uuid.NewV4().String(), "new", 8)),
+		// closed before the start
+		rev(closedBefore, start.Add(-2*time.Hour), workitem.RevisionTypeCreate, fields(itr.ID.String(), "closed", 1)),
+	})
+
+	t.Run("burndown", func(t *testing.T) {
+		b := newBurndown(itr, ids, h, "storypoints", end.AddDate(0, 1, 0))
+		require.Len(t, b.Days, 4)
+		assert.Equal(t, BurndownDay{Date: day(start), Total: 3, Closed: 1, Open: 2, Effort: 12, RemainingEffort: 11}, b.Days[0])
+		assert.Equal(t, BurndownDay{Date: day(start).AddDate(0, 0, 1), Total: 2, Closed: 2, Open: 0, Effort: 4, RemainingEffort: 0}, b.Days[1])
+		assert.Equal(t, BurndownDay{Date: day(start).AddDate(0, 0, 2), Total: 3, Closed: 2, Open: 1, Effort: 9, RemainingEffort: 5}, b.Days[2])
+		assert.Equal(t, b.Days[2].Total, b.Days[3].Total)
+		assert.Equal(t, []ScopeChange{
+			{Time: start.Add(30 * time.Hour), WorkItemID: removed, Added: false},
+			{Time: start.Add(50 * time.Hour), WorkItemID: added, Added: true},
+		}, b.ScopeChanges)
+	})
+
+	t.Run("burndown of a running iteration", func(t *testing.T) {
+		b := newBurndown(itr, ids, h, "", start.Add(26*time.Hour))
+		require.Len(t, b.Days, 2)
+		assert.Equal(t, 0.0, b.Days[1].Effort)
+	})
+
+	t.Run("velocity", func(t *testing.T) {
+		v := newVelocity(itr, ids, h, "storypoints")
+		assert.Equal(t, 2, v.Committed)
+		assert.Equal(t, 11.0, v.CommittedEffort)
+		assert.Equal(t, 1, v.Completed)
+		assert.Equal(t, 3.0, v.CompletedEffort)
+	})
+}
+
+func TestEffort(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	fields := workitem.Fields{"a": 2.5, "b": "3", "c": "many", "d": true}
+	assert.Equal(t, 2.5, effort(fields, "a"))
+	assert.Equal(t, 3.0, effort(fields, "b"))
+	assert.Equal(t, 0.0, effort(fields, "c"))
+	assert.Equal(t, 0.0, effort(fields, "d"))
+	assert.Equal(t, 0.0, effort(fields, "missing"))
+	assert.Equal(t, 0.0, effort(fields, ""))
+}
@@ -0,0 +1,132 @@
+package analytics
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// maxBurndownDays limits the number of days of a burndown, only the last
+// ones are kept for longer iterations
+const maxBurndownDays = 366
+
+// Burndown holds the daily progress of an iteration
+type Burndown struct {
+	IterationID uuid.UUID
+	EffortField string
+	Days        []BurndownDay
+	// ScopeChanges holds the work items added to or removed from the
+	// iteration after it started, in chronological order
+	ScopeChanges []ScopeChange
+}
+
+// BurndownDay holds the state of the work items of an iteration at the end
+// of a day
+type BurndownDay struct {
+	Date            time.Time
+	Total           int
+	Closed          int
+	Open            int
+	Effort          float64
+	RemainingEffort float64
+}
+
+// ScopeChange tells when a work item was added to or removed from an
+// iteration
+type ScopeChange struct {
+	Time       time.Time
+	WorkItemID uuid.UUID
+	Added      bool
+}
+
+// Burndown returns the daily burndown of the given iteration and its child
+// iterations
+func (m *GormRepository) Burndown(ctx context.Context, itr iteration.Iteration, effortField string) (*Burndown, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "analytics", "burndown"}, time.Now())
+	ids, err := m.iterationIDs(ctx, itr)
+	if err != nil {
+		return nil, err
+	}
+	h, err := m.loadHistory(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return newBurndown(itr, ids, h, effortField, time.Now()), nil
+}
+
+// newBurndown computes the burndown of the given iteration from the history
+// of its work items. The burndown runs from the start of the iteration (or
+// its first change) to its end or to the given current time, whichever comes
+// first.
+func newBurndown(itr iteration.Iteration, iterationIDs map[string]struct{}, h history, effortField string, now time.Time) *Burndown {
+	res := Burndown{
+		IterationID:  itr.ID,
+		EffortField:  effortField,
+		Days:         []BurndownDay{},
+		ScopeChanges: []ScopeChange{},
+	}
+	end := day(now)
+	if itr.EndAt != nil && itr.EndAt.Before(now) {
+		end = day(*itr.EndAt)
+	}
+	start := end
+	if itr.StartAt != nil {
+		start = day(*itr.StartAt)
+	} else if first := h.first(); first != nil {
+		start = day(*first)
+	}
+	if start.After(end) {
+		start = end
+	}
+	if end.Sub(start) >= maxBurndownDays*24*time.Hour {
+		start = end.AddDate(0, 0, 1-maxBurndownDays)
+	}
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		bd := BurndownDay{Date: d}
+		cutoff := d.AddDate(0, 0, 1)
+		for id := range h {
+			fields := h.before(id, cutoff)
+			if !inIterations(fields, iterationIDs) {
+				continue
+			}
+			e := effort(fields, effortField)
+			bd.Total++
+			bd.Effort += e
+			if workitem.IsClosed(fields) {
+				bd.Closed++
+				continue
+			}
+			bd.Open++
+			bd.RemainingEffort += e
+		}
+		res.Days = append(res.Days, bd)
+	}
+
+	// scope changes are only relevant once the iteration has started
+	if itr.StartAt != nil {
+		for id, revs := range h {
+			in := inIterations(h.before(id, *itr.StartAt), iterationIDs)
+			for _, r := range revs {
+				if r.Time.Before(*itr.StartAt) || (itr.EndAt != nil && r.Time.After(*itr.EndAt)) {
+					continue
+				}
+				// deletion revisions have no fields, so deleting a work item
+				// removes it from the iteration
+				inScope := inIterations(r.WorkItemFields, iterationIDs)
+				if inScope != in {
+					res.ScopeChanges = append(res.ScopeChanges, ScopeChange{Time: r.Time, WorkItemID: id, Added: inScope})
+					in = inScope
+				}
+			}
+		}
+		sort.Slice(res.ScopeChanges, func(i, j int) bool {
+			return res.ScopeChanges[i].Time.Before(res.ScopeChanges[j].Time)
+		})
+	}
+	return &res
+}
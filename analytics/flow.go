@@ -0,0 +1,342 @@
+package analytics
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/criteria"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// defaultFlowDays is the number of days of a cumulative flow when no time
+// range is given
+const defaultFlowDays = 30
+
+// StateInterval is a period of time a work item spent in a state
+type StateInterval struct {
+	State string
+	Enter time.Time
+	// Exit is nil while the work item is still in the state
+	Exit *time.Time
+}
+
+// Timeline is the state history of a work item derived from its revisions
+type Timeline struct {
+	WorkItemID uuid.UUID
+	// TypeID and AreaID are the ones of the latest revision
+	TypeID  uuid.UUID
+	AreaID  uuid.UUID
+	Created time.Time
+	// Started is when the work item first left the state it was created in,
+	// unless it was to be closed
+	Started *time.Time
+	// Closed is when the work item was last closed, if it is closed
+	Closed *time.Time
+	States []StateInterval
+}
+
+// newTimeline returns the timeline of a work item from its revisions in
+// chronological order, or nil if there are none
+func newTimeline(revs []workitem.Revision) *Timeline {
+	if len(revs) == 0 {
+		return nil
+	}
+	t := Timeline{
+		WorkItemID: revs[0].WorkItemID,
+		Created:    revs[0].Time,
+		States:     []StateInterval{},
+	}
+	for _, r := range revs {
+		at := r.Time
+		if r.Type == workitem.RevisionTypeDelete {
+			if n := len(t.States); n > 0 && t.States[n-1].Exit == nil {
+				t.States[n-1].Exit = &at
+			}
+			t.Closed = nil
+			break
+		}
+		t.TypeID = r.WorkItemTypeID
+		t.AreaID = uuid.Nil
+		if area, ok := r.WorkItemFields[workitem.SystemArea].(string); ok {
+			t.AreaID = uuid.FromStringOrNil(area)
+		}
+		state, _ := r.WorkItemFields[workitem.SystemState].(string)
+		n := len(t.States)
+		if n > 0 && t.States[n-1].State == state {
+			continue
+		}
+		if n > 0 {
+			t.States[n-1].Exit = &at
+		}
+		t.States = append(t.States, StateInterval{State: state, Enter: at})
+		if workitem.IsClosed(r.WorkItemFields) {
+			t.Closed = &at
+			continue
+		}
+		t.Closed = nil
+		if n > 0 && t.Started == nil {
+			t.Started = &at
+		}
+	}
+	return &t
+}
+
+// Stats describes a distribution of durations
+type Stats struct {
+	Count int
+	Mean  time.Duration
+	P50   time.Duration
+	P85   time.Duration
+	P95   time.Duration
+}
+
+// newStats returns the distribution of the given durations
+func newStats(durations []time.Duration) Stats {
+	res := Stats{Count: len(durations)}
+	if len(durations) == 0 {
+		return res
+	}
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	res.Mean = total / time.Duration(len(sorted))
+	res.P50 = percentile(sorted, 50)
+	res.P85 = percentile(sorted, 85)
+	res.P95 = percentile(sorted, 95)
+	return res
+}
+
+// percentile returns the nearest-rank percentile p of the given sorted
+// durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	// ceil(p/100 * n) in integer arithmetic
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// GroupStats holds the flow statistics of a group of work items
+type GroupStats struct {
+	// LeadTime runs from the creation of closed work items to their closing
+	LeadTime Stats
+	// CycleTime runs from the start of closed work items to their closing
+	CycleTime Stats
+	// TimeInState holds the time spent in each state that was left
+	TimeInState map[string]Stats
+}
+
+// groupCollector collects the durations of the timelines of a group
+type groupCollector struct {
+	lead, cycle []time.Duration
+	inState     map[string][]time.Duration
+}
+
+func (c *groupCollector) add(t Timeline) {
+	if c.inState == nil {
+		c.inState = map[string][]time.Duration{}
+	}
+	if t.Closed != nil {
+		c.lead = append(c.lead, t.Closed.Sub(t.Created))
+		if t.Started != nil {
+			c.cycle = append(c.cycle, t.Closed.Sub(*t.Started))
+		}
+	}
+	for _, s := range t.States {
+		if s.Exit != nil {
+			c.inState[s.State] = append(c.inState[s.State], s.Exit.Sub(s.Enter))
+		}
+	}
+}
+
+func (c groupCollector) stats() GroupStats {
+	res := GroupStats{
+		LeadTime:    newStats(c.lead),
+		CycleTime:   newStats(c.cycle),
+		TimeInState: map[string]Stats{},
+	}
+	for state, durations := range c.inState {
+		res.TimeInState[state] = newStats(durations)
+	}
+	return res
+}
+
+// CycleTimeReport holds the flow statistics of work items, overall and per
+// work item type and area. Work items without area are grouped under
+// uuid.Nil.
+type CycleTimeReport struct {
+	Overall GroupStats
+	ByType  map[uuid.UUID]GroupStats
+	ByArea  map[uuid.UUID]GroupStats
+}
+
+// newCycleTimeReport computes the flow statistics of the given timelines
+func newCycleTimeReport(timelines []Timeline) *CycleTimeReport {
+	var overall groupCollector
+	byType := map[uuid.UUID]*groupCollector{}
+	byArea := map[uuid.UUID]*groupCollector{}
+	for _, t := range timelines {
+		overall.add(t)
+		if _, ok := byType[t.TypeID]; !ok {
+			byType[t.TypeID] = &groupCollector{}
+		}
+		byType[t.TypeID].add(t)
+		if _, ok := byArea[t.AreaID]; !ok {
+			byArea[t.AreaID] = &groupCollector{}
+		}
+		byArea[t.AreaID].add(t)
+	}
+	res := CycleTimeReport{
+		Overall: overall.stats(),
+		ByType:  map[uuid.UUID]GroupStats{},
+		ByArea:  map[uuid.UUID]GroupStats{},
+	}
+	for id, c := range byType {
+		res.ByType[id] = c.stats()
+	}
+	for id, c := range byArea {
+		res.ByArea[id] = c.stats()
+	}
+	return &res
+}
+
+// CycleTime returns the flow statistics of the work items of the given space
+// that match the given filter
+func (m *GormRepository) CycleTime(ctx context.Context, spaceID uuid.UUID, filter criteria.Expression) (*CycleTimeReport, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "analytics", "cycletime"}, time.Now())
+	ids, err := m.matchingIDs(ctx, spaceID, filter)
+	if err != nil {
+		return nil, err
+	}
+	h, err := m.loadHistoryOf(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return newCycleTimeReport(h.timelines()), nil
+}
+
+// timelines returns the timelines of all the work items of the history
+func (h history) timelines() []Timeline {
+	res := make([]Timeline, 0, len(h))
+	for _, revs := range h {
+		if t := newTimeline(revs); t != nil {
+			res = append(res, *t)
+		}
+	}
+	return res
+}
+
+// FlowDay holds the number of work items per state at the end of a day
+type FlowDay struct {
+	Date   time.Time
+	States map[string]int
+}
+
+// CumulativeFlow holds the daily number of work items per state
+type CumulativeFlow struct {
+	// States lists the states in workflow order, i.e. by the average
+	// position at which the work items entered them
+	States []string
+	Days   []FlowDay
+}
+
+// CumulativeFlow returns the cumulative flow of the work items of the given
+// space or iteration that match the given filter. It defaults to the dates
+// of the iteration, or to the last 30 days.
+func (m *GormRepository) CumulativeFlow(ctx context.Context, spaceID uuid.UUID, itr *iteration.Iteration, filter criteria.Expression, from, to *time.Time) (*CumulativeFlow, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "analytics", "cumulativeflow"}, time.Now())
+	now := time.Now()
+	end := now
+	switch {
+	case to != nil:
+		end = *to
+	case itr != nil && itr.EndAt != nil && itr.EndAt.Before(now):
+		end = *itr.EndAt
+	}
+	start := end.AddDate(0, 0, 1-defaultFlowDays)
+	switch {
+	case from != nil:
+		start = *from
+	case itr != nil && itr.StartAt != nil:
+		start = *itr.StartAt
+	}
+	if start.After(end) {
+		return nil, errors.NewBadParameterError("from", start).Expected("before " + end.Format(time.RFC3339))
+	}
+	if end.Sub(start) >= maxBurndownDays*24*time.Hour {
+		return nil, errors.NewBadParameterError("from", start).Expected("less than a year before the end")
+	}
+	ids, err := m.matchingIDs(ctx, spaceID, filter)
+	if err != nil {
+		return nil, err
+	}
+	h, err := m.loadHistoryOf(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	var iterationIDs map[string]struct{}
+	if itr != nil {
+		iterationIDs, err = m.iterationIDs(ctx, *itr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return newCumulativeFlow(h, iterationIDs, day(start), day(end)), nil
+}
+
+// newCumulativeFlow counts the work items of the history per state at the end
+// of each day between the given days. When iteration IDs are given, only the
+// work items that were in one of these iterations at the end of the day are
+// counted.
+func newCumulativeFlow(h history, iterationIDs map[string]struct{}, start, end time.Time) *CumulativeFlow {
+	res := CumulativeFlow{States: []string{}, Days: []FlowDay{}}
+	positions := map[string][]int{}
+	for _, t := range h.timelines() {
+		for i, s := range t.States {
+			positions[s.State] = append(positions[s.State], i)
+		}
+	}
+	avg := map[string]float64{}
+	for state, p := range positions {
+		sum := 0
+		for _, i := range p {
+			sum += i
+		}
+		avg[state] = float64(sum) / float64(len(p))
+		res.States = append(res.States, state)
+	}
+	sort.Slice(res.States, func(i, j int) bool {
+		a, b := res.States[i], res.States[j]
+		if avg[a] != avg[b] {
+			return avg[a] < avg[b]
+		}
+		return a < b
+	})
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		cutoff := d.AddDate(0, 0, 1)
+		fd := FlowDay{Date: d, States: map[string]int{}}
+		for _, state := range res.States {
+			fd.States[state] = 0
+		}
+		for id := range h {
+			fields := h.before(id, cutoff)
+			if fields == nil || (iterationIDs != nil && !inIterations(fields, iterationIDs)) {
+				continue
+			}
+			state, _ := fields[workitem.SystemState].(string)
+			fd.States[state]++
+		}
+		res.Days = append(res.Days, fd)
+	}
+	return &res
+}
@@ -0,0 +1,112 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlow(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	created := time.Date(2018, 4, 2, 9, 0, 0, 0, time.UTC)
+	typeID, areaID := uuid.NewV4(), uuid.NewV4()
+	rev := func(id uuid.UUID, hours int, state string) workitem.Revision {
+		return workitem.Revision{
+			WorkItemID:     id,
+			Time:           created.Add(time.Duration(hours) * time.Hour),
+			Type:           workitem.RevisionTypeUpdate,
+			WorkItemTypeID: typeID,
+			WorkItemFields: workitem.Fields{
+				workitem.SystemState: state,
+				workitem.SystemArea:  areaID.String(),
+			},
+		}
+	}
+	done, direct, wip := uuid.NewV4(), uuid.NewV4(), uuid.NewV4()
+	h := newHistory([]workitem.Revision{
+		// new for a day, in progress for two days, then closed
+		rev(done, 0, "new"),
+		rev(done, 24, "in progress"),
+		rev(done, 30, "in progress"),
+		rev(done, 72, "closed"),
+		// closed straight away after 12 hours
+		rev(direct, 0, "new"),
+		rev(direct, 12, "closed"),
+		// still in progress
+		rev(wip, 24, "new"),
+		rev(wip, 48, "in progress"),
+	})
+
+	t.Run("timeline", func(t *testing.T) {
+		tl := newTimeline(h[done])
+		require.NotNil(t, tl)
+		assert.Equal(t, typeID, tl.TypeID)
+		assert.Equal(t, areaID, tl.AreaID)
+		assert.Equal(t, created, tl.Created)
+		require.NotNil(t, tl.Started)
+		assert.Equal(t, created.Add(24*time.Hour), *tl.Started)
+		require.NotNil(t, tl.Closed)
+		assert.Equal(t, created.Add(72*time.Hour), *tl.Closed)
+		require.Len(t, tl.States, 3)
+		assert.Equal(t, "in progress", tl.States[1].State)
+		assert.Nil(t, tl.States[2].Exit)
+
+		tl = newTimeline(h[direct])
+		assert.Nil(t, tl.Started)
+		require.NotNil(t, tl.Closed)
+
+		assert.Nil(t, newTimeline(nil))
+	})
+
+	t.Run("cycle time", func(t *testing.T) {
+		r := newCycleTimeReport(h.timelines())
+		assert.Equal(t, Stats{Count: 2, Mean: 42 * time.Hour, P50: 12 * time.Hour, P85: 72 * time.Hour, P95: 72 * time.Hour}, r.Overall.LeadTime)
+		assert.Equal(t, Stats{Count: 1, Mean: 48 * time.Hour, P50: 48 * time.Hour, P85: 48 * time.Hour, P95: 48 * time.Hour}, r.Overall.CycleTime)
+		assert.Equal(t, 3, r.Overall.TimeInState["new"].Count)
+		assert.Equal(t, 1, r.Overall.TimeInState["in progress"].Count)
+		_, ok := r.Overall.TimeInState["closed"]
+		assert.False(t, ok)
+		require.Len(t, r.ByType, 1)
+		assert.Equal(t, r.Overall, r.ByType[typeID])
+		require.Len(t, r.ByArea, 1)
+		assert.Equal(t, r.Overall, r.ByArea[areaID])
+	})
+
+	t.Run("cumulative flow", func(t *testing.T) {
+		f := newCumulativeFlow(h, nil, day(created), day(created).AddDate(0, 0, 3))
+		assert.Equal(t, []string{"new", "in progress", "closed"}, f.States)
+		require.Len(t, f.Days, 4)
+		assert.Equal(t, map[string]int{"new": 1, "in progress": 0, "closed": 1}, f.Days[0].States)
+		assert.Equal(t, map[string]int{"new": 1, "in progress": 1, "closed": 1}, f.Days[1].States)
+		assert.Equal(t, map[string]int{"new": 0, "in progress": 2, "closed": 1}, f.Days[2].States)
+		assert.Equal(t, map[string]int{"new": 0, "in progress": 1, "closed": 2}, f.Days[3].States)
+	})
+
+	t.Run("cumulative flow of an iteration", func(t *testing.T) {
+		f := newCumulativeFlow(h, map[string]struct{}{uuid.NewV4().String(): {}}, day(created), day(created))
+		require.Len(t, f.Days, 1)
+		assert.Equal(t, 0, f.Days[0].States["new"])
+	})
+}
+
+func TestNewStats(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	var durations []time.Duration
+	for i := 20; i > 0; i-- {
+		durations = append(durations, time.Duration(i)*time.Minute)
+	}
+	s := newStats(durations)
+	assert.Equal(t, 20, s.Count)
+	assert.Equal(t, 10*time.Minute+30*time.Second, s.Mean)
+	assert.Equal(t, 10*time.Minute, s.P50)
+	assert.Equal(t, 17*time.Minute, s.P85)
+	assert.Equal(t, 19*time.Minute, s.P95)
+	// the input is left untouched
+	assert.Equal(t, 20*time.Minute, durations[0])
+	assert.Equal(t, Stats{}, newStats(nil))
+}
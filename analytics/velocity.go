@@ -0,0 +1,106 @@
+package analytics
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Velocity holds the work committed to and completed in an iteration
+type Velocity struct {
+	IterationID uuid.UUID
+	Name        string
+	StartAt     time.Time
+	EndAt       time.Time
+	// Committed is the number of open work items of the iteration when it
+	// started
+	Committed       int
+	CommittedEffort float64
+	// Completed is the number of work items of the iteration closed during
+	// the iteration
+	Completed       int
+	CompletedEffort float64
+}
+
+// VelocityReport holds the velocity of a series of iterations
+type VelocityReport struct {
+	EffortField            string
+	Iterations             []Velocity
+	AverageCompleted       float64
+	AverageCompletedEffort float64
+}
+
+// Velocity returns the velocity of the last ended iterations of the given
+// space. Only the iterations with a start and an end date are considered.
+func (m *GormRepository) Velocity(ctx context.Context, spaceID uuid.UUID, limit int, effortField string) (*VelocityReport, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "analytics", "velocity"}, time.Now())
+	var iterations []iteration.Iteration
+	err := m.db.Where("space_id = ? AND start_at IS NOT NULL AND end_at IS NOT NULL AND end_at <= ?", spaceID, time.Now()).
+		Order("end_at DESC").
+		Limit(limit).
+		Find(&iterations).Error
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":      err,
+			"space_id": spaceID,
+		}, "unable to list the ended iterations of the space")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	res := VelocityReport{
+		EffortField: effortField,
+		Iterations:  make([]Velocity, len(iterations)),
+	}
+	for i, itr := range iterations {
+		ids, err := m.iterationIDs(ctx, itr)
+		if err != nil {
+			return nil, err
+		}
+		h, err := m.loadHistory(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		// oldest first
+		res.Iterations[len(iterations)-1-i] = newVelocity(itr, ids, h, effortField)
+	}
+	for _, v := range res.Iterations {
+		res.AverageCompleted += float64(v.Completed)
+		res.AverageCompletedEffort += v.CompletedEffort
+	}
+	if len(res.Iterations) > 0 {
+		res.AverageCompleted /= float64(len(res.Iterations))
+		res.AverageCompletedEffort /= float64(len(res.Iterations))
+	}
+	return &res, nil
+}
+
+// newVelocity computes the velocity of the given iteration, which must have
+// a start and an end date, from the history of its work items
+func newVelocity(itr iteration.Iteration, iterationIDs map[string]struct{}, h history, effortField string) Velocity {
+	v := Velocity{
+		IterationID: itr.ID,
+		Name:        itr.Name,
+		StartAt:     *itr.StartAt,
+		EndAt:       *itr.EndAt,
+	}
+	for id := range h {
+		atStart := h.before(id, *itr.StartAt)
+		closedAtStart := workitem.IsClosed(atStart)
+		if inIterations(atStart, iterationIDs) && !closedAtStart {
+			v.Committed++
+			v.CommittedEffort += effort(atStart, effortField)
+		}
+		// items closed before the iteration started were not completed in it
+		atEnd := h.before(id, itr.EndAt.Add(time.Nanosecond))
+		if inIterations(atEnd, iterationIDs) && workitem.IsClosed(atEnd) && !closedAtStart {
+			v.Completed++
+			v.CompletedEffort += effort(atEnd, effortField)
+		}
+	}
+	return v
+}
@@ -2,18 +2,25 @@ package application
 
 import (
 	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/analytics"
 	"github.com/fabric8-services/fabric8-wit/area"
+	"github.com/fabric8-services/fabric8-wit/attachment"
 	"github.com/fabric8-services/fabric8-wit/codebase"
+	"github.com/fabric8-services/fabric8-wit/codebase/scm"
 	"github.com/fabric8-services/fabric8-wit/comment"
 	"github.com/fabric8-services/fabric8-wit/iteration"
 	"github.com/fabric8-services/fabric8-wit/label"
 	"github.com/fabric8-services/fabric8-wit/query"
+	"github.com/fabric8-services/fabric8-wit/release"
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
 	"github.com/fabric8-services/fabric8-wit/space"
 	"github.com/fabric8-services/fabric8-wit/spacetemplate"
+	"github.com/fabric8-services/fabric8-wit/watcher"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/event"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/fabric8-services/fabric8-wit/workitem/recurrence"
+	"github.com/fabric8-services/fabric8-wit/workitem/worklog"
 )
 
 //An Application stands for a particular implementation of the business logic of our application
@@ -22,19 +29,30 @@ type Application interface {
 	WorkItemTypes() workitem.WorkItemTypeRepository
 	Trackers() remoteworkitem.TrackerRepository
 	TrackerQueries() remoteworkitem.TrackerQueryRepository
+	TrackerQuerySyncStates() remoteworkitem.SyncStateRepository
 	SearchItems() SearchRepository
 	Identities() account.IdentityRepository
 	WorkItemLinkTypes() link.WorkItemLinkTypeRepository
 	WorkItemLinks() link.WorkItemLinkRepository
 	Comments() comment.Repository
+	CommentReactions() comment.ReactionRepository
+	CommentRevisions() comment.RevisionRepository
+	Attachments() attachment.Repository
+	Worklogs() worklog.Repository
+	Recurrences() recurrence.Repository
+	Watchers() watcher.Repository
 	Spaces() space.Repository
 	Iterations() iteration.Repository
 	Users() account.UserRepository
 	Areas() area.Repository
 	Codebases() codebase.Repository
+	SCMActivities() scm.Repository
 	Labels() label.Repository
+	Releases() release.Repository
 	Queries() query.Repository
 	Events() event.Repository
+	Activities() event.ActivityRepository
+	Analytics() analytics.Repository
 	SpaceTemplates() spacetemplate.Repository
 	WorkItemTypeGroups() workitem.WorkItemTypeGroupRepository
 	Boards() workitem.BoardRepository
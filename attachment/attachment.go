@@ -0,0 +1,43 @@
+package attachment
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	"github.com/fabric8-services/fabric8-wit/id"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Attachment describes the metadata of a file attached to a work item or to
+// one of its comments
+type Attachment struct {
+	gormsupport.Lifecycle
+	ID         uuid.UUID   `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
+	WorkItemID uuid.UUID   `sql:"type:uuid"`
+	CommentID  id.NullUUID `sql:"type:uuid"`
+	Name       string
+	Size       int64
+	MimeType   string
+	// Checksum is the hex encoded SHA-256 checksum of the content
+	Checksum string
+	// StorageKey is the key under which the content is kept in the blob store
+	StorageKey string
+	Creator    uuid.UUID `sql:"type:uuid"`
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (a Attachment) TableName() string {
+	return "attachments"
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (a Attachment) GetETagData() []interface{} {
+	return []interface{}{a.ID, a.Checksum, strconv.FormatInt(a.UpdatedAt.Unix(), 10)}
+}
+
+// GetLastModified returns the last modification time
+func (a Attachment) GetLastModified() time.Time {
+	return a.UpdatedAt.Truncate(time.Second)
+}
@@ -0,0 +1,141 @@
+package attachment
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/application/repository"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Repository describes interactions with attachments
+type Repository interface {
+	repository.Exister
+	// Create stores the metadata of an attachment whose content has already
+	// been uploaded to the blob store (see Upload)
+	Create(ctx context.Context, attachment *Attachment) error
+	Load(ctx context.Context, id uuid.UUID) (*Attachment, error)
+	// List returns the attachments of the given work item, including those
+	// of its comments, oldest first
+	List(ctx context.Context, workItemID uuid.UUID) ([]Attachment, error)
+	// ListByComment returns the attachments of the given comment, oldest
+	// first
+	ListByComment(ctx context.Context, commentID uuid.UUID) ([]Attachment, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// NewRepository creates a new storage type.
+func NewRepository(db *gorm.DB) Repository {
+	return &GormAttachmentRepository{db: db}
+}
+
+// GormAttachmentRepository is the implementation of the storage interface for
+// attachments.
+type GormAttachmentRepository struct {
+	db *gorm.DB
+}
+
+// Create implements Repository
+func (r *GormAttachmentRepository) Create(ctx context.Context, attachment *Attachment) error {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "create"}, time.Now())
+	if attachment.StorageKey == "" || attachment.Checksum == "" {
+		return errors.NewBadParameterError("attachment", attachment.Name).Expected("an uploaded attachment")
+	}
+	if attachment.CommentID.Valid {
+		// an attachment of a comment must belong to the comment's work item
+		var count int
+		err := r.db.Table("comments").Where("id = ? AND parent_id = ? AND deleted_at IS NULL", attachment.CommentID.UUID, attachment.WorkItemID).Count(&count).Error
+		if err != nil {
+			return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to check comment %s", attachment.CommentID.UUID))
+		}
+		if count == 0 {
+			return errors.NewBadParameterError("comment", attachment.CommentID.UUID.String()).Expected("a comment of the work item")
+		}
+	}
+	if attachment.ID == uuid.Nil {
+		attachment.ID = uuid.NewV4()
+	}
+	if err := r.db.Create(attachment).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"attachment_id": attachment.ID,
+			"work_item_id":  attachment.WorkItemID,
+			"err":           err,
+		}, "unable to create the attachment")
+		return errors.NewInternalError(ctx, errs.Wrap(err, "failed to create attachment"))
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"attachment_id": attachment.ID,
+	}, "Attachment created!")
+	return nil
+}
+
+// Load implements Repository
+func (r *GormAttachmentRepository) Load(ctx context.Context, id uuid.UUID) (*Attachment, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "get"}, time.Now())
+	var obj Attachment
+	tx := r.db.Where("id = ?", id).First(&obj)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("attachment", id.String())
+	}
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"attachment_id": id,
+			"err":           tx.Error,
+		}, "unable to load the attachment")
+		return nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	return &obj, nil
+}
+
+// List implements Repository
+func (r *GormAttachmentRepository) List(ctx context.Context, workItemID uuid.UUID) ([]Attachment, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "list"}, time.Now())
+	var result []Attachment
+	if err := r.db.Where("work_item_id = ?", workItemID).Order("created_at, id").Find(&result).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list attachments of work item %s", workItemID))
+	}
+	return result, nil
+}
+
+// ListByComment implements Repository
+func (r *GormAttachmentRepository) ListByComment(ctx context.Context, commentID uuid.UUID) ([]Attachment, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "list_by_comment"}, time.Now())
+	var result []Attachment
+	if err := r.db.Where("comment_id = ?", commentID).Order("created_at, id").Find(&result).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list attachments of comment %s", commentID))
+	}
+	return result, nil
+}
+
+// Delete implements Repository. The content has to be removed from the blob
+// store separately.
+func (r *GormAttachmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "delete"}, time.Now())
+	if id == uuid.Nil {
+		return errors.NewNotFoundError("attachment", id.String())
+	}
+	tx := r.db.Delete(&Attachment{ID: id})
+	if err := tx.Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"attachment_id": id,
+			"err":           err,
+		}, "unable to delete the attachment")
+		return errors.NewInternalError(ctx, errs.Wrap(err, "failed to delete attachment"))
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("attachment", id.String())
+	}
+	return nil
+}
+
+// CheckExists returns nil if the given ID exists otherwise returns an error
+func (r *GormAttachmentRepository) CheckExists(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "exists"}, time.Now())
+	return repository.CheckExists(ctx, r.db, Attachment{}.TableName(), id)
+}
@@ -0,0 +1,142 @@
+package attachment_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/attachment"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/id"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type attachmentRepositoryBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	repo attachment.Repository
+}
+
+func TestRunAttachmentRepositoryBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &attachmentRepositoryBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *attachmentRepositoryBlackBoxTest) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.repo = attachment.NewRepository(s.DB)
+}
+
+func newUploadedAttachment(workItemID, creator uuid.UUID) *attachment.Attachment {
+	a := &attachment.Attachment{
+		ID:         uuid.NewV4(),
+		WorkItemID: workItemID,
+		Name:       "screenshot.png",
+		Size:       42,
+		MimeType:   "image/png",
+		Checksum:   "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		Creator:    creator,
+	}
+	a.StorageKey = attachment.StorageKey(a.WorkItemID, a.ID)
+	return a
+}
+
+func (s *attachmentRepositoryBlackBoxTest) TestCreate() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		a := newUploadedAttachment(fxt.WorkItems[0].ID, fxt.Identities[0].ID)
+		// when
+		err := s.repo.Create(s.Ctx, a)
+		// then
+		require.NoError(t, err)
+		loaded, err := s.repo.Load(s.Ctx, a.ID)
+		require.NoError(t, err)
+		assert.Equal(t, a.Name, loaded.Name)
+		assert.Equal(t, a.Size, loaded.Size)
+		assert.Equal(t, a.Checksum, loaded.Checksum)
+		assert.False(t, loaded.CommentID.Valid)
+	})
+
+	s.T().Run("ok - comment of the work item", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Comments(1))
+		a := newUploadedAttachment(fxt.WorkItems[0].ID, fxt.Identities[0].ID)
+		a.CommentID = id.NullUUID{UUID: fxt.Comments[0].ID, Valid: true}
+		// when
+		err := s.repo.Create(s.Ctx, a)
+		// then
+		require.NoError(t, err)
+		attachments, err := s.repo.ListByComment(s.Ctx, fxt.Comments[0].ID)
+		require.NoError(t, err)
+		require.Len(t, attachments, 1)
+		assert.Equal(t, a.ID, attachments[0].ID)
+	})
+
+	s.T().Run("fail - comment of another work item", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(2), tf.Comments(1))
+		a := newUploadedAttachment(fxt.WorkItems[1].ID, fxt.Identities[0].ID)
+		a.CommentID = id.NullUUID{UUID: fxt.Comments[0].ID, Valid: true}
+		// when
+		err := s.repo.Create(s.Ctx, a)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("fail - not uploaded", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		a := newUploadedAttachment(fxt.WorkItems[0].ID, fxt.Identities[0].ID)
+		a.StorageKey = ""
+		// when
+		err := s.repo.Create(s.Ctx, a)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func (s *attachmentRepositoryBlackBoxTest) TestList() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItems(2))
+	a1 := newUploadedAttachment(fxt.WorkItems[0].ID, fxt.Identities[0].ID)
+	a2 := newUploadedAttachment(fxt.WorkItems[0].ID, fxt.Identities[0].ID)
+	a3 := newUploadedAttachment(fxt.WorkItems[1].ID, fxt.Identities[0].ID)
+	for _, a := range []*attachment.Attachment{a1, a2, a3} {
+		require.NoError(s.T(), s.repo.Create(s.Ctx, a))
+	}
+	// when
+	attachments, err := s.repo.List(s.Ctx, fxt.WorkItems[0].ID)
+	// then
+	require.NoError(s.T(), err)
+	require.Len(s.T(), attachments, 2)
+	assert.Equal(s.T(), a1.ID, attachments[0].ID)
+	assert.Equal(s.T(), a2.ID, attachments[1].ID)
+}
+
+func (s *attachmentRepositoryBlackBoxTest) TestDelete() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		a := newUploadedAttachment(fxt.WorkItems[0].ID, fxt.Identities[0].ID)
+		require.NoError(t, s.repo.Create(s.Ctx, a))
+		// when
+		err := s.repo.Delete(s.Ctx, a.ID)
+		// then
+		require.NoError(t, err)
+		_, err = s.repo.Load(s.Ctx, a.ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+		attachments, err := s.repo.List(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		require.Empty(t, attachments)
+	})
+
+	s.T().Run("fail - unknown attachment", func(t *testing.T) {
+		err := s.repo.Delete(s.Ctx, uuid.NewV4())
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
@@ -0,0 +1,111 @@
+package attachment
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	errs "github.com/pkg/errors"
+)
+
+// BlobStore keeps the content of attachments. Keys are slash separated
+// relative paths such as "<work item ID>/<attachment ID>".
+type BlobStore interface {
+	// Put stores the given content under the given key, replacing any
+	// existing content
+	Put(ctx context.Context, key string, content io.Reader) error
+	// Get returns the content stored under the given key. The caller has to
+	// close the returned reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the content stored under the given key
+	Delete(ctx context.Context, key string) error
+}
+
+// NewFileSystemBlobStore creates a blob store that keeps each blob in a file
+// below the given root directory
+func NewFileSystemBlobStore(root string) *FileSystemBlobStore {
+	return &FileSystemBlobStore{root: root}
+}
+
+// FileSystemBlobStore is a BlobStore backed by the local file system
+type FileSystemBlobStore struct {
+	root string
+}
+
+// Ensure FileSystemBlobStore implements the BlobStore interface
+var _ BlobStore = &FileSystemBlobStore{}
+
+// path returns the file path for the given key or an error if the key would
+// escape the root directory
+func (s *FileSystemBlobStore) path(key string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", errors.NewBadParameterError("key", key).Expected("a relative path")
+	}
+	return filepath.Join(s.root, cleaned), nil
+}
+
+// Put implements BlobStore. The content is written to a temporary file first
+// so that readers never see partially written blobs.
+func (s *FileSystemBlobStore) Put(ctx context.Context, key string, content io.Reader) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(p)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to create directory for blob %q", key))
+	}
+	tmp, err := ioutil.TempFile(dir, ".upload-")
+	if err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to create temporary file for blob %q", key))
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to write blob %q", key))
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to write blob %q", key))
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to store blob %q", key))
+	}
+	return nil
+}
+
+// Get implements BlobStore
+func (s *FileSystemBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if os.IsNotExist(err) {
+		return nil, errors.NewNotFoundError("blob", key)
+	}
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to read blob %q", key))
+	}
+	return f, nil
+}
+
+// Delete implements BlobStore
+func (s *FileSystemBlobStore) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(p)
+	if os.IsNotExist(err) {
+		return errors.NewNotFoundError("blob", key)
+	}
+	if err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to delete blob %q", key))
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package attachment_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/attachment"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFileSystemBlobStore(t *testing.T) (*attachment.FileSystemBlobStore, func()) {
+	root, err := ioutil.TempDir("", "attachments")
+	require.NoError(t, err)
+	return attachment.NewFileSystemBlobStore(root), func() { os.RemoveAll(root) }
+}
+
+func TestFileSystemBlobStore(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("put, get and delete", func(t *testing.T) {
+		// given
+		store, cleanup := newFileSystemBlobStore(t)
+		defer cleanup()
+		// when
+		err := store.Put(ctx, "foo/bar", strings.NewReader("hello"))
+		require.NoError(t, err)
+		// then
+		r, err := store.Get(ctx, "foo/bar")
+		require.NoError(t, err)
+		content, err := ioutil.ReadAll(r)
+		r.Close()
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(content))
+		// when
+		err = store.Delete(ctx, "foo/bar")
+		// then
+		require.NoError(t, err)
+		_, err = store.Get(ctx, "foo/bar")
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		store, cleanup := newFileSystemBlobStore(t)
+		defer cleanup()
+		_, err := store.Get(ctx, "unknown")
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+		err = store.Delete(ctx, "unknown")
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+
+	t.Run("keys must not escape the root", func(t *testing.T) {
+		store, cleanup := newFileSystemBlobStore(t)
+		defer cleanup()
+		for _, key := range []string{"", "../foo", "foo/../../bar", "/etc/passwd"} {
+			err := store.Put(ctx, key, strings.NewReader("hello"))
+			require.IsType(t, errors.BadParameterError{}, errs.Cause(err), "key %q", key)
+		}
+	})
+}
+
+func TestUpload(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("ok", func(t *testing.T) {
+		// given
+		store, cleanup := newFileSystemBlobStore(t)
+		defer cleanup()
+		a := attachment.Attachment{WorkItemID: uuid.NewV4(), Name: "notes.txt"}
+		// when
+		err := attachment.Upload(ctx, store, &a, strings.NewReader("hello"), 10)
+		// then
+		require.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, a.ID)
+		assert.Equal(t, int64(5), a.Size)
+		assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", a.Checksum)
+		assert.Equal(t, "text/plain; charset=utf-8", a.MimeType)
+		assert.Equal(t, attachment.StorageKey(a.WorkItemID, a.ID), a.StorageKey)
+		r, err := store.Get(ctx, a.StorageKey)
+		require.NoError(t, err)
+		r.Close()
+	})
+
+	t.Run("keeps given MIME type", func(t *testing.T) {
+		store, cleanup := newFileSystemBlobStore(t)
+		defer cleanup()
+		a := attachment.Attachment{WorkItemID: uuid.NewV4(), Name: "data.csv", MimeType: "text/csv"}
+		err := attachment.Upload(ctx, store, &a, strings.NewReader("a,b"), 10)
+		require.NoError(t, err)
+		assert.Equal(t, "text/csv", a.MimeType)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		// given
+		store, cleanup := newFileSystemBlobStore(t)
+		defer cleanup()
+		a := attachment.Attachment{ID: uuid.NewV4(), WorkItemID: uuid.NewV4(), Name: "big.txt"}
+		// when
+		err := attachment.Upload(ctx, store, &a, strings.NewReader("hello world"), 10)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		_, err = store.Get(ctx, attachment.StorageKey(a.WorkItemID, a.ID))
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		store, cleanup := newFileSystemBlobStore(t)
+		defer cleanup()
+		a := attachment.Attachment{WorkItemID: uuid.NewV4()}
+		err := attachment.Upload(ctx, store, &a, strings.NewReader("hello"), 10)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
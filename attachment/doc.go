@@ -0,0 +1,4 @@
+// Package attachment contains the operations to manage files attached to work
+// items and comments. The metadata of an attachment is stored in the database
+// while its content is kept in a pluggable blob store.
+package attachment
@@ -0,0 +1,66 @@
+package attachment
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// StorageKey returns the blob store key for the content of the attachment
+// with the given ID on the given work item
+func StorageKey(workItemID, attachmentID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s", workItemID, attachmentID)
+}
+
+// Upload puts the given content into the blob store and fills in the size,
+// checksum and storage key of the given attachment. If the attachment has no
+// MIME type, it is detected from the content. Content larger than maxSize
+// bytes is rejected and not kept in the store.
+func Upload(ctx context.Context, store BlobStore, a *Attachment, content io.Reader, maxSize int64) error {
+	if strings.TrimSpace(a.Name) == "" {
+		return errors.NewBadParameterError("name", a.Name).Expected("a non-empty file name")
+	}
+	if a.ID == uuid.Nil {
+		a.ID = uuid.NewV4()
+	}
+	buffered := bufio.NewReaderSize(content, 512)
+	if a.MimeType == "" {
+		// Peek returns io.EOF for content smaller than 512 bytes, which is fine
+		head, _ := buffered.Peek(512)
+		a.MimeType = http.DetectContentType(head)
+	}
+	hash := sha256.New()
+	counter := &countingWriter{}
+	// read at most one byte more than allowed to detect oversized content
+	limited := io.LimitReader(io.TeeReader(buffered, io.MultiWriter(hash, counter)), maxSize+1)
+	key := StorageKey(a.WorkItemID, a.ID)
+	if err := store.Put(ctx, key, limited); err != nil {
+		return err
+	}
+	if counter.n > maxSize {
+		store.Delete(ctx, key)
+		return errors.NewBadParameterError("size", fmt.Sprintf("more than %d bytes", maxSize)).Expected(fmt.Sprintf("at most %d bytes", maxSize))
+	}
+	a.Size = counter.n
+	a.Checksum = hex.EncodeToString(hash.Sum(nil))
+	a.StorageKey = key
+	return nil
+}
+
+// countingWriter counts the bytes written to it
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
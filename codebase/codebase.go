@@ -130,6 +130,12 @@ type Codebase struct {
 	StackID           *string
 	LastUsedWorkspace string
 	CVEScan           bool
+	// WebhookSecret verifies the push and pull request webhooks of the
+	// codebase
+	WebhookSecret string
+	// MergeState is the state the work items closed by a merged pull request
+	// are moved to, no transition happens if it is nil
+	MergeState *string
 }
 
 // TableName overrides the table name settings in Gorm to force a specific table name
@@ -0,0 +1,124 @@
+package scm
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	"github.com/fabric8-services/fabric8-wit/log"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Kinds of SCM activities
+const (
+	KindCommit      = "commit"
+	KindBranch      = "branch"
+	KindPullRequest = "pull-request"
+)
+
+// States of pull requests
+const (
+	StateOpen   = "open"
+	StateClosed = "closed"
+	StateMerged = "merged"
+)
+
+// Activity is a commit, branch or pull request of a codebase that
+// references a work item
+type Activity struct {
+	gormsupport.Lifecycle
+	ID         uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
+	WorkItemID uuid.UUID `sql:"type:uuid"`
+	CodebaseID uuid.UUID `sql:"type:uuid"`
+	// Kind is one of KindCommit, KindBranch or KindPullRequest
+	Kind string
+	// Ref is the SHA of a commit, the name of a branch or the number of a
+	// pull request
+	Ref string
+	// Title is the message of a commit or the title of a pull request
+	Title  string
+	URL    string
+	Author string
+	// State is the state of a pull request
+	State string
+	// Closes is true if the activity closes the work item, e.g. with
+	// "fixes #12"
+	Closes     bool
+	OccurredAt *time.Time
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (a Activity) TableName() string {
+	return "scm_activities"
+}
+
+// Repository encapsulates storage & retrieval of the SCM activities
+type Repository interface {
+	Save(ctx context.Context, a *Activity) error
+	List(ctx context.Context, workItemID uuid.UUID) ([]Activity, error)
+}
+
+// NewRepository creates a new storage type.
+func NewRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+// GormRepository is the implementation of the storage interface for SCM
+// activities.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+// Save creates the given activity or updates the existing activity of the
+// same work item, codebase, kind and ref
+func (r *GormRepository) Save(ctx context.Context, a *Activity) error {
+	defer goa.MeasureSince([]string{"goa", "db", "scm_activity", "save"}, time.Now())
+	if a.ID == uuid.Nil {
+		a.ID = uuid.NewV4()
+	}
+	// webhook deliveries and scans may record the same activity concurrently,
+	// so the unique index decides whether it is created or updated
+	err := r.db.Raw(`
+		INSERT INTO scm_activities (id, work_item_id, codebase_id, kind, ref, title, url, author, state, closes, occurred_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, now(), now())
+		ON CONFLICT (work_item_id, codebase_id, kind, ref) WHERE deleted_at IS NULL DO UPDATE SET
+			title = EXCLUDED.title,
+			url = EXCLUDED.url,
+			author = EXCLUDED.author,
+			state = EXCLUDED.state,
+			closes = EXCLUDED.closes,
+			occurred_at = EXCLUDED.occurred_at,
+			updated_at = now()
+		RETURNING id, created_at, updated_at`,
+		a.ID, a.WorkItemID, a.CodebaseID, a.Kind, a.Ref, a.Title, a.URL, a.Author, a.State, a.Closes, a.OccurredAt,
+	).Row().Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":          err,
+			"work_item_id": a.WorkItemID,
+			"ref":          a.Ref,
+		}, "unable to save the scm activity")
+		return errors.NewInternalError(ctx, err)
+	}
+	return nil
+}
+
+// List returns the activities referencing the given work item, newest
+// first
+func (r *GormRepository) List(ctx context.Context, workItemID uuid.UUID) ([]Activity, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "scm_activity", "list"}, time.Now())
+	var res []Activity
+	if err := r.db.Where("work_item_id = ?", workItemID).Order("occurred_at DESC NULLS LAST, created_at DESC").Find(&res).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":          err,
+			"work_item_id": workItemID,
+		}, "unable to list the scm activities of the work item")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return res, nil
+}
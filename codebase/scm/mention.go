@@ -0,0 +1,54 @@
+package scm
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Mention is a reference to a work item in a commit message, branch name or
+// pull request
+type Mention struct {
+	// Key is the key of the work item, e.g. "PLAT-12", or empty if the work
+	// item is referenced by its number only
+	Key string
+	// Number is the number of the work item in the space of the codebase if
+	// it is referenced as "#12"
+	Number int
+	// Closes is true if the reference is preceded by a closing keyword, e.g.
+	// "fixes #12" or "Closes PLAT-12"
+	Closes bool
+}
+
+// mentionRegexp matches work item keys and numbers, optionally preceded by a
+// closing keyword
+var mentionRegexp = regexp.MustCompile(`(?i)(?:\b(close[sd]?|fix(?:e[sd])?|resolve[sd]?):?\s+)?(?:\b([a-z][a-z0-9]{1,9}-[0-9]+)\b|#([0-9]+)\b)`)
+
+// ParseMentions returns the work items referenced in the given text. A work
+// item referenced several times is returned once, as closed if any of its
+// references closes it.
+func ParseMentions(text string) []Mention {
+	var res []Mention
+	index := map[Mention]int{}
+	for _, m := range mentionRegexp.FindAllStringSubmatch(text, -1) {
+		mention := Mention{}
+		if m[2] != "" {
+			mention.Key = strings.ToUpper(m[2])
+		} else {
+			n, err := strconv.Atoi(m[3])
+			if err != nil || n == 0 {
+				continue
+			}
+			mention.Number = n
+		}
+		closes := m[1] != ""
+		if i, ok := index[mention]; ok {
+			res[i].Closes = res[i].Closes || closes
+			continue
+		}
+		index[mention] = len(res)
+		mention.Closes = closes
+		res = append(res, mention)
+	}
+	return res
+}
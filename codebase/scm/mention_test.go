@@ -0,0 +1,32 @@
+package scm_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/codebase/scm"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMentions(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	testData := []struct {
+		text     string
+		expected []scm.Mention
+	}{
+		{"Refactor the parser", nil},
+		{"PLAT-12 add the login page", []scm.Mention{{Key: "PLAT-12"}}},
+		{"feature/plat-12-login", []scm.Mention{{Key: "PLAT-12"}}},
+		{"Fix the redirect, fixes #42", []scm.Mention{{Number: 42, Closes: true}}},
+		{"Closes: PLAT-7", []scm.Mention{{Key: "PLAT-7", Closes: true}}},
+		{"resolved #3 and #4, see PLAT-5", []scm.Mention{{Number: 3, Closes: true}, {Number: 4}, {Key: "PLAT-5"}}},
+		{"PLAT-5 first, then fix PLAT-5", []scm.Mention{{Key: "PLAT-5", Closes: true}}},
+		{"Bump to #0", nil},
+		{"foo#12bar", nil},
+	}
+	for _, d := range testData {
+		t.Run(d.text, func(t *testing.T) {
+			assert.Equal(t, d.expected, scm.ParseMentions(d.text))
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package scm
+
+import (
+	"context"
+
+	"github.com/fabric8-services/fabric8-wit/codebase"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Record links the given changes of the codebase to the work items they
+// mention. Work items are mentioned by key in any space or by number in the
+// space of the codebase, unknown ones are skipped. Merged changes that close
+// a work item of the space of the codebase move it to the merge state of the
+// codebase, if any, on behalf of the given modifier. A move that the workflow
+// of the work item doesn't allow is skipped so that the other changes are
+// still recorded.
+// It returns the number of recorded activities.
+func Record(ctx context.Context, activities Repository, workItems workitem.WorkItemRepository, cb codebase.Codebase, modifierID uuid.UUID, changes []Change) (int, error) {
+	count := 0
+	for _, c := range changes {
+		for _, m := range ParseMentions(c.Text) {
+			wiID, err := resolveMention(ctx, workItems, cb.SpaceID, m)
+			if err != nil {
+				return count, err
+			}
+			if wiID == nil {
+				continue
+			}
+			a := Activity{
+				WorkItemID: *wiID,
+				CodebaseID: cb.ID,
+				Kind:       c.Kind,
+				Ref:        c.Ref,
+				Title:      c.Title,
+				URL:        c.URL,
+				Author:     c.Author,
+				State:      c.State,
+				Closes:     m.Closes,
+				OccurredAt: c.OccurredAt,
+			}
+			if err := activities.Save(ctx, &a); err != nil {
+				return count, err
+			}
+			count++
+			if c.Merged && m.Closes && cb.MergeState != nil {
+				if err := transition(ctx, workItems, cb, *wiID, modifierID); err != nil {
+					if !rejectedTransition(err) {
+						return count, err
+					}
+					log.Warn(ctx, map[string]interface{}{
+						"wi_id":       *wiID,
+						"codebase_id": cb.ID,
+						"state":       *cb.MergeState,
+						"err":         err,
+					}, "skipping the move of the work item closed by a merge")
+				}
+			}
+		}
+	}
+	return count, nil
+}
+
+// resolveMention returns the ID of the mentioned work item or nil if there
+// is no such work item
+func resolveMention(ctx context.Context, workItems workitem.WorkItemRepository, spaceID uuid.UUID, m Mention) (*uuid.UUID, error) {
+	var wiID *uuid.UUID
+	var err error
+	if m.Key != "" {
+		wiID, _, err = workItems.LookupIDByKey(ctx, m.Key)
+	} else {
+		var wi *workitem.WorkItem
+		if wi, err = workItems.Load(ctx, spaceID, m.Number); err == nil {
+			wiID = &wi.ID
+		}
+	}
+	if err != nil {
+		if ok, _ := errors.IsNotFoundError(err); ok {
+			log.Debug(ctx, map[string]interface{}{
+				"key":    m.Key,
+				"number": m.Number,
+			}, "ignoring the mention of an unknown work item")
+			return nil, nil
+		}
+		return nil, err
+	}
+	return wiID, nil
+}
+
+// transition moves the given work item to the merge state of the codebase
+// unless it belongs to another space or is already in that state
+func transition(ctx context.Context, workItems workitem.WorkItemRepository, cb codebase.Codebase, wiID uuid.UUID, modifierID uuid.UUID) error {
+	wi, err := workItems.LoadByID(ctx, wiID)
+	if err != nil {
+		return err
+	}
+	if wi.SpaceID != cb.SpaceID || wi.Fields[workitem.SystemState] == *cb.MergeState {
+		return nil
+	}
+	wi.Fields[workitem.SystemState] = *cb.MergeState
+	if _, _, err := workItems.Save(ctx, wi.SpaceID, *wi, modifierID); err != nil {
+		return errs.Wrapf(err, "failed to move work item %s to state %s", wi.ID, *cb.MergeState)
+	}
+	log.Info(ctx, map[string]interface{}{
+		"wi_id":       wi.ID,
+		"codebase_id": cb.ID,
+		"state":       *cb.MergeState,
+	}, "moved the work item closed by a merge")
+	return nil
+}
+
+// rejectedTransition returns true if the given error means that the work item
+// can't be moved to the merge state, e.g. because its workflow doesn't allow
+// it or it was modified concurrently
+func rejectedTransition(err error) bool {
+	if ok, _ := errors.IsBadParameterError(err); ok {
+		return true
+	}
+	if ok, _ := errors.IsForbiddenError(err); ok {
+		return true
+	}
+	ok, _ := errors.IsVersionConflictError(err)
+	return ok
+}
@@ -0,0 +1,174 @@
+package scm_test
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/codebase/scm"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type recordBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	repo scm.Repository
+}
+
+func TestRunRecordBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &recordBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *recordBlackBoxTest) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.repo = scm.NewRepository(s.DB)
+}
+
+func (s *recordBlackBoxTest) TestRecord() {
+	spaceKey := "K" + strings.ToUpper(uuid.NewV4().String()[:8])
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.Spaces(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.Spaces[idx].Key = spaceKey
+			return nil
+		}),
+		tf.Codebases(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.Codebases[idx].MergeState = ptr.String(workitem.SystemStateClosed)
+			return nil
+		}),
+		tf.WorkItems(2),
+	)
+	wir := workitem.NewWorkItemRepository(s.DB)
+	first, second := fxt.WorkItems[0], fxt.WorkItems[1]
+	occurredAt := time.Date(2018, 5, 1, 10, 0, 0, 0, time.UTC)
+	changes := []scm.Change{
+		{Kind: scm.KindBranch, Ref: "login", Text: "login"},
+		{
+			Kind:       scm.KindCommit,
+			Ref:        "3f2a9c1",
+			Title:      "Add the login page",
+			Text:       "Add the login page\n\nfixes #" + strconv.Itoa(first.Number) + ", see " + workitem.FormatKey(spaceKey, second.Number),
+			OccurredAt: &occurredAt,
+		},
+		{Kind: scm.KindPullRequest, Ref: "42", State: scm.StateOpen, Text: "fixes #99999"},
+	}
+
+	s.T().Run("link mentioned work items", func(t *testing.T) {
+		count, err := scm.Record(s.Ctx, s.repo, wir, *fxt.Codebases[0], fxt.Identities[0].ID, changes)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		activities, err := s.repo.List(s.Ctx, first.ID)
+		require.NoError(t, err)
+		require.Len(t, activities, 1)
+		assert.Equal(t, scm.KindCommit, activities[0].Kind)
+		assert.Equal(t, "3f2a9c1", activities[0].Ref)
+		assert.True(t, activities[0].Closes)
+		activities, err = s.repo.List(s.Ctx, second.ID)
+		require.NoError(t, err)
+		require.Len(t, activities, 1)
+		assert.False(t, activities[0].Closes)
+		// not merged, the state is unchanged
+		wi, err := wir.LoadByID(s.Ctx, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, workitem.SystemStateNew, wi.Fields[workitem.SystemState])
+	})
+
+	s.T().Run("update the recorded activities", func(t *testing.T) {
+		pr := scm.Change{Kind: scm.KindPullRequest, Ref: "42", State: scm.StateOpen, Text: "Closes #" + strconv.Itoa(first.Number)}
+		_, err := scm.Record(s.Ctx, s.repo, wir, *fxt.Codebases[0], fxt.Identities[0].ID, []scm.Change{pr})
+		require.NoError(t, err)
+		pr.State = scm.StateMerged
+		pr.Merged = true
+		_, err = scm.Record(s.Ctx, s.repo, wir, *fxt.Codebases[0], fxt.Identities[0].ID, []scm.Change{pr})
+		require.NoError(t, err)
+		activities, err := s.repo.List(s.Ctx, first.ID)
+		require.NoError(t, err)
+		require.Len(t, activities, 2)
+		var prActivity *scm.Activity
+		for i := range activities {
+			if activities[i].Kind == scm.KindPullRequest {
+				prActivity = &activities[i]
+			}
+		}
+		require.NotNil(t, prActivity)
+		assert.Equal(t, scm.StateMerged, prActivity.State)
+		// merged, the work item is moved to the merge state of the codebase
+		wi, err := wir.LoadByID(s.Ctx, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, workitem.SystemStateClosed, wi.Fields[workitem.SystemState])
+	})
+}
+
+func (s *recordBlackBoxTest) TestRecordSkipsForbiddenTransitions() {
+	// given a work item whose workflow doesn't allow to close it directly
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].Transitions = workitem.Transitions{
+				{From: workitem.SystemStateNew, To: workitem.SystemStateOpen},
+				{From: workitem.SystemStateOpen, To: workitem.SystemStateClosed},
+			}
+			return nil
+		}),
+		tf.Codebases(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.Codebases[idx].MergeState = ptr.String(workitem.SystemStateClosed)
+			return nil
+		}),
+		tf.WorkItems(2, tf.SetWorkItemField(workitem.SystemState, workitem.SystemStateNew)),
+	)
+	wir := workitem.NewWorkItemRepository(s.DB)
+	changes := []scm.Change{
+		{Kind: scm.KindPullRequest, Ref: "1", State: scm.StateMerged, Merged: true, Text: "closes #" + strconv.Itoa(fxt.WorkItems[0].Number)},
+		{Kind: scm.KindCommit, Ref: "3f2a9c1", Text: "see #" + strconv.Itoa(fxt.WorkItems[1].Number)},
+	}
+	// when
+	count, err := scm.Record(s.Ctx, s.repo, wir, *fxt.Codebases[0], fxt.Identities[0].ID, changes)
+	// then the activities are recorded but the state is unchanged
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, count)
+	for _, wi := range fxt.WorkItems {
+		activities, err := s.repo.List(s.Ctx, wi.ID)
+		require.NoError(s.T(), err)
+		assert.Len(s.T(), activities, 1)
+	}
+	wi, err := wir.LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), workitem.SystemStateNew, wi.Fields[workitem.SystemState])
+}
+
+func (s *recordBlackBoxTest) TestSaveConcurrently() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Codebases(1), tf.WorkItems(1))
+	// when the same activity is saved concurrently
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = s.repo.Save(s.Ctx, &scm.Activity{
+				WorkItemID: fxt.WorkItems[0].ID,
+				CodebaseID: fxt.Codebases[0].ID,
+				Kind:       scm.KindBranch,
+				Ref:        "login",
+				Title:      "login",
+			})
+		}(i)
+	}
+	wg.Wait()
+	// then it is created once
+	for _, err := range errs {
+		require.NoError(s.T(), err)
+	}
+	activities, err := s.repo.List(s.Ctx, fxt.WorkItems[0].ID)
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), activities, 1)
+}
@@ -0,0 +1,146 @@
+package scm
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+
+	errs "github.com/pkg/errors"
+)
+
+// DefaultScanLimit is the number of most recent commits read by a scan
+const DefaultScanLimit = 500
+
+// scanProtocols are the transports git may use to clone a repository, see
+// GIT_ALLOW_PROTOCOL
+const scanProtocols = "https:ssh"
+
+// scpLikeURL matches the short ssh syntax of git, e.g.
+// "git@github.com:owner/repo.git"
+var scpLikeURL = regexp.MustCompile(`^[\w.-]+@[\w.-]+:[^:]+$`)
+
+// ValidateRepositoryURL checks that the given repository URL can be scanned,
+// which is the case for https and ssh URLs only
+func ValidateRepositoryURL(repoURL string) error {
+	if !strings.HasPrefix(repoURL, "-") {
+		if u, err := url.Parse(repoURL); err == nil && u.Host != "" && (u.Scheme == "https" || u.Scheme == "ssh") {
+			return nil
+		}
+		if scpLikeURL.MatchString(repoURL) {
+			return nil
+		}
+	}
+	return errors.NewBadParameterError("url", repoURL).Expected("an https or ssh repository URL")
+}
+
+// Scan clones the given git repository into a temporary directory and
+// returns its branches and its last commits, at most limit. The changes of
+// a scan are never merged: it only links past activity.
+func Scan(ctx context.Context, repoURL string, limit int) ([]Change, error) {
+	if err := ValidateRepositoryURL(repoURL); err != nil {
+		return nil, err
+	}
+	return scan(ctx, repoURL, limit, scanProtocols)
+}
+
+// scan clones the given repository with the given git transports and reads
+// its changes
+func scan(ctx context.Context, repoURL string, limit int, protocols string) ([]Change, error) {
+	if limit <= 0 {
+		limit = DefaultScanLimit
+	}
+	dir, err := ioutil.TempDir("", "scm-scan")
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to create the scan directory")
+	}
+	defer os.RemoveAll(dir)
+	// the URL and the directory are never taken for options
+	_, err = git(ctx, "", []string{"GIT_ALLOW_PROTOCOL=" + protocols}, "clone", "--quiet", "--bare", "--filter=blob:none", "--", repoURL, dir)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to clone %s", repoURL)
+	}
+	webURL, pathPrefix := repositoryWebURL(repoURL)
+	var res []Change
+
+	branches, err := git(ctx, dir, nil, "for-each-ref", "--format=%(refname:short)", "refs/heads")
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to list the branches of %s", repoURL)
+	}
+	for _, branch := range strings.Fields(branches) {
+		c := Change{Kind: KindBranch, Ref: branch, Title: branch, Text: branch}
+		if webURL != "" {
+			c.URL = webURL + pathPrefix + "/tree/" + branch
+		}
+		res = append(res, c)
+	}
+
+	// commits are separated by 0x1e and their fields by 0x1f
+	out, err := git(ctx, dir, nil, "log", "--all", "--max-count", strconv.Itoa(limit), "--format=%H%x1f%an%x1f%aI%x1f%B%x1e")
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to read the commits of %s", repoURL)
+	}
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x1f", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		c := Change{
+			Kind:       KindCommit,
+			Ref:        fields[0],
+			Title:      firstLine(fields[3]),
+			Author:     fields[1],
+			Text:       fields[3],
+			OccurredAt: parseTime(fields[2]),
+		}
+		if webURL != "" {
+			c.URL = webURL + pathPrefix + "/commit/" + c.Ref
+		}
+		res = append(res, c)
+	}
+	return res, nil
+}
+
+// git runs git with the given arguments and environment variables in the
+// given directory and returns its output
+func git(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// never prompt for credentials
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", errs.Wrapf(err, "git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// repositoryWebURL returns the web URL of the given Github or GitLab
+// repository URL, e.g. "https://github.com/owner/repo" for
+// "git@github.com:owner/repo.git", and the prefix of the paths of its
+// branches and commits. The URL is empty for other repositories.
+func repositoryWebURL(repoURL string) (string, string) {
+	u := strings.TrimSuffix(repoURL, ".git")
+	if strings.HasPrefix(u, "git@") {
+		u = "https://" + strings.Replace(strings.TrimPrefix(u, "git@"), ":", "/", 1)
+	}
+	switch {
+	case strings.HasPrefix(u, "https://github.com/"):
+		return u, ""
+	case strings.HasPrefix(u, "https://gitlab.com/"):
+		return u, "/-"
+	}
+	return "", ""
+}
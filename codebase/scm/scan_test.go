@@ -0,0 +1,47 @@
+package scm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/codebase/scm"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	errs "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRepositoryURL(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Run("valid", func(t *testing.T) {
+		for _, u := range []string{
+			"https://github.com/fabric8-services/fabric8-wit.git",
+			"ssh://git@gitlab.com/group/project.git",
+			"git@github.com:fabric8-services/fabric8-wit.git",
+		} {
+			assert.NoError(t, scm.ValidateRepositoryURL(u), u)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, u := range []string{
+			"",
+			"/tmp/repo",
+			"file:///tmp/repo",
+			"http://github.com/fabric8-services/fabric8-wit.git",
+			"git://github.com/fabric8-services/fabric8-wit.git",
+			"ext::sh -c touch% /tmp/pwned",
+			"--upload-pack=touch /tmp/pwned",
+			"-oProxyCommand=touch /tmp/pwned@github.com:owner/repo",
+			"https:///owner/repo",
+		} {
+			err := scm.ValidateRepositoryURL(u)
+			assert.IsType(t, errors.BadParameterError{}, errs.Cause(err), u)
+		}
+	})
+}
+
+func TestScanRejectsLocalRepositories(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	_, err := scm.Scan(context.Background(), "/tmp", 10)
+	assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+}
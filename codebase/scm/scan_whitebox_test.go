@@ -0,0 +1,55 @@
+package scm
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScan(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir, err := ioutil.TempDir("", "scm-scan-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Octo Cat", "-c", "user.email=octocat@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	run("init", "--quiet")
+	run("commit", "--quiet", "--allow-empty", "-m", "Add the login page\n\nfixes #3")
+	run("branch", "PLAT-1-login")
+
+	// local repositories are only allowed in tests
+	changes, err := scan(context.Background(), dir, 10, "file")
+	require.NoError(t, err)
+	var branches, commits []Change
+	for _, c := range changes {
+		switch c.Kind {
+		case KindBranch:
+			branches = append(branches, c)
+		case KindCommit:
+			commits = append(commits, c)
+		}
+	}
+	require.Len(t, branches, 2)
+	assert.Equal(t, "PLAT-1-login", branches[0].Ref)
+	require.Len(t, commits, 1)
+	assert.Equal(t, "Add the login page", commits[0].Title)
+	assert.Equal(t, "Octo Cat", commits[0].Author)
+	assert.Contains(t, commits[0].Text, "fixes #3")
+	assert.NotNil(t, commits[0].OccurredAt)
+	assert.False(t, commits[0].Merged)
+	// local repositories have no web URL
+	assert.Empty(t, commits[0].URL)
+}
@@ -0,0 +1,250 @@
+package scm
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/codebase"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/rest"
+)
+
+// Change is a commit, branch or pull request of a repository before it is
+// linked to the work items it mentions
+type Change struct {
+	Kind   string
+	Ref    string
+	Title  string
+	URL    string
+	Author string
+	State  string
+	// Text is searched for work item mentions
+	Text       string
+	OccurredAt *time.Time
+	// Merged is true for merged pull requests and for commits pushed to the
+	// default branch, their closing mentions move the work items to the
+	// merge state of the codebase
+	Merged bool
+}
+
+// VerifyWebhook checks that the webhook request with the given headers and
+// body was sent by the repository of the codebase. Github requests are
+// signed with an HMAC of the body and GitLab requests carry the secret in a
+// header.
+func VerifyWebhook(cb codebase.Codebase, header http.Header, body []byte) error {
+	if cb.WebhookSecret == "" {
+		return errors.NewUnauthorizedError("webhooks are not enabled for this codebase")
+	}
+	if rest.ValidWebhookSignature(header, []byte(cb.WebhookSecret), body) {
+		return nil
+	}
+	return errors.NewUnauthorizedError("invalid webhook signature")
+}
+
+// ParseWebhook returns the changes of the given push or pull request webhook
+// request of Github or GitLab. Other events are ignored.
+func ParseWebhook(header http.Header, body []byte) ([]Change, error) {
+	switch {
+	case header.Get("X-GitHub-Event") == "push":
+		return parseGithubPush(body)
+	case header.Get("X-GitHub-Event") == "pull_request":
+		return parseGithubPullRequest(body)
+	case header.Get("X-Gitlab-Event") == "Push Hook":
+		return parseGitLabPush(body)
+	case header.Get("X-Gitlab-Event") == "Merge Request Hook":
+		return parseGitLabMergeRequest(body)
+	}
+	return nil, nil
+}
+
+type pushCommit struct {
+	ID        string `json:"id"`
+	Message   string `json:"message"`
+	URL       string `json:"url"`
+	Timestamp string `json:"timestamp"`
+	Author    struct {
+		Name     string `json:"name"`
+		Username string `json:"username"`
+	} `json:"author"`
+}
+
+// pushChanges returns the branch and the commits of a push
+func pushChanges(ref, branchURL, defaultBranch string, deleted bool, commits []pushCommit) []Change {
+	if !strings.HasPrefix(ref, "refs/heads/") {
+		// tags are not linked
+		return nil
+	}
+	branch := strings.TrimPrefix(ref, "refs/heads/")
+	var res []Change
+	var latest *time.Time
+	for _, c := range commits {
+		author := c.Author.Username
+		if author == "" {
+			author = c.Author.Name
+		}
+		occurredAt := parseTime(c.Timestamp)
+		if occurredAt != nil && (latest == nil || occurredAt.After(*latest)) {
+			latest = occurredAt
+		}
+		res = append(res, Change{
+			Kind:       KindCommit,
+			Ref:        c.ID,
+			Title:      firstLine(c.Message),
+			URL:        c.URL,
+			Author:     author,
+			Text:       c.Message,
+			OccurredAt: occurredAt,
+			Merged:     branch == defaultBranch,
+		})
+	}
+	if !deleted && branch != defaultBranch {
+		res = append(res, Change{
+			Kind:       KindBranch,
+			Ref:        branch,
+			Title:      branch,
+			URL:        branchURL + branch,
+			Text:       branch,
+			OccurredAt: latest,
+		})
+	}
+	return res
+}
+
+func parseGithubPush(body []byte) ([]Change, error) {
+	var payload struct {
+		Ref        string `json:"ref"`
+		Deleted    bool   `json:"deleted"`
+		Repository struct {
+			HTMLURL       string `json:"html_url"`
+			DefaultBranch string `json:"default_branch"`
+		} `json:"repository"`
+		Commits []pushCommit `json:"commits"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.NewBadParameterError("body", nil).Expected("Github push payload")
+	}
+	return pushChanges(payload.Ref, payload.Repository.HTMLURL+"/tree/", payload.Repository.DefaultBranch, payload.Deleted, payload.Commits), nil
+}
+
+func parseGitLabPush(body []byte) ([]Change, error) {
+	var payload struct {
+		Ref     string `json:"ref"`
+		After   string `json:"after"`
+		Project struct {
+			WebURL        string `json:"web_url"`
+			DefaultBranch string `json:"default_branch"`
+		} `json:"project"`
+		Commits []pushCommit `json:"commits"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.NewBadParameterError("body", nil).Expected("GitLab push payload")
+	}
+	deleted := strings.Trim(payload.After, "0") == ""
+	return pushChanges(payload.Ref, payload.Project.WebURL+"/-/tree/", payload.Project.DefaultBranch, deleted, payload.Commits), nil
+}
+
+func parseGithubPullRequest(body []byte) ([]Change, error) {
+	var payload struct {
+		PullRequest *struct {
+			Number    int    `json:"number"`
+			Title     string `json:"title"`
+			Body      string `json:"body"`
+			HTMLURL   string `json:"html_url"`
+			State     string `json:"state"`
+			Merged    bool   `json:"merged"`
+			UpdatedAt string `json:"updated_at"`
+			User      struct {
+				Login string `json:"login"`
+			} `json:"user"`
+			Head struct {
+				Ref string `json:"ref"`
+			} `json:"head"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.NewBadParameterError("body", nil).Expected("Github pull request payload")
+	}
+	pr := payload.PullRequest
+	if pr == nil {
+		return nil, nil
+	}
+	state := StateOpen
+	switch {
+	case pr.Merged:
+		state = StateMerged
+	case pr.State == "closed":
+		state = StateClosed
+	}
+	return []Change{{
+		Kind:       KindPullRequest,
+		Ref:        strconv.Itoa(pr.Number),
+		Title:      pr.Title,
+		URL:        pr.HTMLURL,
+		Author:     pr.User.Login,
+		State:      state,
+		Text:       pr.Title + "\n" + pr.Body + "\n" + pr.Head.Ref,
+		OccurredAt: parseTime(pr.UpdatedAt),
+		Merged:     pr.Merged,
+	}}, nil
+}
+
+func parseGitLabMergeRequest(body []byte) ([]Change, error) {
+	var payload struct {
+		User struct {
+			Username string `json:"username"`
+		} `json:"user"`
+		ObjectAttributes struct {
+			IID          int    `json:"iid"`
+			Title        string `json:"title"`
+			Description  string `json:"description"`
+			URL          string `json:"url"`
+			State        string `json:"state"`
+			SourceBranch string `json:"source_branch"`
+			UpdatedAt    string `json:"updated_at"`
+		} `json:"object_attributes"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.NewBadParameterError("body", nil).Expected("GitLab merge request payload")
+	}
+	mr := payload.ObjectAttributes
+	state := StateOpen
+	switch mr.State {
+	case "merged":
+		state = StateMerged
+	case "closed":
+		state = StateClosed
+	}
+	return []Change{{
+		Kind:       KindPullRequest,
+		Ref:        strconv.Itoa(mr.IID),
+		Title:      mr.Title,
+		URL:        mr.URL,
+		Author:     payload.User.Username,
+		State:      state,
+		Text:       mr.Title + "\n" + mr.Description + "\n" + mr.SourceBranch,
+		OccurredAt: parseTime(mr.UpdatedAt),
+		Merged:     state == StateMerged,
+	}}, nil
+}
+
+// parseTime parses the timestamps of Github and GitLab, it returns nil if
+// the value is empty or invalid
+func parseTime(value string) *time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05 MST"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// firstLine returns the first line of the given commit message
+func firstLine(message string) string {
+	if i := strings.IndexByte(message, '\n'); i >= 0 {
+		return strings.TrimSpace(message[:i])
+	}
+	return strings.TrimSpace(message)
+}
@@ -0,0 +1,153 @@
+package scm_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/codebase"
+	"github.com/fabric8-services/fabric8-wit/codebase/scm"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyWebhook(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	body := []byte(`{"ref":"refs/heads/master"}`)
+	mac := hmac.New(sha256.New, []byte("s3cr3t"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	cb := codebase.Codebase{WebhookSecret: "s3cr3t"}
+
+	t.Run("github signature", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-Hub-Signature-256", signature)
+		require.NoError(t, scm.VerifyWebhook(cb, header, body))
+		require.Error(t, scm.VerifyWebhook(cb, header, []byte(`{}`)))
+	})
+	t.Run("gitlab token", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-Gitlab-Token", "s3cr3t")
+		require.NoError(t, scm.VerifyWebhook(cb, header, body))
+		header.Set("X-Gitlab-Token", "wrong")
+		require.Error(t, scm.VerifyWebhook(cb, header, body))
+	})
+	t.Run("no secret", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-Hub-Signature-256", signature)
+		err := scm.VerifyWebhook(codebase.Codebase{}, header, body)
+		require.Error(t, err)
+		ok, _ := errors.IsUnauthorizedError(err)
+		assert.True(t, ok)
+	})
+}
+
+func TestParseWebhook(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	event := func(name, value string) http.Header {
+		header := http.Header{}
+		header.Set(name, value)
+		return header
+	}
+
+	t.Run("github push", func(t *testing.T) {
+		body := []byte(`{
+			"ref": "refs/heads/PLAT-12-login",
+			"repository": {"html_url": "https://github.com/owner/repo", "default_branch": "master"},
+			"commits": [{
+				"id": "3f2a9c1",
+				"message": "Add the login page\n\nfixes #12",
+				"url": "https://github.com/owner/repo/commit/3f2a9c1",
+				"timestamp": "2018-05-01T10:00:00Z",
+				"author": {"name": "Octo Cat", "username": "octocat"}
+			}]
+		}`)
+		changes, err := scm.ParseWebhook(event("X-GitHub-Event", "push"), body)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		assert.Equal(t, scm.KindCommit, changes[0].Kind)
+		assert.Equal(t, "3f2a9c1", changes[0].Ref)
+		assert.Equal(t, "Add the login page", changes[0].Title)
+		assert.Equal(t, "octocat", changes[0].Author)
+		assert.False(t, changes[0].Merged)
+		require.NotNil(t, changes[0].OccurredAt)
+		assert.Equal(t, scm.KindBranch, changes[1].Kind)
+		assert.Equal(t, "PLAT-12-login", changes[1].Ref)
+		assert.Equal(t, "https://github.com/owner/repo/tree/PLAT-12-login", changes[1].URL)
+	})
+	t.Run("github push to the default branch", func(t *testing.T) {
+		body := []byte(`{
+			"ref": "refs/heads/master",
+			"repository": {"html_url": "https://github.com/owner/repo", "default_branch": "master"},
+			"commits": [{"id": "3f2a9c1", "message": "fixes #12"}]
+		}`)
+		changes, err := scm.ParseWebhook(event("X-GitHub-Event", "push"), body)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.True(t, changes[0].Merged)
+	})
+	t.Run("github tag push", func(t *testing.T) {
+		changes, err := scm.ParseWebhook(event("X-GitHub-Event", "push"), []byte(`{"ref": "refs/tags/v1.0"}`))
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+	t.Run("github pull request", func(t *testing.T) {
+		body := []byte(`{
+			"action": "closed",
+			"pull_request": {
+				"number": 42,
+				"title": "Login page",
+				"body": "Closes PLAT-12",
+				"html_url": "https://github.com/owner/repo/pull/42",
+				"state": "closed",
+				"merged": true,
+				"user": {"login": "octocat"},
+				"head": {"ref": "login"}
+			}
+		}`)
+		changes, err := scm.ParseWebhook(event("X-GitHub-Event", "pull_request"), body)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, scm.KindPullRequest, changes[0].Kind)
+		assert.Equal(t, "42", changes[0].Ref)
+		assert.Equal(t, scm.StateMerged, changes[0].State)
+		assert.True(t, changes[0].Merged)
+		assert.Contains(t, changes[0].Text, "Closes PLAT-12")
+	})
+	t.Run("gitlab merge request", func(t *testing.T) {
+		body := []byte(`{
+			"object_kind": "merge_request",
+			"user": {"username": "root"},
+			"object_attributes": {
+				"iid": 7,
+				"title": "Fix PLAT-3",
+				"url": "https://gitlab.com/owner/repo/merge_requests/7",
+				"state": "opened",
+				"source_branch": "fix",
+				"updated_at": "2018-05-01 10:00:00 UTC"
+			}
+		}`)
+		changes, err := scm.ParseWebhook(event("X-Gitlab-Event", "Merge Request Hook"), body)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "7", changes[0].Ref)
+		assert.Equal(t, scm.StateOpen, changes[0].State)
+		assert.False(t, changes[0].Merged)
+		assert.NotNil(t, changes[0].OccurredAt)
+	})
+	t.Run("gitlab branch deletion", func(t *testing.T) {
+		body := []byte(`{"ref": "refs/heads/PLAT-3", "after": "0000000000000000000000000000000000000000", "commits": []}`)
+		changes, err := scm.ParseWebhook(event("X-Gitlab-Event", "Push Hook"), body)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+	t.Run("other event", func(t *testing.T) {
+		changes, err := scm.ParseWebhook(event("X-GitHub-Event", "ping"), []byte(`{}`))
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+}
@@ -19,6 +19,19 @@ type Comment struct {
 	Creator         uuid.UUID   `sql:"type:uuid"` // Belongs To Identity
 	Body            string
 	Markup          string
+	// ReplyCount and LastReplyAt describe the direct replies to the comment.
+	// They are not persisted but loaded on demand (see
+	// Repository.ReplyStats) so that new replies are reflected in the ETag
+	// and the last modification time of the comment.
+	ReplyCount  int        `gorm:"-"`
+	LastReplyAt *time.Time `gorm:"-"`
+}
+
+// ReplyStats holds the number of direct replies to a comment and the
+// creation time of the latest one
+type ReplyStats struct {
+	Count    int
+	LatestAt time.Time
 }
 
 // TableName overrides the table name settings in Gorm to force a specific table name
@@ -30,10 +43,25 @@ func (m Comment) TableName() string {
 // GetETagData returns the field values to use to generate the ETag
 func (m Comment) GetETagData() []interface{} {
 	// using the 'ID' and 'UpdatedAt' (converted to number of seconds since epoch) fields
-	return []interface{}{m.ID, strconv.FormatInt(m.UpdatedAt.Unix(), 10)}
+	data := []interface{}{m.ID, strconv.FormatInt(m.UpdatedAt.Unix(), 10)}
+	if m.DeletedAt != nil {
+		// a deletion doesn't change 'UpdatedAt' but changes a tombstone
+		data = append(data, strconv.FormatInt(m.DeletedAt.Unix(), 10))
+	}
+	if m.ReplyCount > 0 {
+		data = append(data, strconv.Itoa(m.ReplyCount))
+	}
+	return data
 }
 
 // GetLastModified returns the last modification time
 func (m Comment) GetLastModified() time.Time {
-	return m.UpdatedAt.Truncate(time.Second)
+	lastModified := m.UpdatedAt
+	if m.DeletedAt != nil && m.DeletedAt.After(lastModified) {
+		lastModified = *m.DeletedAt
+	}
+	if m.LastReplyAt != nil && m.LastReplyAt.After(lastModified) {
+		lastModified = *m.LastReplyAt
+	}
+	return lastModified.Truncate(time.Second)
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/log"
 	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/fabric8-services/fabric8-wit/watcher"
 	"github.com/goadesign/goa"
 	"github.com/jinzhu/gorm"
 
@@ -23,25 +24,54 @@ type Repository interface {
 	Create(ctx context.Context, comment *Comment, creator uuid.UUID) error
 	Save(ctx context.Context, comment *Comment, modifier uuid.UUID) error
 	Delete(ctx context.Context, commentID uuid.UUID, suppressor uuid.UUID) error
+	DeleteByParent(ctx context.Context, parentID uuid.UUID, suppressor uuid.UUID) error
+	RestoreByParent(ctx context.Context, parentID uuid.UUID, deletedSince time.Time, restorer uuid.UUID) error
 	List(ctx context.Context, parent uuid.UUID, start *int, limit *int) ([]Comment, uint64, error)
+	// ListIncludingDeleted works like List but also returns the deleted
+	// comments of the given parent so that they can be shown as tombstones
+	ListIncludingDeleted(ctx context.Context, parent uuid.UUID, start *int, limit *int) ([]Comment, uint64, error)
+	// ListTopLevel works like List but only returns the comments that are no
+	// replies, optionally including the deleted ones
+	ListTopLevel(ctx context.Context, parent uuid.UUID, includeDeleted bool, start *int, limit *int) ([]Comment, uint64, error)
+	// ListReplies returns the direct replies to the given comment, oldest
+	// first
+	ListReplies(ctx context.Context, parentCommentID uuid.UUID, start *int, limit *int) ([]Comment, uint64, error)
 	Load(ctx context.Context, id uuid.UUID) (*Comment, error)
 	Count(ctx context.Context, parentID uuid.UUID) (int, error)
+	// ReplyStats returns the number of direct replies to each of the given
+	// comments along with the creation time of the latest reply. Comments
+	// without replies are not part of the result.
+	ReplyStats(ctx context.Context, commentIDs ...uuid.UUID) (map[uuid.UUID]ReplyStats, error)
 }
 
 // NewRepository creates a new storage type.
 func NewRepository(db *gorm.DB) Repository {
-	return &GormCommentRepository{db: db, revisionRepository: &GormCommentRevisionRepository{db}}
+	return &GormCommentRepository{db: db, revisionRepository: &GormCommentRevisionRepository{db}, watchers: watcher.NewRepository(db)}
 }
 
 // GormCommentRepository is the implementation of the storage interface for Comments.
 type GormCommentRepository struct {
 	db                 *gorm.DB
 	revisionRepository RevisionRepository
+	watchers           watcher.Repository
 }
 
 // Create creates a new record.
 func (m *GormCommentRepository) Create(ctx context.Context, comment *Comment, creatorID uuid.UUID) error {
 	defer goa.MeasureSince([]string{"goa", "db", "comment", "create"}, time.Now())
+	if comment.ParentCommentID.Valid {
+		// a reply must belong to the same parent as the comment it replies to
+		parentComment, err := m.Load(ctx, comment.ParentCommentID.UUID)
+		if err != nil {
+			if ok, _ := errors.IsNotFoundError(err); ok {
+				return errors.NewBadParameterError("parent comment", comment.ParentCommentID.UUID.String())
+			}
+			return errs.Wrapf(err, "failed to load parent comment %s", comment.ParentCommentID.UUID)
+		}
+		if parentComment.ParentID != comment.ParentID {
+			return errors.NewBadParameterError("parent comment", comment.ParentCommentID.UUID.String()).Expected("a comment with the same parent")
+		}
+	}
 	comment.ID = uuid.NewV4()
 	// make sure no comment is created with an empty 'markup' value
 	if comment.Markup == "" {
@@ -58,6 +88,11 @@ func (m *GormCommentRepository) Create(ctx context.Context, comment *Comment, cr
 	if err := m.revisionRepository.Create(ctx, creatorID, RevisionTypeCreate, *comment); err != nil {
 		return errs.Wrapf(err, "error while creating comment")
 	}
+	// commenters watch the commented work item
+	err := m.watchers.Watch(ctx, watcher.TargetWorkItem, comment.ParentID, creatorID)
+	if ok, _ := errors.IsNotFoundError(err); !ok && err != nil {
+		return errs.Wrapf(err, "failed to add watcher %s to work item %s", creatorID, comment.ParentID)
+	}
 	log.Debug(ctx, map[string]interface{}{
 		"comment_id": comment.ID,
 	}, "Comment created!")
@@ -130,11 +165,107 @@ func (m *GormCommentRepository) Delete(ctx context.Context, commentID uuid.UUID,
 	return nil
 }
 
+// DeleteByParent deletes all comments of the given parent, e.g. when the
+// parent work item is deleted. RestoreByParent brings them back.
+func (m *GormCommentRepository) DeleteByParent(ctx context.Context, parentID uuid.UUID, suppressorID uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "comment", "deleteByParent"}, time.Now())
+	var comments []Comment
+	tx := m.db.Select("id, parent_id, parent_comment_id").Where("parent_id = ?", parentID).Find(&comments)
+	if err := tx.Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"parent_id": parentID,
+			"err":       err,
+		}, "unable to find the comments to delete")
+		return errors.NewInternalError(ctx, err)
+	}
+	// delete one by one to trigger the creation of a new comment revision
+	for _, c := range comments {
+		if err := m.db.Delete(c).Error; err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"comment_id": c.ID,
+				"err":        err,
+			}, "unable to delete the comment")
+			return errors.NewInternalError(ctx, err)
+		}
+		if err := m.revisionRepository.Create(ctx, suppressorID, RevisionTypeDelete, c); err != nil {
+			return errs.Wrapf(err, "error while deleting comment")
+		}
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"parent_id": parentID,
+		"count":     len(comments),
+	}, "Comments deleted!")
+	return nil
+}
+
+// RestoreByParent restores all comments of the given parent that were deleted
+// at or after the given time
+func (m *GormCommentRepository) RestoreByParent(ctx context.Context, parentID uuid.UUID, deletedSince time.Time, restorerID uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "comment", "restore"}, time.Now())
+	var comments []Comment
+	tx := m.db.Unscoped().Where("parent_id = ? AND deleted_at >= ?", parentID, deletedSince).Find(&comments)
+	if err := tx.Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"parent_id": parentID,
+			"err":       err,
+		}, "unable to find the deleted comments")
+		return errors.NewInternalError(ctx, err)
+	}
+	// restore one by one to trigger the creation of a new comment revision
+	for _, c := range comments {
+		c.DeletedAt = nil
+		if err := m.db.Unscoped().Save(&c).Error; err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"comment_id": c.ID,
+				"err":        err,
+			}, "unable to restore the comment")
+			return errors.NewInternalError(ctx, err)
+		}
+		// save a revision of the restored comment
+		if err := m.revisionRepository.Create(ctx, restorerID, RevisionTypeRestore, c); err != nil {
+			return errs.Wrapf(err, "error while restoring comment")
+		}
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"parent_id": parentID,
+		"count":     len(comments),
+	}, "Comments restored!")
+	return nil
+}
+
 // List all comments related to a single item
 func (m *GormCommentRepository) List(ctx context.Context, parentID uuid.UUID, start *int, limit *int) ([]Comment, uint64, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "comment", "query"}, time.Now())
+	return m.list(ctx, m.db.Model(&Comment{}).Where("parent_id = ?", parentID), "created_at desc", start, limit)
+}
 
-	db := m.db.Model(&Comment{}).Where("parent_id = ?", parentID)
+// ListIncludingDeleted lists all comments related to a single item,
+// including the deleted ones
+func (m *GormCommentRepository) ListIncludingDeleted(ctx context.Context, parentID uuid.UUID, start *int, limit *int) ([]Comment, uint64, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "comment", "query_including_deleted"}, time.Now())
+	return m.list(ctx, m.db.Unscoped().Model(&Comment{}).Where("parent_id = ?", parentID), "created_at desc", start, limit)
+}
+
+// ListTopLevel lists the comments related to a single item that are no
+// replies. Replies are listed with ListReplies.
+func (m *GormCommentRepository) ListTopLevel(ctx context.Context, parentID uuid.UUID, includeDeleted bool, start *int, limit *int) ([]Comment, uint64, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "comment", "query_top_level"}, time.Now())
+	db := m.db
+	if includeDeleted {
+		db = db.Unscoped()
+	}
+	return m.list(ctx, db.Model(&Comment{}).Where("parent_id = ? AND parent_comment_id IS NULL", parentID), "created_at desc", start, limit)
+}
+
+// ListReplies lists the direct replies to a single comment
+func (m *GormCommentRepository) ListReplies(ctx context.Context, parentCommentID uuid.UUID, start *int, limit *int) ([]Comment, uint64, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "comment", "query_replies"}, time.Now())
+	return m.list(ctx, m.db.Model(&Comment{}).Where("parent_comment_id = ?", parentCommentID), "created_at asc", start, limit)
+}
+
+// list returns a page of the comments matching the given query together with
+// the total number of matching comments
+func (m *GormCommentRepository) list(ctx context.Context, db *gorm.DB, order string, start *int, limit *int) ([]Comment, uint64, error) {
 	orgDB := db
 	if start != nil {
 		if *start < 0 {
@@ -148,7 +279,7 @@ func (m *GormCommentRepository) List(ctx context.Context, parentID uuid.UUID, st
 		}
 		db = db.Limit(*limit)
 	}
-	db = db.Select("count(*) over () as cnt2 , *").Order("created_at desc")
+	db = db.Select("count(*) over () as cnt2 , *").Order(order)
 
 	rows, err := db.Rows()
 	defer closeable.Close(ctx, rows)
@@ -209,6 +340,34 @@ func (m *GormCommentRepository) Count(ctx context.Context, parentID uuid.UUID) (
 	return count, nil
 }
 
+// ReplyStats counts the direct replies to each of the given comments and
+// finds the latest of them
+func (m *GormCommentRepository) ReplyStats(ctx context.Context, commentIDs ...uuid.UUID) (map[uuid.UUID]ReplyStats, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "comment", "reply_stats"}, time.Now())
+	result := map[uuid.UUID]ReplyStats{}
+	if len(commentIDs) == 0 {
+		return result, nil
+	}
+	rows, err := m.db.Model(&Comment{}).Select("parent_comment_id, count(*), max(created_at)").Where("parent_comment_id IN (?)", commentIDs).Group("parent_comment_id").Rows()
+	defer closeable.Close(ctx, rows)
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"comment_ids": commentIDs,
+			"err":         err,
+		}, "unable to count the replies of the comments")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	for rows.Next() {
+		var id uuid.UUID
+		var stats ReplyStats
+		if err := rows.Scan(&id, &stats.Count, &stats.LatestAt); err != nil {
+			return nil, errors.NewInternalError(ctx, err)
+		}
+		result[id] = stats
+	}
+	return result, nil
+}
+
 // Load a single comment regardless of parent
 func (m *GormCommentRepository) Load(ctx context.Context, id uuid.UUID) (*Comment, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "comment", "get"}, time.Now())
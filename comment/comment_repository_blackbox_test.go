@@ -58,7 +58,7 @@ func (s *TestCommentRepository) TestCreateCommentWithParentComment() {
 	parentComment := newComment(uuid.NewV4(), "Test A", rendering.SystemMarkupMarkdown)
 	s.repo.Create(s.Ctx, parentComment, fxt.Identities[0].ID)
 	// child comments
-	childComment := newComment(uuid.NewV4(), "Test Child A", rendering.SystemMarkupMarkdown)
+	childComment := newComment(parentComment.ParentID, "Test Child A", rendering.SystemMarkupMarkdown)
 	childComment.ParentCommentID = id.NullUUID{
 		UUID:  parentComment.ID,
 		Valid: true,
@@ -81,6 +81,125 @@ func (s *TestCommentRepository) TestCreateCommentWithParentComment() {
 	require.Equal(s.T(), parentComment.ID, resultComment.ParentCommentID.UUID, "Parent comment id was not correctly set")
 }
 
+func (s *TestCommentRepository) TestCreateCommentWithInvalidParentComment() {
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Comments(1))
+	s.T().Run("parent comment with another parent", func(t *testing.T) {
+		// given
+		reply := newComment(uuid.NewV4(), "Test Child A", rendering.SystemMarkupMarkdown)
+		reply.ParentCommentID = id.NullUUID{
+			UUID:  fxt.Comments[0].ID,
+			Valid: true,
+		}
+		// when
+		err := s.repo.Create(s.Ctx, reply, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, err)
+	})
+	s.T().Run("unknown parent comment", func(t *testing.T) {
+		// given
+		reply := newComment(fxt.Comments[0].ParentID, "Test Child A", rendering.SystemMarkupMarkdown)
+		reply.ParentCommentID = id.NullUUID{
+			UUID:  uuid.NewV4(),
+			Valid: true,
+		}
+		// when
+		err := s.repo.Create(s.Ctx, reply, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, err)
+	})
+}
+
+func (s *TestCommentRepository) TestListAndCountReplies() {
+	// given a comment with 2 replies and a reply to a reply
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Comments(2))
+	parent := fxt.Comments[0]
+	newReply := func(parentComment *comment.Comment, body string) *comment.Comment {
+		reply := newComment(parentComment.ParentID, body, rendering.SystemMarkupMarkdown)
+		reply.ParentCommentID = id.NullUUID{
+			UUID:  parentComment.ID,
+			Valid: true,
+		}
+		s.createComment(reply, fxt.Identities[0].ID)
+		return reply
+	}
+	reply1 := newReply(parent, "reply 1")
+	reply2 := newReply(parent, "reply 2")
+	newReply(reply1, "reply 1.1")
+
+	s.T().Run("list replies", func(t *testing.T) {
+		// when
+		replies, count, err := s.repo.ListReplies(s.Ctx, parent.ID, nil, nil)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), count)
+		require.Len(t, replies, 2)
+		// oldest first
+		assert.Equal(t, reply1.ID, replies[0].ID)
+		assert.Equal(t, reply2.ID, replies[1].ID)
+	})
+	s.T().Run("list replies paged", func(t *testing.T) {
+		// when
+		offset := 1
+		limit := 1
+		replies, count, err := s.repo.ListReplies(s.Ctx, parent.ID, &offset, &limit)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), count)
+		require.Len(t, replies, 1)
+		assert.Equal(t, reply2.ID, replies[0].ID)
+	})
+	s.T().Run("reply stats", func(t *testing.T) {
+		// when
+		stats, err := s.repo.ReplyStats(s.Ctx, parent.ID, reply1.ID, reply2.ID, fxt.Comments[1].ID)
+		// then
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+		assert.Equal(t, 2, stats[parent.ID].Count)
+		assert.WithinDuration(t, reply2.CreatedAt, stats[parent.ID].LatestAt, time.Millisecond)
+		assert.Equal(t, 1, stats[reply1.ID].Count)
+	})
+	commentIDs := func(comments []comment.Comment) []uuid.UUID {
+		ids := []uuid.UUID{}
+		for _, c := range comments {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+	s.T().Run("replies are listed and counted with the other comments", func(t *testing.T) {
+		// when
+		comments, count, err := s.repo.List(s.Ctx, parent.ParentID, nil, nil)
+		// then
+		require.NoError(t, err)
+		ids := commentIDs(comments)
+		assert.Contains(t, ids, parent.ID)
+		assert.Contains(t, ids, reply1.ID)
+		assert.Contains(t, ids, reply2.ID)
+		total, err := s.repo.Count(s.Ctx, parent.ParentID)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(total), count)
+	})
+	s.T().Run("replies are not listed with the top-level comments", func(t *testing.T) {
+		// when
+		comments, count, err := s.repo.ListTopLevel(s.Ctx, parent.ParentID, false, nil, nil)
+		// then
+		require.NoError(t, err)
+		ids := commentIDs(comments)
+		assert.Contains(t, ids, parent.ID)
+		assert.NotContains(t, ids, reply1.ID)
+		assert.NotContains(t, ids, reply2.ID)
+		assert.Equal(t, uint64(len(comments)), count)
+	})
+	s.T().Run("reply stats without comments", func(t *testing.T) {
+		// when
+		counts, err := s.repo.ReplyStats(s.Ctx)
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, counts)
+	})
+}
+
 func (s *TestCommentRepository) TestCreateCommentWithMarkup() {
 	// given
 	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Identities(1))
@@ -177,6 +296,52 @@ func (s *TestCommentRepository) TestDeleteComment() {
 	})
 }
 
+func (s *TestCommentRepository) TestDeleteAndRestoreByParent() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given a work item with three comments of which one was deleted before
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(2), tf.Comments(4, func(fxt *tf.TestFixture, idx int) error {
+			fxt.Comments[idx].ParentID = fxt.WorkItems[0].ID
+			if idx == 3 {
+				fxt.Comments[idx].ParentID = fxt.WorkItems[1].ID
+			}
+			return nil
+		}))
+		require.NoError(t, s.repo.Delete(s.Ctx, fxt.Comments[0].ID, fxt.Identities[0].ID))
+		deletedAt := time.Now()
+		// when
+		err := s.repo.DeleteByParent(s.Ctx, fxt.WorkItems[0].ID, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		count, err := s.repo.Count(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+		// comments of other parents are untouched
+		count, err = s.repo.Count(s.Ctx, fxt.WorkItems[1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		// when
+		err = s.repo.RestoreByParent(s.Ctx, fxt.WorkItems[0].ID, deletedAt, fxt.Identities[0].ID)
+		// then only the comments deleted with the parent are back
+		require.NoError(t, err)
+		comments, _, err := s.repo.List(s.Ctx, fxt.WorkItems[0].ID, nil, nil)
+		require.NoError(t, err)
+		ids := []uuid.UUID{}
+		for _, c := range comments {
+			ids = append(ids, c.ID)
+		}
+		assert.ElementsMatch(t, []uuid.UUID{fxt.Comments[1].ID, fxt.Comments[2].ID}, ids)
+	})
+
+	s.T().Run("ok - nothing to restore", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Comments(1))
+		err := s.repo.RestoreByParent(s.Ctx, fxt.Comments[0].ParentID, time.Now(), fxt.Identities[0].ID)
+		require.NoError(t, err)
+		count, err := s.repo.Count(s.Ctx, fxt.Comments[0].ParentID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
+
 func (s *TestCommentRepository) TestCountComments() {
 	// given
 	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItems(2), tf.Comments(2, func(fxt *tf.TestFixture, idx int) error {
@@ -208,6 +373,30 @@ func (s *TestCommentRepository) TestListComments() {
 	assert.Equal(s.T(), fxt.Comments[0].Body, resultComments[0].Body)
 }
 
+func (s *TestCommentRepository) TestListCommentsIncludingDeleted() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Comments(2))
+	err := s.repo.Delete(s.Ctx, fxt.Comments[0].ID, fxt.Identities[0].ID)
+	require.NoError(s.T(), err)
+	// when
+	resultComments, count, err := s.repo.ListIncludingDeleted(s.Ctx, fxt.Comments[0].ParentID, nil, nil)
+	// then
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), uint64(2), count)
+	require.Len(s.T(), resultComments, 2)
+	deleted := map[uuid.UUID]bool{}
+	for _, c := range resultComments {
+		deleted[c.ID] = c.DeletedAt != nil
+	}
+	assert.Equal(s.T(), map[uuid.UUID]bool{fxt.Comments[0].ID: true, fxt.Comments[1].ID: false}, deleted)
+	// the regular list doesn't contain the deleted comment
+	resultComments, count, err = s.repo.List(s.Ctx, fxt.Comments[0].ParentID, nil, nil)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), uint64(1), count)
+	require.Len(s.T(), resultComments, 1)
+	assert.Equal(s.T(), fxt.Comments[1].ID, resultComments[0].ID)
+}
+
 func (s *TestCommentRepository) TestListCommentsWrongOffset() {
 	// given
 	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Comments(2))
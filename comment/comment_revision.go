@@ -1,9 +1,13 @@
 package comment
 
 import (
-	"github.com/fabric8-services/fabric8-wit/id"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/fabric8-services/fabric8-wit/id"
+	"github.com/pmezard/go-difflib/difflib"
+
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -19,8 +23,25 @@ const (
 	_                  // ignore 3rd value
 	// RevisionTypeUpdate a comment update
 	RevisionTypeUpdate // 4
+	// RevisionTypeRestore a comment restoration after a deletion
+	RevisionTypeRestore // 5
 )
 
+// String implements the Stringer interface
+func (t RevisionType) String() string {
+	switch t {
+	case RevisionTypeCreate:
+		return "create"
+	case RevisionTypeDelete:
+		return "delete"
+	case RevisionTypeUpdate:
+		return "update"
+	case RevisionTypeRestore:
+		return "restore"
+	}
+	return strconv.Itoa(int(t))
+}
+
 // Revision represents a version of a comment
 type Revision struct {
 	ID uuid.UUID `gorm:"primary_key"`
@@ -50,3 +71,34 @@ const (
 func (w Revision) TableName() string {
 	return revisionTableName
 }
+
+// BodyDiff returns the unified diff between the bodies of the given
+// revisions, in which nil bodies (i.e., no previous revision or a deleted
+// comment) are treated as empty. The result is empty if the bodies are equal.
+func BodyDiff(previous, current *Revision) (string, error) {
+	body := func(r *Revision) string {
+		if r == nil || r.CommentBody == nil {
+			return ""
+		}
+		return *r.CommentBody
+	}
+	a, b := body(previous), body(current)
+	if a == b {
+		return "", nil
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(a),
+		B:        splitLines(b),
+		FromFile: "previous",
+		ToFile:   "current",
+		Context:  3,
+	})
+}
+
+// splitLines splits the given text into lines that all end with a newline
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return difflib.SplitLines(strings.TrimSuffix(s, "\n"))
+}
@@ -0,0 +1,46 @@
+package comment_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/comment"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevisionTypeString(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, "create", comment.RevisionTypeCreate.String())
+	assert.Equal(t, "delete", comment.RevisionTypeDelete.String())
+	assert.Equal(t, "update", comment.RevisionTypeUpdate.String())
+	assert.Equal(t, "restore", comment.RevisionTypeRestore.String())
+	assert.Equal(t, "42", comment.RevisionType(42).String())
+}
+
+func TestBodyDiff(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Run("creation", func(t *testing.T) {
+		diff, err := comment.BodyDiff(nil, &comment.Revision{CommentBody: ptr.String("hello")})
+		require.NoError(t, err)
+		assert.Equal(t, "--- previous\n+++ current\n@@ -0,0 +1 @@\n+hello\n", diff)
+	})
+	t.Run("update", func(t *testing.T) {
+		diff, err := comment.BodyDiff(
+			&comment.Revision{CommentBody: ptr.String("first line\nsecond line\n")},
+			&comment.Revision{CommentBody: ptr.String("first line\nupdated line")})
+		require.NoError(t, err)
+		assert.Equal(t, "--- previous\n+++ current\n@@ -1,2 +1,2 @@\n first line\n-second line\n+updated line\n", diff)
+	})
+	t.Run("deletion", func(t *testing.T) {
+		diff, err := comment.BodyDiff(&comment.Revision{CommentBody: ptr.String("hello")}, &comment.Revision{})
+		require.NoError(t, err)
+		assert.Equal(t, "--- previous\n+++ current\n@@ -1 +0,0 @@\n-hello\n", diff)
+	})
+	t.Run("unchanged", func(t *testing.T) {
+		diff, err := comment.BodyDiff(&comment.Revision{CommentBody: ptr.String("hello")}, &comment.Revision{CommentBody: ptr.String("hello")})
+		require.NoError(t, err)
+		assert.Empty(t, diff)
+	})
+}
@@ -0,0 +1,45 @@
+package comment
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// Reaction is an emoji-style reaction of an identity on a comment
+type Reaction struct {
+	ID         uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
+	CreatedAt  time.Time
+	CommentID  uuid.UUID `sql:"type:uuid"`
+	IdentityID uuid.UUID `sql:"type:uuid"`
+	Emoji      string
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (r Reaction) TableName() string {
+	return "comment_reactions"
+}
+
+// ReactionSummary aggregates the reactions with the same emoji on a comment
+type ReactionSummary struct {
+	Emoji       string
+	IdentityIDs []uuid.UUID
+}
+
+// Summarize groups the given reactions by emoji, keeping the order in which
+// each emoji was first used
+func Summarize(reactions []Reaction) []ReactionSummary {
+	result := []ReactionSummary{}
+	index := map[string]int{}
+	for _, r := range reactions {
+		i, ok := index[r.Emoji]
+		if !ok {
+			i = len(result)
+			index[r.Emoji] = i
+			result = append(result, ReactionSummary{Emoji: r.Emoji})
+		}
+		result[i].IdentityIDs = append(result[i].IdentityIDs, r.IdentityID)
+	}
+	return result
+}
@@ -0,0 +1,137 @@
+package comment
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// maxEmojiLength is the maximum number of characters of a reaction emoji (to
+// allow for short names such as ":thumbsup:" as well as unicode sequences)
+const maxEmojiLength = 32
+
+// ReactionRepository describes interactions with comment reactions
+type ReactionRepository interface {
+	// Add adds the reaction of the given identity on the given comment. Adding
+	// the same reaction twice is a no-op that returns the existing reaction.
+	Add(ctx context.Context, commentID uuid.UUID, identityID uuid.UUID, emoji string) (*Reaction, error)
+	// Remove removes the reaction of the given identity on the given comment
+	Remove(ctx context.Context, commentID uuid.UUID, identityID uuid.UUID, emoji string) error
+	// List returns the reactions on the given comments, grouped by comment ID
+	// and sorted by creation time
+	List(ctx context.Context, commentIDs ...uuid.UUID) (map[uuid.UUID][]Reaction, error)
+}
+
+// NewReactionRepository creates a new storage type.
+func NewReactionRepository(db *gorm.DB) ReactionRepository {
+	return &GormReactionRepository{db: db}
+}
+
+// GormReactionRepository is the implementation of the storage interface for
+// comment reactions.
+type GormReactionRepository struct {
+	db *gorm.DB
+}
+
+func validateEmoji(emoji string) error {
+	if strings.TrimSpace(emoji) == "" || utf8.RuneCountInString(emoji) > maxEmojiLength || strings.ContainsAny(emoji, " \t\n\r") {
+		return errors.NewBadParameterError("emoji", emoji).Expected("a non-empty emoji without whitespaces")
+	}
+	return nil
+}
+
+// Add implements ReactionRepository
+func (r *GormReactionRepository) Add(ctx context.Context, commentID uuid.UUID, identityID uuid.UUID, emoji string) (*Reaction, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "comment_reaction", "create"}, time.Now())
+	if err := validateEmoji(emoji); err != nil {
+		return nil, err
+	}
+	existing := Reaction{}
+	tx := r.db.Where("comment_id = ? AND identity_id = ? AND emoji = ?", commentID, identityID, emoji).First(&existing)
+	if tx.Error == nil {
+		return &existing, nil
+	}
+	if !tx.RecordNotFound() {
+		return nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	reaction := Reaction{
+		ID:         uuid.NewV4(),
+		CommentID:  commentID,
+		IdentityID: identityID,
+		Emoji:      emoji,
+	}
+	if err := r.db.Create(&reaction).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"comment_id":  commentID,
+			"identity_id": identityID,
+			"err":         err,
+		}, "unable to create the comment reaction")
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to create comment reaction"))
+	}
+	if err := r.touchComment(ctx, commentID); err != nil {
+		return nil, err
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"comment_id": commentID,
+		"emoji":      emoji,
+	}, "Comment reaction created!")
+	return &reaction, nil
+}
+
+// touchComment updates the modification time of the given comment so that
+// the ETag and Last-Modified of the comment reflect the reaction changes.
+// No comment revision is created since the comment itself is unchanged.
+func (r *GormReactionRepository) touchComment(ctx context.Context, commentID uuid.UUID) error {
+	if err := r.db.Model(&Comment{}).Where("id = ?", commentID).UpdateColumn("updated_at", time.Now()).Error; err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to update comment %s", commentID))
+	}
+	return nil
+}
+
+// Remove implements ReactionRepository
+func (r *GormReactionRepository) Remove(ctx context.Context, commentID uuid.UUID, identityID uuid.UUID, emoji string) error {
+	defer goa.MeasureSince([]string{"goa", "db", "comment_reaction", "delete"}, time.Now())
+	tx := r.db.Where("comment_id = ? AND identity_id = ? AND emoji = ?", commentID, identityID, emoji).Delete(&Reaction{})
+	if err := tx.Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"comment_id":  commentID,
+			"identity_id": identityID,
+			"err":         err,
+		}, "unable to delete the comment reaction")
+		return errors.NewInternalError(ctx, errs.Wrap(err, "failed to delete comment reaction"))
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("comment reaction", emoji)
+	}
+	return r.touchComment(ctx, commentID)
+}
+
+// List implements ReactionRepository
+func (r *GormReactionRepository) List(ctx context.Context, commentIDs ...uuid.UUID) (map[uuid.UUID][]Reaction, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "comment_reaction", "list"}, time.Now())
+	result := map[uuid.UUID][]Reaction{}
+	if len(commentIDs) == 0 {
+		return result, nil
+	}
+	var reactions []Reaction
+	if err := r.db.Where("comment_id IN (?)", commentIDs).Order("created_at, id").Find(&reactions).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"comment_ids": commentIDs,
+			"err":         err,
+		}, "unable to list the comment reactions")
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to list comment reactions"))
+	}
+	for _, reaction := range reactions {
+		result[reaction.CommentID] = append(result[reaction.CommentID], reaction)
+	}
+	return result, nil
+}
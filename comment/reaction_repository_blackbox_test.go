@@ -0,0 +1,124 @@
+package comment_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/comment"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type reactionRepositoryBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	repo comment.ReactionRepository
+}
+
+func TestRunReactionRepositoryBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &reactionRepositoryBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *reactionRepositoryBlackBoxTest) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.repo = comment.NewReactionRepository(s.DB)
+}
+
+func (s *reactionRepositoryBlackBoxTest) TestAdd() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Comments(1), tf.Identities(2))
+		// when
+		r1, err := s.repo.Add(s.Ctx, fxt.Comments[0].ID, fxt.Identities[0].ID, ":thumbsup:")
+		require.NoError(t, err)
+		r2, err := s.repo.Add(s.Ctx, fxt.Comments[0].ID, fxt.Identities[1].ID, ":thumbsup:")
+		require.NoError(t, err)
+		// then
+		assert.NotEqual(t, uuid.Nil, r1.ID)
+		assert.NotEqual(t, r1.ID, r2.ID)
+		reactions, err := s.repo.List(s.Ctx, fxt.Comments[0].ID)
+		require.NoError(t, err)
+		require.Len(t, reactions[fxt.Comments[0].ID], 2)
+	})
+	s.T().Run("same reaction twice", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Comments(1))
+		r1, err := s.repo.Add(s.Ctx, fxt.Comments[0].ID, fxt.Identities[0].ID, ":tada:")
+		require.NoError(t, err)
+		// when
+		r2, err := s.repo.Add(s.Ctx, fxt.Comments[0].ID, fxt.Identities[0].ID, ":tada:")
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, r1.ID, r2.ID)
+		reactions, err := s.repo.List(s.Ctx, fxt.Comments[0].ID)
+		require.NoError(t, err)
+		require.Len(t, reactions[fxt.Comments[0].ID], 1)
+	})
+	s.T().Run("invalid emoji", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Comments(1))
+		for _, emoji := range []string{"", " ", "thumbs up", "this-emoji-name-is-way-too-long-to-be-valid"} {
+			// when
+			_, err := s.repo.Add(s.Ctx, fxt.Comments[0].ID, fxt.Identities[0].ID, emoji)
+			// then
+			require.Error(t, err, "emoji %q", emoji)
+			assert.IsType(t, errors.BadParameterError{}, err)
+		}
+	})
+}
+
+func (s *reactionRepositoryBlackBoxTest) TestRemove() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Comments(1))
+		_, err := s.repo.Add(s.Ctx, fxt.Comments[0].ID, fxt.Identities[0].ID, ":heart:")
+		require.NoError(t, err)
+		// when
+		err = s.repo.Remove(s.Ctx, fxt.Comments[0].ID, fxt.Identities[0].ID, ":heart:")
+		// then
+		require.NoError(t, err)
+		reactions, err := s.repo.List(s.Ctx, fxt.Comments[0].ID)
+		require.NoError(t, err)
+		assert.Empty(t, reactions[fxt.Comments[0].ID])
+	})
+	s.T().Run("not found", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Comments(1))
+		// when
+		err := s.repo.Remove(s.Ctx, fxt.Comments[0].ID, fxt.Identities[0].ID, ":heart:")
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.NotFoundError{}, err)
+	})
+}
+
+func (s *reactionRepositoryBlackBoxTest) TestList() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Comments(3), tf.Identities(2))
+	_, err := s.repo.Add(s.Ctx, fxt.Comments[0].ID, fxt.Identities[0].ID, ":thumbsup:")
+	require.NoError(s.T(), err)
+	_, err = s.repo.Add(s.Ctx, fxt.Comments[0].ID, fxt.Identities[1].ID, ":heart:")
+	require.NoError(s.T(), err)
+	_, err = s.repo.Add(s.Ctx, fxt.Comments[0].ID, fxt.Identities[1].ID, ":thumbsup:")
+	require.NoError(s.T(), err)
+	_, err = s.repo.Add(s.Ctx, fxt.Comments[1].ID, fxt.Identities[0].ID, ":tada:")
+	require.NoError(s.T(), err)
+	// when
+	reactions, err := s.repo.List(s.Ctx, fxt.Comments[0].ID, fxt.Comments[1].ID, fxt.Comments[2].ID)
+	// then
+	require.NoError(s.T(), err)
+	require.Len(s.T(), reactions, 2)
+	summary := comment.Summarize(reactions[fxt.Comments[0].ID])
+	require.Len(s.T(), summary, 2)
+	assert.Equal(s.T(), ":thumbsup:", summary[0].Emoji)
+	assert.Equal(s.T(), []uuid.UUID{fxt.Identities[0].ID, fxt.Identities[1].ID}, summary[0].IdentityIDs)
+	assert.Equal(s.T(), ":heart:", summary[1].Emoji)
+	assert.Equal(s.T(), []uuid.UUID{fxt.Identities[1].ID}, summary[1].IdentityIDs)
+	require.Len(s.T(), reactions[fxt.Comments[1].ID], 1)
+	assert.Empty(s.T(), reactions[fxt.Comments[2].ID])
+}
@@ -55,6 +55,7 @@ const (
 	varAuthURL                      = "auth.url"
 	varAuthorizationEnabled         = "authz.enabled"
 	varGithubAuthToken              = "github.auth.token"
+	varGitLabAuthToken              = "gitlab.auth.token"
 	varOpenshiftProxyURL            = "osoproxy.url"
 	varKeycloakSecret               = "keycloak.secret"
 	varKeycloakClientID             = "keycloak.client.id"
@@ -98,6 +99,9 @@ const (
 	varCacheControlLabel            = "cachecontrol.label"
 	varCacheControlQuery            = "cachecontrol.query"
 	varCacheControlComment          = "cachecontrol.comment"
+	varCacheControlAttachment       = "cachecontrol.attachment"
+	varCacheControlWorklog          = "cachecontrol.worklog"
+	varCacheControlRecurrence       = "cachecontrol.recurrence"
 
 	defaultConfigFile            = "config.yaml"
 	varOpenshiftTenantMasterURL  = "openshift.tenant.masterurl"
@@ -112,6 +116,10 @@ const (
 	varCodebaseServiceURL        = "codebase.serviceurl"
 	varAnalyticsGeminiServiceURL = "analytics.gemini.serviceurl"
 	varDeploymentsHTTPTimeout    = "deployments.http.timeout"
+	varAttachmentsStoragePath    = "attachments.storage.path"
+	varAttachmentsMaxSize        = "attachments.maxsize"
+	varWorkItemCloneLinkType     = "workitem.clone.linktype"
+	varRecurrenceReloadInterval  = "recurrence.reload.interval"
 )
 
 // Registry encapsulates the Viper configuration registry which stores the
@@ -211,6 +219,7 @@ func (c *Registry) setConfigDefaults() {
 	c.v.SetDefault(varKeycloakClientID, defaultKeycloakClientID)
 	c.v.SetDefault(varKeycloakSecret, defaultKeycloakSecret)
 	c.v.SetDefault(varGithubAuthToken, defaultActualToken)
+	c.v.SetDefault(varGitLabAuthToken, "")
 	c.v.SetDefault(varKeycloakDomainPrefix, defaultKeycloakDomainPrefix)
 	c.v.SetDefault(varKeycloakTesUserName, defaultKeycloakTesUserName)
 	c.v.SetDefault(varAuthorizationEnabled, true)
@@ -242,6 +251,10 @@ func (c *Registry) setConfigDefaults() {
 	c.v.SetDefault(varCacheControlIteration, "private,max-age=2")
 	c.v.SetDefault(varCacheControlArea, "private,max-age=120")
 	c.v.SetDefault(varCacheControlComment, "private,max-age=120")
+	// the content of an attachment never changes
+	c.v.SetDefault(varCacheControlAttachment, "private,max-age=86400")
+	c.v.SetDefault(varCacheControlWorklog, "private,max-age=120")
+	c.v.SetDefault(varCacheControlRecurrence, "private,max-age=120")
 	// data returned from '/api/user' must not be cached by intermediate proxies,
 	// but can only be kept in the client's local cache.
 	c.v.SetDefault(varCacheControlUser, "private,max-age=120")
@@ -257,6 +270,13 @@ func (c *Registry) setConfigDefaults() {
 	c.v.SetDefault(varCodebaseServiceURL, defaultCodebaseServiceURL)
 	c.v.SetDefault(varDeploymentsHTTPTimeout, defaultDeploymentsHTTPTimeout)
 	c.v.SetDefault(varAnalyticsGeminiServiceURL, defaultAnalyticsGeminiServiceURL)
+	// the content of attachments must survive restarts, so there is no
+	// default directory
+	c.v.SetDefault(varAttachmentsStoragePath, "")
+	c.v.SetDefault(varAttachmentsMaxSize, defaultAttachmentsMaxSize)
+	c.v.SetDefault(varWorkItemCloneLinkType, defaultWorkItemCloneLinkType)
+	// recurrences changed on other replicas are picked up once a minute
+	c.v.SetDefault(varRecurrenceReloadInterval, time.Duration(time.Minute))
 }
 
 // GetPostgresHost returns the postgres host as set via default, config file, or environment variable
@@ -534,6 +554,24 @@ func (c *Registry) GetCacheControlComment() string {
 	return c.v.GetString(varCacheControlComment)
 }
 
+// GetCacheControlAttachment returns the value to set in the "Cache-Control" HTTP response header
+// when returning an attachment.
+func (c *Registry) GetCacheControlAttachment() string {
+	return c.v.GetString(varCacheControlAttachment)
+}
+
+// GetCacheControlWorklog returns the value to set in the "Cache-Control" HTTP response header
+// when returning a worklog.
+func (c *Registry) GetCacheControlWorklog() string {
+	return c.v.GetString(varCacheControlWorklog)
+}
+
+// GetCacheControlRecurrence returns the value to set in the "Cache-Control" HTTP response header
+// when returning a recurrence.
+func (c *Registry) GetCacheControlRecurrence() string {
+	return c.v.GetString(varCacheControlRecurrence)
+}
+
 // GetCacheControlFilters returns the value to set in the "Cache-Control" HTTP response header
 // when returning comments.
 func (c *Registry) GetCacheControlFilters() string {
@@ -628,6 +666,12 @@ func (c *Registry) GetGithubAuthToken() string {
 	return c.v.GetString(varGithubAuthToken)
 }
 
+// GetGitLabAuthToken returns the personal access token to fetch issues from
+// GitLab
+func (c *Registry) GetGitLabAuthToken() string {
+	return c.v.GetString(varGitLabAuthToken)
+}
+
 // GetKeycloakSecret returns the keycloak client secret (as set via config file or environment variable)
 // that is used to make authorized Keycloak API Calls.
 func (c *Registry) GetKeycloakSecret() string {
@@ -890,6 +934,29 @@ func (c *Registry) GetDeploymentsHTTPTimeoutSeconds() time.Duration {
 	return time.Duration(timeout) * time.Second
 }
 
+// GetAttachmentsStoragePath returns the directory in which the content of
+// attachments is stored. It has to be set explicitly to a persistent directory.
+func (c *Registry) GetAttachmentsStoragePath() string {
+	return c.v.GetString(varAttachmentsStoragePath)
+}
+
+// GetAttachmentsMaxSize returns the maximum size of an attachment in bytes
+func (c *Registry) GetAttachmentsMaxSize() int64 {
+	return c.v.GetInt64(varAttachmentsMaxSize)
+}
+
+// GetWorkItemCloneLinkTypeID returns the ID of the link type used to link a
+// cloned work item to its original. No link is created if it is empty.
+func (c *Registry) GetWorkItemCloneLinkTypeID() string {
+	return c.v.GetString(varWorkItemCloneLinkType)
+}
+
+// GetRecurrenceReloadInterval returns how often the recurrences are reloaded
+// from the database to pick up changes made through other instances
+func (c *Registry) GetRecurrenceReloadInterval() time.Duration {
+	return c.v.GetDuration(varRecurrenceReloadInterval)
+}
+
 const (
 	defaultHeaderMaxLength = 5000 // bytes
 
@@ -921,6 +988,9 @@ const (
 	defaultCheStarterURL            = "che-server"
 	minimumDeploymentsHTTPTimeout   = 1
 	defaultDeploymentsHTTPTimeout   = 30
+	defaultAttachmentsMaxSize       = 10 * 1024 * 1024 // bytes
+	// the "Related" link type of the base space template
+	defaultWorkItemCloneLinkType = "9B631885-83B1-4ABB-A340-3A9EDE8493FA"
 
 	// as of now deployments and codebase service is integrated in wit, but
 	// going forward this will change
@@ -215,3 +215,20 @@ func TestGetDeploymentsTimeoutDefault(t *testing.T) {
 	expectedTimeSeconds := time.Duration(30) * time.Second
 	assert.Equal(t, expectedTimeSeconds, viperValue)
 }
+
+func TestGetAttachmentsStoragePathHasNoDefault(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	envName := "F8_ATTACHMENTS_STORAGE_PATH"
+	env, isSet := os.LookupEnv(envName)
+	defer func() {
+		if isSet {
+			os.Setenv(envName, env)
+		}
+		resetConfiguration(defaultValuesConfigFilePath)
+	}()
+
+	os.Unsetenv(envName)
+	resetConfiguration(defaultValuesConfigFilePath)
+
+	assert.Equal(t, "", config.GetAttachmentsStoragePath())
+}
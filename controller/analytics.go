@@ -0,0 +1,363 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/fabric8-services/fabric8-wit/analytics"
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/area"
+	"github.com/fabric8-services/fabric8-wit/criteria"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/search"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AnalyticsController implements the analytics resource.
+type AnalyticsController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewAnalyticsController creates an analytics controller.
+func NewAnalyticsController(service *goa.Service, db application.DB) *AnalyticsController {
+	return &AnalyticsController{
+		Controller: service.NewController("AnalyticsController"),
+		db:         db,
+	}
+}
+
+// Burndown runs the burndown action.
+func (c *AnalyticsController) Burndown(ctx *app.BurndownAnalyticsContext) error {
+	var effortField string
+	if ctx.Effort != nil {
+		effortField = *ctx.Effort
+	}
+	var burndown *analytics.Burndown
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		itr, err := appl.Iterations().Load(ctx, ctx.IterationID)
+		if err != nil {
+			return err
+		}
+		burndown, err = appl.Analytics().Burndown(ctx, *itr, effortField)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.BurndownSingle{
+		Data: ConvertBurndown(ctx.Request, *burndown),
+	})
+}
+
+// Velocity runs the velocity action.
+func (c *AnalyticsController) Velocity(ctx *app.VelocityAnalyticsContext) error {
+	var effortField string
+	if ctx.Effort != nil {
+		effortField = *ctx.Effort
+	}
+	var report *analytics.VelocityReport
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		err := appl.Spaces().CheckExists(ctx, ctx.SpaceID)
+		if err != nil {
+			return err
+		}
+		report, err = appl.Analytics().Velocity(ctx, ctx.SpaceID, ctx.Iterations, effortField)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.VelocityList{
+		Data: make([]*app.Velocity, len(report.Iterations)),
+		Meta: &app.VelocityListMeta{
+			AverageCompleted:       report.AverageCompleted,
+			AverageCompletedEffort: report.AverageCompletedEffort,
+		},
+	}
+	if effortField != "" {
+		res.Meta.EffortField = &effortField
+	}
+	for i, v := range report.Iterations {
+		res.Data[i] = ConvertVelocity(ctx.Request, v)
+	}
+	return ctx.OK(res)
+}
+
+// parseAnalyticsFilter returns the expression of the given filter, if any
+func parseAnalyticsFilter(ctx context.Context, filter *string) (criteria.Expression, error) {
+	if filter == nil {
+		return nil, nil
+	}
+	exp, _, err := search.ParseFilterString(ctx, *filter)
+	if err != nil {
+		return nil, err
+	}
+	if exp == nil {
+		return nil, errors.NewBadParameterError("filter[expression]", *filter)
+	}
+	return exp, nil
+}
+
+// CycleTime runs the cycle-time action.
+func (c *AnalyticsController) CycleTime(ctx *app.CycleTimeAnalyticsContext) error {
+	filter, err := parseAnalyticsFilter(ctx, ctx.FilterExpression)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	var report *analytics.CycleTimeReport
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		err := appl.Spaces().CheckExists(ctx, ctx.SpaceID)
+		if err != nil {
+			return err
+		}
+		report, err = appl.Analytics().CycleTime(ctx, ctx.SpaceID, filter)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.CycleTimeSingle{
+		Data: ConvertCycleTime(ctx.Request, ctx.SpaceID, *report),
+	})
+}
+
+// CumulativeFlow runs the cumulative-flow action.
+func (c *AnalyticsController) CumulativeFlow(ctx *app.CumulativeFlowAnalyticsContext) error {
+	filter, err := parseAnalyticsFilter(ctx, ctx.FilterExpression)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	var flow *analytics.CumulativeFlow
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		err := appl.Spaces().CheckExists(ctx, ctx.SpaceID)
+		if err != nil {
+			return err
+		}
+		var itr *iteration.Iteration
+		if ctx.Iteration != nil {
+			itr, err = appl.Iterations().Load(ctx, *ctx.Iteration)
+			if err != nil {
+				return err
+			}
+			if itr.SpaceID != ctx.SpaceID {
+				return errors.NewNotFoundError("iteration", ctx.Iteration.String())
+			}
+		}
+		flow, err = appl.Analytics().CumulativeFlow(ctx, ctx.SpaceID, itr, filter, ctx.From, ctx.To)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	id := ctx.SpaceID
+	var relationships *app.AnalyticsRelations
+	if ctx.Iteration != nil {
+		id = *ctx.Iteration
+		relationships = convertAnalyticsIteration(ctx.Request, id)
+	}
+	return ctx.OK(&app.CumulativeFlowSingle{
+		Data: ConvertCumulativeFlow(id, relationships, *flow),
+	})
+}
+
+// convertAnalyticsIteration returns the relationship to the given iteration
+func convertAnalyticsIteration(request *http.Request, iterationID uuid.UUID) *app.AnalyticsRelations {
+	relatedURL := rest.AbsoluteURL(request, app.IterationHref(iterationID))
+	return &app.AnalyticsRelations{
+		Iteration: &app.RelationGeneric{
+			Data: &app.GenericData{
+				Type: ptr.String(iteration.APIStringTypeIteration),
+				ID:   ptr.String(iterationID.String()),
+			},
+			Links: &app.GenericLinks{
+				Self:    &relatedURL,
+				Related: &relatedURL,
+			},
+		},
+	}
+}
+
+// ConvertBurndown converts the burndown of an iteration from internal to
+// external REST representation
+func ConvertBurndown(request *http.Request, burndown analytics.Burndown) *app.Burndown {
+	selfURL := rest.AbsoluteURL(request, fmt.Sprintf("/api/analytics/iterations/%s/burndown", burndown.IterationID))
+	attrs := &app.BurndownAttributes{
+		Days:         make([]*app.BurndownDay, len(burndown.Days)),
+		ScopeChanges: make([]*app.ScopeChange, len(burndown.ScopeChanges)),
+	}
+	if burndown.EffortField != "" {
+		attrs.EffortField = &burndown.EffortField
+	}
+	for i, d := range burndown.Days {
+		attrs.Days[i] = &app.BurndownDay{
+			Date:            d.Date,
+			Total:           d.Total,
+			Closed:          d.Closed,
+			Open:            d.Open,
+			Effort:          d.Effort,
+			RemainingEffort: d.RemainingEffort,
+		}
+	}
+	for i, change := range burndown.ScopeChanges {
+		data, links := ConvertWorkItemSimple(request, change.WorkItemID)
+		attrs.ScopeChanges[i] = &app.ScopeChange{
+			Time:   change.Time,
+			Change: "removed",
+			WorkItem: &app.RelationGeneric{
+				Data:  data,
+				Links: links,
+			},
+		}
+		if change.Added {
+			attrs.ScopeChanges[i].Change = "added"
+		}
+	}
+	return &app.Burndown{
+		Type:          "burndowns",
+		ID:            burndown.IterationID,
+		Attributes:    attrs,
+		Relationships: convertAnalyticsIteration(request, burndown.IterationID),
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+}
+
+// ConvertVelocity converts the velocity of an iteration from internal to
+// external REST representation
+func ConvertVelocity(request *http.Request, v analytics.Velocity) *app.Velocity {
+	return &app.Velocity{
+		Type: "velocities",
+		ID:   v.IterationID,
+		Attributes: &app.VelocityAttributes{
+			Name:            v.Name,
+			StartAt:         v.StartAt,
+			EndAt:           v.EndAt,
+			Committed:       v.Committed,
+			CommittedEffort: v.CommittedEffort,
+			Completed:       v.Completed,
+			CompletedEffort: v.CompletedEffort,
+		},
+		Relationships: convertAnalyticsIteration(request, v.IterationID),
+	}
+}
+
+// convertDurationStats converts a distribution of durations to seconds
+func convertDurationStats(stats analytics.Stats) *app.DurationStats {
+	return &app.DurationStats{
+		Count: stats.Count,
+		Mean:  stats.Mean.Seconds(),
+		P50:   stats.P50.Seconds(),
+		P85:   stats.P85.Seconds(),
+		P95:   stats.P95.Seconds(),
+	}
+}
+
+// convertFlowStats converts the flow statistics of a group of work items,
+// the time in state ordered by state
+func convertFlowStats(stats analytics.GroupStats) *app.FlowStats {
+	res := &app.FlowStats{
+		LeadTime:    convertDurationStats(stats.LeadTime),
+		CycleTime:   convertDurationStats(stats.CycleTime),
+		TimeInState: []*app.StateDurationStats{},
+	}
+	for state, s := range stats.TimeInState {
+		res.TimeInState = append(res.TimeInState, &app.StateDurationStats{
+			State: state,
+			Stats: convertDurationStats(s),
+		})
+	}
+	sort.Slice(res.TimeInState, func(i, j int) bool {
+		return res.TimeInState[i].State < res.TimeInState[j].State
+	})
+	return res
+}
+
+// sortedGroupIDs returns the IDs of the given groups in a stable order
+func sortedGroupIDs(groups map[uuid.UUID]analytics.GroupStats) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(groups))
+	for id := range groups {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+	return ids
+}
+
+// ConvertCycleTime converts the cycle time report of a space from internal to
+// external REST representation
+func ConvertCycleTime(request *http.Request, spaceID uuid.UUID, report analytics.CycleTimeReport) *app.CycleTime {
+	attrs := &app.CycleTimeAttributes{
+		Overall: convertFlowStats(report.Overall),
+		ByType:  []*app.FlowStats{},
+		ByArea:  []*app.FlowStats{},
+	}
+	for _, id := range sortedGroupIDs(report.ByType) {
+		stats := convertFlowStats(report.ByType[id])
+		stats.WorkItemType = &app.RelationGeneric{
+			Data: &app.GenericData{
+				Type: ptr.String(APIStringTypeWorkItemType),
+				ID:   ptr.String(id.String()),
+			},
+			Links: &app.GenericLinks{
+				Self: ptr.String(rest.AbsoluteURL(request, app.WorkitemtypeHref(id))),
+			},
+		}
+		attrs.ByType = append(attrs.ByType, stats)
+	}
+	for _, id := range sortedGroupIDs(report.ByArea) {
+		// work items without area are only part of the overall statistics
+		if id == uuid.Nil {
+			continue
+		}
+		stats := convertFlowStats(report.ByArea[id])
+		relatedURL := rest.AbsoluteURL(request, app.AreaHref(id))
+		stats.Area = &app.RelationGeneric{
+			Data: &app.GenericData{
+				Type: ptr.String(area.APIStringTypeAreas),
+				ID:   ptr.String(id.String()),
+			},
+			Links: &app.GenericLinks{
+				Self:    &relatedURL,
+				Related: &relatedURL,
+			},
+		}
+		attrs.ByArea = append(attrs.ByArea, stats)
+	}
+	return &app.CycleTime{
+		Type:       "cycle-times",
+		ID:         spaceID,
+		Attributes: attrs,
+	}
+}
+
+// ConvertCumulativeFlow converts the cumulative flow of a space or iteration
+// from internal to external REST representation
+func ConvertCumulativeFlow(id uuid.UUID, relationships *app.AnalyticsRelations, flow analytics.CumulativeFlow) *app.CumulativeFlow {
+	attrs := &app.CumulativeFlowAttributes{
+		States: flow.States,
+		Days:   make([]*app.FlowDay, len(flow.Days)),
+	}
+	for i, d := range flow.Days {
+		attrs.Days[i] = &app.FlowDay{
+			Date:   d.Date,
+			States: d.States,
+		}
+	}
+	return &app.CumulativeFlow{
+		Type:          "cumulative-flows",
+		ID:            id,
+		Attributes:    attrs,
+		Relationships: relationships,
+	}
+}
@@ -0,0 +1,221 @@
+package controller_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestAnalyticsREST struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunAnalyticsREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestAnalyticsREST{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *TestAnalyticsREST) unsecuredController() (*goa.Service, *AnalyticsController) {
+	svc := goa.New("Analytics-Service")
+	return svc, NewAnalyticsController(svc, s.GormDB)
+}
+
+// closeWorkItem closes the given work item
+func (s *TestAnalyticsREST) closeWorkItem(t *testing.T, fxt *tf.TestFixture, wi workitem.WorkItem) {
+	wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+	_, _, err := workitem.NewWorkItemRepository(s.DB).Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+	require.NoError(t, err)
+}
+
+func (s *TestAnalyticsREST) TestBurndown() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given an iteration with an open and a closed work item
+		now := time.Now().UTC()
+		start := now.Add(-48 * time.Hour)
+		end := now.Add(48 * time.Hour)
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.CreateWorkItemEnvironment(),
+			tf.Iterations(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.Iterations[idx].StartAt = &start
+				fxt.Iterations[idx].EndAt = &end
+				return nil
+			}),
+			tf.WorkItems(2, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[0].ID.String()
+				return nil
+			}),
+		)
+		s.closeWorkItem(t, fxt, *fxt.WorkItems[0])
+		svc, ctrl := s.unsecuredController()
+		// when
+		_, res := test.BurndownAnalyticsOK(t, svc.Context, svc, ctrl, fxt.Iterations[0].ID, nil)
+		// then
+		require.NotNil(t, res.Data)
+		assert.Equal(t, fxt.Iterations[0].ID, res.Data.ID)
+		assert.Equal(t, fxt.Iterations[0].ID.String(), *res.Data.Relationships.Iteration.Data.ID)
+		assert.Nil(t, res.Data.Attributes.EffortField)
+		require.Len(t, res.Data.Attributes.Days, 3)
+		today := res.Data.Attributes.Days[2]
+		assert.Equal(t, 2, today.Total)
+		assert.Equal(t, 1, today.Closed)
+		assert.Equal(t, 1, today.Open)
+		require.Len(t, res.Data.Attributes.ScopeChanges, 2)
+		for _, change := range res.Data.Attributes.ScopeChanges {
+			assert.Equal(t, "added", change.Change)
+		}
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		svc, ctrl := s.unsecuredController()
+		test.BurndownAnalyticsNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), nil)
+	})
+}
+
+func (s *TestAnalyticsREST) TestVelocity() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given an ended iteration in which one of two work items was closed
+		now := time.Now().UTC()
+		start := now.Add(-10 * 24 * time.Hour)
+		end := now.Add(-24 * time.Hour)
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.CreateWorkItemEnvironment(),
+			tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemTypes[idx].Fields["storypoints"] = workitem.FieldDefinition{
+					Label: "Story points",
+					Type:  workitem.SimpleType{Kind: workitem.KindFloat},
+				}
+				return nil
+			}),
+			tf.Iterations(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.Iterations[idx].StartAt = &start
+				fxt.Iterations[idx].EndAt = &end
+				return nil
+			}),
+			tf.WorkItems(2, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[0].ID.String()
+				fxt.WorkItems[idx].Fields["storypoints"] = float64(idx + 2)
+				return nil
+			}),
+		)
+		s.closeWorkItem(t, fxt, *fxt.WorkItems[0])
+		// move the history of the work items into the iteration
+		err := s.DB.Exec("UPDATE work_item_revisions SET revision_time = revision_time - interval '5 days' WHERE work_item_id IN (?, ?)",
+			fxt.WorkItems[0].ID, fxt.WorkItems[1].ID).Error
+		require.NoError(t, err)
+		svc, ctrl := s.unsecuredController()
+		// when
+		_, res := test.VelocityAnalyticsOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, ptr.String("storypoints"), ptr.Int(5))
+		// then
+		require.Len(t, res.Data, 1)
+		assert.Equal(t, fxt.Iterations[0].ID, res.Data[0].ID)
+		assert.Equal(t, 1, res.Data[0].Attributes.Completed)
+		assert.Equal(t, 2.0, res.Data[0].Attributes.CompletedEffort)
+		require.NotNil(t, res.Meta)
+		assert.Equal(t, "storypoints", *res.Meta.EffortField)
+		assert.Equal(t, 1.0, res.Meta.AverageCompleted)
+		assert.Equal(t, 2.0, res.Meta.AverageCompletedEffort)
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		svc, ctrl := s.unsecuredController()
+		test.VelocityAnalyticsNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), nil, nil)
+	})
+}
+
+func (s *TestAnalyticsREST) TestCycleTimeAndCumulativeFlow() {
+	// given two work item types of which only one has a closed work item
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.CreateWorkItemEnvironment(),
+		tf.Iterations(1),
+		tf.WorkItemTypes(2),
+		tf.WorkItems(3, func(fxt *tf.TestFixture, idx int) error {
+			if idx == 2 {
+				fxt.WorkItems[idx].Type = fxt.WorkItemTypes[1].ID
+				fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[0].ID.String()
+			}
+			return nil
+		}),
+	)
+	wir := workitem.NewWorkItemRepository(s.DB)
+	for _, state := range []string{workitem.SystemStateOpen, workitem.SystemStateClosed} {
+		wi, err := wir.LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(s.T(), err)
+		wi.Fields[workitem.SystemState] = state
+		_, _, err = wir.Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[0].ID)
+		require.NoError(s.T(), err)
+	}
+	svc, ctrl := s.unsecuredController()
+
+	s.T().Run("cycle time", func(t *testing.T) {
+		_, res := test.CycleTimeAnalyticsOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil)
+		require.NotNil(t, res.Data)
+		assert.Equal(t, fxt.Spaces[0].ID, res.Data.ID)
+		assert.Equal(t, 1, res.Data.Attributes.Overall.LeadTime.Count)
+		assert.Equal(t, 1, res.Data.Attributes.Overall.CycleTime.Count)
+		assert.Len(t, res.Data.Attributes.ByType, 2)
+	})
+
+	s.T().Run("cycle time with filter", func(t *testing.T) {
+		filter := fmt.Sprintf(`{"workitemtype": "%s"}`, fxt.WorkItemTypes[1].ID)
+		_, res := test.CycleTimeAnalyticsOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, &filter)
+		assert.Equal(t, 0, res.Data.Attributes.Overall.LeadTime.Count)
+		require.Len(t, res.Data.Attributes.ByType, 1)
+		assert.Equal(t, fxt.WorkItemTypes[1].ID.String(), *res.Data.Attributes.ByType[0].WorkItemType.Data.ID)
+	})
+
+	s.T().Run("cycle time with invalid filter", func(t *testing.T) {
+		test.CycleTimeAnalyticsBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, ptr.String("not a filter"))
+	})
+
+	s.T().Run("cycle time of unknown space", func(t *testing.T) {
+		test.CycleTimeAnalyticsNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), nil)
+	})
+
+	s.T().Run("cumulative flow of the space", func(t *testing.T) {
+		_, res := test.CumulativeFlowAnalyticsOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, nil, nil, nil)
+		require.NotNil(t, res.Data)
+		assert.Equal(t, fxt.Spaces[0].ID, res.Data.ID)
+		assert.Nil(t, res.Data.Relationships)
+		require.Len(t, res.Data.Attributes.Days, 30)
+		today := res.Data.Attributes.Days[29]
+		assert.Equal(t, 2, today.States[workitem.SystemStateNew])
+		assert.Equal(t, 1, today.States[workitem.SystemStateClosed])
+	})
+
+	s.T().Run("cumulative flow of an iteration", func(t *testing.T) {
+		_, res := test.CumulativeFlowAnalyticsOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, nil, &fxt.Iterations[0].ID, nil)
+		assert.Equal(t, fxt.Iterations[0].ID, res.Data.ID)
+		require.NotNil(t, res.Data.Relationships)
+		assert.Equal(t, fxt.Iterations[0].ID.String(), *res.Data.Relationships.Iteration.Data.ID)
+		require.NotEmpty(t, res.Data.Attributes.Days)
+		today := res.Data.Attributes.Days[len(res.Data.Attributes.Days)-1]
+		assert.Equal(t, 1, today.States[workitem.SystemStateNew])
+		assert.Equal(t, 0, today.States[workitem.SystemStateClosed])
+	})
+
+	s.T().Run("cumulative flow with invalid filter", func(t *testing.T) {
+		test.CumulativeFlowAnalyticsBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, ptr.String("not a filter"), nil, nil, nil)
+	})
+
+	s.T().Run("cumulative flow of an iteration of another space", func(t *testing.T) {
+		other := tf.NewTestFixture(t, s.DB, tf.Iterations(1))
+		test.CumulativeFlowAnalyticsNotFound(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, nil, &other.Iterations[0].ID, nil)
+	})
+
+	s.T().Run("cumulative flow of unknown space", func(t *testing.T) {
+		test.CumulativeFlowAnalyticsNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), nil, nil, nil, nil)
+	})
+}
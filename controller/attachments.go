@@ -0,0 +1,250 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/attachment"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/space/authz"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+)
+
+// Defines the constants to be used in json api
+const (
+	APIStringTypeAttachments = "attachments"
+)
+
+func init() {
+	rendering.AttachmentContentPath = attachmentContentHref
+}
+
+// attachmentContentHref returns the path of the download action of the
+// attachment with the given ID
+func attachmentContentHref(attachmentID string) string {
+	return app.AttachmentsHref(url.PathEscape(attachmentID)) + "/content"
+}
+
+// AttachmentsController implements the attachments resource.
+type AttachmentsController struct {
+	*goa.Controller
+	db     application.DB
+	store  attachment.BlobStore
+	config AttachmentsControllerConfiguration
+}
+
+// AttachmentsControllerConfiguration the configuration for the AttachmentsController
+type AttachmentsControllerConfiguration interface {
+	GetCacheControlAttachment() string
+}
+
+// NewAttachmentsController creates an attachments controller.
+func NewAttachmentsController(service *goa.Service, db application.DB, store attachment.BlobStore, config AttachmentsControllerConfiguration) *AttachmentsController {
+	return &AttachmentsController{
+		Controller: service.NewController("AttachmentsController"),
+		db:         db,
+		store:      store,
+		config:     config,
+	}
+}
+
+// Show runs the show action.
+func (c *AttachmentsController) Show(ctx *app.ShowAttachmentsContext) error {
+	var a *attachment.Attachment
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		a, err = appl.Attachments().Load(ctx, ctx.AttachmentID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.ConditionalRequest(*a, c.config.GetCacheControlAttachment, func() error {
+		return ctx.OK(&app.AttachmentSingle{
+			Data: ConvertAttachment(ctx.Request, *a),
+		})
+	})
+}
+
+// Download runs the download action.
+func (c *AttachmentsController) Download(ctx *app.DownloadAttachmentsContext) error {
+	var a *attachment.Attachment
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		a, err = appl.Attachments().Load(ctx, ctx.AttachmentID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.ConditionalRequest(*a, c.config.GetCacheControlAttachment, func() error {
+		content, err := c.store.Get(ctx, a.StorageKey)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		defer content.Close()
+		ctx.ResponseData.Header().Set("Content-Type", a.MimeType)
+		ctx.ResponseData.Header().Set("Content-Length", strconv.FormatInt(a.Size, 10))
+		ctx.ResponseData.Header().Set("Content-Disposition", contentDisposition(*a))
+		ctx.ResponseData.Header().Set("X-Content-Type-Options", "nosniff")
+		ctx.ResponseData.WriteHeader(http.StatusOK)
+		// stream the content instead of holding whole files in memory; once the
+		// header is written, errors can only be logged
+		if _, err := io.Copy(ctx.ResponseData, content); err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"attachment_id": a.ID,
+				"storage_key":   a.StorageKey,
+				"err":           err,
+			}, "unable to stream the content of the attachment")
+		}
+		return nil
+	})
+}
+
+// contentDisposition returns the value of the Content-Disposition header for
+// the given attachment. Only images are displayed inline; everything else
+// (e.g. HTML files) is offered as a download so that uploaded content can't
+// run scripts in the context of the application.
+func contentDisposition(a attachment.Attachment) string {
+	disposition := "attachment"
+	if strings.HasPrefix(a.MimeType, "image/") && !strings.HasPrefix(a.MimeType, "image/svg") {
+		disposition = "inline"
+	}
+	return mime.FormatMediaType(disposition, map[string]string{"filename": a.Name})
+}
+
+// Delete runs the delete action.
+func (c *AttachmentsController) Delete(ctx *app.DeleteAttachmentsContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	var a *attachment.Attachment
+	var wi *workitem.WorkItem
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		a, err = appl.Attachments().Load(ctx, ctx.AttachmentID)
+		if err != nil {
+			return err
+		}
+		wi, err = appl.WorkItems().LoadByID(ctx, a.WorkItemID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	// User is allowed to delete if user is the uploader of the attachment OR
+	// user is a space collaborator
+	if *identityID != a.Creator {
+		authorized, err := authz.Authorize(ctx, wi.SpaceID.String())
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+		}
+		if !authorized {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not a space collaborator"))
+		}
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		return appl.Attachments().Delete(ctx, a.ID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if err := c.store.Delete(ctx, a.StorageKey); err != nil {
+		// the metadata is gone, so the orphaned content is only logged
+		log.Error(ctx, map[string]interface{}{
+			"attachment_id": a.ID,
+			"storage_key":   a.StorageKey,
+			"err":           err,
+		}, "unable to delete the content of the attachment")
+	}
+	return ctx.OK([]byte{})
+}
+
+// ConvertAttachments converts a list of attachments from internal to external
+// REST representation
+func ConvertAttachments(request *http.Request, attachments []attachment.Attachment) []*app.Attachment {
+	result := make([]*app.Attachment, len(attachments))
+	for i, a := range attachments {
+		result[i] = ConvertAttachment(request, a)
+	}
+	return result
+}
+
+// ConvertAttachment converts an attachment from internal to external REST
+// representation
+func ConvertAttachment(request *http.Request, a attachment.Attachment) *app.Attachment {
+	selfURL := rest.AbsoluteURL(request, app.AttachmentsHref(a.ID))
+	contentURL := rest.AbsoluteURL(request, attachmentContentHref(a.ID.String()))
+	workItemID := a.WorkItemID.String()
+	workItemURL := rest.AbsoluteURL(request, app.WorkitemHref(workItemID))
+	creatorID := a.Creator.String()
+	creatorURL := rest.AbsoluteURL(request, fmt.Sprintf("%s/%s", usersEndpoint, creatorID))
+	result := &app.Attachment{
+		Type: APIStringTypeAttachments,
+		ID:   &a.ID,
+		Attributes: &app.AttachmentAttributes{
+			Name:            &a.Name,
+			Size:            ptr.Int(int(a.Size)),
+			MimeType:        &a.MimeType,
+			Checksum:        &a.Checksum,
+			MarkupReference: ptr.String(rendering.AttachmentScheme + a.ID.String()),
+			CreatedAt:       ptr.Time(a.CreatedAt.UTC()),
+		},
+		Relationships: &app.AttachmentRelationships{
+			WorkItem: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: ptr.String(APIStringTypeWorkItem),
+					ID:   &workItemID,
+				},
+				Links: &app.GenericLinks{
+					Self:    &workItemURL,
+					Related: &workItemURL,
+				},
+			},
+			Creator: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: ptr.String(APIStringTypeUser),
+					ID:   &creatorID,
+				},
+				Links: &app.GenericLinks{
+					Self:    &creatorURL,
+					Related: &creatorURL,
+				},
+			},
+		},
+		Links: &app.GenericLinks{
+			Self:    &selfURL,
+			Related: &contentURL,
+		},
+	}
+	if a.CommentID.Valid {
+		commentID := a.CommentID.UUID.String()
+		commentURL := rest.AbsoluteURL(request, app.CommentsHref(commentID))
+		result.Relationships.Comment = &app.RelationGeneric{
+			Data: &app.GenericData{
+				Type: ptr.String(APIStringTypeComments),
+				ID:   &commentID,
+			},
+			Links: &app.GenericLinks{
+				Self:    &commentURL,
+				Related: &commentURL,
+			},
+		}
+	}
+	return result
+}
@@ -0,0 +1,147 @@
+package controller_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	"github.com/fabric8-services/fabric8-wit/attachment"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/goadesign/goa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestAttachmentsREST struct {
+	gormtestsupport.DBTestSuite
+	storagePath string
+	store       attachment.BlobStore
+}
+
+func TestRunAttachmentsREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestAttachmentsREST{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *TestAttachmentsREST) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	storagePath, err := ioutil.TempDir("", "attachments")
+	require.NoError(s.T(), err)
+	s.storagePath = storagePath
+	s.store = attachment.NewFileSystemBlobStore(storagePath)
+}
+
+func (s *TestAttachmentsREST) TearDownTest() {
+	os.RemoveAll(s.storagePath)
+	s.DBTestSuite.TearDownTest()
+}
+
+// securedControllers returns the controllers for the given user who is a
+// collaborator of the spaces owned by the given owner
+func (s *TestAttachmentsREST) securedControllers(user, owner account.Identity) (*goa.Service, *WorkItemAttachmentsController, *AttachmentsController) {
+	svc := testsupport.ServiceAsSpaceUser("Attachments-Service", user, &TestSpaceAuthzService{owner, ""})
+	return svc, NewWorkItemAttachmentsController(svc, s.GormDB, s.store, s.Configuration), NewAttachmentsController(svc, s.GormDB, s.store, s.Configuration)
+}
+
+// newAttachmentFile returns the header of a file that was uploaded in a
+// multipart form with the given name, MIME type and content
+func newAttachmentFile(t *testing.T, name, mimeType, content string) *multipart.FileHeader {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, name))
+	h.Set("Content-Type", mimeType)
+	part, err := w.CreatePart(h)
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	form, err := multipart.NewReader(body, w.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	require.Len(t, form.File["file"], 1)
+	return form.File["file"][0]
+}
+
+func (s *TestAttachmentsREST) TestCreate() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+		svc, ctrl, _ := s.securedControllers(*fxt.Identities[0], *fxt.Identities[0])
+		payload := app.CreateWorkItemAttachmentsPayload{
+			File: newAttachmentFile(t, "build.log", "text/plain", "build failed"),
+		}
+		// when
+		_, res := test.CreateWorkItemAttachmentsCreated(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID, &payload)
+		// then
+		require.NotNil(t, res.Data)
+		assert.Equal(t, "build.log", *res.Data.Attributes.Name)
+		assert.Equal(t, len("build failed"), *res.Data.Attributes.Size)
+		assert.Equal(t, rendering.AttachmentScheme+res.Data.ID.String(), *res.Data.Attributes.MarkupReference)
+	})
+
+	s.T().Run("forbidden for non-collaborators", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.Identities(2), tf.WorkItems(1))
+		svc, ctrl, _ := s.securedControllers(*fxt.Identities[1], *fxt.Identities[0])
+		payload := app.CreateWorkItemAttachmentsPayload{
+			File: newAttachmentFile(t, "build.log", "text/plain", "build failed"),
+		}
+		// when
+		test.CreateWorkItemAttachmentsForbidden(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID, &payload)
+		// then nothing was stored
+		files, err := ioutil.ReadDir(s.storagePath)
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	})
+}
+
+func (s *TestAttachmentsREST) TestDownload() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+	svc, wiCtrl, ctrl := s.securedControllers(*fxt.Identities[0], *fxt.Identities[0])
+	content := "<script>alert(1)</script>"
+	payload := app.CreateWorkItemAttachmentsPayload{
+		File: newAttachmentFile(s.T(), "page.html", "text/html", content),
+	}
+	_, created := test.CreateWorkItemAttachmentsCreated(s.T(), svc.Context, svc, wiCtrl, fxt.WorkItems[0].ID, &payload)
+	// when
+	rw := test.DownloadAttachmentsOK(s.T(), svc.Context, svc, ctrl, *created.Data.ID, nil, nil)
+	// then the content is streamed and offered as a download
+	res, ok := rw.(*httptest.ResponseRecorder)
+	require.True(s.T(), ok)
+	assert.Equal(s.T(), content, res.Body.String())
+	assert.Equal(s.T(), "text/html", res.Header().Get("Content-Type"))
+	assert.Equal(s.T(), fmt.Sprintf("%d", len(content)), res.Header().Get("Content-Length"))
+	assert.Equal(s.T(), `attachment; filename=page.html`, res.Header().Get("Content-Disposition"))
+	assert.Equal(s.T(), "nosniff", res.Header().Get("X-Content-Type-Options"))
+}
+
+func (s *TestAttachmentsREST) TestRenderReference() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+	svc, wiCtrl, _ := s.securedControllers(*fxt.Identities[0], *fxt.Identities[0])
+	payload := app.CreateWorkItemAttachmentsPayload{
+		File: newAttachmentFile(s.T(), "screenshot.png", "image/png", "png"),
+	}
+	_, created := test.CreateWorkItemAttachmentsCreated(s.T(), svc.Context, svc, wiCtrl, fxt.WorkItems[0].ID, &payload)
+	// when
+	result := rendering.RenderMarkupToHTML("![screenshot]("+*created.Data.Attributes.MarkupReference+")", rendering.SystemMarkupMarkdown)
+	// then the reference points to the download action
+	assert.Contains(s.T(), result, `<img src="`+app.AttachmentsHref(*created.Data.ID)+`/content" alt="screenshot"`)
+	assert.True(s.T(), strings.HasSuffix(*created.Data.Links.Related, app.AttachmentsHref(*created.Data.ID)+"/content"))
+}
@@ -3,8 +3,11 @@ package controller
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/app"
@@ -12,6 +15,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/codebase"
 	gemini "github.com/fabric8-services/fabric8-wit/codebase/analytics-gemini"
 	"github.com/fabric8-services/fabric8-wit/codebase/che"
+	"github.com/fabric8-services/fabric8-wit/codebase/scm"
 	"github.com/fabric8-services/fabric8-wit/configuration"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
 	"github.com/fabric8-services/fabric8-wit/log"
@@ -30,6 +34,9 @@ const (
 	APIStringTypeCodebase = "codebases"
 	// APIStringTypeWorkspace contains the JSON API type for worksapces
 	APIStringTypeWorkspace = "workspaces"
+	// maxConcurrentScans limits the number of repositories that are cloned
+	// at the same time
+	maxConcurrentScans = 4
 )
 
 // CodebaseConfiguration contains the configuraiton required by this Controller
@@ -44,6 +51,10 @@ type CodebaseCheClientProvider func(ctx context.Context, ns string) (che.Client,
 // AnalyticsGeminiClientProvider the function that provides a ScanRepoClient
 type AnalyticsGeminiClientProvider func() *gemini.ScanRepoClient
 
+// CodebaseScanProvider the function that reads the branches and commits of
+// a repository
+type CodebaseScanProvider func(ctx context.Context, repoURL string, limit int) ([]scm.Change, error)
+
 // CodebaseController implements the codebase resource.
 type CodebaseController struct {
 	*goa.Controller
@@ -52,14 +63,23 @@ type CodebaseController struct {
 	ShowTenant            account.CodebaseInitTenantProvider
 	NewCheClient          CodebaseCheClientProvider
 	AnalyticsGeminiClient AnalyticsGeminiClientProvider
+	ScanRepository        CodebaseScanProvider
+	// scanning holds the IDs of the codebases whose repository is being
+	// scanned, scanSlots bounds the number of running scans
+	scanLock  sync.Mutex
+	scanning  map[uuid.UUID]bool
+	scanSlots chan struct{}
 }
 
 // NewCodebaseController creates a codebase controller.
 func NewCodebaseController(service *goa.Service, db application.DB, config codebaseConfiguration) *CodebaseController {
 	return &CodebaseController{
-		Controller: service.NewController("CodebaseController"),
-		db:         db,
-		config:     config,
+		Controller:     service.NewController("CodebaseController"),
+		db:             db,
+		config:         config,
+		ScanRepository: scm.Scan,
+		scanning:       map[uuid.UUID]bool{},
+		scanSlots:      make(chan struct{}, maxConcurrentScans),
 	}
 }
 
@@ -105,6 +125,16 @@ func (c *CodebaseController) Update(ctx *app.UpdateCodebaseContext) error {
 	if reqAttributes.Type != nil {
 		cb.Type = *reqAttributes.Type
 	}
+	if reqAttributes.WebhookSecret != nil {
+		cb.WebhookSecret = *reqAttributes.WebhookSecret
+	}
+	if reqAttributes.MergeState != nil {
+		// an empty merge state disables the transitions on merge
+		cb.MergeState = nil
+		if *reqAttributes.MergeState != "" {
+			cb.MergeState = reqAttributes.MergeState
+		}
+	}
 
 	var updatedCb *codebase.Codebase
 	// now save the object back into the database
@@ -137,6 +167,130 @@ func (c *CodebaseController) Update(ctx *app.UpdateCodebaseContext) error {
 	return ctx.OK(res)
 }
 
+// Webhook runs the webhook action.
+func (c *CodebaseController) Webhook(ctx *app.WebhookCodebaseContext) error {
+	body, err := ioutil.ReadAll(io.LimitReader(ctx.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("body", nil).Expected("readable body"))
+	}
+	var cb *codebase.Codebase
+	var cbSpace *space.Space
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		cb, err = appl.Codebases().Load(ctx, ctx.CodebaseID)
+		if err != nil {
+			return err
+		}
+		cbSpace, err = appl.Spaces().Load(ctx, cb.SpaceID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if err := scm.VerifyWebhook(*cb, ctx.Request.Header, body); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	changes, err := scm.ParseWebhook(ctx.Request.Header, body)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if len(changes) == 0 {
+		return ctx.NoContent()
+	}
+	// the work items closed by a merge are moved on behalf of the space owner
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		_, err := scm.Record(ctx, appl.SCMActivities(), appl.WorkItems(), *cb, cbSpace.OwnerID, changes)
+		return err
+	})
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":         err,
+			"codebase_id": cb.ID,
+		}, "failed to record the changes of the webhook")
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.NoContent()
+}
+
+// Scan runs the scan action.
+func (c *CodebaseController) Scan(ctx *app.ScanCodebaseContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	cb, err := c.verifyCodebaseOwner(ctx, ctx.CodebaseID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if err := scm.ValidateRepositoryURL(cb.URL); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	limit := scm.DefaultScanLimit
+	if ctx.Limit != nil {
+		limit = *ctx.Limit
+	}
+	if !c.beginScan(cb.ID) {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewVersionConflictError(fmt.Sprintf("the repository of codebase %s is already being scanned", cb.ID)))
+	}
+	// cloning takes long, the scan outlives the request
+	go func() {
+		defer c.endScan(cb.ID)
+		c.scanSlots <- struct{}{}
+		defer func() { <-c.scanSlots }()
+		c.scan(context.Background(), *cb, *currentUser, limit)
+	}()
+	return ctx.Accepted()
+}
+
+// beginScan marks the repository of the given codebase as being scanned. It
+// returns false if it is already being scanned.
+func (c *CodebaseController) beginScan(codebaseID uuid.UUID) bool {
+	c.scanLock.Lock()
+	defer c.scanLock.Unlock()
+	if c.scanning[codebaseID] {
+		return false
+	}
+	c.scanning[codebaseID] = true
+	return true
+}
+
+// endScan marks the scan of the repository of the given codebase as done
+func (c *CodebaseController) endScan(codebaseID uuid.UUID) {
+	c.scanLock.Lock()
+	defer c.scanLock.Unlock()
+	delete(c.scanning, codebaseID)
+}
+
+// scan reads the changes of the repository of the given codebase and records
+// the mentions of work items on behalf of the given user
+func (c *CodebaseController) scan(ctx context.Context, cb codebase.Codebase, currentUser uuid.UUID, limit int) {
+	changes, err := c.ScanRepository(ctx, cb.URL, limit)
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":         err,
+			"codebase_id": cb.ID,
+			"url":         cb.URL,
+		}, "failed to scan the repository of the codebase")
+		return
+	}
+	var count int
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		count, err = scm.Record(ctx, appl.SCMActivities(), appl.WorkItems(), cb, currentUser, changes)
+		return err
+	})
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":         err,
+			"codebase_id": cb.ID,
+		}, "failed to record the changes of the repository of the codebase")
+		return
+	}
+	log.Info(ctx, map[string]interface{}{
+		"codebase_id": cb.ID,
+		"changes":     len(changes),
+		"activities":  count,
+	}, "scanned the repository of the codebase")
+}
+
 // Edit Deprecated: ListWorkspaces action should be used instead.
 func (c *CodebaseController) Edit(ctx *app.EditCodebaseContext) error {
 	listWorkspacesContext := app.ListWorkspacesCodebaseContext{ctx.Context, ctx.ResponseData, ctx.RequestData, ctx.CodebaseID}
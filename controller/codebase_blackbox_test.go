@@ -13,6 +13,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/app/test"
 	gemini "github.com/fabric8-services/fabric8-wit/codebase/analytics-gemini"
 	"github.com/fabric8-services/fabric8-wit/codebase/che"
+	"github.com/fabric8-services/fabric8-wit/codebase/scm"
 	"github.com/fabric8-services/fabric8-wit/configuration"
 	. "github.com/fabric8-services/fabric8-wit/controller"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
@@ -70,6 +71,13 @@ func withAnalyticsGeminiClient(f AnalyticsGeminiClientProvider) ConfigureCodebas
 	}
 }
 
+// withScanRepository replaces the scan of the repositories of the codebases
+func withScanRepository(f CodebaseScanProvider) ConfigureCodebaseController {
+	return func(codebaseCtrl *CodebaseController) {
+		codebaseCtrl.ScanRepository = f
+	}
+}
+
 func (s *CodebaseControllerTestSuite) UnsecuredController(settings ...ConfigureCodebaseController) (*goa.Service, *CodebaseController) {
 	svc := goa.New("Codebases-service")
 	codebaseCtrl := NewCodebaseController(svc, s.GormDB, s.Configuration)
@@ -329,6 +337,84 @@ func (s *CodebaseControllerTestSuite) TestDeleteCodebase() {
 
 }
 
+func (s *CodebaseControllerTestSuite) TestScanCodebase() {
+	// scanned records the repositories that were scanned
+	type scanned struct {
+		url   string
+		limit int
+	}
+	fakeScan := func(c chan scanned) CodebaseScanProvider {
+		return func(ctx context.Context, repoURL string, limit int) ([]scm.Change, error) {
+			c <- scanned{repoURL, limit}
+			return nil, nil
+		}
+	}
+
+	s.T().Run("accepted", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Codebases(1))
+		c := make(chan scanned, 1)
+		svc, ctrl := s.SecuredControllers(*fxt.Identities[0], withScanRepository(fakeScan(c)))
+		// when
+		test.ScanCodebaseAccepted(t, svc.Context, svc, ctrl, fxt.Codebases[0].ID, ptr.Int(20))
+		// then the scan runs in the background
+		select {
+		case res := <-c:
+			require.Equal(t, fxt.Codebases[0].URL, res.url)
+			require.Equal(t, 20, res.limit)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the repository was not scanned")
+		}
+	})
+
+	s.T().Run("conflict while the repository is scanned", func(t *testing.T) {
+		// given a scan that runs until it is released
+		fxt := tf.NewTestFixture(t, s.DB, tf.Codebases(1))
+		started := make(chan scanned, 1)
+		release := make(chan struct{})
+		blockingScan := func(ctx context.Context, repoURL string, limit int) ([]scm.Change, error) {
+			started <- scanned{repoURL, limit}
+			<-release
+			return nil, nil
+		}
+		svc, ctrl := s.SecuredControllers(*fxt.Identities[0], withScanRepository(blockingScan))
+		test.ScanCodebaseAccepted(t, svc.Context, svc, ctrl, fxt.Codebases[0].ID, nil)
+		defer close(release)
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the repository was not scanned")
+		}
+		// when/then
+		test.ScanCodebaseConflict(t, svc.Context, svc, ctrl, fxt.Codebases[0].ID, nil)
+	})
+
+	s.T().Run("bad request for local repositories", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Codebases(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.Codebases[idx].URL = "/var/lib/git/fabric8-wit"
+			return nil
+		}))
+		c := make(chan scanned, 1)
+		svc, ctrl := s.SecuredControllers(*fxt.Identities[0], withScanRepository(fakeScan(c)))
+		// when
+		test.ScanCodebaseBadRequest(t, svc.Context, svc, ctrl, fxt.Codebases[0].ID, nil)
+		// then
+		require.Empty(t, c)
+	})
+
+	s.T().Run("forbidden for wrong user", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.Codebases(1))
+		c := make(chan scanned, 1)
+		svc, ctrl := s.SecuredControllers(*fxt.Identities[1], withScanRepository(fakeScan(c)))
+		// when
+		test.ScanCodebaseForbidden(t, svc.Context, svc, ctrl, fxt.Codebases[0].ID, nil)
+		// then
+		require.Empty(t, c)
+	})
+}
+
 func (s *CodebaseControllerTestSuite) TestUpdateCodebase() {
 	t := s.T()
 
@@ -9,7 +9,9 @@ import (
 	"github.com/fabric8-services/fabric8-wit/application"
 	"github.com/fabric8-services/fabric8-wit/comment"
 	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/id"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/log"
 	"github.com/fabric8-services/fabric8-wit/login"
 	"github.com/fabric8-services/fabric8-wit/notification"
 	"github.com/fabric8-services/fabric8-wit/ptr"
@@ -18,6 +20,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/space/authz"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -62,9 +65,14 @@ func NewNotifyingCommentsController(service *goa.Service, db application.DB, not
 // Show runs the show action.
 func (c *CommentsController) Show(ctx *app.ShowCommentsContext) error {
 	var cmt *comment.Comment
+	var includeRepliesAndReactions CommentConvertFunc
 	err := application.Transactional(c.db, func(appl application.Application) error {
 		var err error
 		cmt, err = appl.Comments().Load(ctx, ctx.CommentID)
+		if err != nil {
+			return err
+		}
+		includeRepliesAndReactions, err = CommentIncludeRepliesAndReactions(ctx, appl, cmt)
 		return err
 	})
 	if err != nil {
@@ -77,7 +85,8 @@ func (c *CommentsController) Show(ctx *app.ShowCommentsContext) error {
 		res.Data = ConvertComment(
 			ctx.Request,
 			*cmt,
-			includeParentWorkItem)
+			includeParentWorkItem,
+			includeRepliesAndReactions)
 		return ctx.OK(res)
 	})
 }
@@ -106,9 +115,17 @@ func (c *CommentsController) Update(ctx *app.UpdateCommentsContext) error {
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
 	}
+	var includeRepliesAndReactions CommentConvertFunc
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		includeRepliesAndReactions, err = CommentIncludeRepliesAndReactions(ctx, appl, cm)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
 	// This code should change if others type of parents than WI are allowed
 	res := &app.CommentSingle{
-		Data: ConvertComment(ctx.Request, *cm, CommentIncludeParentWorkItem(ctx, cm)),
+		Data: ConvertComment(ctx.Request, *cm, CommentIncludeParentWorkItem(ctx, cm), includeRepliesAndReactions),
 	}
 	c.notification.Send(ctx, notification.NewCommentUpdated(cm.ID.String()))
 	return ctx.OK(res)
@@ -172,6 +189,237 @@ func (c *CommentsController) Delete(ctx *app.DeleteCommentsContext) error {
 	return ctx.OK([]byte{})
 }
 
+// Revisions runs the revisions action.
+func (c *CommentsController) Revisions(ctx *app.RevisionsCommentsContext) error {
+	var revisions []comment.Revision
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		revisions, err = appl.CommentRevisions().List(ctx, ctx.CommentID)
+		if err != nil {
+			return err
+		}
+		if len(revisions) == 0 {
+			return errors.NewNotFoundError("comment", ctx.CommentID.String())
+		}
+		if revisions[len(revisions)-1].Type != comment.RevisionTypeDelete {
+			return nil
+		}
+		// the history of a deleted comment is only visible to the moderators
+		// of the space in which the comment was written
+		wi, err := appl.WorkItems().LoadByID(ctx, revisions[0].CommentParentID)
+		if err != nil || !isSpaceModerator(ctx, wi.SpaceID) {
+			return errors.NewNotFoundError("comment", ctx.CommentID.String())
+		}
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	data, err := ConvertCommentRevisions(ctx.Request, revisions)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.CommentRevisionList{
+		Data: data,
+	})
+}
+
+// ListReplies runs the list-replies action.
+func (c *CommentsController) ListReplies(ctx *app.ListRepliesCommentsContext) error {
+	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
+	var replies []comment.Comment
+	var count int
+	var includeRepliesAndReactions CommentConvertFunc
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.Comments().CheckExists(ctx, ctx.CommentID); err != nil {
+			return err
+		}
+		var tc uint64
+		var err error
+		replies, tc, err = appl.Comments().ListReplies(ctx, ctx.CommentID, &offset, &limit)
+		if err != nil {
+			return err
+		}
+		count = int(tc)
+		includeRepliesAndReactions, err = CommentIncludeRepliesAndReactions(ctx, appl, commentsRef(replies)...)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.ConditionalEntities(replies, c.config.GetCacheControlComments, func() error {
+		res := &app.CommentList{
+			Data:  ConvertComments(ctx.Request, replies, includeRepliesAndReactions),
+			Meta:  &app.CommentListMeta{TotalCount: count},
+			Links: &app.PagingLinks{},
+		}
+		setPagingLinks(res.Links, buildAbsoluteURL(ctx.Request), len(replies), offset, limit, count)
+		return ctx.OK(res)
+	})
+}
+
+// CreateReply runs the create-reply action.
+func (c *CommentsController) CreateReply(ctx *app.CreateReplyCommentsContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	reqComment := ctx.Payload.Data
+	var reply comment.Comment
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		parentComment, err := appl.Comments().Load(ctx, ctx.CommentID)
+		if err != nil {
+			return err
+		}
+		// the reply belongs to the work item of the comment it replies to
+		reply = comment.Comment{
+			ParentID: parentComment.ParentID,
+			ParentCommentID: id.NullUUID{
+				UUID:  parentComment.ID,
+				Valid: true,
+			},
+			Body:    reqComment.Attributes.Body,
+			Markup:  rendering.NilSafeGetMarkup(reqComment.Attributes.Markup),
+			Creator: *identityID,
+		}
+		return appl.Comments().Create(ctx, &reply, *identityID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.CommentSingle{
+		Data: ConvertComment(ctx.Request, reply, CommentIncludeParentWorkItem(ctx, &reply), commentIncludeRepliesAndReactions(nil)),
+	}
+	c.notification.Send(ctx, notification.NewCommentCreated(reply.ID.String()))
+	ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.Request, app.CommentsHref(reply.ID)))
+	return ctx.Created(res)
+}
+
+// AddReaction runs the add-reaction action.
+func (c *CommentsController) AddReaction(ctx *app.AddReactionCommentsContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	res, err := c.updateReactions(ctx, ctx.Request, ctx.CommentID, func(appl application.Application) error {
+		_, err := appl.CommentReactions().Add(ctx, ctx.CommentID, *identityID, ctx.Payload.Data.Attributes.Emoji)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(res)
+}
+
+// RemoveReaction runs the remove-reaction action.
+func (c *CommentsController) RemoveReaction(ctx *app.RemoveReactionCommentsContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	res, err := c.updateReactions(ctx, ctx.Request, ctx.CommentID, func(appl application.Application) error {
+		return appl.CommentReactions().Remove(ctx, ctx.CommentID, *identityID, ctx.Emoji)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(res)
+}
+
+// updateReactions applies the given change to the reactions of a comment and
+// returns the updated comment
+func (c *CommentsController) updateReactions(ctx context.Context, req *http.Request, commentID uuid.UUID, change func(appl application.Application) error) (*app.CommentSingle, error) {
+	var cmt *comment.Comment
+	var includeRepliesAndReactions CommentConvertFunc
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.Comments().CheckExists(ctx, commentID); err != nil {
+			return err
+		}
+		if err := change(appl); err != nil {
+			return err
+		}
+		var err error
+		cmt, err = appl.Comments().Load(ctx, commentID)
+		if err != nil {
+			return err
+		}
+		includeRepliesAndReactions, err = CommentIncludeRepliesAndReactions(ctx, appl, cmt)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.notification.Send(ctx, notification.NewCommentUpdated(cmt.ID.String()))
+	return &app.CommentSingle{
+		Data: ConvertComment(req, *cmt, CommentIncludeParentWorkItem(ctx, cmt), includeRepliesAndReactions),
+	}, nil
+}
+
+// isSpaceModerator returns true if the current user is allowed to moderate the
+// comments of the given space, i.e., if the user is a space collaborator.
+func isSpaceModerator(ctx context.Context, spaceID uuid.UUID) bool {
+	if _, err := login.ContextIdentity(ctx); err != nil {
+		return false
+	}
+	authorized, err := authz.Authorize(ctx, spaceID.String())
+	if err != nil {
+		log.Warn(ctx, map[string]interface{}{
+			"space_id": spaceID,
+			"err":      err,
+		}, "unable to check if the user is a space collaborator")
+		return false
+	}
+	return authorized
+}
+
+// ConvertCommentRevisions converts between internal and external REST
+// representation. The given revisions must be sorted from the oldest to the
+// most recent one so that the diff of each body with its previous version
+// can be computed.
+func ConvertCommentRevisions(request *http.Request, revisions []comment.Revision) ([]*app.CommentRevision, error) {
+	result := make([]*app.CommentRevision, len(revisions))
+	var previous *comment.Revision
+	for i := range revisions {
+		r := revisions[i]
+		diff, err := comment.BodyDiff(previous, &r)
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to compute the body diff of comment revision %s", r.ID)
+		}
+		modifierData, modifierLinks := ConvertUserSimple(request, r.ModifierIdentity)
+		commentURL := rest.AbsoluteURL(request, app.CommentsHref(r.CommentID))
+		result[i] = &app.CommentRevision{
+			Type: "comment-revisions",
+			ID:   r.ID,
+			Attributes: &app.CommentRevisionAttributes{
+				RevisionType: r.Type.String(),
+				Timestamp:    r.Time,
+				Body:         r.CommentBody,
+				Markup:       r.CommentMarkup,
+			},
+			Relationships: &app.CommentRevisionRelations{
+				Modifier: &app.RelationGeneric{
+					Data:  modifierData,
+					Links: modifierLinks,
+				},
+				Comment: &app.RelationGeneric{
+					Data: &app.GenericData{
+						Type: ptr.String(APIStringTypeComments),
+						ID:   ptr.String(r.CommentID.String()),
+					},
+					Links: &app.GenericLinks{
+						Self: &commentURL,
+					},
+				},
+			},
+		}
+		if diff != "" {
+			result[i].Attributes.BodyDiff = &diff
+		}
+		previous = &r
+	}
+	return result, nil
+}
+
 // CommentConvertFunc is a open ended function to add additional links/data/relations to a Comment during
 // conversion from internal to API
 type CommentConvertFunc func(*http.Request, *comment.Comment, *app.Comment)
@@ -245,6 +493,13 @@ func ConvertComment(request *http.Request, comment comment.Comment, additional .
 			Related: &relatedURL,
 		},
 	}
+	if comment.DeletedAt != nil {
+		// deleted comments are only returned as tombstones, without body
+		c.Attributes.Body = nil
+		c.Attributes.BodyRendered = nil
+		c.Attributes.Markup = nil
+		c.Attributes.DeletedAt = comment.DeletedAt
+	}
 	if comment.ParentCommentID.Valid == true {
 		c.Relationships.ParentComment = &app.RelationGeneric{
 			Data: &app.GenericData{
@@ -284,3 +539,62 @@ func CommentIncludeParent(request *http.Request, comment *comment.Comment, data
 		},
 	}
 }
+
+// CommentIncludeRepliesAndReactions loads the reply statistics and the
+// reactions of the given comments and returns a CommentConvertFunc that adds
+// them to the converted comments. The reply statistics are also stored in
+// the given comments so that they are part of their ETag.
+func CommentIncludeRepliesAndReactions(ctx context.Context, appl application.Application, comments ...*comment.Comment) (CommentConvertFunc, error) {
+	ids := make([]uuid.UUID, len(comments))
+	for i, c := range comments {
+		ids[i] = c.ID
+	}
+	replyStats, err := appl.Comments().ReplyStats(ctx, ids...)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to count the replies of the comments")
+	}
+	for _, c := range comments {
+		if stats, ok := replyStats[c.ID]; ok {
+			c.ReplyCount = stats.Count
+			c.LastReplyAt = &stats.LatestAt
+		}
+	}
+	reactions, err := appl.CommentReactions().List(ctx, ids...)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to load the reactions of the comments")
+	}
+	return commentIncludeRepliesAndReactions(reactions), nil
+}
+
+// commentsRef returns pointers to the elements of the given comments
+func commentsRef(comments []comment.Comment) []*comment.Comment {
+	res := make([]*comment.Comment, len(comments))
+	for i := range comments {
+		res[i] = &comments[i]
+	}
+	return res
+}
+
+// commentIncludeRepliesAndReactions adds the "replies" relationship and the
+// "reactions" attribute to a Comment using the given preloaded reactions
+func commentIncludeRepliesAndReactions(reactions map[uuid.UUID][]comment.Reaction) CommentConvertFunc {
+	return func(request *http.Request, cmt *comment.Comment, data *app.Comment) {
+		repliesRelated := rest.AbsoluteURL(request, app.CommentsHref(cmt.ID)) + "/replies"
+		data.Relationships.Replies = &app.RelationGeneric{
+			Links: &app.GenericLinks{
+				Related: &repliesRelated,
+			},
+			Meta: map[string]interface{}{
+				"totalCount": cmt.ReplyCount,
+			},
+		}
+		data.Attributes.Reactions = []*app.CommentReaction{}
+		for _, summary := range comment.Summarize(reactions[cmt.ID]) {
+			data.Attributes.Reactions = append(data.Attributes.Reactions, &app.CommentReaction{
+				Emoji:      summary.Emoji,
+				Count:      len(summary.IdentityIDs),
+				Identities: summary.IdentityIDs,
+			})
+		}
+	}
+}
@@ -265,6 +265,21 @@ func (s *CommentsSuite) TestShowCommentWithoutAuthNotModifiedUsingIfNoneMatchHea
 	assertResponseHeaders(s.T(), res)
 }
 
+func (s *CommentsSuite) TestShowCommentETagChangesWithReplies() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+	c := s.createWorkItemComment(s.testIdentity, fxt.WorkItems[0].ID, "body", &markdownMarkup, nil)
+	svc, commentsCtrl := s.unsecuredController()
+	res, _ := test.ShowCommentsOK(s.T(), svc.Context, svc, commentsCtrl, *c.Data.ID, nil, nil)
+	ifNoneMatch := res.Header()[app.ETag][0]
+	// when
+	s.createWorkItemComment(s.testIdentity, fxt.WorkItems[0].ID, "reply", &markdownMarkup, c.Data.ID)
+	// then
+	res, result := test.ShowCommentsOK(s.T(), svc.Context, svc, commentsCtrl, *c.Data.ID, nil, &ifNoneMatch)
+	assert.NotEqual(s.T(), ifNoneMatch, res.Header()[app.ETag][0])
+	assert.Equal(s.T(), 1, result.Data.Relationships.Replies.Meta["totalCount"])
+}
+
 func (s *CommentsSuite) TestShowCommentWithoutAuthWithMarkup() {
 	// given
 	fxt := tf.NewTestFixture(s.T(), s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
@@ -421,6 +436,48 @@ func (s *CommentsSuite) TestCollaboratorCanDelete() {
 	test.DeleteCommentsOK(s.T(), svc.Context, svc, commentCtrl, *c.Data.ID)
 }
 
+func (s *CommentsSuite) TestListDeletedComments() {
+	// given a deleted comment on a work item of a secured space
+	owner, err := testsupport.CreateTestIdentity(s.DB, testsupport.CreateRandomValidTestName("TestListDeletedComments-"), "TestWIComments")
+	require.NoError(s.T(), err)
+	space := CreateSecuredSpace(s.T(), s.GormDB, s.Configuration, *owner, "")
+	payload := minimumRequiredCreateWithTypeAndSpace(workitem.SystemFeature, *space.ID)
+	payload.Data.Attributes[workitem.SystemTitle] = "Test WI"
+	payload.Data.Attributes[workitem.SystemState] = workitem.SystemStateNew
+	svc := testsupport.ServiceAsSpaceUser("Collaborators-Service", *owner, &TestSpaceAuthzService{*owner, ""})
+	workitemsCtrl := NewWorkitemsController(svc, s.GormDB, s.Configuration)
+	_, wi := test.CreateWorkitemsCreated(s.T(), svc.Context, svc, workitemsCtrl, *payload.Data.Relationships.Space.Data.ID, &payload)
+	s.createWorkItemComment(*owner, *wi.Data.ID, "kept", &plaintextMarkup, nil)
+	deleted := s.createWorkItemComment(*owner, *wi.Data.ID, "deleted", &plaintextMarkup, nil)
+	test.DeleteCommentsOK(s.T(), svc.Context, svc, NewCommentsController(svc, s.GormDB, s.Configuration), *deleted.Data.ID)
+	ctrl := NewWorkItemCommentsController(svc, s.GormDB, s.Configuration)
+
+	s.T().Run("without tombstones", func(t *testing.T) {
+		_, comments := test.ListWorkItemCommentsOK(t, svc.Context, svc, ctrl, *wi.Data.ID, nil, nil, nil, nil, nil, nil)
+		require.Len(t, comments.Data, 1)
+		assert.Nil(t, comments.Data[0].Attributes.DeletedAt)
+	})
+
+	s.T().Run("with tombstones", func(t *testing.T) {
+		_, comments := test.ListWorkItemCommentsOK(t, svc.Context, svc, ctrl, *wi.Data.ID, ptr.Bool(true), nil, nil, nil, nil, nil)
+		require.Len(t, comments.Data, 2)
+		// the most recent comment comes first
+		assert.Equal(t, *deleted.Data.ID, *comments.Data[0].ID)
+		assert.NotNil(t, comments.Data[0].Attributes.DeletedAt)
+	})
+
+	s.T().Run("tombstones for non-collaborators", func(t *testing.T) {
+		other, err := testsupport.CreateTestIdentity(s.DB, testsupport.CreateRandomValidTestName("TestListDeletedComments-"), "TestWIComments")
+		require.NoError(t, err)
+		otherSvc := testsupport.ServiceAsSpaceUser("Collaborators-Service", *other, &TestSpaceAuthzService{*owner, ""})
+		otherCtrl := NewWorkItemCommentsController(otherSvc, s.GormDB, s.Configuration)
+		test.ListWorkItemCommentsForbidden(t, otherSvc.Context, otherSvc, otherCtrl, *wi.Data.ID, ptr.Bool(true), nil, nil, nil, nil, nil)
+		// but they can still list the comments
+		_, comments := test.ListWorkItemCommentsOK(t, otherSvc.Context, otherSvc, otherCtrl, *wi.Data.ID, nil, nil, nil, nil, nil, nil)
+		require.Len(t, comments.Data, 1)
+	})
+}
+
 func (s *CommentsSuite) TestCreatorCanDelete() {
 	fxt := tf.NewTestFixture(s.T(), s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
 	wID := fxt.WorkItems[0].ID
@@ -618,3 +675,102 @@ func (s *TestSpaceAuthzService) Authorize(ctx context.Context, spaceID string) (
 func (s *TestSpaceAuthzService) Configuration() auth.ServiceConfiguration {
 	return nil
 }
+
+func newCreateReplyCommentsPayload(body string) *app.CreateReplyCommentsPayload {
+	return &app.CreateReplyCommentsPayload{
+		Data: &app.CreateComment{
+			Type: "comments",
+			Attributes: &app.CreateCommentAttributes{
+				Body:   body,
+				Markup: &markdownMarkup,
+			},
+		},
+	}
+}
+
+func (s *CommentsSuite) TestReplies() {
+	s.T().Run("create and list", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+		parent := s.createWorkItemComment(s.testIdentity, fxt.WorkItems[0].ID, "parent", &markdownMarkup, nil)
+		svc, _, _, workitemCommentsCtrl, commentsCtrl := s.securedControllers(s.testIdentity2)
+		// when
+		_, reply1 := test.CreateReplyCommentsCreated(t, svc.Context, svc, commentsCtrl, *parent.Data.ID, newCreateReplyCommentsPayload("reply 1"))
+		_, reply2 := test.CreateReplyCommentsCreated(t, svc.Context, svc, commentsCtrl, *parent.Data.ID, newCreateReplyCommentsPayload("reply 2"))
+		// then
+		assertComment(t, reply1.Data, s.testIdentity2, "reply 1", rendering.SystemMarkupMarkdown)
+		assert.Equal(t, parent.Data.ID.String(), *reply1.Data.Relationships.ParentComment.Data.ID)
+		assert.Equal(t, fxt.WorkItems[0].ID.String(), *reply1.Data.Relationships.Parent.Data.ID)
+		_, replies := test.ListRepliesCommentsOK(t, svc.Context, svc, commentsCtrl, *parent.Data.ID, nil, nil, nil, nil)
+		require.Len(t, replies.Data, 2)
+		assert.Equal(t, 2, replies.Meta.TotalCount)
+		// oldest first
+		assert.Equal(t, *reply1.Data.ID, *replies.Data[0].ID)
+		assert.Equal(t, *reply2.Data.ID, *replies.Data[1].ID)
+		// replies are listed with the comments of the work item
+		_, comments := test.ListWorkItemCommentsOK(t, svc.Context, svc, workitemCommentsCtrl, fxt.WorkItems[0].ID, nil, nil, nil, nil, nil, nil)
+		require.Len(t, comments.Data, 3)
+		// unless only the top-level comments are listed
+		_, comments = test.ListWorkItemCommentsOK(t, svc.Context, svc, workitemCommentsCtrl, fxt.WorkItems[0].ID, nil, nil, nil, ptr.Bool(true), nil, nil)
+		require.Len(t, comments.Data, 1)
+		assert.Equal(t, *parent.Data.ID, *comments.Data[0].ID)
+		assert.Equal(t, 2, comments.Data[0].Relationships.Replies.Meta["totalCount"])
+	})
+
+	s.T().Run("unauthorized", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+		parent := s.createWorkItemComment(s.testIdentity, fxt.WorkItems[0].ID, "parent", &markdownMarkup, nil)
+		svc, commentsCtrl := s.unsecuredController()
+		test.CreateReplyCommentsUnauthorized(t, svc.Context, svc, commentsCtrl, *parent.Data.ID, newCreateReplyCommentsPayload("reply"))
+	})
+
+	s.T().Run("unknown comment", func(t *testing.T) {
+		svc, _, _, _, commentsCtrl := s.securedControllers(s.testIdentity)
+		test.CreateReplyCommentsNotFound(t, svc.Context, svc, commentsCtrl, uuid.NewV4(), newCreateReplyCommentsPayload("reply"))
+		test.ListRepliesCommentsNotFound(t, svc.Context, svc, commentsCtrl, uuid.NewV4(), nil, nil, nil, nil)
+	})
+}
+
+func newAddReactionCommentsPayload(emoji string) *app.AddReactionCommentsPayload {
+	return &app.AddReactionCommentsPayload{
+		Data: &app.CreateCommentReaction{
+			Type: "reactions",
+			Attributes: &app.CreateCommentReactionAttributes{
+				Emoji: emoji,
+			},
+		},
+	}
+}
+
+func (s *CommentsSuite) TestReactions() {
+	s.T().Run("add and remove", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+		c := s.createWorkItemComment(s.testIdentity, fxt.WorkItems[0].ID, "body", &markdownMarkup, nil)
+		svc, _, _, _, commentsCtrl := s.securedControllers(s.testIdentity2)
+		// when
+		_, result := test.AddReactionCommentsOK(t, svc.Context, svc, commentsCtrl, *c.Data.ID, newAddReactionCommentsPayload(":thumbsup:"))
+		// then
+		require.Len(t, result.Data.Attributes.Reactions, 1)
+		assert.Equal(t, ":thumbsup:", result.Data.Attributes.Reactions[0].Emoji)
+		assert.Equal(t, 1, result.Data.Attributes.Reactions[0].Count)
+		assert.Equal(t, []uuid.UUID{s.testIdentity2.ID}, result.Data.Attributes.Reactions[0].Identities)
+		// when
+		_, result = test.RemoveReactionCommentsOK(t, svc.Context, svc, commentsCtrl, *c.Data.ID, ":thumbsup:")
+		// then
+		assert.Empty(t, result.Data.Attributes.Reactions)
+	})
+
+	s.T().Run("unauthorized", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+		c := s.createWorkItemComment(s.testIdentity, fxt.WorkItems[0].ID, "body", &markdownMarkup, nil)
+		svc, commentsCtrl := s.unsecuredController()
+		test.AddReactionCommentsUnauthorized(t, svc.Context, svc, commentsCtrl, *c.Data.ID, newAddReactionCommentsPayload(":thumbsup:"))
+		test.RemoveReactionCommentsUnauthorized(t, svc.Context, svc, commentsCtrl, *c.Data.ID, ":thumbsup:")
+	})
+
+	s.T().Run("unknown comment", func(t *testing.T) {
+		svc, _, _, _, commentsCtrl := s.securedControllers(s.testIdentity)
+		test.AddReactionCommentsNotFound(t, svc.Context, svc, commentsCtrl, uuid.NewV4(), newAddReactionCommentsPayload(":thumbsup:"))
+	})
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/workitem/worklog"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// IterationWorklogsController implements the iteration_worklogs resource.
+type IterationWorklogsController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewIterationWorklogsController creates an iteration_worklogs controller.
+func NewIterationWorklogsController(service *goa.Service, db application.DB) *IterationWorklogsController {
+	return &IterationWorklogsController{
+		Controller: service.NewController("IterationWorklogsController"),
+		db:         db,
+	}
+}
+
+// Totals runs the totals action.
+func (c *IterationWorklogsController) Totals(ctx *app.TotalsIterationWorklogsContext) error {
+	id, err := uuid.FromString(ctx.IterationID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrNotFound(err.Error()))
+	}
+	var itr *iteration.Iteration
+	var totals []worklog.IdentityTotal
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		itr, err = appl.Iterations().Load(ctx, id)
+		if err != nil {
+			return err
+		}
+		totals, err = appl.Worklogs().TotalsByIteration(ctx, *itr)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	timeSpent := 0
+	for _, t := range totals {
+		timeSpent += t.TimeSpent
+	}
+	selfURL := rest.AbsoluteURL(ctx.Request, app.IterationHref(id)+"/worklogs/totals")
+	return ctx.OK(&app.WorklogTotalsSingle{
+		Data: &app.WorklogTotals{
+			Type: APIStringTypeWorklogTotals,
+			ID:   id,
+			Attributes: &app.WorklogTotalsAttributes{
+				TimeSpent: timeSpent,
+				Users:     ConvertWorklogIdentityTotals(ctx.Request, totals),
+			},
+			Links: &app.GenericLinks{
+				Self: &selfURL,
+			},
+		},
+	})
+}
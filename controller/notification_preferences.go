@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/notification"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Defines the constants to be used in json api
+const (
+	APIStringTypeNotificationPreferences = "notification-preferences"
+)
+
+// NotificationPreferencesController implements the notification_preferences resource.
+type NotificationPreferencesController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewNotificationPreferencesController creates a notification_preferences controller.
+func NewNotificationPreferencesController(service *goa.Service, db application.DB) *NotificationPreferencesController {
+	return &NotificationPreferencesController{
+		Controller: service.NewController("NotificationPreferencesController"),
+		db:         db,
+	}
+}
+
+// Show runs the show action.
+func (c *NotificationPreferencesController) Show(ctx *app.ShowNotificationPreferencesContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	var preferences map[string]bool
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		preferences, err = appl.Watchers().Preferences(ctx, *identityID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.NotificationPreferencesSingle{
+		Data: ConvertNotificationPreferences(ctx.Request, *identityID, preferences),
+	})
+}
+
+// Update runs the update action.
+func (c *NotificationPreferencesController) Update(ctx *app.UpdateNotificationPreferencesContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload == nil || ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	updated := ctx.Payload.Data.Attributes.MessageTypes
+	for messageType := range updated {
+		if !isNotificationMessageType(messageType) {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("message-types", messageType).Expected(fmt.Sprintf("one of %v", notification.MessageTypes)))
+		}
+	}
+	var preferences map[string]bool
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.Watchers().SetPreferences(ctx, *identityID, updated); err != nil {
+			return err
+		}
+		var err error
+		preferences, err = appl.Watchers().Preferences(ctx, *identityID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.NotificationPreferencesSingle{
+		Data: ConvertNotificationPreferences(ctx.Request, *identityID, preferences),
+	})
+}
+
+func isNotificationMessageType(messageType string) bool {
+	for _, t := range notification.MessageTypes {
+		if t == messageType {
+			return true
+		}
+	}
+	return false
+}
+
+// ConvertNotificationPreferences converts the notification preferences of an
+// identity from internal to external REST representation. All message types
+// are listed; those without a preference are enabled.
+func ConvertNotificationPreferences(request *http.Request, identityID uuid.UUID, preferences map[string]bool) *app.NotificationPreferences {
+	messageTypes := make(map[string]bool, len(notification.MessageTypes))
+	for _, t := range notification.MessageTypes {
+		enabled, ok := preferences[t]
+		messageTypes[t] = !ok || enabled
+	}
+	selfURL := rest.AbsoluteURL(request, app.NotificationPreferencesHref())
+	return &app.NotificationPreferences{
+		Type: APIStringTypeNotificationPreferences,
+		ID:   &identityID,
+		Attributes: &app.NotificationPreferencesAttributes{
+			MessageTypes: messageTypes,
+		},
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+}
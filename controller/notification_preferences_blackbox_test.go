@@ -0,0 +1,112 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/notification"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/goadesign/goa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestNotificationPreferencesREST struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunNotificationPreferencesREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestNotificationPreferencesREST{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *TestNotificationPreferencesREST) UnSecuredController() (*goa.Service, *NotificationPreferencesController) {
+	svc := goa.New("NotificationPreferences-Service")
+	return svc, NewNotificationPreferencesController(svc, s.GormDB)
+}
+
+func (s *TestNotificationPreferencesREST) SecuredController(fxt *tf.TestFixture) (*goa.Service, *NotificationPreferencesController) {
+	svc := testsupport.ServiceAsUser("NotificationPreferences-Service", *fxt.Identities[0])
+	return svc, NewNotificationPreferencesController(svc, s.GormDB)
+}
+
+func newUpdateNotificationPreferencesPayload(messageTypes map[string]bool) *app.UpdateNotificationPreferencesPayload {
+	return &app.UpdateNotificationPreferencesPayload{
+		Data: &app.NotificationPreferences{
+			Type: APIStringTypeNotificationPreferences,
+			Attributes: &app.NotificationPreferencesAttributes{
+				MessageTypes: messageTypes,
+			},
+		},
+	}
+}
+
+func (s *TestNotificationPreferencesREST) TestShow() {
+	s.T().Run("all message types are enabled by default", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		svc, ctrl := s.SecuredController(fxt)
+		// when
+		_, res := test.ShowNotificationPreferencesOK(t, svc.Context, svc, ctrl)
+		// then
+		require.NotNil(t, res.Data)
+		assert.Equal(t, fxt.Identities[0].ID, *res.Data.ID)
+		require.Len(t, res.Data.Attributes.MessageTypes, len(notification.MessageTypes))
+		for _, messageType := range notification.MessageTypes {
+			assert.True(t, res.Data.Attributes.MessageTypes[messageType], messageType)
+		}
+	})
+	s.T().Run("unauthorized", func(t *testing.T) {
+		// given
+		svc, ctrl := s.UnSecuredController()
+		// when/then
+		test.ShowNotificationPreferencesUnauthorized(t, svc.Context, svc, ctrl)
+	})
+}
+
+func (s *TestNotificationPreferencesREST) TestUpdate() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		svc, ctrl := s.SecuredController(fxt)
+		payload := newUpdateNotificationPreferencesPayload(map[string]bool{notification.MessageTypeWorkItemUpdate: false})
+		// when
+		_, res := test.UpdateNotificationPreferencesOK(t, svc.Context, svc, ctrl, payload)
+		// then
+		assert.False(t, res.Data.Attributes.MessageTypes[notification.MessageTypeWorkItemUpdate])
+		assert.True(t, res.Data.Attributes.MessageTypes[notification.MessageTypeWorkItemCreate])
+		_, shown := test.ShowNotificationPreferencesOK(t, svc.Context, svc, ctrl)
+		assert.Equal(t, res.Data.Attributes.MessageTypes, shown.Data.Attributes.MessageTypes)
+	})
+	s.T().Run("enable again", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		svc, ctrl := s.SecuredController(fxt)
+		test.UpdateNotificationPreferencesOK(t, svc.Context, svc, ctrl, newUpdateNotificationPreferencesPayload(map[string]bool{notification.MessageTypeCommentCreate: false}))
+		// when
+		_, res := test.UpdateNotificationPreferencesOK(t, svc.Context, svc, ctrl, newUpdateNotificationPreferencesPayload(map[string]bool{notification.MessageTypeCommentCreate: true}))
+		// then
+		assert.True(t, res.Data.Attributes.MessageTypes[notification.MessageTypeCommentCreate])
+	})
+	s.T().Run("unknown message type", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		svc, ctrl := s.SecuredController(fxt)
+		payload := newUpdateNotificationPreferencesPayload(map[string]bool{"space.delete": false})
+		// when/then
+		test.UpdateNotificationPreferencesBadRequest(t, svc.Context, svc, ctrl, payload)
+	})
+	s.T().Run("unauthorized", func(t *testing.T) {
+		// given
+		svc, ctrl := s.UnSecuredController()
+		payload := newUpdateNotificationPreferencesPayload(map[string]bool{notification.MessageTypeWorkItemUpdate: false})
+		// when/then
+		test.UpdateNotificationPreferencesUnauthorized(t, svc.Context, svc, ctrl, payload)
+	})
+}
@@ -0,0 +1,288 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/space/authz"
+	"github.com/fabric8-services/fabric8-wit/workitem/recurrence"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Defines the constants to be used in json api
+const (
+	APIStringTypeRecurrences = "recurrences"
+	APIStringTypeOccurrences = "occurrences"
+)
+
+// RecurrencesController implements the recurrences resource.
+type RecurrencesController struct {
+	*goa.Controller
+	db        application.DB
+	scheduler *recurrence.Scheduler
+	config    RecurrencesControllerConfiguration
+}
+
+// RecurrencesControllerConfiguration the configuration for the RecurrencesController
+type RecurrencesControllerConfiguration interface {
+	GetCacheControlRecurrence() string
+}
+
+// NewRecurrencesController creates a recurrences controller.
+func NewRecurrencesController(service *goa.Service, db application.DB, scheduler *recurrence.Scheduler, config RecurrencesControllerConfiguration) *RecurrencesController {
+	return &RecurrencesController{
+		Controller: service.NewController("RecurrencesController"),
+		db:         db,
+		scheduler:  scheduler,
+		config:     config,
+	}
+}
+
+// Show runs the show action.
+func (c *RecurrencesController) Show(ctx *app.ShowRecurrencesContext) error {
+	var r *recurrence.Recurrence
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		r, err = appl.Recurrences().Load(ctx, ctx.RecurrenceID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.ConditionalRequest(*r, c.config.GetCacheControlRecurrence, func() error {
+		return ctx.OK(&app.RecurrenceSingle{
+			Data: ConvertRecurrence(ctx.Request, *r),
+		})
+	})
+}
+
+// Update runs the update action.
+func (c *RecurrencesController) Update(ctx *app.UpdateRecurrencesContext) error {
+	if _, err := login.ContextIdentity(ctx); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload == nil || ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	r, err := c.loadAuthorizedRecurrence(ctx, ctx.RecurrenceID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if err := updateRecurrenceFromPayload(r, ctx.Payload.Data); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		return appl.Recurrences().Save(ctx, r)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	c.reschedule()
+	return ctx.OK(&app.RecurrenceSingle{
+		Data: ConvertRecurrence(ctx.Request, *r),
+	})
+}
+
+// Delete runs the delete action.
+func (c *RecurrencesController) Delete(ctx *app.DeleteRecurrencesContext) error {
+	if _, err := login.ContextIdentity(ctx); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	r, err := c.loadAuthorizedRecurrence(ctx, ctx.RecurrenceID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		return appl.Recurrences().Delete(ctx, r.ID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	c.reschedule()
+	return ctx.OK([]byte{})
+}
+
+// Occurrences runs the occurrences action.
+func (c *RecurrencesController) Occurrences(ctx *app.OccurrencesRecurrencesContext) error {
+	var occurrences []recurrence.Occurrence
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		occurrences, err = appl.Recurrences().Occurrences(ctx, ctx.RecurrenceID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	data := make([]*app.Occurrence, len(occurrences))
+	for i, o := range occurrences {
+		workItemID := o.WorkItemID.String()
+		workItemURL := rest.AbsoluteURL(ctx.Request, app.WorkitemHref(workItemID))
+		data[i] = &app.Occurrence{
+			Type: APIStringTypeOccurrences,
+			ID:   o.ID,
+			Attributes: &app.OccurrenceAttributes{
+				CreatedAt: ptr.Time(o.CreatedAt.UTC()),
+			},
+			Relationships: &app.OccurrenceRelationships{
+				WorkItem: &app.RelationGeneric{
+					Data: &app.GenericData{
+						Type: ptr.String(APIStringTypeWorkItem),
+						ID:   &workItemID,
+					},
+					Links: &app.GenericLinks{
+						Self:    &workItemURL,
+						Related: &workItemURL,
+					},
+				},
+			},
+		}
+	}
+	return ctx.OK(&app.OccurrenceList{
+		Data: data,
+		Meta: &app.OccurrenceListMeta{TotalCount: len(data)},
+	})
+}
+
+// loadAuthorizedRecurrence loads the given recurrence if the user is a
+// collaborator of its space
+func (c *RecurrencesController) loadAuthorizedRecurrence(ctx context.Context, recurrenceID uuid.UUID) (*recurrence.Recurrence, error) {
+	var r *recurrence.Recurrence
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		r, err = appl.Recurrences().Load(ctx, recurrenceID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeRecurrences(ctx, r.SpaceID); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// reschedule makes the scheduler pick up the changed recurrences
+func (c *RecurrencesController) reschedule() {
+	if c.scheduler != nil {
+		// the runs outlive the request, so they must not use its context
+		c.scheduler.ScheduleAll(context.Background())
+	}
+}
+
+// authorizeRecurrences returns an error unless the user is a collaborator of
+// the given space
+func authorizeRecurrences(ctx context.Context, spaceID uuid.UUID) error {
+	authorized, err := authz.Authorize(ctx, spaceID.String())
+	if err != nil {
+		return errors.NewUnauthorizedError(err.Error())
+	}
+	if !authorized {
+		return errors.NewForbiddenError("user is not a space collaborator")
+	}
+	return nil
+}
+
+// updateRecurrenceFromPayload copies the given attributes and the type of the
+// work items to the given recurrence
+func updateRecurrenceFromPayload(r *recurrence.Recurrence, data *app.Recurrence) error {
+	attrs := data.Attributes
+	if attrs.Name != nil {
+		r.Name = *attrs.Name
+	}
+	if attrs.Fields != nil {
+		r.Fields = attrs.Fields
+	}
+	if attrs.Schedule != nil {
+		r.Schedule = *attrs.Schedule
+	}
+	if attrs.CurrentIteration != nil {
+		r.CurrentIteration = *attrs.CurrentIteration
+	}
+	if attrs.SkipIfOpen != nil {
+		r.SkipIfOpen = *attrs.SkipIfOpen
+	}
+	if attrs.Enabled != nil {
+		r.Enabled = *attrs.Enabled
+	}
+	if data.Relationships != nil && data.Relationships.Workitemtype != nil &&
+		data.Relationships.Workitemtype.Data != nil && data.Relationships.Workitemtype.Data.ID != nil {
+		typeID, err := uuid.FromString(*data.Relationships.Workitemtype.Data.ID)
+		if err != nil {
+			return errors.NewBadParameterError("data.relationships.workitemtype.data.id", *data.Relationships.Workitemtype.Data.ID).Expected("UUID")
+		}
+		r.TypeID = typeID
+	}
+	return nil
+}
+
+// ConvertRecurrences converts a list of recurrences from internal to external
+// REST representation
+func ConvertRecurrences(request *http.Request, recurrences []recurrence.Recurrence) []*app.Recurrence {
+	result := make([]*app.Recurrence, len(recurrences))
+	for i, r := range recurrences {
+		result[i] = ConvertRecurrence(request, r)
+	}
+	return result
+}
+
+// ConvertRecurrence converts a recurrence from internal to external REST
+// representation
+func ConvertRecurrence(request *http.Request, r recurrence.Recurrence) *app.Recurrence {
+	selfURL := rest.AbsoluteURL(request, app.RecurrencesHref(r.ID))
+	spaceID := r.SpaceID.String()
+	spaceURL := rest.AbsoluteURL(request, app.SpaceHref(spaceID))
+	typeID := r.TypeID.String()
+	typeURL := rest.AbsoluteURL(request, app.WorkitemtypeHref(typeID))
+	result := &app.Recurrence{
+		Type: APIStringTypeRecurrences,
+		ID:   &r.ID,
+		Attributes: &app.RecurrenceAttributes{
+			Name:             ptr.String(r.Name),
+			Fields:           r.Fields,
+			Schedule:         ptr.String(r.Schedule),
+			CurrentIteration: ptr.Bool(r.CurrentIteration),
+			SkipIfOpen:       ptr.Bool(r.SkipIfOpen),
+			Enabled:          ptr.Bool(r.Enabled),
+			CreatedAt:        ptr.Time(r.CreatedAt.UTC()),
+			UpdatedAt:        ptr.Time(r.UpdatedAt.UTC()),
+		},
+		Relationships: &app.RecurrenceRelationships{
+			Space: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: ptr.String(APIStringTypeSpace),
+					ID:   &spaceID,
+				},
+				Links: &app.GenericLinks{
+					Self:    &spaceURL,
+					Related: &spaceURL,
+				},
+			},
+			Workitemtype: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: ptr.String(APIStringTypeWorkItemType),
+					ID:   &typeID,
+				},
+				Links: &app.GenericLinks{
+					Self:    &typeURL,
+					Related: &typeURL,
+				},
+			},
+			Creator: convertWorklogIdentity(request, r.CreatorID),
+		},
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+	if r.LastRunAt != nil {
+		result.Attributes.LastRunAt = ptr.Time(r.LastRunAt.UTC())
+	}
+	return result
+}
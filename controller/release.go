@@ -0,0 +1,319 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/release"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ReleaseController implements the release resource.
+type ReleaseController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewReleaseController creates a release controller.
+func NewReleaseController(service *goa.Service, db application.DB) *ReleaseController {
+	return &ReleaseController{
+		Controller: service.NewController("ReleaseController"),
+		db:         db,
+	}
+}
+
+// loadRelease loads the release with the given ID and makes sure that it
+// belongs to the given space
+func loadRelease(ctx context.Context, appl application.Application, spaceID, releaseID uuid.UUID) (*release.Release, error) {
+	r, err := appl.Releases().Load(ctx, releaseID)
+	if err != nil {
+		return nil, err
+	}
+	if r.SpaceID != spaceID {
+		return nil, errors.NewNotFoundError("release", releaseID.String())
+	}
+	return r, nil
+}
+
+// Show runs the show action.
+func (c *ReleaseController) Show(ctx *app.ShowReleaseContext) error {
+	var r *release.Release
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		r, err = loadRelease(ctx, appl, ctx.SpaceID, ctx.ReleaseID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.ReleaseSingle{
+		Data: ConvertRelease(ctx.Request, *r),
+	})
+}
+
+// List runs the list action.
+func (c *ReleaseController) List(ctx *app.ListReleaseContext) error {
+	var releases []release.Release
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		err := appl.Spaces().CheckExists(ctx, ctx.SpaceID)
+		if err != nil {
+			return err
+		}
+		releases, err = appl.Releases().List(ctx, ctx.SpaceID, ctx.Archived != nil && *ctx.Archived)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.ReleaseList{Data: []*app.Release{}}
+	for _, r := range releases {
+		res.Data = append(res.Data, ConvertRelease(ctx.Request, r))
+	}
+	return ctx.OK(res)
+}
+
+// Create runs the create action.
+func (c *ReleaseController) Create(ctx *app.CreateReleaseContext) error {
+	_, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil || ctx.Payload.Data.Attributes.Name == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.name", nil).Expected("not nil"))
+	}
+	attrs := ctx.Payload.Data.Attributes
+	r := release.Release{
+		SpaceID:    ctx.SpaceID,
+		Name:       strings.TrimSpace(*attrs.Name),
+		TargetDate: attrs.TargetDate,
+	}
+	if attrs.Description != nil {
+		r.Description = *attrs.Description
+	}
+	if attrs.Status != nil {
+		r.Status = *attrs.Status
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		err := appl.Spaces().CheckExists(ctx, ctx.SpaceID)
+		if err != nil {
+			return err
+		}
+		return appl.Releases().Create(ctx, &r)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	result := &app.ReleaseSingle{
+		Data: ConvertRelease(ctx.Request, r),
+	}
+	ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.Request, app.ReleaseHref(ctx.SpaceID, r.ID)))
+	return ctx.Created(result)
+}
+
+// Update runs the update action.
+func (c *ReleaseController) Update(ctx *app.UpdateReleaseContext) error {
+	_, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	attrs := ctx.Payload.Data.Attributes
+	if attrs.Version == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.version", nil).Expected("not nil"))
+	}
+	var r *release.Release
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		r, err = loadRelease(ctx, appl, ctx.SpaceID, ctx.ReleaseID)
+		if err != nil {
+			return err
+		}
+		if r.Version != *attrs.Version {
+			return errors.NewVersionConflictError("version conflict")
+		}
+		if attrs.Name != nil {
+			r.Name = *attrs.Name
+		}
+		if attrs.Description != nil {
+			r.Description = *attrs.Description
+		}
+		if attrs.TargetDate != nil {
+			r.TargetDate = attrs.TargetDate
+		}
+		if attrs.Status != nil {
+			r.Status = *attrs.Status
+		}
+		r, err = appl.Releases().Save(ctx, *r)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.ReleaseSingle{
+		Data: ConvertRelease(ctx.Request, *r),
+	})
+}
+
+// Archive runs the archive action.
+func (c *ReleaseController) Archive(ctx *app.ArchiveReleaseContext) error {
+	_, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	var r *release.Release
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		_, err := loadRelease(ctx, appl, ctx.SpaceID, ctx.ReleaseID)
+		if err != nil {
+			return err
+		}
+		r, err = appl.Releases().Archive(ctx, ctx.ReleaseID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.ReleaseSingle{
+		Data: ConvertRelease(ctx.Request, *r),
+	})
+}
+
+// Readiness runs the readiness action.
+func (c *ReleaseController) Readiness(ctx *app.ReadinessReleaseContext) error {
+	var readiness *release.Readiness
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		r, err := loadRelease(ctx, appl, ctx.SpaceID, ctx.ReleaseID)
+		if err != nil {
+			return err
+		}
+		readiness, err = appl.Releases().Readiness(ctx, *r)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.ReleaseReadinessSingle{
+		Data: ConvertReleaseReadiness(ctx.Request, ctx.SpaceID, ctx.ReleaseID, *readiness),
+	})
+}
+
+// Notes runs the notes action.
+func (c *ReleaseController) Notes(ctx *app.NotesReleaseContext) error {
+	var notes string
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		r, err := loadRelease(ctx, appl, ctx.SpaceID, ctx.ReleaseID)
+		if err != nil {
+			return err
+		}
+		notes, err = appl.Releases().Notes(ctx, *r)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	ctx.ResponseData.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	return ctx.OK([]byte(notes))
+}
+
+// ConvertRelease converts from internal to external REST representation
+func ConvertRelease(request *http.Request, r release.Release) *app.Release {
+	spaceID := r.SpaceID.String()
+	relatedURL := rest.AbsoluteURL(request, app.ReleaseHref(spaceID, r.ID))
+	spaceRelatedURL := rest.AbsoluteURL(request, app.SpaceHref(spaceID))
+	return &app.Release{
+		Type: release.APIStringTypeReleases,
+		ID:   &r.ID,
+		Attributes: &app.ReleaseAttributes{
+			Name:        &r.Name,
+			Description: &r.Description,
+			TargetDate:  r.TargetDate,
+			Status:      &r.Status,
+			ReleasedAt:  r.ReleasedAt,
+			ArchivedAt:  r.ArchivedAt,
+			CreatedAt:   &r.CreatedAt,
+			UpdatedAt:   &r.UpdatedAt,
+			Version:     &r.Version,
+		},
+		Relationships: &app.ReleaseRelations{
+			Space: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &space.SpaceType,
+					ID:   &spaceID,
+				},
+				Links: &app.GenericLinks{
+					Self:    &spaceRelatedURL,
+					Related: &spaceRelatedURL,
+				},
+			},
+		},
+		Links: &app.GenericLinks{
+			Self:    &relatedURL,
+			Related: &relatedURL,
+		},
+	}
+}
+
+// ConvertReleaseSimple converts a release ID into a Generic Relationship
+func ConvertReleaseSimple(request *http.Request, releaseID interface{}) *app.GenericData {
+	i := fmt.Sprint(releaseID)
+	return &app.GenericData{
+		Type: ptr.String(release.APIStringTypeReleases),
+		ID:   &i,
+	}
+}
+
+// ConvertReleaseReadiness converts the readiness report of a release from
+// internal to external REST representation
+func ConvertReleaseReadiness(request *http.Request, spaceID, releaseID uuid.UUID, readiness release.Readiness) *app.ReleaseReadiness {
+	relatedURL := rest.AbsoluteURL(request, app.ReleaseHref(spaceID, releaseID)+"/readiness")
+	attrs := &app.ReleaseReadinessAttributes{
+		Total:    readiness.Total,
+		Open:     readiness.Open,
+		Closed:   readiness.Closed,
+		Types:    make([]*app.ReleaseTypeCount, len(readiness.Types)),
+		Blocking: make([]*app.RelationGeneric, len(readiness.Blocking)),
+	}
+	for i, t := range readiness.Types {
+		attrs.Types[i] = &app.ReleaseTypeCount{
+			WorkItemType: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: ptr.String(APIStringTypeWorkItemType),
+					ID:   ptr.String(t.TypeID.String()),
+				},
+				Links: &app.GenericLinks{
+					Self: ptr.String(rest.AbsoluteURL(request, app.WorkitemtypeHref(t.TypeID))),
+				},
+			},
+			Name:   t.TypeName,
+			Open:   t.Open,
+			Closed: t.Closed,
+		}
+	}
+	for i, item := range readiness.Blocking {
+		data, links := ConvertWorkItemSimple(request, item.ID)
+		attrs.Blocking[i] = &app.RelationGeneric{
+			Data:  data,
+			Links: links,
+		}
+	}
+	return &app.ReleaseReadiness{
+		Type:       "release-readiness",
+		ID:         releaseID,
+		Attributes: attrs,
+		Links: &app.GenericLinks{
+			Self: &relatedURL,
+		},
+	}
+}
@@ -146,6 +146,8 @@ func (c *SearchController) Show(ctx *app.ShowSearchContext) error {
 	search.RegisterAsKnownURL(search.HostRegistrationKeyForListWI, urlRegexString)
 	urlRegexString = fmt.Sprintf("(?P<domain>%s)(?P<path>/work-item/board/detail/)(?P<id>\\d*)", hostString)
 	search.RegisterAsKnownURL(search.HostRegistrationKeyForBoardWI, urlRegexString)
+	urlRegexString = fmt.Sprintf("(?P<domain>%s)(?P<path>/api/workitemkeys/)(?P<key>[A-Za-z][A-Za-z0-9]{1,9}-\\d+)", hostString)
+	search.RegisterAsKnownURL(search.HostRegistrationKeyForWorkItemKey, urlRegexString)
 
 	if ctx.FilterExpression != nil {
 		var result []workitem.WorkItem
@@ -201,6 +203,23 @@ func (c *SearchController) Show(ctx *app.ShowSearchContext) error {
 		if err != nil {
 			return errs.Wrap(err, "failed to enrich work item list")
 		}
+		// append work items referenced by fields of kind "workitem" unless
+		// they are already included as ancestors
+		referenced, err := includeReferencedWorkItems(ctx, ctx.Request, c.db, wits, result)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		alreadyIncluded := map[uuid.UUID]struct{}{}
+		for _, ifObj := range response.Included {
+			if wi, ok := ifObj.(app.WorkItem); ok && wi.ID != nil {
+				alreadyIncluded[*wi.ID] = struct{}{}
+			}
+		}
+		for _, ifObj := range referenced {
+			if _, ok := alreadyIncluded[*ifObj.(app.WorkItem).ID]; !ok {
+				response.Included = append(response.Included, ifObj)
+			}
+		}
 		setPagingLinks(response.Links, buildAbsoluteURL(ctx.Request), len(result), offset, limit, count, "filter[expression]="+*ctx.FilterExpression)
 
 		// Sort "data" by name or ID if no title given
@@ -279,10 +298,15 @@ func (c *SearchController) Show(ctx *app.ShowSearchContext) error {
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
 	}
+	included, err := includeReferencedWorkItems(ctx, ctx.Request, c.db, wits, result)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
 	response := app.SearchWorkItemList{
-		Links: &app.PagingLinks{},
-		Meta:  &app.WorkItemListResponseMeta{TotalCount: count},
-		Data:  wis,
+		Links:    &app.PagingLinks{},
+		Meta:     &app.WorkItemListResponseMeta{TotalCount: count},
+		Data:     wis,
+		Included: included,
 	}
 	setPagingLinks(response.Links, buildAbsoluteURL(ctx.Request), len(result), offset, limit, count, "q="+*ctx.Q)
 	return ctx.OK(&response)
@@ -98,6 +98,9 @@ func (c *SpaceController) Create(ctx *app.CreateSpaceContext) error {
 		if reqSpace.Attributes.Description != nil {
 			newSpace.Description = *reqSpace.Attributes.Description
 		}
+		if reqSpace.Attributes.Key != nil {
+			newSpace.Key = *reqSpace.Attributes.Key
+		}
 		// if given, use space template from relationship
 		if reqSpace.Relationships != nil && reqSpace.Relationships.SpaceTemplate != nil && reqSpace.Relationships.SpaceTemplate.Data != nil {
 			stID := reqSpace.Relationships.SpaceTemplate.Data.ID
@@ -540,6 +543,9 @@ func (c *SpaceController) Update(ctx *app.UpdateSpaceContext) error {
 		if ctx.Payload.Data.Attributes.Description != nil {
 			s.Description = *ctx.Payload.Data.Attributes.Description
 		}
+		if ctx.Payload.Data.Attributes.Key != nil {
+			s.Key = *ctx.Payload.Data.Attributes.Key
+		}
 
 		s, err = appl.Spaces().Save(ctx.Context, s)
 		return err
@@ -620,6 +626,9 @@ func ConvertSpaceToModel(appSpace app.Space) space.Space {
 		if appSpace.Attributes.Description != nil {
 			modelSpace.Description = *appSpace.Attributes.Description
 		}
+		if appSpace.Attributes.Key != nil {
+			modelSpace.Key = *appSpace.Attributes.Key
+		}
 	}
 	if appSpace.Relationships != nil && appSpace.Relationships.OwnedBy != nil &&
 		appSpace.Relationships.OwnedBy.Data != nil && appSpace.Relationships.OwnedBy.Data.ID != nil {
@@ -769,6 +778,9 @@ func ConvertSpaceFromModel(request *http.Request, sp space.Space, options ...Spa
 			SpaceTemplate: app.NewSpaceTemplateRelation(sp.SpaceTemplateID, relatedSpaceTemplateURL),
 		},
 	}
+	if sp.Key != "" {
+		s.Attributes.Key = &sp.Key
+	}
 	// apply options (ie, if extra content needs to be provided in the response element)
 	for _, option := range options {
 		err := option(request, &sp, s)
@@ -0,0 +1,276 @@
+package controller
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/fabric8-services/fabric8-wit/workitem/event"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// SpaceActivitiesController implements the space_activities resource.
+type SpaceActivitiesController struct {
+	*goa.Controller
+	db     application.DB
+	config SpaceActivitiesControllerConfig
+}
+
+// SpaceActivitiesControllerConfig the config interface for the SpaceActivitiesController
+type SpaceActivitiesControllerConfig interface {
+	GetCacheControlEvents() string
+}
+
+// NewSpaceActivitiesController creates a space_activities controller.
+func NewSpaceActivitiesController(service *goa.Service, db application.DB, config SpaceActivitiesControllerConfig) *SpaceActivitiesController {
+	return &SpaceActivitiesController{
+		Controller: service.NewController("SpaceActivitiesController"),
+		db:         db,
+		config:     config,
+	}
+}
+
+// List runs the list action.
+func (c *SpaceActivitiesController) List(ctx *app.ListSpaceActivitiesContext) error {
+	filter, err := newActivityFilter(ctx.FilterActor, ctx.FilterType, ctx.FilterSince, ctx.FilterUntil)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
+	var activities []event.Activity
+	var count int
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.Spaces().CheckExists(ctx, ctx.SpaceID); err != nil {
+			return errs.Wrapf(err, "failed to find space %s", ctx.SpaceID)
+		}
+		activities, count, err = appl.Activities().List(ctx, ctx.SpaceID, *filter, &offset, &limit)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.ConditionalEntities(activities, c.config.GetCacheControlEvents, func() error {
+		res := &app.ActivityList{
+			Data:  ConvertActivities(ctx.Request, activities),
+			Meta:  &app.WorkItemListResponseMeta{TotalCount: count},
+			Links: &app.PagingLinks{},
+		}
+		setPagingLinks(res.Links, buildAbsoluteURL(ctx.Request), len(activities), offset, limit, count, activityFilterQuery(ctx.Request)...)
+		return ctx.OK(res)
+	})
+}
+
+// Feed runs the feed action.
+func (c *SpaceActivitiesController) Feed(ctx *app.FeedSpaceActivitiesContext) error {
+	filter, err := newActivityFilter(ctx.FilterActor, ctx.FilterType, ctx.FilterSince, ctx.FilterUntil)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
+	var s *space.Space
+	var activities []event.Activity
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		s, err = appl.Spaces().Load(ctx, ctx.SpaceID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to load space %s", ctx.SpaceID)
+		}
+		activities, _, err = appl.Activities().List(ctx, ctx.SpaceID, *filter, &offset, &limit)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	feed, err := ConvertActivitiesToAtom(ctx.Request, *s, activities)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	ctx.ResponseData.Header().Set("Content-Type", "application/atom+xml")
+	return ctx.OK(feed)
+}
+
+// newActivityFilter builds an activity filter from the optional query
+// parameters of the space_activities actions. The type filter is a comma
+// separated list of activity kinds.
+func newActivityFilter(actor *uuid.UUID, kinds *string, since, until *time.Time) (*event.ActivityFilter, error) {
+	filter := event.ActivityFilter{
+		ActorID: actor,
+		Since:   since,
+		Until:   until,
+	}
+	if kinds != nil && strings.TrimSpace(*kinds) != "" {
+		for _, k := range strings.Split(*kinds, ",") {
+			kind, err := event.ParseActivityKind(strings.TrimSpace(k))
+			if err != nil {
+				return nil, err
+			}
+			filter.Kinds = append(filter.Kinds, kind)
+		}
+	}
+	return &filter, nil
+}
+
+// activityFilterQuery returns the filter parameters of the given request so
+// that they can be kept in the paging links
+func activityFilterQuery(request *http.Request) []string {
+	var result []string
+	for _, name := range []string{"filter[actor]", "filter[type]", "filter[since]", "filter[until]"} {
+		if v := request.URL.Query().Get(name); v != "" {
+			result = append(result, name+"="+v)
+		}
+	}
+	return result
+}
+
+// ConvertActivities converts from internal to external REST representation
+func ConvertActivities(request *http.Request, activities []event.Activity) []*app.Activity {
+	res := make([]*app.Activity, len(activities))
+	for i, a := range activities {
+		res[i] = ConvertActivity(request, a)
+	}
+	return res
+}
+
+// ConvertActivity converts from internal to external REST representation
+func ConvertActivity(request *http.Request, a event.Activity) *app.Activity {
+	res := &app.Activity{
+		Type: event.APIStringTypeActivities,
+		ID:   a.ID,
+		Attributes: &app.ActivityAttributes{
+			Kind:      string(a.Kind),
+			Action:    a.Action.String(),
+			Timestamp: a.Timestamp,
+		},
+		Relationships: &app.ActivityRelations{
+			Target: convertActivityTarget(request, a),
+		},
+	}
+	if a.ActorID != nil {
+		data, links := ConvertUserSimple(request, *a.ActorID)
+		res.Relationships.Actor = &app.RelationGeneric{
+			Data:  data,
+			Links: links,
+		}
+	}
+	if a.WorkItemID != nil {
+		relatedURL := rest.AbsoluteURL(request, app.WorkitemHref(*a.WorkItemID))
+		res.Relationships.Workitem = &app.RelationGeneric{
+			Data: &app.GenericData{
+				ID:   ptr.String(a.WorkItemID.String()),
+				Type: ptr.String(APIStringTypeWorkItem),
+			},
+			Links: &app.GenericLinks{
+				Self:    &relatedURL,
+				Related: &relatedURL,
+			},
+		}
+	}
+	return res
+}
+
+// convertActivityTarget returns the relationship to the entity that changed
+func convertActivityTarget(request *http.Request, a event.Activity) *app.RelationGeneric {
+	var t, relatedURL string
+	switch a.Kind {
+	case event.ActivityKindWorkItem:
+		t = APIStringTypeWorkItem
+		relatedURL = rest.AbsoluteURL(request, app.WorkitemHref(a.TargetID))
+	case event.ActivityKindComment:
+		t = APIStringTypeComments
+		relatedURL = rest.AbsoluteURL(request, app.CommentsHref(a.TargetID))
+	case event.ActivityKindLink:
+		t = link.EndpointWorkItemLinks
+		relatedURL = rest.AbsoluteURL(request, app.WorkItemLinkHref(a.TargetID))
+	case event.ActivityKindIteration:
+		data, links := ConvertIterationSimple(request, a.TargetID)
+		return &app.RelationGeneric{Data: data, Links: links}
+	}
+	return &app.RelationGeneric{
+		Data: &app.GenericData{
+			ID:   ptr.String(a.TargetID.String()),
+			Type: ptr.String(t),
+		},
+		Links: &app.GenericLinks{
+			Self:    &relatedURL,
+			Related: &relatedURL,
+		},
+	}
+}
+
+// atomFeed is the Atom (RFC 4287) representation of the activities of a space
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Author  *atomAuthor `xml:"author,omitempty"`
+}
+
+// ConvertActivitiesToAtom renders the given activities of the given space as
+// an Atom feed.
+func ConvertActivitiesToAtom(request *http.Request, s space.Space, activities []event.Activity) ([]byte, error) {
+	feed := atomFeed{
+		ID:      "urn:uuid:" + s.ID.String(),
+		Title:   fmt.Sprintf("Activities in %s", s.Name),
+		Updated: s.UpdatedAt.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: buildAbsoluteURL(request), Rel: "self"},
+			{Href: rest.AbsoluteURL(request, app.SpaceHref(s.ID)), Rel: "alternate"},
+		},
+		Entries: make([]atomEntry, len(activities)),
+	}
+	if len(activities) > 0 {
+		// activities are sorted with the most recent one first
+		feed.Updated = activities[0].Timestamp.UTC().Format(time.RFC3339)
+	}
+	for i, a := range activities {
+		target := convertActivityTarget(request, a)
+		entry := atomEntry{
+			ID:      fmt.Sprintf("urn:uuid:%s:%s:%s", a.ID, a.Kind, a.Action),
+			Title:   fmt.Sprintf("%s %s", a.Action, a.Kind),
+			Updated: a.Timestamp.UTC().Format(time.RFC3339),
+			Link:    atomLink{Href: *target.Links.Related},
+		}
+		if a.ActorID != nil {
+			entry.Author = &atomAuthor{
+				Name: a.ActorID.String(),
+				URI:  rest.AbsoluteURL(request, app.UsersHref(a.ActorID.String())),
+			}
+		}
+		feed.Entries[i] = entry
+	}
+	out, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to render activities as Atom feed")
+	}
+	return append([]byte(xml.Header), out...), nil
+}
@@ -0,0 +1,166 @@
+package controller_test
+
+import (
+	"encoding/xml"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem/event"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSpaceActivitiesREST struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunSpaceActivitiesREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestSpaceActivitiesREST{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *TestSpaceActivitiesREST) UnSecuredController() (*goa.Service, *SpaceActivitiesController) {
+	svc := goa.New("SpaceActivities-Service")
+	return svc, NewSpaceActivitiesController(svc, s.GormDB, s.Configuration)
+}
+
+func (s *TestSpaceActivitiesREST) TestList() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(2), tf.Comments(1), tf.Iterations(1))
+		svc, ctrl := s.UnSecuredController()
+		// when
+		res, list := test.ListSpaceActivitiesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, nil, nil, nil, nil, nil, nil, nil)
+		// then
+		require.Len(t, list.Data, 4)
+		assert.Equal(t, 4, list.Meta.TotalCount)
+		assertResponseHeaders(t, res)
+		kinds := map[string]int{}
+		for _, a := range list.Data {
+			kinds[a.Attributes.Kind]++
+			assert.Equal(t, "create", a.Attributes.Action)
+			require.NotNil(t, a.Relationships.Target)
+		}
+		assert.Equal(t, map[string]int{"workitem": 2, "comment": 1, "iteration": 1}, kinds)
+	})
+
+	s.T().Run("ok - iteration update has its own ID", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Iterations(1))
+		err := s.DB.Model(&iteration.Iteration{}).Where("id = ?", fxt.Iterations[0].ID).UpdateColumn("updated_at", time.Now().Add(time.Minute)).Error
+		require.NoError(t, err)
+		svc, ctrl := s.UnSecuredController()
+		// when
+		_, list := test.ListSpaceActivitiesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, nil, ptr.String("iteration"), nil, nil, nil, nil, nil)
+		// then
+		require.Len(t, list.Data, 2)
+		assert.Equal(t, "update", list.Data[0].Attributes.Action)
+		assert.Equal(t, "create", list.Data[1].Attributes.Action)
+		assert.NotEqual(t, list.Data[0].ID, list.Data[1].ID)
+		for _, a := range list.Data {
+			assert.Equal(t, fxt.Iterations[0].ID.String(), *a.Relationships.Target.Data.ID)
+		}
+	})
+
+	s.T().Run("ok - filtered by actor and paged", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(3))
+		svc, ctrl := s.UnSecuredController()
+		// when
+		_, list := test.ListSpaceActivitiesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, &fxt.Identities[0].ID, nil, nil, nil, ptr.Int(2), ptr.String("0"), nil, nil)
+		// then
+		require.Len(t, list.Data, 2)
+		assert.Equal(t, 3, list.Meta.TotalCount)
+		require.NotNil(t, list.Links.Next)
+		assert.Contains(t, *list.Links.Next, "filter[actor]="+fxt.Identities[0].ID.String())
+	})
+
+	s.T().Run("not modified", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		svc, ctrl := s.UnSecuredController()
+		res, _ := test.ListSpaceActivitiesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, nil, nil, nil, nil, nil, nil, nil)
+		ifNoneMatch := res.Header()["Etag"][0]
+		// when/then
+		test.ListSpaceActivitiesNotModified(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, nil, nil, nil, nil, nil, nil, &ifNoneMatch)
+	})
+
+	s.T().Run("unknown type", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		svc, ctrl := s.UnSecuredController()
+		test.ListSpaceActivitiesBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, nil, ptr.String("workitem,foo"), nil, nil, nil, nil, nil)
+	})
+
+	s.T().Run("unknown space", func(t *testing.T) {
+		svc, ctrl := s.UnSecuredController()
+		test.ListSpaceActivitiesNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), nil, nil, nil, nil, nil, nil, nil, nil)
+	})
+}
+
+func (s *TestSpaceActivitiesREST) TestFeed() {
+	type feed struct {
+		ID      string `xml:"id"`
+		Title   string `xml:"title"`
+		Entries []struct {
+			ID     string `xml:"id"`
+			Title  string `xml:"title"`
+			Author *struct {
+				URI string `xml:"uri"`
+			} `xml:"author"`
+		} `xml:"entry"`
+	}
+
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1), tf.Comments(1))
+		svc, ctrl := s.UnSecuredController()
+		// when
+		rw := test.FeedSpaceActivitiesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, nil, nil, nil, nil, nil)
+		// then
+		assert.Equal(t, "application/atom+xml", rw.Header().Get("Content-Type"))
+		var f feed
+		require.NoError(t, xml.Unmarshal(rw.(*httptest.ResponseRecorder).Body.Bytes(), &f))
+		assert.Equal(t, "urn:uuid:"+fxt.Spaces[0].ID.String(), f.ID)
+		assert.Equal(t, "Activities in "+fxt.Spaces[0].Name, f.Title)
+		require.Len(t, f.Entries, 2)
+		assert.Equal(t, "create "+string(event.ActivityKindComment), f.Entries[0].Title)
+		assert.Equal(t, "create "+string(event.ActivityKindWorkItem), f.Entries[1].Title)
+		assert.NotEqual(t, f.Entries[0].ID, f.Entries[1].ID)
+		for _, e := range f.Entries {
+			require.NotNil(t, e.Author)
+			assert.Contains(t, e.Author.URI, fxt.Identities[0].ID.String())
+		}
+	})
+
+	s.T().Run("ok - filtered by type", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1), tf.Comments(1))
+		svc, ctrl := s.UnSecuredController()
+		rw := test.FeedSpaceActivitiesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, nil, ptr.String("comment"), nil, nil, nil)
+		var f feed
+		require.NoError(t, xml.Unmarshal(rw.(*httptest.ResponseRecorder).Body.Bytes(), &f))
+		require.Len(t, f.Entries, 1)
+	})
+
+	s.T().Run("unknown type", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		svc, ctrl := s.UnSecuredController()
+		test.FeedSpaceActivitiesBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, nil, ptr.String("foo"), nil, nil, nil)
+	})
+
+	s.T().Run("unknown space", func(t *testing.T) {
+		svc, ctrl := s.UnSecuredController()
+		test.FeedSpaceActivitiesNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), nil, nil, nil, nil, nil, nil)
+	})
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/workitem/recurrence"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// SpaceRecurrencesController implements the space_recurrences resource.
+type SpaceRecurrencesController struct {
+	*goa.Controller
+	db        application.DB
+	scheduler *recurrence.Scheduler
+}
+
+// NewSpaceRecurrencesController creates a space_recurrences controller.
+func NewSpaceRecurrencesController(service *goa.Service, db application.DB, scheduler *recurrence.Scheduler) *SpaceRecurrencesController {
+	return &SpaceRecurrencesController{
+		Controller: service.NewController("SpaceRecurrencesController"),
+		db:         db,
+		scheduler:  scheduler,
+	}
+}
+
+// List runs the list action.
+func (c *SpaceRecurrencesController) List(ctx *app.ListSpaceRecurrencesContext) error {
+	var recurrences []recurrence.Recurrence
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.Spaces().CheckExists(ctx, ctx.SpaceID); err != nil {
+			return err
+		}
+		var err error
+		recurrences, err = appl.Recurrences().List(ctx, ctx.SpaceID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.RecurrenceList{
+		Data: ConvertRecurrences(ctx.Request, recurrences),
+		Meta: &app.RecurrenceListMeta{TotalCount: len(recurrences)},
+	})
+}
+
+// Create runs the create action.
+func (c *SpaceRecurrencesController) Create(ctx *app.CreateSpaceRecurrencesContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload == nil || ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		return appl.Spaces().CheckExists(ctx, ctx.SpaceID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if err := authorizeRecurrences(ctx, ctx.SpaceID); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	r := recurrence.Recurrence{
+		SpaceID:   ctx.SpaceID,
+		CreatorID: *identityID,
+		Enabled:   true,
+	}
+	if err := updateRecurrenceFromPayload(&r, ctx.Payload.Data); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if r.TypeID == uuid.Nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.relationships.workitemtype", nil).Expected("not nil"))
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		return appl.Recurrences().Create(ctx, &r)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	c.reschedule()
+	ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.Request, app.RecurrencesHref(r.ID)))
+	return ctx.Created(&app.RecurrenceSingle{
+		Data: ConvertRecurrence(ctx.Request, r),
+	})
+}
+
+// reschedule makes the scheduler pick up the new recurrence
+func (c *SpaceRecurrencesController) reschedule() {
+	if c.scheduler != nil {
+		// the runs outlive the request, so they must not use its context
+		c.scheduler.ScheduleAll(context.Background())
+	}
+}
@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/fabric8-services/fabric8-wit/app"
@@ -9,13 +11,16 @@ import (
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
 	"github.com/fabric8-services/fabric8-wit/log"
 	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/ptr"
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
 	"github.com/fabric8-services/fabric8-wit/rest"
 	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
 )
 
 type trackerConfiguration interface {
 	GetGithubAuthToken() string
+	GetGitLabAuthToken() string
 }
 
 // TrackerController implements the tracker resource.
@@ -29,6 +34,7 @@ type TrackerController struct {
 func GetAccessTokens(configuration trackerConfiguration) map[string]string {
 	tokens := map[string]string{
 		remoteworkitem.ProviderGithub: configuration.GetGithubAuthToken(),
+		remoteworkitem.ProviderGitLab: configuration.GetGitLabAuthToken(),
 		// add tokens for other types
 	}
 	return tokens
@@ -59,6 +65,10 @@ func (c *TrackerController) Create(ctx *app.CreateTrackerContext) error {
 			URL:  ctx.Payload.Data.Attributes.URL,
 			Type: ctx.Payload.Data.Attributes.Type,
 		}
+		updateTrackerSyncAttributes(tracker, ctx.Payload.Data.Attributes)
+		if err := updateTrackerMappings(tracker, ctx.Payload.Data); err != nil {
+			return err
+		}
 		return appl.Trackers().Create(ctx.Context, tracker)
 	})
 	if err != nil {
@@ -133,6 +143,45 @@ func (c *TrackerController) List(ctx *app.ListTrackerContext) error {
 	return ctx.OK(res)
 }
 
+// maxWebhookBodySize is the maximum size of the webhook requests
+const maxWebhookBodySize = 1 << 20
+
+// Webhook runs the webhook action.
+func (c *TrackerController) Webhook(ctx *app.WebhookTrackerContext) error {
+	body, err := ioutil.ReadAll(io.LimitReader(ctx.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("body", nil).Expected("readable body"))
+	}
+	var tracker *remoteworkitem.Tracker
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		tracker, err = appl.Trackers().Load(ctx.Context, ctx.ID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if err := remoteworkitem.VerifyWebhook(*tracker, ctx.Request.Header, ctx.Request.URL.Query(), body); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	authToken := GetAccessTokens(c.configuration)[tracker.Type]
+	item, err := remoteworkitem.WebhookItem(*tracker, body, authToken)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if item == nil {
+		return ctx.NoContent()
+	}
+	if _, err := c.scheduler.SyncWebhookItem(ctx, *tracker, *item, authToken); err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":        err,
+			"tracker_id": tracker.ID,
+			"remote_id":  item.ID,
+		}, "failed to sync the item of the webhook")
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.NoContent()
+}
+
 // ConvertTrackers from internal to external REST representation
 func ConvertTrackers(request *http.Request, trackers []remoteworkitem.Tracker) []*app.Tracker {
 	var ls = []*app.Tracker{}
@@ -164,6 +213,10 @@ func (c *TrackerController) Update(ctx *app.UpdateTrackerContext) error {
 		if &ctx.Payload.Data.Attributes.Type != nil {
 			trkr.Type = ctx.Payload.Data.Attributes.Type
 		}
+		updateTrackerSyncAttributes(trkr, ctx.Payload.Data.Attributes)
+		if err := updateTrackerMappings(trkr, ctx.Payload.Data); err != nil {
+			return err
+		}
 		_, err = appl.Trackers().Save(ctx.Context, trkr)
 		return err
 	})
@@ -183,16 +236,87 @@ func ConvertTracker(request *http.Request, tracker remoteworkitem.Tracker) *app.
 		Type: trackerStringType,
 		ID:   &tracker.ID,
 		Attributes: &app.TrackerAttributes{
-			URL:  tracker.URL,
-			Type: tracker.Type,
+			URL:            tracker.URL,
+			Type:           tracker.Type,
+			WriteBack:      ptr.Bool(tracker.WriteBack),
+			ConflictPolicy: ptr.String(tracker.ConflictPolicy),
 		},
+		Relationships: &app.TrackerRelations{},
 		Links: &app.GenericLinks{
 			Self: &selfURL,
 		},
 	}
+	for _, m := range tracker.FieldMappings {
+		mapping := &app.TrackerFieldMapping{
+			Expression: m.Expression,
+			Field:      m.Field,
+			Converter:  m.Converter,
+			Values:     m.Values,
+		}
+		if m.Markup != "" {
+			mapping.Markup = ptr.String(m.Markup)
+		}
+		t.Attributes.FieldMappings = append(t.Attributes.FieldMappings, mapping)
+	}
+	if tracker.WorkItemTypeID != nil {
+		typeID := tracker.WorkItemTypeID.String()
+		typeURL := rest.AbsoluteURL(request, app.WorkitemtypeHref(typeID))
+		t.Relationships.Workitemtype = &app.RelationGeneric{
+			Data: &app.GenericData{
+				Type: ptr.String(APIStringTypeWorkItemType),
+				ID:   &typeID,
+			},
+			Links: &app.GenericLinks{
+				Self:    &typeURL,
+				Related: &typeURL,
+			},
+		}
+	}
 	return t
 }
 
+// updateTrackerMappings copies the work item type and the field mappings of
+// the given tracker data to the tracker
+func updateTrackerMappings(tracker *remoteworkitem.Tracker, data *app.Tracker) error {
+	if data.Attributes.FieldMappings != nil {
+		tracker.FieldMappings = make(remoteworkitem.FieldMappings, len(data.Attributes.FieldMappings))
+		for i, m := range data.Attributes.FieldMappings {
+			tracker.FieldMappings[i] = remoteworkitem.FieldMapping{
+				Expression: m.Expression,
+				Field:      m.Field,
+				Converter:  m.Converter,
+				Values:     m.Values,
+			}
+			if m.Markup != nil {
+				tracker.FieldMappings[i].Markup = *m.Markup
+			}
+		}
+	}
+	if data.Relationships != nil && data.Relationships.Workitemtype != nil &&
+		data.Relationships.Workitemtype.Data != nil && data.Relationships.Workitemtype.Data.ID != nil {
+		typeID, err := uuid.FromString(*data.Relationships.Workitemtype.Data.ID)
+		if err != nil {
+			return errors.NewBadParameterError("data.relationships.workitemtype.data.id", *data.Relationships.Workitemtype.Data.ID).Expected("UUID")
+		}
+		tracker.WorkItemTypeID = &typeID
+	}
+	return nil
+}
+
+// updateTrackerSyncAttributes copies the write-back settings of the given
+// attributes to the tracker
+func updateTrackerSyncAttributes(tracker *remoteworkitem.Tracker, attrs *app.TrackerAttributes) {
+	if attrs.WriteBack != nil {
+		tracker.WriteBack = *attrs.WriteBack
+	}
+	if attrs.ConflictPolicy != nil {
+		tracker.ConflictPolicy = *attrs.ConflictPolicy
+	}
+	if attrs.WebhookSecret != nil {
+		tracker.WebhookSecret = *attrs.WebhookSecret
+	}
+}
+
 func validateCreateTrackerPayload(ctx *app.CreateTrackerContext) error {
 	if ctx.Payload.Data.Attributes.URL == "" {
 		return errors.NewBadParameterError("URL", "").Expected("not nil")
@@ -2,9 +2,11 @@ package controller
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
 	"github.com/fabric8-services/fabric8-wit/log"
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
@@ -15,6 +17,7 @@ import (
 
 type trackerQueryConfiguration interface {
 	GetGithubAuthToken() string
+	GetGitLabAuthToken() string
 }
 
 // TrackerqueryController implements the trackerquery resource.
@@ -28,6 +31,7 @@ type TrackerqueryController struct {
 func getAccessTokensForTrackerQuery(configuration trackerQueryConfiguration) map[string]string {
 	tokens := map[string]string{
 		remoteworkitem.ProviderGithub: configuration.GetGithubAuthToken(),
+		remoteworkitem.ProviderGitLab: configuration.GetGitLabAuthToken(),
 		// add tokens for other types
 	}
 	return tokens
@@ -155,3 +159,61 @@ func (c *TrackerqueryController) List(ctx *app.ListTrackerqueryContext) error {
 	}
 	return nil
 }
+
+// ShowSyncStatus runs the show-sync-status action.
+func (c *TrackerqueryController) ShowSyncStatus(ctx *app.ShowSyncStatusTrackerqueryContext) error {
+	var state *remoteworkitem.SyncState
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		state, err = appl.TrackerQuerySyncStates().Load(ctx.Context, ctx.ID)
+		if err != nil {
+			cause := errs.Cause(err)
+			switch cause.(type) {
+			case remoteworkitem.NotFoundError:
+				return goa.ErrNotFound(err.Error())
+			default:
+				return errs.WithStack(err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(ConvertSyncState(*state))
+}
+
+// Sync runs the sync action.
+func (c *TrackerqueryController) Sync(ctx *app.SyncTrackerqueryContext) error {
+	accessTokens := getAccessTokensForTrackerQuery(c.configuration)
+	state, err := c.scheduler.RunQuery(ctx, ctx.ID, accessTokens)
+	if err != nil {
+		cause := errs.Cause(err)
+		switch cause.(type) {
+		case remoteworkitem.NotFoundError:
+			return jsonapi.JSONErrorResponse(ctx, goa.ErrNotFound(err.Error()))
+		case remoteworkitem.BadParameterError:
+			return jsonapi.JSONErrorResponse(ctx, goa.ErrBadRequest(err.Error()))
+		case remoteworkitem.VersionConflictError:
+			return jsonapi.JSONErrorResponse(ctx, errors.NewVersionConflictError(err.Error()))
+		default:
+			return jsonapi.JSONErrorResponse(ctx, goa.ErrInternal(err.Error()))
+		}
+	}
+	return ctx.OK(ConvertSyncState(*state))
+}
+
+// ConvertSyncState converts the sync state of a tracker query from internal
+// to external REST representation
+func ConvertSyncState(state remoteworkitem.SyncState) *app.TrackerQuerySyncStatus {
+	return &app.TrackerQuerySyncStatus{
+		TrackerQueryID: strconv.FormatUint(state.TrackerQueryID, 10),
+		LastRunAt:      state.LastRunAt,
+		LastSuccessAt:  state.LastSuccessAt,
+		HighWaterMark:  state.HighWaterMark,
+		FetchedCount:   state.FetchedCount,
+		SyncedCount:    state.SyncedCount,
+		FailedCount:    state.FailedCount,
+		LastError:      state.LastError,
+	}
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/watcher"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// watcherTargetTypes maps the target types in the URL to the watched entities
+var watcherTargetTypes = map[string]watcher.TargetType{
+	"workitems":  watcher.TargetWorkItem,
+	"spaces":     watcher.TargetSpace,
+	"areas":      watcher.TargetArea,
+	"iterations": watcher.TargetIteration,
+}
+
+// WatchersController implements the watchers resource.
+type WatchersController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewWatchersController creates a watchers controller.
+func NewWatchersController(service *goa.Service, db application.DB) *WatchersController {
+	return &WatchersController{
+		Controller: service.NewController("WatchersController"),
+		db:         db,
+	}
+}
+
+func watcherTargetType(targetType string) (watcher.TargetType, error) {
+	t, ok := watcherTargetTypes[targetType]
+	if !ok {
+		return "", errors.NewBadParameterError("targetType", targetType).Expected("workitems, spaces, areas or iterations")
+	}
+	return t, nil
+}
+
+// List runs the list action.
+func (c *WatchersController) List(ctx *app.ListWatchersContext) error {
+	targetType, err := watcherTargetType(ctx.TargetType)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	var identityIDs []uuid.UUID
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		identityIDs, err = appl.Watchers().List(ctx, targetType, ctx.TargetID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.WatcherList{
+		Data: ConvertWatchers(ctx.Request, identityIDs),
+	})
+}
+
+// Watch runs the watch action.
+func (c *WatchersController) Watch(ctx *app.WatchWatchersContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	targetType, err := watcherTargetType(ctx.TargetType)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		return appl.Watchers().Watch(ctx, targetType, ctx.TargetID, *identityID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK([]byte{})
+}
+
+// Unwatch runs the unwatch action.
+func (c *WatchersController) Unwatch(ctx *app.UnwatchWatchersContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	targetType, err := watcherTargetType(ctx.TargetType)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		return appl.Watchers().Unwatch(ctx, targetType, ctx.TargetID, *identityID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK([]byte{})
+}
+
+// ConvertWatchers converts a list of watching identities from internal to
+// external REST representation
+func ConvertWatchers(request *http.Request, identityIDs []uuid.UUID) []*app.Watcher {
+	result := make([]*app.Watcher, len(identityIDs))
+	for i, id := range identityIDs {
+		userURL := rest.AbsoluteURL(request, fmt.Sprintf("%s/%s", usersEndpoint, id))
+		result[i] = &app.Watcher{
+			Type: APIStringTypeUser,
+			ID:   id,
+			Links: &app.GenericLinks{
+				Self:    &userURL,
+				Related: &userURL,
+			},
+		}
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestWatchersREST struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunWatchersREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestWatchersREST{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *TestWatchersREST) UnSecuredController() (*goa.Service, *WatchersController) {
+	svc := goa.New("Watchers-Service")
+	return svc, NewWatchersController(svc, s.GormDB)
+}
+
+func (s *TestWatchersREST) SecuredController(fxt *tf.TestFixture, idx int) (*goa.Service, *WatchersController) {
+	svc := testsupport.ServiceAsUser("Watchers-Service", *fxt.Identities[idx])
+	return svc, NewWatchersController(svc, s.GormDB)
+}
+
+func (s *TestWatchersREST) TestWatch() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.Spaces(1))
+		svc, ctrl := s.SecuredController(fxt, 1)
+		// when
+		test.WatchWatchersOK(t, svc.Context, svc, ctrl, "spaces", fxt.Spaces[0].ID)
+		// then
+		_, list := test.ListWatchersOK(t, svc.Context, svc, ctrl, "spaces", fxt.Spaces[0].ID)
+		require.Len(t, list.Data, 1)
+		assert.Equal(t, fxt.Identities[1].ID, list.Data[0].ID)
+		assert.Equal(t, APIStringTypeUser, list.Data[0].Type)
+		require.NotNil(t, list.Data[0].Links)
+		assert.Contains(t, *list.Data[0].Links.Self, fxt.Identities[1].ID.String())
+	})
+	s.T().Run("watching twice is ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1), tf.Iterations(1))
+		svc, ctrl := s.SecuredController(fxt, 0)
+		test.WatchWatchersOK(t, svc.Context, svc, ctrl, "iterations", fxt.Iterations[0].ID)
+		// when
+		test.WatchWatchersOK(t, svc.Context, svc, ctrl, "iterations", fxt.Iterations[0].ID)
+		// then
+		_, list := test.ListWatchersOK(t, svc.Context, svc, ctrl, "iterations", fxt.Iterations[0].ID)
+		assert.Len(t, list.Data, 1)
+	})
+	s.T().Run("unknown target", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		svc, ctrl := s.SecuredController(fxt, 0)
+		// when/then
+		test.WatchWatchersNotFound(t, svc.Context, svc, ctrl, "workitems", uuid.NewV4())
+	})
+	s.T().Run("unauthorized", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		svc, ctrl := s.UnSecuredController()
+		// when/then
+		test.WatchWatchersUnauthorized(t, svc.Context, svc, ctrl, "spaces", fxt.Spaces[0].ID)
+	})
+}
+
+func (s *TestWatchersREST) TestUnwatch() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1), tf.Areas(1))
+		svc, ctrl := s.SecuredController(fxt, 0)
+		test.WatchWatchersOK(t, svc.Context, svc, ctrl, "areas", fxt.Areas[0].ID)
+		// when
+		test.UnwatchWatchersOK(t, svc.Context, svc, ctrl, "areas", fxt.Areas[0].ID)
+		// then
+		_, list := test.ListWatchersOK(t, svc.Context, svc, ctrl, "areas", fxt.Areas[0].ID)
+		assert.Empty(t, list.Data)
+	})
+	s.T().Run("not watching", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1), tf.Areas(1))
+		svc, ctrl := s.SecuredController(fxt, 0)
+		// when/then
+		test.UnwatchWatchersNotFound(t, svc.Context, svc, ctrl, "areas", fxt.Areas[0].ID)
+	})
+	s.T().Run("unauthorized", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Areas(1))
+		svc, ctrl := s.UnSecuredController()
+		// when/then
+		test.UnwatchWatchersUnauthorized(t, svc.Context, svc, ctrl, "areas", fxt.Areas[0].ID)
+	})
+}
+
+func (s *TestWatchersREST) TestList() {
+	s.T().Run("creator watches the work item", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+		svc, ctrl := s.UnSecuredController()
+		// when
+		_, list := test.ListWatchersOK(t, svc.Context, svc, ctrl, "workitems", fxt.WorkItems[0].ID)
+		// then
+		require.Len(t, list.Data, 1)
+		assert.Equal(t, fxt.Identities[0].ID, list.Data[0].ID)
+	})
+	s.T().Run("unknown target", func(t *testing.T) {
+		// given
+		svc, ctrl := s.UnSecuredController()
+		// when/then
+		test.ListWatchersNotFound(t, svc.Context, svc, ctrl, "spaces", uuid.NewV4())
+	})
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"path/filepath"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/attachment"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/id"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/space/authz"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+)
+
+// WorkItemAttachmentsController implements the work_item_attachments resource.
+type WorkItemAttachmentsController struct {
+	*goa.Controller
+	db     application.DB
+	store  attachment.BlobStore
+	config WorkItemAttachmentsControllerConfiguration
+}
+
+// WorkItemAttachmentsControllerConfiguration the configuration for the WorkItemAttachmentsController
+type WorkItemAttachmentsControllerConfiguration interface {
+	GetAttachmentsMaxSize() int64
+}
+
+// NewWorkItemAttachmentsController creates a work_item_attachments controller.
+func NewWorkItemAttachmentsController(service *goa.Service, db application.DB, store attachment.BlobStore, config WorkItemAttachmentsControllerConfiguration) *WorkItemAttachmentsController {
+	return &WorkItemAttachmentsController{
+		Controller: service.NewController("WorkItemAttachmentsController"),
+		db:         db,
+		store:      store,
+		config:     config,
+	}
+}
+
+// List runs the list action.
+func (c *WorkItemAttachmentsController) List(ctx *app.ListWorkItemAttachmentsContext) error {
+	var attachments []attachment.Attachment
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.WorkItems().CheckExists(ctx, ctx.WiID); err != nil {
+			return err
+		}
+		var err error
+		attachments, err = appl.Attachments().List(ctx, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.AttachmentList{
+		Data: ConvertAttachments(ctx.Request, attachments),
+	})
+}
+
+// Create runs the create action.
+func (c *WorkItemAttachmentsController) Create(ctx *app.CreateWorkItemAttachmentsContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	var wi *workitem.WorkItem
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		wi, err = appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	authorized, err := authz.Authorize(ctx, wi.SpaceID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not a space collaborator"))
+	}
+	fileHeader := ctx.Payload.File
+	a := attachment.Attachment{
+		WorkItemID: ctx.WiID,
+		Name:       filepath.Base(fileHeader.Filename),
+		Creator:    *identityID,
+	}
+	// let the content decide if the client didn't know the MIME type
+	if mimeType := fileHeader.Header.Get("Content-Type"); mimeType != "application/octet-stream" {
+		a.MimeType = mimeType
+	}
+	if ctx.Payload.CommentID != nil {
+		a.CommentID = id.NullUUID{UUID: *ctx.Payload.CommentID, Valid: true}
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("file", fileHeader.Filename))
+	}
+	defer file.Close()
+	if err := attachment.Upload(ctx, c.store, &a, file, c.config.GetAttachmentsMaxSize()); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		return appl.Attachments().Create(ctx, &a)
+	})
+	if err != nil {
+		// don't keep content without metadata
+		if deleteErr := c.store.Delete(ctx, a.StorageKey); deleteErr != nil {
+			log.Error(ctx, map[string]interface{}{
+				"storage_key": a.StorageKey,
+				"err":         deleteErr,
+			}, "unable to delete the content of the attachment")
+		}
+		return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "failed to attach %q to work item %s", a.Name, ctx.WiID))
+	}
+	res := &app.AttachmentSingle{
+		Data: ConvertAttachment(ctx.Request, a),
+	}
+	ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.Request, app.AttachmentsHref(a.ID)))
+	return ctx.Created(res)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/application"
 	"github.com/fabric8-services/fabric8-wit/comment"
+	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/id"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
 	"github.com/fabric8-services/fabric8-wit/login"
@@ -88,11 +89,14 @@ func (c *WorkItemCommentsController) Create(ctx *app.CreateWorkItemCommentsConte
 
 		err = appl.Comments().Create(ctx, &newComment, *currentUserIdentityID)
 		if err != nil {
+			if ok, _ := errors.IsBadParameterError(err); ok {
+				return err
+			}
 			return goa.ErrInternal(err.Error())
 		}
 
 		res := &app.CommentSingle{
-			Data: ConvertComment(ctx.Request, newComment),
+			Data: ConvertComment(ctx.Request, newComment, commentIncludeRepliesAndReactions(nil)),
 		}
 		return ctx.OK(res)
 	})
@@ -109,20 +113,38 @@ func (c *WorkItemCommentsController) Create(ctx *app.CreateWorkItemCommentsConte
 func (c *WorkItemCommentsController) List(ctx *app.ListWorkItemCommentsContext) error {
 	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
 	err := application.Transactional(c.db, func(appl application.Application) error {
-		err := appl.WorkItems().CheckExists(ctx, ctx.WiID)
+		wi, err := appl.WorkItems().LoadByID(ctx, ctx.WiID)
 		if err != nil {
 			return goa.ErrNotFound(err.Error())
 		}
-		comments, tc, err := appl.Comments().List(ctx, ctx.WiID, &offset, &limit)
+		// only moderators may see the deleted comments as tombstones
+		includeDeleted := ctx.IncludeDeleted != nil && *ctx.IncludeDeleted
+		if includeDeleted && !isSpaceModerator(ctx, wi.SpaceID) {
+			return errors.NewForbiddenError("only space collaborators can list deleted comments")
+		}
+		var comments []comment.Comment
+		var tc uint64
+		switch {
+		case ctx.TopLevel != nil && *ctx.TopLevel:
+			comments, tc, err = appl.Comments().ListTopLevel(ctx, ctx.WiID, includeDeleted, &offset, &limit)
+		case includeDeleted:
+			comments, tc, err = appl.Comments().ListIncludingDeleted(ctx, ctx.WiID, &offset, &limit)
+		default:
+			comments, tc, err = appl.Comments().List(ctx, ctx.WiID, &offset, &limit)
+		}
 		count := int(tc)
 		if err != nil {
 			return goa.ErrInternal(err.Error())
 		}
+		includeRepliesAndReactions, err := CommentIncludeRepliesAndReactions(ctx, appl, commentsRef(comments)...)
+		if err != nil {
+			return err
+		}
 		return ctx.ConditionalEntities(comments, c.config.GetCacheControlComments, func() error {
 			res := &app.CommentList{}
 			res.Data = []*app.Comment{}
 			res.Meta = &app.CommentListMeta{TotalCount: count}
-			res.Data = ConvertComments(ctx.Request, comments)
+			res.Data = ConvertComments(ctx.Request, comments, includeRepliesAndReactions)
 			res.Links = &app.PagingLinks{}
 			setPagingLinks(res.Links, buildAbsoluteURL(ctx.Request), len(comments), offset, limit, count)
 			return ctx.OK(res)
@@ -178,6 +178,12 @@ func ConvertEvent(ctx context.Context, appl application.Application, req *http.R
 		case workitem.KindCodebase:
 			data, _ := ConvertCodebaseSimple(req, val)
 			return data, true
+		case workitem.KindWorkItem:
+			data, _ := ConvertWorkItemSimple(req, val)
+			return data, true
+		case workitem.KindRelease:
+			data := ConvertReleaseSimple(req, val)
+			return data, true
 		}
 		return nil, false
 	}
@@ -190,6 +196,13 @@ func ConvertEvent(ctx context.Context, appl application.Application, req *http.R
 		}
 		kind = enumType.BaseType.GetKind()
 	}
+	if kind == workitem.KindComputed {
+		computedType, ok := fieldDef.Type.(workitem.ComputedType)
+		if !ok {
+			return nil, errs.Errorf("failed to convert field %q to computed type: %+v", fieldName, fieldDef)
+		}
+		kind = computedType.BaseType.GetKind()
+	}
 
 	// handle all single value fields (including enums)
 	if kind != workitem.KindList {
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"net/url"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+)
+
+func init() {
+	rendering.WorkItemKeyPath = func(key string) string {
+		return app.WorkItemKeysHref(url.PathEscape(key))
+	}
+}
+
+// WorkItemKeysController implements the work_item_keys resource.
+type WorkItemKeysController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewWorkItemKeysController creates a work_item_keys controller.
+func NewWorkItemKeysController(service *goa.Service, db application.DB) *WorkItemKeysController {
+	return &WorkItemKeysController{
+		Controller: service.NewController("WorkItemKeysController"),
+		db:         db,
+	}
+}
+
+// Show redirects to the work item with the given human-readable key (e.g. PLAT-123)
+func (c *WorkItemKeysController) Show(ctx *app.ShowWorkItemKeysContext) error {
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		wiID, _, err := appl.WorkItems().LookupIDByKey(ctx, ctx.Key)
+		if err != nil {
+			return errs.Wrapf(err, "failed to load work item with key %s", ctx.Key)
+		}
+		ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.Request, app.WorkitemHref(wiID)))
+		return ctx.TemporaryRedirect()
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/codebase/scm"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/goadesign/goa"
+)
+
+// APIStringTypeScmActivities contains the JSON API type for SCM activities
+const APIStringTypeScmActivities = "scm-activities"
+
+// WorkItemScmActivitiesController implements the work_item_scm_activities resource.
+type WorkItemScmActivitiesController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewWorkItemScmActivitiesController creates a work_item_scm_activities controller.
+func NewWorkItemScmActivitiesController(service *goa.Service, db application.DB) *WorkItemScmActivitiesController {
+	return &WorkItemScmActivitiesController{
+		Controller: service.NewController("WorkItemScmActivitiesController"),
+		db:         db,
+	}
+}
+
+// List runs the list action.
+func (c *WorkItemScmActivitiesController) List(ctx *app.ListWorkItemScmActivitiesContext) error {
+	var activities []scm.Activity
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.WorkItems().CheckExists(ctx, ctx.WiID); err != nil {
+			return err
+		}
+		var err error
+		activities, err = appl.SCMActivities().List(ctx, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.ScmActivityList{
+		Data: make([]*app.ScmActivity, 0, len(activities)),
+	}
+	for _, a := range activities {
+		res.Data = append(res.Data, ConvertScmActivity(ctx.Request, a))
+	}
+	return ctx.OK(res)
+}
+
+// ConvertScmActivity converts between internal and external REST representation
+func ConvertScmActivity(request *http.Request, a scm.Activity) *app.ScmActivity {
+	workItemID := a.WorkItemID.String()
+	workItemURL := rest.AbsoluteURL(request, app.WorkitemHref(workItemID))
+	codebaseID := a.CodebaseID.String()
+	codebaseURL := rest.AbsoluteURL(request, app.CodebaseHref(codebaseID))
+	res := &app.ScmActivity{
+		Type: APIStringTypeScmActivities,
+		ID:   &a.ID,
+		Attributes: &app.ScmActivityAttributes{
+			Kind:      a.Kind,
+			Ref:       a.Ref,
+			Closes:    a.Closes,
+			CreatedAt: ptr.Time(a.CreatedAt.UTC()),
+		},
+		Relationships: &app.ScmActivityRelationships{
+			WorkItem: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: ptr.String(APIStringTypeWorkItem),
+					ID:   &workItemID,
+				},
+				Links: &app.GenericLinks{
+					Self:    &workItemURL,
+					Related: &workItemURL,
+				},
+			},
+			Codebase: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: ptr.String(APIStringTypeCodebase),
+					ID:   &codebaseID,
+				},
+				Links: &app.GenericLinks{
+					Self:    &codebaseURL,
+					Related: &codebaseURL,
+				},
+			},
+		},
+	}
+	if a.Title != "" {
+		res.Attributes.Title = ptr.String(a.Title)
+	}
+	if a.URL != "" {
+		res.Attributes.URL = ptr.String(a.URL)
+		res.Links = &app.GenericLinks{Related: ptr.String(a.URL)}
+	}
+	if a.Author != "" {
+		res.Attributes.Author = ptr.String(a.Author)
+	}
+	if a.State != "" {
+		res.Attributes.State = ptr.String(a.State)
+	}
+	if a.OccurredAt != nil {
+		res.Attributes.OccurredAt = ptr.Time(a.OccurredAt.UTC())
+	}
+	return res
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeWorkItemTransitions contains the JSON API type for work item
+// transitions
+const APIStringTypeWorkItemTransitions = "workitemtransitions"
+
+// WorkItemTransitionsController implements the work_item_transitions resource.
+type WorkItemTransitionsController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewWorkItemTransitionsController creates a work_item_transitions controller.
+func NewWorkItemTransitionsController(service *goa.Service, db application.DB) *WorkItemTransitionsController {
+	return &WorkItemTransitionsController{
+		Controller: service.NewController("WorkItemTransitionsController"),
+		db:         db,
+	}
+}
+
+// List runs the list action.
+func (c *WorkItemTransitionsController) List(ctx *app.ListWorkItemTransitionsContext) error {
+	// anonymous users have no role and only get the unrestricted transitions
+	identityID := uuid.Nil
+	if currentUserIdentityID, err := login.ContextIdentity(ctx); err == nil {
+		identityID = *currentUserIdentityID
+	}
+	var transitions workitem.Transitions
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		transitions, err = appl.WorkItems().ListTransitions(ctx, ctx.WiID, identityID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.WorkItemTransitionList{
+		Data: make([]*app.WorkItemTransition, len(transitions)),
+	}
+	for i, tr := range transitions {
+		res.Data[i] = &app.WorkItemTransition{
+			Type:       APIStringTypeWorkItemTransitions,
+			Attributes: ConvertTransitionFromModel(tr),
+		}
+	}
+	return ctx.OK(res)
+}
+
+// ConvertTransitionFromModel converts a transition from model to app
+// representation
+func ConvertTransitionFromModel(tr workitem.Transition) *app.WorkItemTypeTransition {
+	res := &app.WorkItemTypeTransition{
+		From:           tr.From,
+		To:             tr.To,
+		RequiredFields: tr.RequiredFields,
+	}
+	if tr.Role != "" {
+		role := string(tr.Role)
+		res.Role = &role
+	}
+	return res
+}
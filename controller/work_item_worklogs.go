@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/workitem/worklog"
+	"github.com/goadesign/goa"
+)
+
+// WorkItemWorklogsController implements the work_item_worklogs resource.
+type WorkItemWorklogsController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewWorkItemWorklogsController creates a work_item_worklogs controller.
+func NewWorkItemWorklogsController(service *goa.Service, db application.DB) *WorkItemWorklogsController {
+	return &WorkItemWorklogsController{
+		Controller: service.NewController("WorkItemWorklogsController"),
+		db:         db,
+	}
+}
+
+// List runs the list action.
+func (c *WorkItemWorklogsController) List(ctx *app.ListWorkItemWorklogsContext) error {
+	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
+	var worklogs []worklog.Worklog
+	var count int
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.WorkItems().CheckExists(ctx, ctx.WiID); err != nil {
+			return err
+		}
+		var err error
+		worklogs, count, err = appl.Worklogs().List(ctx, ctx.WiID, &offset, &limit)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.WorklogList{
+		Data:  ConvertWorklogs(ctx.Request, worklogs),
+		Meta:  &app.WorklogListMeta{TotalCount: count},
+		Links: &app.PagingLinks{},
+	}
+	setPagingLinks(res.Links, buildAbsoluteURL(ctx.Request), len(worklogs), offset, limit, count)
+	return ctx.OK(res)
+}
+
+// Create runs the create action.
+func (c *WorkItemWorklogsController) Create(ctx *app.CreateWorkItemWorklogsContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload == nil || ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	attrs := ctx.Payload.Data.Attributes
+	if attrs.Duration == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.duration", nil).Expected("not nil"))
+	}
+	w := worklog.Worklog{
+		WorkItemID: ctx.WiID,
+		IdentityID: *identityID,
+		Duration:   *attrs.Duration,
+	}
+	if attrs.WorkDate != nil {
+		w.WorkDate = *attrs.WorkDate
+	}
+	if attrs.Comment != nil {
+		w.Comment = *attrs.Comment
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		return appl.Worklogs().Create(ctx, &w, attrs.RemainingEstimate)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.Request, app.WorklogsHref(w.ID)))
+	return ctx.Created(&app.WorklogSingle{
+		Data: ConvertWorklog(ctx.Request, w),
+	})
+}
+
+// Totals runs the totals action.
+func (c *WorkItemWorklogsController) Totals(ctx *app.TotalsWorkItemWorklogsContext) error {
+	var totals *worklog.Totals
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		totals, err = appl.Worklogs().Totals(ctx, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	selfURL := rest.AbsoluteURL(ctx.Request, app.WorkitemHref(ctx.WiID.String())+"/worklogs/totals")
+	return ctx.OK(&app.WorklogTotalsSingle{
+		Data: &app.WorklogTotals{
+			Type: APIStringTypeWorklogTotals,
+			ID:   ctx.WiID,
+			Attributes: &app.WorklogTotalsAttributes{
+				TimeSpent:      totals.TimeSpent,
+				TimeSpentTotal: ptr.Int(totals.TimeSpentTotal),
+			},
+			Links: &app.GenericLinks{
+				Self: &selfURL,
+			},
+		},
+	})
+}
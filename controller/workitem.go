@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"html"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
@@ -51,6 +52,7 @@ type WorkitemController struct {
 type WorkItemControllerConfig interface {
 	GetCacheControlWorkItems() string
 	GetCacheControlWorkItem() string
+	GetWorkItemCloneLinkTypeID() string
 }
 
 // NewWorkitemController creates a workitem controller.
@@ -191,20 +193,213 @@ func (c *WorkitemController) Update(ctx *app.UpdateWorkitemContext) error {
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
 	}
+	included, err := includeReferencedWorkItems(ctx, ctx.Request, c.db, []workitem.WorkItemType{*wit}, []workitem.WorkItem{*wi})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
 	resp := &app.WorkItemSingle{
 		Data: converted,
 		Links: &app.WorkItemLinks{
 			Self: buildAbsoluteURL(ctx.Request),
 		},
+		Included: included,
 	}
 	ctx.ResponseData.Header().Set("Last-Modified", lastModified(*wi))
 	return ctx.OK(resp)
 }
 
+// Move moves a work item to another space
+func (c *WorkitemController) Move(ctx *app.MoveWorkitemContext) error {
+	if ctx.Payload == nil || ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	attributes := ctx.Payload.Data.Attributes
+	var wi *workitem.WorkItem
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wi, err = appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	creator := wi.Fields[workitem.SystemCreator]
+	if creator == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewInternalError(ctx, errs.New("work item doesn't have creator")))
+	}
+	// moving a work item changes its type and the space it belongs to
+	authorized, err := c.authorizeWorkitemTypeEditor(ctx, wi.SpaceID, creator.(string), currentUserIdentityID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to move the work item"))
+	}
+	authorized, err = authz.Authorize(ctx, attributes.Space.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to access the target space"))
+	}
+	mapping := workitem.MoveMapping{
+		Type:      attributes.Type,
+		Iteration: attributes.Iteration,
+		Area:      attributes.Area,
+		Labels:    map[uuid.UUID]uuid.UUID{},
+		Releases:  map[uuid.UUID]uuid.UUID{},
+	}
+	for oldID, newID := range attributes.Labels {
+		labelID, err := uuid.FromString(oldID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("labels", oldID).Expected("label ID"))
+		}
+		mapping.Labels[labelID] = newID
+	}
+	for oldID, newID := range attributes.Releases {
+		releaseID, err := uuid.FromString(oldID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("releases", oldID).Expected("release ID"))
+		}
+		mapping.Releases[releaseID] = newID
+	}
+	var rev *workitem.Revision
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		// removing the links also updates the computed fields of the former
+		// parent and its ancestors
+		if attributes.RemoveLinks != nil && *attributes.RemoveLinks {
+			if err := appl.WorkItemLinks().DeleteRelatedLinks(ctx, ctx.WiID, *currentUserIdentityID); err != nil {
+				return errs.Wrapf(err, "failed to remove the links of work item %s", ctx.WiID)
+			}
+		}
+		wi, rev, err = appl.WorkItems().Move(ctx, ctx.WiID, attributes.Version, attributes.Space, mapping, *currentUserIdentityID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to move work item %s to space %s", ctx.WiID, attributes.Space)
+		}
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	wit, err := c.db.WorkItemTypes().Load(ctx.Context, wi.Type)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "failed to load work item type: %s", wi.Type))
+	}
+	c.notification.Send(ctx, notification.NewWorkItemUpdated(ctx.WiID.String(), rev.ID))
+	converted, err := ConvertWorkItem(ctx.Request, *wit, *wi, workItemIncludeHasChildren(ctx, c.db))
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	resp := &app.WorkItemSingle{
+		Data: converted,
+		Links: &app.WorkItemLinks{
+			Self: rest.AbsoluteURL(ctx.Request, app.WorkitemHref(wi.ID)),
+		},
+	}
+	ctx.ResponseData.Header().Set("Last-Modified", lastModified(*wi))
+	return ctx.OK(resp)
+}
+
+// Clone does POST workitem/:wiID/clone
+func (c *WorkitemController) Clone(ctx *app.CloneWorkitemContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	opts := workitem.CloneOptions{Labels: true}
+	if ctx.Payload != nil && ctx.Payload.Data != nil && ctx.Payload.Data.Attributes != nil {
+		attributes := ctx.Payload.Data.Attributes
+		if attributes.Children != nil {
+			opts.Children = *attributes.Children
+		}
+		if attributes.Labels != nil {
+			opts.Labels = *attributes.Labels
+		}
+		if attributes.ResetState != nil {
+			opts.ResetState = *attributes.ResetState
+		}
+		if attributes.ResetAssignees != nil {
+			opts.ResetAssignees = *attributes.ResetAssignees
+		}
+		if attributes.ResetIteration != nil {
+			opts.ResetIteration = *attributes.ResetIteration
+		}
+	}
+	clonedFromLinkTypeID := uuid.Nil
+	if linkTypeID := c.config.GetWorkItemCloneLinkTypeID(); linkTypeID != "" {
+		clonedFromLinkTypeID, err = uuid.FromString(linkTypeID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewInternalError(ctx, errs.Wrapf(err, "invalid link type for clones: %s", linkTypeID)))
+		}
+	}
+	var wi *workitem.WorkItem
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wi, err = appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	authorized, err := authz.Authorize(ctx, wi.SpaceID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to clone the work item"))
+	}
+	var clones map[uuid.UUID]uuid.UUID
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		clones, err = appl.WorkItemLinks().CloneTree(ctx, ctx.WiID, opts, clonedFromLinkTypeID, *currentUserIdentityID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to clone work item %s", ctx.WiID)
+		}
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	mapping := make(map[string]uuid.UUID, len(clones))
+	for originalID, cloneID := range clones {
+		mapping[originalID.String()] = cloneID
+	}
+	cloneURL := rest.AbsoluteURL(ctx.Request, app.WorkitemHref(clones[ctx.WiID]))
+	resp := &app.WorkItemCloneSingle{
+		Data: &app.WorkItemClone{
+			Type: "workitem-clones",
+			Attributes: &app.WorkItemCloneAttributes{
+				Children:       &opts.Children,
+				Labels:         &opts.Labels,
+				ResetState:     &opts.ResetState,
+				ResetAssignees: &opts.ResetAssignees,
+				ResetIteration: &opts.ResetIteration,
+				Mapping:        mapping,
+			},
+			Relationships: &app.WorkItemCloneRelationships{
+				Clone: &app.RelationGeneric{
+					Data: &app.GenericData{
+						Type: ptr.String(APIStringTypeWorkItem),
+						ID:   ptr.String(clones[ctx.WiID].String()),
+					},
+					Links: &app.GenericLinks{
+						Self:    &cloneURL,
+						Related: &cloneURL,
+					},
+				},
+			},
+		},
+	}
+	ctx.ResponseData.Header().Set("Location", cloneURL)
+	return ctx.Created(resp)
+}
+
 // Show does GET workitem
 func (c *WorkitemController) Show(ctx *app.ShowWorkitemContext) error {
 	var wi *workitem.WorkItem
 	var wit *workitem.WorkItemType
+	var included []interface{}
 	err := application.Transactional(c.db, func(appl application.Application) error {
 		var err error
 		wi, err = appl.WorkItems().LoadByID(ctx, ctx.WiID)
@@ -215,7 +410,8 @@ func (c *WorkitemController) Show(ctx *app.ShowWorkitemContext) error {
 		if err != nil {
 			return errs.Wrapf(err, "failed to load work item type: %s", wi.Type)
 		}
-		return nil
+		included, err = includeReferencedWorkItems(ctx, ctx.Request, appl, []workitem.WorkItemType{*wit}, []workitem.WorkItem{*wi})
+		return err
 	})
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
@@ -228,7 +424,8 @@ func (c *WorkitemController) Show(ctx *app.ShowWorkitemContext) error {
 			return jsonapi.JSONErrorResponse(ctx, err)
 		}
 		resp := &app.WorkItemSingle{
-			Data: wi2,
+			Data:     wi2,
+			Included: included,
 		}
 		return ctx.OK(resp)
 	})
@@ -269,6 +466,9 @@ func (c *WorkitemController) Delete(ctx *app.DeleteWorkitemContext) error {
 		if err := appl.WorkItemLinks().DeleteRelatedLinks(ctx, ctx.WiID, *currentUserIdentityID); err != nil {
 			return errs.Wrapf(err, "failed to delete work item links related to work item %s", ctx.WiID)
 		}
+		if err := appl.Comments().DeleteByParent(ctx, ctx.WiID, *currentUserIdentityID); err != nil {
+			return errs.Wrapf(err, "failed to delete comments of work item %s", ctx.WiID)
+		}
 		return nil
 	})
 	if err != nil {
@@ -277,6 +477,114 @@ func (c *WorkitemController) Delete(ctx *app.DeleteWorkitemContext) error {
 	return ctx.OK([]byte{})
 }
 
+// Restore does POST workitem/:wiID/restore
+func (c *WorkitemController) Restore(ctx *app.RestoreWorkitemContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	var wi *workitem.WorkItem
+	var rev *workitem.Revision
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		deletedAt, err := appl.WorkItems().DeletedAt(ctx, ctx.WiID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to load work item %s", ctx.WiID)
+		}
+		if deletedAt == nil {
+			return errors.NewBadParameterError("wiID", ctx.WiID).Expected("ID of a deleted work item")
+		}
+		wi, rev, err = appl.WorkItems().Restore(ctx, ctx.WiID, *currentUserIdentityID)
+		if err != nil {
+			return errs.Wrapf(err, "error restoring work item %s", ctx.WiID)
+		}
+		// the space is only known once the work item was restored, an
+		// unauthorized user causes the whole transaction to be rolled back.
+		authorized, err := authz.Authorize(ctx, wi.SpaceID.String())
+		if err != nil {
+			return errors.NewUnauthorizedError(err.Error())
+		}
+		if !authorized {
+			return errors.NewForbiddenError("user is not authorized to access the space")
+		}
+		if err := appl.WorkItemLinks().RestoreRelatedLinks(ctx, ctx.WiID, *deletedAt, *currentUserIdentityID); err != nil {
+			return errs.Wrapf(err, "failed to restore work item links related to work item %s", ctx.WiID)
+		}
+		if err := appl.Comments().RestoreByParent(ctx, ctx.WiID, *deletedAt, *currentUserIdentityID); err != nil {
+			return errs.Wrapf(err, "failed to restore comments of work item %s", ctx.WiID)
+		}
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	wit, err := c.db.WorkItemTypes().Load(ctx.Context, wi.Type)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "failed to load work item type: %s", wi.Type))
+	}
+	c.notification.Send(ctx, notification.NewWorkItemUpdated(ctx.WiID.String(), rev.ID))
+	converted, err := ConvertWorkItem(ctx.Request, *wit, *wi, workItemIncludeHasChildren(ctx, c.db))
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	ctx.ResponseData.Header().Set("Last-Modified", lastModified(*wi))
+	return ctx.OK(&app.WorkItemSingle{
+		Data: converted,
+		Links: &app.WorkItemLinks{
+			Self: rest.AbsoluteURL(ctx.Request, app.WorkitemHref(ctx.WiID)),
+		},
+	})
+}
+
+// Revert does POST workitem/:wiID/revisions/:revisionID/revert
+func (c *WorkitemController) Revert(ctx *app.RevertWorkitemContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	wi, err := c.db.WorkItems().LoadByID(ctx, ctx.WiID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "failed to load work item with id %v", ctx.WiID))
+	}
+	creator := wi.Fields[workitem.SystemCreator]
+	if creator == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewInternalError(ctx, errs.New("work item doesn't have creator")))
+	}
+	authorized, err := authorizeWorkitemEditor(ctx, c.db, wi.SpaceID, creator.(string), currentUserIdentityID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to access the space"))
+	}
+	var rev *workitem.Revision
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wi, rev, err = appl.WorkItems().RevertToRevision(ctx, ctx.WiID, ctx.RevisionID, ctx.Version, *currentUserIdentityID)
+		if err != nil {
+			return errs.Wrapf(err, "error reverting work item %s to revision %s", ctx.WiID, ctx.RevisionID)
+		}
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	wit, err := c.db.WorkItemTypes().Load(ctx.Context, wi.Type)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "failed to load work item type: %s", wi.Type))
+	}
+	c.notification.Send(ctx, notification.NewWorkItemUpdated(ctx.WiID.String(), rev.ID))
+	converted, err := ConvertWorkItem(ctx.Request, *wit, *wi, workItemIncludeHasChildren(ctx, c.db))
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	ctx.ResponseData.Header().Set("Last-Modified", lastModified(*wi))
+	return ctx.OK(&app.WorkItemSingle{
+		Data: converted,
+		Links: &app.WorkItemLinks{
+			Self: rest.AbsoluteURL(ctx.Request, app.WorkitemHref(ctx.WiID)),
+		},
+	})
+}
+
 // Time is default value if no UpdatedAt field is found
 func updatedAt(wi workitem.WorkItem) time.Time {
 	var t time.Time
@@ -703,6 +1011,7 @@ func ConvertWorkItem(request *http.Request, wit workitem.WorkItemType, wi workit
 	workItemIncludeComments(request, &wi, op)
 	workItemIncludeChildren(request, &wi, op)
 	workItemIncludeEvents(request, &wi, op)
+	workItemIncludeReferencedWorkItems(request, wit, &wi, op)
 	for _, add := range additional {
 		if err := add(request, &wi, op); err != nil {
 			return nil, errs.Wrap(err, "failed to run additional conversion function")
@@ -848,6 +1157,41 @@ func workItemIncludeEvents(request *http.Request, wi *workitem.WorkItem, wi2 *ap
 	}
 }
 
+// workItemIncludeReferencedWorkItems adds a relationship to the work items
+// that are referenced by fields of kind "workitem". The values of these
+// fields remain in the attributes so that they can be updated like any other
+// field.
+func workItemIncludeReferencedWorkItems(request *http.Request, wit workitem.WorkItemType, wi *workitem.WorkItem, wi2 *app.WorkItem) {
+	refs := wit.Fields.ReferencedWorkItemIDs(wi.Fields)
+	if len(refs) == 0 {
+		return
+	}
+	names := make([]string, 0, len(refs))
+	for name := range refs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	rel := &app.RelationGenericList{
+		Data: []*app.GenericData{},
+		Meta: map[string]interface{}{},
+	}
+	seen := map[uuid.UUID]struct{}{}
+	for _, name := range names {
+		ids := make([]string, len(refs[name]))
+		for i, id := range refs[name] {
+			ids[i] = id.String()
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			data, _ := ConvertWorkItemSimple(request, id)
+			rel.Data = append(rel.Data, data)
+		}
+		rel.Meta[name] = ids
+	}
+	wi2.Relationships.ReferencedWorkItems = rel
+}
+
 func loadWorkItemTypesFromArr(ctx context.Context, appl application.Application, wis []workitem.WorkItem) ([]workitem.WorkItemType, error) {
 	wits := make([]workitem.WorkItemType, len(wis))
 	for idx, wi := range wis {
@@ -871,3 +1215,63 @@ func loadWorkItemTypesFromPtrArr(ctx context.Context, appl application.Applicati
 	}
 	return wits, nil
 }
+
+// ConvertWorkItemSimple returns the resource identifier and links of the work
+// item with the given ID, e.g. for values of fields of kind "workitem"
+func ConvertWorkItemSimple(request *http.Request, id interface{}) (*app.GenericData, *app.GenericLinks) {
+	t := APIStringTypeWorkItem
+	i := fmt.Sprint(id)
+	data := &app.GenericData{
+		Type: &t,
+		ID:   &i,
+	}
+	relatedURL := rest.AbsoluteURL(request, app.WorkitemHref(i))
+	links := &app.GenericLinks{
+		Self:    &relatedURL,
+		Related: &relatedURL,
+	}
+	return data, links
+}
+
+// includeReferencedWorkItems returns the work items that are referenced by
+// fields of kind "workitem" of the given work items in a form that can be
+// added to the "included" section of a response. The given work item types
+// must be the types of the given work items (same index). Referenced work
+// items that are part of the given work items are skipped.
+func includeReferencedWorkItems(ctx context.Context, request *http.Request, appl application.Application, wits []workitem.WorkItemType, wis []workitem.WorkItem) ([]interface{}, error) {
+	known := map[uuid.UUID]struct{}{}
+	for _, wi := range wis {
+		known[wi.ID] = struct{}{}
+	}
+	ids := []uuid.UUID{}
+	for i, wi := range wis {
+		for _, fieldIDs := range wits[i].Fields.ReferencedWorkItemIDs(wi.Fields) {
+			for _, id := range fieldIDs {
+				if _, ok := known[id]; !ok {
+					known[id] = struct{}{}
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	referenced, err := appl.WorkItems().LoadBatchByID(ctx, ids)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to load referenced work items: %s", ids)
+	}
+	included := make([]interface{}, 0, len(referenced))
+	for _, ref := range referenced {
+		wit, err := appl.WorkItemTypes().Load(ctx, ref.Type)
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to load work item type: %s", ref.Type)
+		}
+		converted, err := ConvertWorkItem(request, *wit, *ref)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		included = append(included, *converted)
+	}
+	return included, nil
+}
@@ -3392,3 +3392,70 @@ func (s *WorkItem2Suite) TestCreateAndUpdateWorkItemForEveryWIT() {
 		})
 	}
 }
+
+func (s *WorkItem2Suite) TestReferencedWorkItems() {
+	// given a work item whose fields reference two other work items
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].Fields["duplicate_of"] = workitem.FieldDefinition{
+				Label: "Duplicate of",
+				Type:  workitem.SimpleType{Kind: workitem.KindWorkItem},
+			}
+			fxt.WorkItemTypes[idx].Fields["related"] = workitem.FieldDefinition{
+				Label: "Related",
+				Type: workitem.ListType{
+					SimpleType:    workitem.SimpleType{Kind: workitem.KindList},
+					ComponentType: workitem.SimpleType{Kind: workitem.KindWorkItem},
+				},
+			}
+			return nil
+		}),
+		tf.WorkItems(3),
+	)
+	wi := *fxt.WorkItems[0]
+	wi.Fields["duplicate_of"] = fxt.WorkItems[1].ID.String()
+	wi.Fields["related"] = []interface{}{fxt.WorkItems[1].ID.String(), fxt.WorkItems[2].ID.String()}
+	_, _, err := workitem.NewWorkItemRepository(s.DB).Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+	require.NoError(s.T(), err)
+
+	s.T().Run("relationship", func(t *testing.T) {
+		// when
+		_, res := test.ShowWorkitemOK(t, s.svc.Context, s.svc, s.workitemCtrl, wi.ID, nil, nil)
+		// then
+		rel := res.Data.Relationships.ReferencedWorkItems
+		require.NotNil(t, rel)
+		ids := []string{}
+		for _, d := range rel.Data {
+			require.NotNil(t, d.ID)
+			assert.Equal(t, APIStringTypeWorkItem, *d.Type)
+			ids = append(ids, *d.ID)
+		}
+		assert.Equal(t, []string{fxt.WorkItems[1].ID.String(), fxt.WorkItems[2].ID.String()}, ids)
+		assert.Equal(t, []interface{}{fxt.WorkItems[1].ID.String()}, rel.Meta["duplicate_of"])
+		assert.Equal(t, []interface{}{fxt.WorkItems[1].ID.String(), fxt.WorkItems[2].ID.String()}, rel.Meta["related"])
+		// the values remain in the attributes
+		assert.Equal(t, fxt.WorkItems[1].ID.String(), res.Data.Attributes["duplicate_of"])
+	})
+
+	s.T().Run("every included work item is linked", func(t *testing.T) {
+		// when
+		_, res := test.ShowWorkitemOK(t, s.svc.Context, s.svc, s.workitemCtrl, wi.ID, nil, nil)
+		// then
+		require.Len(t, res.Included, 2)
+		linked := map[string]bool{}
+		for _, d := range res.Data.Relationships.ReferencedWorkItems.Data {
+			linked[*d.ID] = true
+		}
+		for _, inc := range res.Included {
+			m, ok := inc.(map[string]interface{})
+			require.True(t, ok, "unexpected included object %+v", inc)
+			assert.True(t, linked[m["id"].(string)], "included work item %s is not linked", m["id"])
+		}
+	})
+
+	s.T().Run("no relationship without references", func(t *testing.T) {
+		_, res := test.ShowWorkitemOK(t, s.svc.Context, s.svc, s.workitemCtrl, fxt.WorkItems[2].ID, nil, nil)
+		assert.Nil(t, res.Data.Relationships.ReferencedWorkItems)
+		assert.Empty(t, res.Included)
+	})
+}
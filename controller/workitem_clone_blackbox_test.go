@@ -0,0 +1,140 @@
+package controller_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestWorkItemCloneREST struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunWorkItemCloneREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestWorkItemCloneREST{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+// securedController returns a controller for the given user who is a
+// collaborator of the spaces owned by the given owner
+func (s *TestWorkItemCloneREST) securedController(user, owner account.Identity) (*goa.Service, *WorkitemController) {
+	svc := testsupport.ServiceAsSpaceUser("WorkItemClone-Service", user, &TestSpaceAuthzService{owner, ""})
+	return svc, NewWorkitemController(svc, s.GormDB, s.Configuration)
+}
+
+func newClonePayload(attributes app.WorkItemCloneAttributes) *app.CloneWorkitemPayload {
+	return &app.CloneWorkitemPayload{
+		Data: &app.WorkItemClone{
+			Type:       "workitem-clones",
+			Attributes: &attributes,
+		},
+	}
+}
+
+// countLinks returns the number of links of the given type from the given
+// source to the given target
+func (s *TestWorkItemCloneREST) countLinks(t *testing.T, sourceID, targetID, linkTypeID uuid.UUID) int {
+	var count int
+	err := s.DB.Table("work_item_links").Where("source_id = ? AND target_id = ? AND link_type_id = ? AND deleted_at IS NULL", sourceID, targetID, linkTypeID).Count(&count).Error
+	require.NoError(t, err)
+	return count
+}
+
+func (s *TestWorkItemCloneREST) TestClone() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(1))
+		svc, ctrl := s.securedController(*fxt.Identities[0], *fxt.Identities[0])
+		// when
+		rw, res := test.CloneWorkitemCreated(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID, newClonePayload(app.WorkItemCloneAttributes{}))
+		// then
+		require.NotNil(t, res.Data)
+		require.Len(t, res.Data.Attributes.Mapping, 1)
+		cloneID := res.Data.Attributes.Mapping[fxt.WorkItems[0].ID.String()]
+		assert.NotEqual(t, fxt.WorkItems[0].ID, cloneID)
+		assert.Equal(t, cloneID.String(), *res.Data.Relationships.Clone.Data.ID)
+		assert.True(t, *res.Data.Attributes.Labels, "labels are copied by default")
+		assert.False(t, *res.Data.Attributes.Children)
+		recorder, ok := rw.(*httptest.ResponseRecorder)
+		require.True(t, ok)
+		assert.Equal(t, *res.Data.Relationships.Clone.Links.Self, recorder.Header().Get("Location"))
+		clone, err := workitem.NewWorkItemRepository(s.DB).LoadByID(svc.Context, cloneID)
+		require.NoError(t, err)
+		assert.Equal(t, fxt.WorkItems[0].Fields[workitem.SystemTitle], clone.Fields[workitem.SystemTitle])
+		assert.Equal(t, 1, s.countLinks(t, cloneID, fxt.WorkItems[0].ID, link.SystemWorkItemLinkPlannerItemRelatedID), "the clone is linked to its original")
+	})
+
+	s.T().Run("ok - with children and reset state", func(t *testing.T) {
+		// given a parent with a child that are both open
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.CreateWorkItemEnvironment(),
+			tf.WorkItems(2, tf.SetWorkItemTitles("parent", "child"), func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Fields[workitem.SystemState] = workitem.SystemStateOpen
+				return nil
+			}),
+			tf.WorkItemLinksCustom(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemLinks[idx].LinkTypeID = link.SystemWorkItemLinkTypeParentChildID
+				fxt.WorkItemLinks[idx].SourceID = fxt.WorkItemByTitle("parent").ID
+				fxt.WorkItemLinks[idx].TargetID = fxt.WorkItemByTitle("child").ID
+				return nil
+			}),
+		)
+		svc, ctrl := s.securedController(*fxt.Identities[0], *fxt.Identities[0])
+		parent := fxt.WorkItemByTitle("parent")
+		child := fxt.WorkItemByTitle("child")
+		payload := newClonePayload(app.WorkItemCloneAttributes{
+			Children:   ptr.Bool(true),
+			ResetState: ptr.Bool(true),
+		})
+		// when
+		_, res := test.CloneWorkitemCreated(t, svc.Context, svc, ctrl, parent.ID, payload)
+		// then
+		require.NotNil(t, res.Data)
+		require.Len(t, res.Data.Attributes.Mapping, 2)
+		parentCloneID := res.Data.Attributes.Mapping[parent.ID.String()]
+		childCloneID := res.Data.Attributes.Mapping[child.ID.String()]
+		assert.Equal(t, 1, s.countLinks(t, parentCloneID, childCloneID, link.SystemWorkItemLinkTypeParentChildID), "the clones are linked like their originals")
+		for _, id := range []uuid.UUID{parentCloneID, childCloneID} {
+			clone, err := workitem.NewWorkItemRepository(s.DB).LoadByID(svc.Context, id)
+			require.NoError(t, err)
+			assert.Equal(t, workitem.SystemStateNew, clone.Fields[workitem.SystemState])
+		}
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment())
+		svc, ctrl := s.securedController(*fxt.Identities[0], *fxt.Identities[0])
+		// when/then
+		test.CloneWorkitemNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), newClonePayload(app.WorkItemCloneAttributes{}))
+	})
+
+	s.T().Run("forbidden for non-collaborators", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.Identities(2), tf.WorkItems(1))
+		svc, ctrl := s.securedController(*fxt.Identities[1], *fxt.Identities[0])
+		// when
+		test.CloneWorkitemForbidden(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID, newClonePayload(app.WorkItemCloneAttributes{}))
+		// then no clone was created
+		var count int
+		err := s.DB.Table("work_items").Where("space_id = ? AND deleted_at IS NULL", fxt.Spaces[0].ID).Count(&count).Error
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
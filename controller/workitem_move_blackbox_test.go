@@ -0,0 +1,143 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestWorkItemMoveREST struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunWorkItemMoveREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestWorkItemMoveREST{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+// securedController returns a controller for the given user who is a
+// collaborator of the spaces owned by the given owner
+func (s *TestWorkItemMoveREST) securedController(user, owner account.Identity) (*goa.Service, *WorkitemController) {
+	svc := testsupport.ServiceAsSpaceUser("WorkItemMove-Service", user, &TestSpaceAuthzService{owner, ""})
+	return svc, NewWorkitemController(svc, s.GormDB, s.Configuration)
+}
+
+// newMovePayload returns the payload to move the given work item to the given
+// space
+func newMovePayload(wi workitem.WorkItem, spaceID uuid.UUID) *app.MoveWorkitemPayload {
+	return &app.MoveWorkitemPayload{
+		Data: &app.WorkItemMove{
+			Type: "workitem-moves",
+			Attributes: &app.WorkItemMoveAttributes{
+				Version: wi.Version,
+				Space:   spaceID,
+			},
+		},
+	}
+}
+
+// newParentChildFixture returns two spaces and a parent with a child in the
+// first space. The type of the work items sums up the estimates of the
+// children.
+func (s *TestWorkItemMoveREST) newParentChildFixture(t *testing.T) *tf.TestFixture {
+	return tf.NewTestFixture(t, s.DB,
+		tf.Spaces(2),
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].Fields["estimate"] = workitem.FieldDefinition{
+				Label: "Estimate",
+				Type:  workitem.SimpleType{Kind: workitem.KindFloat},
+			}
+			fxt.WorkItemTypes[idx].Fields["children_estimate"] = workitem.FieldDefinition{
+				Label: "Children estimate",
+				Type: workitem.ComputedType{
+					SimpleType: workitem.SimpleType{Kind: workitem.KindComputed},
+					BaseType:   workitem.SimpleType{Kind: workitem.KindFloat},
+					Expression: "sum(estimate)",
+				},
+			}
+			return nil
+		}),
+		tf.WorkItems(2, tf.SetWorkItemTitles("parent", "child"), func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].SpaceID = fxt.Spaces[0].ID
+			fxt.WorkItems[idx].Fields["estimate"] = float64(3)
+			return nil
+		}),
+		tf.WorkItemLinksCustom(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemLinks[idx].LinkTypeID = link.SystemWorkItemLinkTypeParentChildID
+			fxt.WorkItemLinks[idx].SourceID = fxt.WorkItemByTitle("parent").ID
+			fxt.WorkItemLinks[idx].TargetID = fxt.WorkItemByTitle("child").ID
+			return nil
+		}),
+	)
+}
+
+func (s *TestWorkItemMoveREST) TestMove() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItems(1), tf.Comments(1))
+		svc, ctrl := s.securedController(*fxt.Identities[0], *fxt.Identities[0])
+		// when
+		_, res := test.MoveWorkitemOK(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID, newMovePayload(*fxt.WorkItems[0], fxt.Spaces[1].ID))
+		// then
+		require.NotNil(t, res.Data)
+		assert.Equal(t, fxt.Spaces[1].ID, *res.Data.Relationships.Space.Data.ID)
+		var comments int
+		err := s.DB.Table("comments").Where("parent_id = ? AND deleted_at IS NULL", fxt.WorkItems[0].ID).Count(&comments).Error
+		require.NoError(t, err)
+		assert.Equal(t, 1, comments)
+	})
+
+	s.T().Run("fail - work item has links", func(t *testing.T) {
+		// given
+		fxt := s.newParentChildFixture(t)
+		svc, ctrl := s.securedController(*fxt.Identities[0], *fxt.Identities[0])
+		child := fxt.WorkItemByTitle("child")
+		// when/then
+		test.MoveWorkitemBadRequest(t, svc.Context, svc, ctrl, child.ID, newMovePayload(*child, fxt.Spaces[1].ID))
+	})
+
+	s.T().Run("ok - links are removed", func(t *testing.T) {
+		// given
+		fxt := s.newParentChildFixture(t)
+		svc, ctrl := s.securedController(*fxt.Identities[0], *fxt.Identities[0])
+		parent := fxt.WorkItemByTitle("parent")
+		child := fxt.WorkItemByTitle("child")
+		payload := newMovePayload(*child, fxt.Spaces[1].ID)
+		payload.Data.Attributes.RemoveLinks = ptr.Bool(true)
+		// when
+		_, res := test.MoveWorkitemOK(t, svc.Context, svc, ctrl, child.ID, payload)
+		// then the child is moved and its former parent no longer counts it
+		require.NotNil(t, res.Data)
+		assert.Equal(t, fxt.Spaces[1].ID, *res.Data.Relationships.Space.Data.ID)
+		var links int
+		err := s.DB.Table("work_item_links").Where("? IN (source_id, target_id) AND deleted_at IS NULL", child.ID).Count(&links).Error
+		require.NoError(t, err)
+		assert.Equal(t, 0, links)
+		updatedParent, err := workitem.NewWorkItemRepository(s.DB).LoadByID(svc.Context, parent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, float64(0), updatedParent.Fields["children_estimate"])
+	})
+
+	s.T().Run("forbidden for non-collaborators of the target space", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.Spaces(2), tf.WorkItems(1))
+		svc, ctrl := s.securedController(*fxt.Identities[0], *fxt.Identities[1])
+		// when/then
+		test.MoveWorkitemForbidden(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID, newMovePayload(*fxt.WorkItems[0], fxt.Spaces[1].ID))
+	})
+}
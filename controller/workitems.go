@@ -142,11 +142,16 @@ func (c *WorkitemsController) Create(ctx *app.CreateWorkitemsContext) error {
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
 	}
+	included, err := includeReferencedWorkItems(ctx, ctx.Request, c.db, []workitem.WorkItemType{*workItemType}, []workitem.WorkItem{*wi})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
 	resp := &app.WorkItemSingle{
 		Data: wi2,
 		Links: &app.WorkItemLinks{
 			Self: buildAbsoluteURL(ctx.Request),
 		},
+		Included: included,
 	}
 	ctx.ResponseData.Header().Set("Last-Modified", lastModified(*wi))
 	ctx.ResponseData.Header().Set("Location", app.WorkitemHref(wi2.ID))
@@ -252,10 +257,15 @@ func (c *WorkitemsController) List(ctx *app.ListWorkitemsContext) error {
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, err)
 		}
+		included, err := includeReferencedWorkItems(ctx, ctx.Request, c.db, wits, workitems)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
 		response := app.WorkItemList{
-			Links: &app.PagingLinks{},
-			Meta:  &app.WorkItemListResponseMeta{TotalCount: count},
-			Data:  converted,
+			Links:    &app.PagingLinks{},
+			Meta:     &app.WorkItemListResponseMeta{TotalCount: count},
+			Data:     converted,
+			Included: included,
 		}
 		setPagingLinks(response.Links, buildAbsoluteURL(ctx.Request), len(workitems), offset, limit, count, additionalQuery...)
 		addFilterLinks(response.Links, ctx.Request)
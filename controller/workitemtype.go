@@ -85,6 +85,7 @@ func ConvertWorkItemTypeFromModel(request *http.Request, t *workitem.WorkItemTyp
 			Label:       def.Label,
 			Description: def.Description,
 			Type:        &ct,
+			Constraints: ConvertFieldConstraintsFromModel(def.Constraints),
 		}
 	}
 	if len(t.ChildTypeIDs) > 0 {
@@ -98,9 +99,42 @@ func ConvertWorkItemTypeFromModel(request *http.Request, t *workitem.WorkItemTyp
 			}
 		}
 	}
+	if len(t.Transitions) > 0 {
+		converted.Attributes.Transitions = make([]*app.WorkItemTypeTransition, len(t.Transitions))
+		for i, tr := range t.Transitions {
+			converted.Attributes.Transitions[i] = ConvertTransitionFromModel(tr)
+		}
+	}
 	return converted
 }
 
+// ConvertFieldConstraintsFromModel converts the constraints of a field from
+// model to app representation
+func ConvertFieldConstraintsFromModel(c *workitem.FieldConstraints) *app.FieldConstraints {
+	if c == nil {
+		return nil
+	}
+	res := &app.FieldConstraints{
+		Min:       c.Min,
+		Max:       c.Max,
+		MinLength: c.MinLength,
+		MaxLength: c.MaxLength,
+		MinDate:   c.MinDate,
+		MaxDate:   c.MaxDate,
+		MaxItems:  c.MaxItems,
+	}
+	if c.Pattern != "" {
+		res.Pattern = ptr.String(c.Pattern)
+	}
+	if c.RequiredWhen != nil {
+		res.RequiredWhen = &app.FieldCondition{
+			Field: c.RequiredWhen.Field,
+			In:    c.RequiredWhen.In,
+		}
+	}
+	return res
+}
+
 // converts the field type from model to app representation
 func ConvertFieldTypeFromModel(t workitem.FieldType) app.FieldType {
 	result := app.FieldType{}
@@ -117,6 +151,9 @@ func ConvertFieldTypeFromModel(t workitem.FieldType) app.FieldType {
 		if modelFieldType.DefaultValue != nil {
 			result.DefaultValue = &modelFieldType.DefaultValue
 		}
+	case workitem.ComputedType:
+		result.BaseType = ptr.String(string(modelFieldType.BaseType.GetKind()))
+		result.Expression = ptr.String(modelFieldType.Expression)
 	case workitem.SimpleType:
 		if modelFieldType.DefaultValue != nil {
 			result.DefaultValue = &modelFieldType.DefaultValue
@@ -188,6 +225,25 @@ func ConvertFieldTypeToModel(t app.FieldType) (workitem.FieldType, error) {
 			return fieldType, nil
 		}
 		return enumType, nil
+	case workitem.KindComputed:
+		if t.BaseType == nil {
+			return nil, errs.New("computed type has no base type")
+		}
+		bt, err := workitem.ConvertAnyToKind(*t.BaseType)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		computedType := workitem.ComputedType{
+			SimpleType: workitem.SimpleType{Kind: *kind},
+			BaseType:   workitem.SimpleType{Kind: *bt},
+		}
+		if t.Expression != nil {
+			computedType.Expression = *t.Expression
+		}
+		if err := computedType.Validate(); err != nil {
+			return nil, errs.WithStack(err)
+		}
+		return computedType, nil
 	default:
 		simpleType := workitem.SimpleType{Kind: *kind}
 		// convert simple type default value from app to model
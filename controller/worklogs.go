@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/space/authz"
+	"github.com/fabric8-services/fabric8-wit/workitem/worklog"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Defines the constants to be used in json api
+const (
+	APIStringTypeWorklogs      = "worklogs"
+	APIStringTypeWorklogTotals = "worklog-totals"
+)
+
+// WorklogsController implements the worklogs resource.
+type WorklogsController struct {
+	*goa.Controller
+	db     application.DB
+	config WorklogsControllerConfiguration
+}
+
+// WorklogsControllerConfiguration the configuration for the WorklogsController
+type WorklogsControllerConfiguration interface {
+	GetCacheControlWorklog() string
+}
+
+// NewWorklogsController creates a worklogs controller.
+func NewWorklogsController(service *goa.Service, db application.DB, config WorklogsControllerConfiguration) *WorklogsController {
+	return &WorklogsController{
+		Controller: service.NewController("WorklogsController"),
+		db:         db,
+		config:     config,
+	}
+}
+
+// Show runs the show action.
+func (c *WorklogsController) Show(ctx *app.ShowWorklogsContext) error {
+	var w *worklog.Worklog
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		w, err = appl.Worklogs().Load(ctx, ctx.WorklogID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.ConditionalRequest(*w, c.config.GetCacheControlWorklog, func() error {
+		return ctx.OK(&app.WorklogSingle{
+			Data: ConvertWorklog(ctx.Request, *w),
+		})
+	})
+}
+
+// Update runs the update action.
+func (c *WorklogsController) Update(ctx *app.UpdateWorklogsContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload == nil || ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	w, err := c.loadAuthorizedWorklog(ctx, ctx.WorklogID, *identityID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	attrs := ctx.Payload.Data.Attributes
+	if attrs.Duration != nil {
+		w.Duration = *attrs.Duration
+	}
+	if attrs.WorkDate != nil {
+		w.WorkDate = *attrs.WorkDate
+	}
+	if attrs.Comment != nil {
+		w.Comment = *attrs.Comment
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		return appl.Worklogs().Save(ctx, w, attrs.RemainingEstimate)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.WorklogSingle{
+		Data: ConvertWorklog(ctx.Request, *w),
+	})
+}
+
+// Delete runs the delete action.
+func (c *WorklogsController) Delete(ctx *app.DeleteWorklogsContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	w, err := c.loadAuthorizedWorklog(ctx, ctx.WorklogID, *identityID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		return appl.Worklogs().Delete(ctx, w.ID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK([]byte{})
+}
+
+// loadAuthorizedWorklog loads the given worklog if the user is allowed to
+// change it, i.e. if the user logged the time OR is a space collaborator
+func (c *WorklogsController) loadAuthorizedWorklog(ctx context.Context, worklogID uuid.UUID, identityID uuid.UUID) (*worklog.Worklog, error) {
+	var w *worklog.Worklog
+	var spaceID uuid.UUID
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		w, err = appl.Worklogs().Load(ctx, worklogID)
+		if err != nil {
+			return err
+		}
+		wi, err := appl.WorkItems().LoadByID(ctx, w.WorkItemID)
+		if err != nil {
+			return err
+		}
+		spaceID = wi.SpaceID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if identityID != w.IdentityID {
+		authorized, err := authz.Authorize(ctx, spaceID.String())
+		if err != nil {
+			return nil, errors.NewUnauthorizedError(err.Error())
+		}
+		if !authorized {
+			return nil, errors.NewForbiddenError("user is not the author of the worklog or a space collaborator")
+		}
+	}
+	return w, nil
+}
+
+// ConvertWorklogs converts a list of worklogs from internal to external REST
+// representation
+func ConvertWorklogs(request *http.Request, worklogs []worklog.Worklog) []*app.Worklog {
+	result := make([]*app.Worklog, len(worklogs))
+	for i, w := range worklogs {
+		result[i] = ConvertWorklog(request, w)
+	}
+	return result
+}
+
+// ConvertWorklog converts a worklog from internal to external REST
+// representation
+func ConvertWorklog(request *http.Request, w worklog.Worklog) *app.Worklog {
+	selfURL := rest.AbsoluteURL(request, app.WorklogsHref(w.ID))
+	workItemID := w.WorkItemID.String()
+	workItemURL := rest.AbsoluteURL(request, app.WorkitemHref(workItemID))
+	return &app.Worklog{
+		Type: APIStringTypeWorklogs,
+		ID:   &w.ID,
+		Attributes: &app.WorklogAttributes{
+			Duration:  ptr.Int(w.Duration),
+			WorkDate:  ptr.Time(w.WorkDate.UTC()),
+			Comment:   ptr.String(w.Comment),
+			CreatedAt: ptr.Time(w.CreatedAt.UTC()),
+			UpdatedAt: ptr.Time(w.UpdatedAt.UTC()),
+		},
+		Relationships: &app.WorklogRelationships{
+			WorkItem: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: ptr.String(APIStringTypeWorkItem),
+					ID:   &workItemID,
+				},
+				Links: &app.GenericLinks{
+					Self:    &workItemURL,
+					Related: &workItemURL,
+				},
+			},
+			Identity: convertWorklogIdentity(request, w.IdentityID),
+		},
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+}
+
+func convertWorklogIdentity(request *http.Request, identityID uuid.UUID) *app.RelationGeneric {
+	id := identityID.String()
+	identityURL := rest.AbsoluteURL(request, fmt.Sprintf("%s/%s", usersEndpoint, id))
+	return &app.RelationGeneric{
+		Data: &app.GenericData{
+			Type: ptr.String(APIStringTypeUser),
+			ID:   &id,
+		},
+		Links: &app.GenericLinks{
+			Self:    &identityURL,
+			Related: &identityURL,
+		},
+	}
+}
+
+// ConvertWorklogIdentityTotals converts the time logged per identity from
+// internal to external REST representation
+func ConvertWorklogIdentityTotals(request *http.Request, totals []worklog.IdentityTotal) []*app.WorklogUserTotal {
+	result := make([]*app.WorklogUserTotal, len(totals))
+	for i, t := range totals {
+		result[i] = &app.WorklogUserTotal{
+			Identity:  convertWorklogIdentity(request, t.IdentityID),
+			TimeSpent: t.TimeSpent,
+		}
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var activity = a.Type("Activity", func() {
+	a.Description(`JSONAPI store for the data of an activity in a space.  See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("activities")
+	})
+	a.Attribute("id", d.UUID, "ID of the revision the activity originates from (derived from the iteration ID for iteration activities)", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", activityAttributes)
+	a.Attribute("relationships", activityRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "id", "attributes", "relationships")
+})
+
+var activityAttributes = a.Type("ActivityAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of an activity. +See also see http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("kind", d.String, "The kind of entity that changed", func() {
+		a.Enum("workitem", "comment", "link", "iteration")
+	})
+	a.Attribute("action", d.String, "What happened to the entity", func() {
+		a.Example("update")
+	})
+	a.Attribute("timestamp", d.DateTime, "When the activity occurred", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Required("kind", "action", "timestamp")
+})
+
+var activityRelationships = a.Type("ActivityRelations", func() {
+	a.Attribute("actor", relationGeneric, "The identity who performed the activity")
+	a.Attribute("workitem", relationGeneric, "The work item affected by the activity")
+	a.Attribute("target", relationGeneric, "The work item, comment, link or iteration that changed")
+	a.Required("target")
+})
+
+var activityList = JSONList(
+	"Activity", "Holds the paginated response to an activity list request",
+	activity,
+	pagingLinks,
+	meta)
+
+var _ = a.Resource("space_activities", func() {
+	a.Parent("space")
+	a.BasePath("/activities")
+
+	activityFilters := func() {
+		a.Param("filter[actor]", d.UUID, "ID of the identity to filter activities by")
+		a.Param("filter[type]", d.String, "comma separated list of activity kinds to filter by (workitem, comment, link, iteration)")
+		a.Param("filter[since]", d.DateTime, "only list activities that occurred at or after the given time")
+		a.Param("filter[until]", d.DateTime, "only list activities that occurred before the given time")
+		a.Param("page[offset]", d.String, "Paging start position")
+		a.Param("page[limit]", d.Integer, "Paging size")
+	}
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List the activities in the given space, most recent first.")
+		a.Params(activityFilters)
+		a.UseTrait("conditional")
+		a.Response(d.OK, activityList)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+
+	a.Action("feed", func() {
+		a.Routing(
+			a.GET("/atom"),
+		)
+		a.Description("List the activities in the given space as an Atom feed.")
+		a.Params(activityFilters)
+		a.Response(d.OK, "application/atom+xml")
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+})
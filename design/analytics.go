@@ -0,0 +1,262 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var burndownDay = a.Type("BurndownDay", func() {
+	a.Description(`State of the work items of an iteration at the end of a day`)
+	a.Attribute("date", d.DateTime, "The day", func() {
+		a.Example("2016-11-29T00:00:00Z")
+	})
+	a.Attribute("total", d.Integer, "Number of work items in the iteration")
+	a.Attribute("closed", d.Integer, "Number of closed work items in the iteration")
+	a.Attribute("open", d.Integer, "Number of open work items in the iteration")
+	a.Attribute("effort", d.Number, "Total effort of the work items in the iteration")
+	a.Attribute("remaining-effort", d.Number, "Effort of the open work items in the iteration")
+	a.Required("date", "total", "closed", "open", "effort", "remaining-effort")
+})
+
+var scopeChange = a.Type("ScopeChange", func() {
+	a.Description(`A work item added to or removed from an iteration after it started`)
+	a.Attribute("time", d.DateTime, "When the work item was added or removed", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("change", d.String, "Whether the work item was added or removed", func() {
+		a.Enum("added", "removed")
+	})
+	a.Attribute("work-item", relationGeneric, "The work item")
+	a.Required("time", "change", "work-item")
+})
+
+var burndown = a.Type("Burndown", func() {
+	a.Description(`Daily burndown of an iteration reconstructed from the revisions of its work items`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("burndowns")
+	})
+	a.Attribute("id", d.UUID, "ID of the iteration", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", burndownAttributes)
+	a.Attribute("relationships", analyticsRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "id", "attributes")
+})
+
+var burndownAttributes = a.Type("BurndownAttributes", func() {
+	a.Attribute("effort-field", d.String, "Name of the numeric field holding the effort of the work items", func() {
+		a.Example("storypoints")
+	})
+	a.Attribute("days", a.ArrayOf(burndownDay), "The days of the iteration so far")
+	a.Attribute("scope-changes", a.ArrayOf(scopeChange), "The work items added or removed after the iteration started")
+	a.Required("days", "scope-changes")
+})
+
+var analyticsRelationships = a.Type("AnalyticsRelations", func() {
+	a.Attribute("iteration", relationGeneric, "The iteration")
+})
+
+var burndownSingle = JSONSingle(
+	"Burndown", "Holds the burndown of an iteration",
+	burndown,
+	nil)
+
+var velocity = a.Type("Velocity", func() {
+	a.Description(`Work committed to and completed in an iteration`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("velocities")
+	})
+	a.Attribute("id", d.UUID, "ID of the iteration", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", velocityAttributes)
+	a.Attribute("relationships", analyticsRelationships)
+	a.Required("type", "id", "attributes")
+})
+
+var velocityAttributes = a.Type("VelocityAttributes", func() {
+	a.Attribute("name", d.String, "The iteration name")
+	a.Attribute("startAt", d.DateTime, "When the iteration started", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("endAt", d.DateTime, "When the iteration ended", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("committed", d.Integer, "Number of open work items in the iteration when it started")
+	a.Attribute("committed-effort", d.Number, "Effort of the open work items in the iteration when it started")
+	a.Attribute("completed", d.Integer, "Number of work items of the iteration closed during the iteration")
+	a.Attribute("completed-effort", d.Number, "Effort of the work items of the iteration closed during the iteration")
+	a.Required("name", "startAt", "endAt", "committed", "committed-effort", "completed", "completed-effort")
+})
+
+var velocityListMeta = a.Type("VelocityListMeta", func() {
+	a.Attribute("effort-field", d.String, "Name of the numeric field holding the effort of the work items")
+	a.Attribute("average-completed", d.Number, "Average number of work items completed per iteration")
+	a.Attribute("average-completed-effort", d.Number, "Average effort completed per iteration")
+	a.Required("average-completed", "average-completed-effort")
+})
+
+var velocityList = JSONList(
+	"Velocity", "Holds the velocity of the last ended iterations, oldest first",
+	velocity,
+	nil,
+	velocityListMeta)
+
+var durationStats = a.Type("DurationStats", func() {
+	a.Description(`Distribution of durations in seconds`)
+	a.Attribute("count", d.Integer, "Number of durations")
+	a.Attribute("mean", d.Number, "Mean duration in seconds")
+	a.Attribute("p50", d.Number, "50th percentile in seconds")
+	a.Attribute("p85", d.Number, "85th percentile in seconds")
+	a.Attribute("p95", d.Number, "95th percentile in seconds")
+	a.Required("count", "mean", "p50", "p85", "p95")
+})
+
+var stateDurationStats = a.Type("StateDurationStats", func() {
+	a.Attribute("state", d.String, "The state")
+	a.Attribute("stats", durationStats, "Distribution of the time spent in the state")
+	a.Required("state", "stats")
+})
+
+var flowStats = a.Type("FlowStats", func() {
+	a.Description(`Flow statistics of a group of work items`)
+	a.Attribute("work-item-type", relationGeneric, "The work item type of the group, if grouped by type")
+	a.Attribute("area", relationGeneric, "The area of the group, if grouped by area")
+	a.Attribute("lead-time", durationStats, "Time from the creation to the closing of closed work items")
+	a.Attribute("cycle-time", durationStats, "Time from the first state change to the closing of closed work items")
+	a.Attribute("time-in-state", a.ArrayOf(stateDurationStats), "Time spent in each state")
+	a.Required("lead-time", "cycle-time", "time-in-state")
+})
+
+var cycleTime = a.Type("CycleTime", func() {
+	a.Description(`Lead time, cycle time and time in state of the work items of a space reconstructed from their revisions`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("cycle-times")
+	})
+	a.Attribute("id", d.UUID, "ID of the space", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", cycleTimeAttributes)
+	a.Required("type", "id", "attributes")
+})
+
+var cycleTimeAttributes = a.Type("CycleTimeAttributes", func() {
+	a.Attribute("overall", flowStats, "Statistics of all the work items")
+	a.Attribute("by-type", a.ArrayOf(flowStats), "Statistics per work item type")
+	a.Attribute("by-area", a.ArrayOf(flowStats), "Statistics per area")
+	a.Required("overall", "by-type", "by-area")
+})
+
+var cycleTimeSingle = JSONSingle(
+	"CycleTime", "Holds the cycle time report of a space",
+	cycleTime,
+	nil)
+
+var flowDay = a.Type("FlowDay", func() {
+	a.Attribute("date", d.DateTime, "The day", func() {
+		a.Example("2016-11-29T00:00:00Z")
+	})
+	a.Attribute("states", a.HashOf(d.String, d.Integer), "Number of work items per state at the end of the day")
+	a.Required("date", "states")
+})
+
+var cumulativeFlow = a.Type("CumulativeFlow", func() {
+	a.Description(`Daily number of work items per state reconstructed from their revisions`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("cumulative-flows")
+	})
+	a.Attribute("id", d.UUID, "ID of the space or of the iteration", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", cumulativeFlowAttributes)
+	a.Attribute("relationships", analyticsRelationships)
+	a.Required("type", "id", "attributes")
+})
+
+var cumulativeFlowAttributes = a.Type("CumulativeFlowAttributes", func() {
+	a.Attribute("states", a.ArrayOf(d.String), "The states in workflow order")
+	a.Attribute("days", a.ArrayOf(flowDay), "The days")
+	a.Required("states", "days")
+})
+
+var cumulativeFlowSingle = JSONSingle(
+	"CumulativeFlow", "Holds the cumulative flow of a space or iteration",
+	cumulativeFlow,
+	nil)
+
+var _ = a.Resource("analytics", func() {
+	a.BasePath("/analytics")
+
+	a.Action("burndown", func() {
+		a.Routing(
+			a.GET("/iterations/:iterationID/burndown"),
+		)
+		a.Description("Daily burndown of the iteration and its child iterations, with the work items added or removed after it started.")
+		a.Params(func() {
+			a.Param("iterationID", d.UUID, "ID of the iteration")
+			a.Param("effort", d.String, "Name of the numeric field holding the effort of the work items, e.g. storypoints")
+		})
+		a.Response(d.OK, burndownSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("velocity", func() {
+		a.Routing(
+			a.GET("/spaces/:spaceID/velocity"),
+		)
+		a.Description("Velocity of the last ended iterations of the space.")
+		a.Params(func() {
+			a.Param("spaceID", d.UUID, "ID of the space")
+			a.Param("iterations", d.Integer, "Number of iterations", func() {
+				a.Default(5)
+				a.Minimum(1)
+				a.Maximum(20)
+			})
+			a.Param("effort", d.String, "Name of the numeric field holding the effort of the work items, e.g. storypoints")
+		})
+		a.Response(d.OK, velocityList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("cycle-time", func() {
+		a.Routing(
+			a.GET("/spaces/:spaceID/cycle-time"),
+		)
+		a.Description("Lead time, cycle time and time in state distributions of the work items of the space, overall, per type and per area.")
+		a.Params(func() {
+			a.Param("spaceID", d.UUID, "ID of the space")
+			a.Param("filter[expression]", d.String, "Filter expression in JSON format, see the search", func() {
+				a.Example(`{"workitemtype": "26787039-b68f-4e28-8814-c2f93be1ef4e"}`)
+			})
+		})
+		a.Response(d.OK, cycleTimeSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("cumulative-flow", func() {
+		a.Routing(
+			a.GET("/spaces/:spaceID/cumulative-flow"),
+		)
+		a.Description("Daily number of work items per state of the space or of one of its iterations.")
+		a.Params(func() {
+			a.Param("spaceID", d.UUID, "ID of the space")
+			a.Param("iteration", d.UUID, "ID of the iteration, the cumulative flow then runs over the iteration by default")
+			a.Param("from", d.DateTime, "The first day, 30 days before the last one by default")
+			a.Param("to", d.DateTime, "The last day, today by default")
+			a.Param("filter[expression]", d.String, "Filter expression in JSON format, see the search", func() {
+				a.Example(`{"workitemtype": "26787039-b68f-4e28-8814-c2f93be1ef4e"}`)
+			})
+		})
+		a.Response(d.OK, cumulativeFlowSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+})
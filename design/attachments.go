@@ -0,0 +1,152 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var attachment = a.Type("Attachment", func() {
+	a.Description(`JSONAPI store for the metadata of an attachment. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("attachments")
+	})
+	a.Attribute("id", d.UUID, "ID of the attachment", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", attachmentAttributes)
+	a.Attribute("relationships", attachmentRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "id", "attributes")
+})
+
+var attachmentAttributes = a.Type("AttachmentAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of an attachment. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("name", d.String, "The file name", func() {
+		a.Example("screenshot.png")
+	})
+	a.Attribute("size", d.Integer, "The size of the content in bytes", func() {
+		a.Example(2048)
+	})
+	a.Attribute("mime-type", d.String, "The MIME type of the content", func() {
+		a.Example("image/png")
+	})
+	a.Attribute("checksum", d.String, "The hex encoded SHA-256 checksum of the content", func() {
+		a.Example("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+	})
+	a.Attribute("markup-reference", d.String, "The reference to use in markup to link or display the attachment inline", func() {
+		a.Example("attachment:40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("created-at", d.DateTime, "When the attachment was uploaded", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+})
+
+var attachmentRelationships = a.Type("AttachmentRelationships", func() {
+	a.Attribute("work-item", relationGeneric, "The work item to which the file is attached")
+	a.Attribute("comment", relationGeneric, "The comment to which the file is attached (if any)")
+	a.Attribute("creator", relationGeneric, "The user who uploaded the file")
+})
+
+var attachmentUpload = a.Type("AttachmentUpload", func() {
+	a.Description("The multipart form to upload an attachment")
+	a.Attribute("file", d.File, "The file to attach")
+	a.Attribute("comment-id", d.UUID, "The comment of the work item to attach the file to (optional)")
+	a.Required("file")
+})
+
+var attachmentSingle = JSONSingle(
+	"Attachment", "Holds the metadata of a single attachment",
+	attachment,
+	nil)
+
+var attachmentList = JSONList(
+	"Attachment", "Holds the list of attachments",
+	attachment,
+	nil,
+	nil)
+
+var _ = a.Resource("attachments", func() {
+	a.BasePath("/attachments")
+
+	a.Action("show", func() {
+		a.Routing(
+			a.GET("/:attachmentID"),
+		)
+		a.Description("Retrieve the metadata of the attachment with the given id.")
+		a.Params(func() {
+			a.Param("attachmentID", d.UUID, "ID of the attachment")
+		})
+		a.UseTrait("conditional")
+		a.Response(d.OK, attachmentSingle)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("download", func() {
+		a.Routing(
+			a.GET("/:attachmentID/content"),
+		)
+		a.Description("Download the content of the attachment with the given id.")
+		a.Params(func() {
+			a.Param("attachmentID", d.UUID, "ID of the attachment")
+		})
+		a.UseTrait("conditional")
+		a.Response(d.OK)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:attachmentID"),
+		)
+		a.Description("Delete the attachment with the given id.")
+		a.Params(func() {
+			a.Param("attachmentID", d.UUID, "ID of the attachment")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
+
+var _ = a.Resource("work_item_attachments", func() {
+	a.Parent("workitem")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET("attachments"),
+		)
+		a.Description("List the attachments of the given work item and its comments.")
+		a.Response(d.OK, attachmentList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("attachments"),
+		)
+		a.Description("Upload a file and attach it to the given work item or one of its comments.")
+		a.MultipartForm()
+		a.Payload(attachmentUpload)
+		a.Response(d.Created, "/attachments/.*", func() {
+			a.Media(attachmentSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
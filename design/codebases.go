@@ -39,6 +39,12 @@ var codebaseAttributes = a.Type("CodebaseAttributes", func() {
 	a.Attribute("cve-scan", d.Boolean, "Should this codebase be scanned for CVEs", func() {
 		a.Example(true)
 	})
+	a.Attribute("webhook-secret", d.String, "Secret verifying the push and pull request webhooks of the codebase, never returned", func() {
+		a.Example("s3cr3t")
+	})
+	a.Attribute("merge-state", d.String, "State the work items closed by a merged pull request are moved to, none by default", func() {
+		a.Example("resolved")
+	})
 })
 
 var codebaseLinks = a.Type("CodebaseLinks", func() {
@@ -291,6 +297,44 @@ var _ = a.Resource("codebase", func() {
 		a.Response(d.NotFound, JSONAPIErrors)
 		a.Response(d.Unauthorized, JSONAPIErrors)
 	})
+	a.Action("webhook", func() {
+		a.Routing(
+			a.POST("/:codebaseID/webhook"),
+		)
+		a.Description(`Receive a push or pull request event of the repository of the codebase and
+link the commits, branches and pull requests to the work items they mention.
+Requests are verified with the webhook secret of the codebase: Github requests
+must be signed with it and GitLab requests must carry it in the X-Gitlab-Token
+header.`)
+		a.Params(func() {
+			a.Param("codebaseID", d.UUID, "Codebase Identifier")
+		})
+		a.Response(d.NoContent)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
+	a.Action("scan", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:codebaseID/scan"),
+		)
+		a.Description("Scan the branches and the last commits of the repository of the codebase for mentions of work items. The scan runs in the background, only https and ssh repositories can be scanned and only one scan of a codebase runs at a time.")
+		a.Params(func() {
+			a.Param("codebaseID", d.UUID, "Codebase Identifier")
+			a.Param("limit", d.Integer, "Maximum number of commits to read, 500 by default", func() {
+				a.Minimum(1)
+			})
+		})
+		a.Response(d.Accepted)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
 	a.Action("cheState", func() {
 		a.Security("jwt")
 		a.Routing(
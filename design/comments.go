@@ -46,8 +46,91 @@ var commentAttributes = a.Type("CommentAttributes", func() {
 	a.Attribute("markup", d.String, "The comment markup associated with the body", func() {
 		a.Example("Markdown")
 	})
+	a.Attribute("reactions", a.ArrayOf(commentReaction), "The reactions on the comment, grouped by emoji")
+	a.Attribute("deleted-at", d.DateTime, "When the comment was deleted. Only set on the tombstones of deleted comments, which have no body.", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+})
+
+var commentRevision = a.Type("CommentRevision", func() {
+	a.Description(`JSONAPI store for the data of a comment revision.  See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("comment-revisions")
+	})
+	a.Attribute("id", d.UUID, "ID of the comment revision", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", commentRevisionAttributes)
+	a.Attribute("relationships", commentRevisionRelationships)
+	a.Required("type", "id", "attributes", "relationships")
+})
+
+var commentRevisionAttributes = a.Type("CommentRevisionAttributes", func() {
+	a.Attribute("revision-type", d.String, "The kind of modification", func() {
+		a.Enum("create", "update", "delete", "restore")
+	})
+	a.Attribute("timestamp", d.DateTime, "When the modification occurred", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("body", d.String, "The comment body after the modification (not set on deletions)", func() {
+		a.Example("This is really interesting")
+	})
+	a.Attribute("markup", d.String, "The comment markup after the modification (not set on deletions)", func() {
+		a.Example("Markdown")
+	})
+	a.Attribute("body-diff", d.String, "The unified diff of the comment body with the previous revision", func() {
+		a.Example("--- previous\n+++ current\n@@ -1 +1 @@\n-This is interesting\n+This is really interesting\n")
+	})
+	a.Required("revision-type", "timestamp")
+})
+
+var commentRevisionRelationships = a.Type("CommentRevisionRelations", func() {
+	a.Attribute("modifier", relationGeneric, "The identity who modified the comment")
+	a.Attribute("comment", relationGeneric, "The comment that was modified")
+	a.Required("modifier", "comment")
+})
+
+var commentRevisionList = JSONList(
+	"CommentRevision", "Holds the response of comment revisions",
+	commentRevision,
+	nil,
+	nil,
+)
+
+var commentReaction = a.Type("CommentReaction", func() {
+	a.Description(`The reactions with the same emoji on a comment`)
+	a.Attribute("emoji", d.String, "The emoji of the reaction", func() {
+		a.Example(":thumbsup:")
+	})
+	a.Attribute("count", d.Integer, "The number of identities who reacted with the emoji")
+	a.Attribute("identities", a.ArrayOf(d.UUID), "The identities who reacted with the emoji")
+	a.Required("emoji", "count", "identities")
 })
 
+var createReaction = a.Type("CreateCommentReaction", func() {
+	a.Description(`JSONAPI store for the data of a comment reaction to create`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("reactions")
+	})
+	a.Attribute("attributes", createReactionAttributes)
+	a.Required("type", "attributes")
+})
+
+var createReactionAttributes = a.Type("CreateCommentReactionAttributes", func() {
+	a.Attribute("emoji", d.String, "The emoji of the reaction", func() {
+		a.MinLength(1)
+		a.MaxLength(32)
+		a.Example(":thumbsup:")
+	})
+	a.Required("emoji")
+})
+
+var createSingleReaction = JSONSingle(
+	"CreateCommentReaction", "Holds the create data for a comment reaction",
+	createReaction,
+	nil,
+)
+
 var createCommentAttributes = a.Type("CreateCommentAttributes", func() {
 	a.Description(`JSONAPI store for all the "attributes" for creating a comment. +See also see http://jsonapi.org/format/#document-resource-object-attributes`)
 	a.Attribute("body", d.String, "The comment body", func() {
@@ -71,6 +154,7 @@ var commentRelationships = a.Type("CommentRelations", func() {
 	a.Attribute("created-by", commentCreatedBy, "DEPRECATED. This defines the creator of the comment.")
 	a.Attribute("parent", relationGeneric, "This defines the owning resource of the comment.")
 	a.Attribute("parent-comment", relationGeneric, "This defines the parent comment resource.")
+	a.Attribute("replies", relationGeneric, "This defines the replies to the comment. The total number of replies is in the meta.")
 })
 
 var commentCreatedBy = a.Type("CommentCreatedBy", func() {
@@ -170,7 +254,92 @@ var _ = a.Resource("comments", func() {
 		a.Response(d.Unauthorized, JSONAPIErrors)
 		a.Response(d.Forbidden, JSONAPIErrors)
 	})
-
+	a.Action("revisions", func() {
+		a.Routing(
+			a.GET("/:commentId/revisions"),
+		)
+		a.Description(`List the revisions of the comment with given commentId, oldest first.
+The revisions of a deleted comment are only visible to the space collaborators.`)
+		a.Params(func() {
+			a.Param("commentId", d.UUID, "commentId")
+		})
+		a.Response(d.OK, commentRevisionList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+	a.Action("list-replies", func() {
+		a.Routing(
+			a.GET("/:commentId/replies"),
+		)
+		a.Description("List the replies to the comment with the given commentId, oldest first.")
+		a.Params(func() {
+			a.Param("commentId", d.UUID, "commentId")
+			a.Param("page[offset]", d.String, `Paging start position is a string pointing to
+			the beginning of pagination.  The value starts from 0 onwards.`)
+			a.Param("page[limit]", d.Integer, `Paging size is the number of items in a page`)
+		})
+		a.UseTrait("conditional")
+		a.Response(d.OK, commentArray)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+	a.Action("create-reply", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:commentId/replies"),
+		)
+		a.Description("Creates a reply to the comment with the given commentId.")
+		a.Params(func() {
+			a.Param("commentId", d.UUID, "commentId")
+		})
+		a.Payload(createSingleComment)
+		a.Response(d.Created, "/comments/.*", func() {
+			a.Media(commentSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
+	a.Action("add-reaction", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:commentId/reactions"),
+		)
+		a.Description("Adds a reaction of the current user to the comment with the given commentId.")
+		a.Params(func() {
+			a.Param("commentId", d.UUID, "commentId")
+		})
+		a.Payload(createSingleReaction)
+		a.Response(d.OK, func() {
+			a.Media(commentSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
+	a.Action("remove-reaction", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:commentId/reactions/:emoji"),
+		)
+		a.Description("Removes a reaction of the current user from the comment with the given commentId.")
+		a.Params(func() {
+			a.Param("commentId", d.UUID, "commentId")
+			a.Param("emoji", d.String, "emoji of the reaction to remove")
+		})
+		a.Response(d.OK, func() {
+			a.Media(commentSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
 })
 
 var _ = a.Resource("work_item_comments", func() {
@@ -180,11 +349,16 @@ var _ = a.Resource("work_item_comments", func() {
 		a.Routing(
 			a.GET("comments"),
 		)
-		a.Description("List comments associated with the given work item")
+		a.Description(`List comments associated with the given work item.
+Space collaborators can ask for the deleted comments to be listed as tombstones.`)
 		a.Params(func() {
+			a.Param("include_deleted", d.Boolean, `List the deleted comments as tombstones, only allowed
+			for space collaborators`)
 			a.Param("page[offset]", d.String, `Paging start position is a string pointing to
 			the beginning of pagination.  The value starts from 0 onwards.`)
 			a.Param("page[limit]", d.Integer, `Paging size is the number of items in a page`)
+			a.Param("top_level", d.Boolean, `Only list the comments that are no replies, the replies
+			of a comment are listed with the list-replies action of the comments`)
 		})
 		a.UseTrait("conditional")
 		a.Response(d.OK, commentArray)
@@ -192,6 +366,7 @@ var _ = a.Resource("work_item_comments", func() {
 		a.Response(d.BadRequest, JSONAPIErrors)
 		a.Response(d.InternalServerError, JSONAPIErrors)
 		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
 	})
 	a.Action("relations", func() {
 		a.Routing(
@@ -73,6 +73,36 @@ var TrackerQuery = a.MediaType("application/vnd.trackerquery+json", func() {
 	})
 })
 
+// TrackerQuerySyncStatus represents the outcome of the last runs of a tracker query
+var TrackerQuerySyncStatus = a.MediaType("application/vnd.trackerquerysyncstatus+json", func() {
+	a.TypeName("TrackerQuerySyncStatus")
+	a.Description("Sync status of a tracker query")
+	a.Attribute("trackerQueryID", d.String, "ID of the tracker query")
+	a.Attribute("lastRunAt", d.DateTime, "When the query was last run")
+	a.Attribute("lastSuccessAt", d.DateTime, "When the query was last run without errors")
+	a.Attribute("highWaterMark", d.DateTime, "Latest remote update time of the synced items, the next run only fetches the items updated since then")
+	a.Attribute("fetchedCount", d.Integer, "Number of items fetched by the last run")
+	a.Attribute("syncedCount", d.Integer, "Number of items synced by the last run")
+	a.Attribute("failedCount", d.Integer, "Number of items that failed to sync in the last run")
+	a.Attribute("lastError", d.String, "Error of the last run, if any")
+
+	a.Required("trackerQueryID")
+	a.Required("fetchedCount")
+	a.Required("syncedCount")
+	a.Required("failedCount")
+
+	a.View("default", func() {
+		a.Attribute("trackerQueryID")
+		a.Attribute("lastRunAt")
+		a.Attribute("lastSuccessAt")
+		a.Attribute("highWaterMark")
+		a.Attribute("fetchedCount")
+		a.Attribute("syncedCount")
+		a.Attribute("failedCount")
+		a.Attribute("lastError")
+	})
+})
+
 var trackerQueryRelationships = a.Type("TrackerQueryRelationships", func() {
 	a.Attribute("space", relationSpaces, "This defines the owning space of this work item type.")
 })
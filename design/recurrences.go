@@ -0,0 +1,202 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var recurrence = a.Type("Recurrence", func() {
+	a.Description(`JSONAPI store for the data of a recurring work item. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("recurrences")
+	})
+	a.Attribute("id", d.UUID, "ID of the recurrence", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", recurrenceAttributes)
+	a.Attribute("relationships", recurrenceRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var recurrenceAttributes = a.Type("RecurrenceAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a recurrence. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("name", d.String, "The name of the recurrence", func() {
+		a.MinLength(1)
+		a.Example("Release checklist")
+	})
+	a.Attribute("fields", a.HashOf(d.String, d.Any), "The field values of the work items to create; a title is required", func() {
+		a.Example(map[string]interface{}{"system.title": "Release checklist"})
+	})
+	a.Attribute("schedule", d.String, `The cron schedule to create the work items on, with an optional seconds field,
+e.g. "0 0 9 * * MON" or "@weekly"`, func() {
+		a.Example("0 0 9 * * MON")
+	})
+	a.Attribute("current-iteration", d.Boolean, "Whether the work items are put into the active iteration of the space")
+	a.Attribute("skip-if-open", d.Boolean, "Whether to skip an occurrence while the work item of the previous one is still open")
+	a.Attribute("enabled", d.Boolean, "Whether work items are created on the schedule (defaults to true)")
+	a.Attribute("last-run-at", d.DateTime, "(read-only) When the schedule was last due", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("created-at", d.DateTime, "When the recurrence was created", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("updated-at", d.DateTime, "When the recurrence was updated", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+})
+
+var recurrenceRelationships = a.Type("RecurrenceRelationships", func() {
+	a.Attribute("space", relationGeneric, "The space in which the work items are created")
+	a.Attribute("workitemtype", relationGeneric, "The type of the work items to create")
+	a.Attribute("creator", relationGeneric, "The user who creates the work items")
+})
+
+var recurrenceListMeta = a.Type("RecurrenceListMeta", func() {
+	a.Attribute("totalCount", d.Integer)
+	a.Required("totalCount")
+})
+
+var recurrenceSingle = JSONSingle(
+	"Recurrence", "Holds a single recurrence",
+	recurrence,
+	nil)
+
+var recurrenceList = JSONList(
+	"Recurrence", "Holds the list of recurrences",
+	recurrence,
+	nil,
+	recurrenceListMeta)
+
+var occurrence = a.Type("Occurrence", func() {
+	a.Description(`A work item that was created for a recurrence`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("occurrences")
+	})
+	a.Attribute("id", d.UUID, "ID of the occurrence")
+	a.Attribute("attributes", occurrenceAttributes)
+	a.Attribute("relationships", occurrenceRelationships)
+	a.Required("type", "id", "attributes")
+})
+
+var occurrenceAttributes = a.Type("OccurrenceAttributes", func() {
+	a.Attribute("created-at", d.DateTime, "When the work item was created", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+})
+
+var occurrenceRelationships = a.Type("OccurrenceRelationships", func() {
+	a.Attribute("work-item", relationGeneric, "The work item that was created")
+})
+
+var occurrenceListMeta = a.Type("OccurrenceListMeta", func() {
+	a.Attribute("totalCount", d.Integer)
+	a.Required("totalCount")
+})
+
+var occurrenceList = JSONList(
+	"Occurrence", "Holds the history of the work items created for a recurrence",
+	occurrence,
+	nil,
+	occurrenceListMeta)
+
+var _ = a.Resource("recurrences", func() {
+	a.BasePath("/recurrences")
+
+	a.Action("show", func() {
+		a.Routing(
+			a.GET("/:recurrenceID"),
+		)
+		a.Description("Retrieve the recurrence with the given id.")
+		a.Params(func() {
+			a.Param("recurrenceID", d.UUID, "ID of the recurrence")
+		})
+		a.UseTrait("conditional")
+		a.Response(d.OK, recurrenceSingle)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/:recurrenceID"),
+		)
+		a.Description("Update the recurrence with the given id.")
+		a.Params(func() {
+			a.Param("recurrenceID", d.UUID, "ID of the recurrence")
+		})
+		a.Payload(recurrenceSingle)
+		a.Response(d.OK, recurrenceSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:recurrenceID"),
+		)
+		a.Description("Delete the recurrence with the given id. The work items created for it are kept.")
+		a.Params(func() {
+			a.Param("recurrenceID", d.UUID, "ID of the recurrence")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("occurrences", func() {
+		a.Routing(
+			a.GET("/:recurrenceID/occurrences"),
+		)
+		a.Description("List the work items created for the recurrence with the given id, latest first.")
+		a.Params(func() {
+			a.Param("recurrenceID", d.UUID, "ID of the recurrence")
+		})
+		a.Response(d.OK, occurrenceList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+})
+
+var _ = a.Resource("space_recurrences", func() {
+	a.Parent("space")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET("recurrences"),
+		)
+		a.Description("List the recurrences of the given space.")
+		a.Response(d.OK, recurrenceList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("recurrences"),
+		)
+		a.Description("Create a recurrence in the given space. The type of the work items is given in the workitemtype relationship.")
+		a.Payload(recurrenceSingle)
+		a.Response(d.Created, "/recurrences/.*", func() {
+			a.Media(recurrenceSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
@@ -0,0 +1,217 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var release = a.Type("Release", func() {
+	a.Description(`JSONAPI store for the data of a release. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("releases")
+	})
+	a.Attribute("id", d.UUID, "ID of the release", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", releaseAttributes)
+	a.Attribute("relationships", releaseRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var releaseAttributes = a.Type("ReleaseAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a release. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("name", d.String, "The release name", nameValidationFunction)
+	a.Attribute("description", d.String, "Description of the release", func() {
+		a.Example("First release with the new planner")
+	})
+	a.Attribute("target-date", d.DateTime, "When the release is planned to ship", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("status", d.String, "Status of the release", func() {
+		a.Enum("planned", "active", "released")
+	})
+	a.Attribute("released-at", d.DateTime, "When the release was shipped", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("archived-at", d.DateTime, "When the release was archived", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("created-at", d.DateTime, "When the release was created", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("updated-at", d.DateTime, "When the release was updated", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("version", d.Integer, "Version for optimistic concurrency control (optional during creating)", func() {
+		a.Example(23)
+	})
+})
+
+var releaseRelationships = a.Type("ReleaseRelations", func() {
+	a.Attribute("space", relationGeneric, "This defines the owning space")
+})
+
+var releaseList = JSONList(
+	"Release", "Holds the list of releases",
+	release,
+	nil,
+	nil)
+
+var releaseSingle = JSONSingle(
+	"Release", "Holds a single release",
+	release,
+	nil)
+
+var releaseTypeCount = a.Type("ReleaseTypeCount", func() {
+	a.Attribute("work-item-type", relationGeneric, "The work item type")
+	a.Attribute("name", d.String, "Name of the work item type")
+	a.Attribute("open", d.Integer, "Number of open work items of the type")
+	a.Attribute("closed", d.Integer, "Number of closed work items of the type")
+	a.Required("work-item-type", "name", "open", "closed")
+})
+
+var releaseReadinessAttributes = a.Type("ReleaseReadinessAttributes", func() {
+	a.Attribute("total", d.Integer, "Number of work items of the release")
+	a.Attribute("open", d.Integer, "Number of open work items of the release")
+	a.Attribute("closed", d.Integer, "Number of closed work items of the release")
+	a.Attribute("types", a.ArrayOf(releaseTypeCount), "Open and closed work items per type")
+	a.Attribute("blocking", a.ArrayOf(relationGeneric), "Open work items of the release that block other work items")
+	a.Required("total", "open", "closed", "types", "blocking")
+})
+
+var releaseReadiness = a.Type("ReleaseReadiness", func() {
+	a.Description(`Readiness report of a release`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("release-readiness")
+	})
+	a.Attribute("id", d.UUID, "ID of the release")
+	a.Attribute("attributes", releaseReadinessAttributes)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "id", "attributes")
+})
+
+var releaseReadinessSingle = JSONSingle(
+	"ReleaseReadiness", "Holds the readiness report of a release",
+	releaseReadiness,
+	nil)
+
+var _ = a.Resource("release", func() {
+	a.Parent("space")
+	a.BasePath("/releases")
+
+	a.Action("show", func() {
+		a.Routing(
+			a.GET("/:releaseID"),
+		)
+		a.Description("Retrieve the release with the given id.")
+		a.Params(func() {
+			a.Param("releaseID", d.UUID, "ID of the release")
+		})
+		a.Response(d.OK, releaseSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List the releases of the space by target date.")
+		a.Params(func() {
+			a.Param("archived", d.Boolean, "Whether to list the archived releases too")
+		})
+		a.Response(d.OK, releaseList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description("Create a release in the space.")
+		a.Payload(releaseSingle)
+		a.Response(d.Created, "/releases/.*", func() {
+			a.Media(releaseSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+	})
+
+	a.Action("update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/:releaseID"),
+		)
+		a.Description("Update the release with the given id.")
+		a.Params(func() {
+			a.Param("releaseID", d.UUID, "ID of the release to update")
+		})
+		a.Payload(releaseSingle)
+		a.Response(d.OK, func() {
+			a.Media(releaseSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("archive", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:releaseID/archive"),
+		)
+		a.Description("Archive the released release with the given id.")
+		a.Params(func() {
+			a.Param("releaseID", d.UUID, "ID of the release to archive")
+		})
+		a.Response(d.OK, func() {
+			a.Media(releaseSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("readiness", func() {
+		a.Routing(
+			a.GET("/:releaseID/readiness"),
+		)
+		a.Description("Report the open and closed work items of the release per type and the blocking ones.")
+		a.Params(func() {
+			a.Param("releaseID", d.UUID, "ID of the release")
+		})
+		a.Response(d.OK, releaseReadinessSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("notes", func() {
+		a.Routing(
+			a.GET("/:releaseID/notes"),
+		)
+		a.Description("Generate the release notes of the release in Markdown from its closed work items.")
+		a.Params(func() {
+			a.Param("releaseID", d.UUID, "ID of the release")
+		})
+		a.Response(d.OK, "text/markdown")
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+})
@@ -95,6 +95,39 @@ var _ = a.Resource("trackerquery", func() {
 		a.Response(d.InternalServerError, JSONAPIErrors)
 		a.Response(d.NotFound, JSONAPIErrors)
 	})
+	a.Action("show-sync-status", func() {
+		a.Routing(
+			a.GET("/:id/sync"),
+		)
+		a.Description("Retrieve the sync status of the tracker query for the given id.")
+		a.Params(func() {
+			a.Param("id", d.String, "id")
+		})
+		a.Response(d.OK, func() {
+			a.Media(TrackerQuerySyncStatus)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+	a.Action("sync", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:id/sync"),
+		)
+		a.Description("Fetch and import the remote items of the tracker query immediately. Fails with a conflict while the tracker query is already being run.")
+		a.Params(func() {
+			a.Param("id", d.String, "id")
+		})
+		a.Response(d.OK, func() {
+			a.Media(TrackerQuerySyncStatus)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+	})
 })
 
 var nameValidationFunction = func() {
@@ -0,0 +1,76 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var scmActivity = a.Type("ScmActivity", func() {
+	a.Description(`JSONAPI store for the data of a commit, branch or pull request referencing a work item. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("scm-activities")
+	})
+	a.Attribute("id", d.UUID, "ID of the activity", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", scmActivityAttributes)
+	a.Attribute("relationships", scmActivityRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var scmActivityAttributes = a.Type("ScmActivityAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of an SCM activity. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("kind", d.String, "The kind of the activity", func() {
+		a.Enum("commit", "branch", "pull-request")
+	})
+	a.Attribute("ref", d.String, "The SHA of the commit, the name of the branch or the number of the pull request", func() {
+		a.Example("3f2a9c1")
+	})
+	a.Attribute("title", d.String, "The first line of the commit message or the title of the pull request", func() {
+		a.Example("Fix the login redirect, fixes #12")
+	})
+	a.Attribute("url", d.String, "The web URL of the activity", func() {
+		a.Example("https://github.com/fabric8-services/fabric8-wit/pull/42")
+	})
+	a.Attribute("author", d.String, "The login or name of the author", func() {
+		a.Example("octocat")
+	})
+	a.Attribute("state", d.String, "The state of the pull request", func() {
+		a.Enum("open", "closed", "merged")
+	})
+	a.Attribute("closes", d.Boolean, "Whether the activity closes the work item, e.g. with 'fixes #12'")
+	a.Attribute("occurred-at", d.DateTime, "When the commit was authored or the pull request was last updated", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("created-at", d.DateTime, "When the activity was linked", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Required("kind", "ref", "closes")
+})
+
+var scmActivityRelationships = a.Type("ScmActivityRelationships", func() {
+	a.Attribute("work-item", relationGeneric, "The referenced work item")
+	a.Attribute("codebase", relationGeneric, "The codebase of the activity")
+})
+
+var scmActivityList = JSONList(
+	"ScmActivity", "Holds the list of SCM activities",
+	scmActivity,
+	nil,
+	nil)
+
+var _ = a.Resource("work_item_scm_activities", func() {
+	a.Parent("workitem")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET("scm-activities"),
+		)
+		a.Description("List the commits, branches and pull requests referencing the given work item, latest first.")
+		a.Response(d.OK, scmActivityList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+})
@@ -68,6 +68,11 @@ var spaceAttributes = a.Type("SpaceAttributes", func() {
 	a.Attribute("description", d.String, "Description for the space", func() {
 		a.Example("This is the foobar collaboration space")
 	})
+	a.Attribute("key", d.String, `Short key of the space that makes its work items addressable as <key>-<number>,
+e.g. PLAT-123. Former keys remain resolvable after the key was changed. An empty key removes it.`, func() {
+		a.Pattern("^([A-Z][A-Z0-9]{1,9})?$")
+		a.Example("PLAT")
+	})
 	a.Attribute("version", d.Integer, "Version for optimistic concurrency control (optional during creating)", func() {
 		a.Example(23)
 	})
@@ -31,12 +31,41 @@ var trackerAttributes = a.Type("TrackerAttributes", func() {
 		a.Example("#ffa7cb")
 	})
 	a.Attribute("Type", d.String, "Type of the tracker", func() {
-		a.Enum("github", "jira")
+		a.Enum("github", "jira", "gitlab")
+	})
+	a.Attribute("write-back", d.Boolean, "Whether local changes of the linked work items are pushed back to the tracker", func() {
+		a.Example(false)
+	})
+	a.Attribute("webhook-secret", d.String, "Secret verifying the webhook requests of the tracker, never returned", func() {
+		a.Example("s3cr3t")
+	})
+	a.Attribute("field-mappings", a.ArrayOf(trackerFieldMapping), "Mappings of remote attributes to fields of the work item type of the tracker, on top of the built-in ones")
+	a.Attribute("conflict-policy", d.String, "What happens when an item was changed both locally and remotely since the last sync", func() {
+		a.Enum("remote-wins", "local-wins", "flag")
 	})
 	a.Required("URL", "Type")
 })
 
+var trackerFieldMapping = a.Type("TrackerFieldMapping", func() {
+	a.Description(`Mapping of an attribute of the remote items to a field of the work item type of a tracker`)
+	a.Attribute("expression", d.String, "Key of the attribute in the flattened remote item, a '?' stands for every index of an array", func() {
+		a.Example("fields.components.?.name")
+	})
+	a.Attribute("field", d.String, "Name of the work item field", func() {
+		a.Example("system.labels")
+	})
+	a.Attribute("converter", d.String, "Converter of the remote value", func() {
+		a.Enum("string", "list", "enum", "user", "markup")
+	})
+	a.Attribute("values", a.HashOf(d.String, d.String), "Local values of the remote values for the enum converter")
+	a.Attribute("markup", d.String, "Markup of the markup converter, Markdown by default", func() {
+		a.Example("Markdown")
+	})
+	a.Required("expression", "field", "converter")
+})
+
 var trackerRelationships = a.Type("TrackerRelations", func() {
+	a.Attribute("workitemtype", relationGeneric, "The type of the work items the remote items are converted into, the bug type by default")
 })
 
 var trackerList = JSONList(
@@ -132,5 +161,23 @@ var _ = a.Resource("tracker", func() {
 		a.Response(d.Unauthorized, JSONAPIErrors)
 		a.Response(d.Forbidden, JSONAPIErrors)
 	})
+	a.Action("webhook", func() {
+		a.Routing(
+			a.POST("/:id/webhook"),
+		)
+		a.Description(`Receive an update of a remote item from the tracker. Requests are verified
+with the webhook secret of the tracker: Github requests must be signed with it,
+GitLab requests must carry it in the X-Gitlab-Token header and Jira requests in
+the "secret" query parameter.`)
+		a.Params(func() {
+			a.Param("id", d.UUID, "id")
+			a.Param("secret", d.String, "Webhook secret of Jira trackers")
+		})
+		a.Response(d.NoContent)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
 
 })
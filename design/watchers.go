@@ -0,0 +1,122 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var watcher = a.Type("Watcher", func() {
+	a.Description(`JSONAPI store for a user watching a work item, space, area or iteration. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("identities")
+	})
+	a.Attribute("id", d.UUID, "ID of the watching user", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("links", genericLinks)
+	a.Required("type", "id")
+})
+
+var watcherList = JSONList(
+	"Watcher", "Holds the list of users watching a work item, space, area or iteration",
+	watcher,
+	nil,
+	nil)
+
+var notificationPreferences = a.Type("NotificationPreferences", func() {
+	a.Description(`JSONAPI store for the notification preferences of a user. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("notification-preferences")
+	})
+	a.Attribute("id", d.UUID, "ID of the user")
+	a.Attribute("attributes", notificationPreferencesAttributes)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var notificationPreferencesAttributes = a.Type("NotificationPreferencesAttributes", func() {
+	a.Attribute("message-types", a.HashOf(d.String, d.Boolean), `Whether the user receives notifications of a type,
+e.g. {"workitem.create": true, "comment.create": false}. On update, types that are missing are left unchanged.`)
+	a.Required("message-types")
+})
+
+var notificationPreferencesSingle = JSONSingle(
+	"NotificationPreferences", "Holds the notification preferences of a user",
+	notificationPreferences,
+	nil)
+
+var _ = a.Resource("watchers", func() {
+	a.BasePath("/watchers/:targetType/:targetID")
+	a.Params(func() {
+		a.Param("targetType", d.String, "The type of the watched entity", func() {
+			a.Enum("workitems", "spaces", "areas", "iterations")
+		})
+		a.Param("targetID", d.UUID, "ID of the watched entity")
+	})
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List the users watching the given work item, space, area or iteration.")
+		a.Response(d.OK, watcherList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("watch", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description("Watch the given work item, space, area or iteration as the current user.")
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
+
+	a.Action("unwatch", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE(""),
+		)
+		a.Description("Stop watching the given work item, space, area or iteration as the current user.")
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
+})
+
+var _ = a.Resource("notification_preferences", func() {
+	a.BasePath("/user/notificationpreferences")
+
+	a.Action("show", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("Get the notification preferences of the current user.")
+		a.Response(d.OK, notificationPreferencesSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
+
+	a.Action("update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH(""),
+		)
+		a.Description("Enable or disable the notifications of the given types for the current user.")
+		a.Payload(notificationPreferencesSingle)
+		a.Response(d.OK, notificationPreferencesSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
+})
@@ -0,0 +1,56 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+// workItemTypeTransition describes an allowed change of the state of the
+// work items of a type
+var workItemTypeTransition = a.Type("WorkItemTypeTransition", func() {
+	a.Description("A transition allows to change the state of a work item from one value to another")
+	a.Attribute("from", d.String, `The state before the transition or "*" for any state`, func() {
+		a.Example("In Progress")
+	})
+	a.Attribute("to", d.String, "The state after the transition", func() {
+		a.Example("Resolved")
+	})
+	a.Attribute("required-fields", a.ArrayOf(d.String), "The fields that must be set to perform the transition", func() {
+		a.Example([]string{"system.assignees"})
+	})
+	a.Attribute("role", d.String, "The role that is required to perform the transition", func() {
+		a.Enum("creator", "assignee", "space_owner")
+	})
+	a.Required("from", "to")
+})
+
+var workItemTransition = a.Type("WorkItemTransition", func() {
+	a.Description(`JSONAPI store for the data of a transition of a work item. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("workitemtransitions")
+	})
+	a.Attribute("attributes", workItemTypeTransition)
+	a.Required("type", "attributes")
+})
+
+var workItemTransitionList = JSONList(
+	"WorkItemTransition", "Holds the transitions that the current user can perform on a work item",
+	workItemTransition,
+	nil,
+	nil,
+)
+
+var _ = a.Resource("work_item_transitions", func() {
+	a.Parent("workitem")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET("transitions"),
+		)
+		a.Description("List the transitions that the current user can perform from the current state of the given work item")
+		a.Response(d.OK, workItemTransitionList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+})
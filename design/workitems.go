@@ -46,8 +46,73 @@ var workItemRelationships = a.Type("WorkItemRelationships", func() {
 	a.Attribute("parent", relationKindUUID, "This defines the parent of this work item.")
 	a.Attribute("workItemLinks", relationGeneric, "List of links in which this work item is involved")
 	a.Attribute("events", relationGeneric, "List of events in which this work item is involved")
+	a.Attribute("referencedWorkItems", relationGenericList, `The work items referenced by fields of kind "workitem". The
+meta object maps the name of each of these fields to the IDs of the work items it references.`)
 })
 
+// workItemMove defines the payload to move a work item to another space
+var workItemMove = a.Type("WorkItemMove", func() {
+	a.Attribute("type", d.String, func() {
+		a.Enum("workitem-moves")
+	})
+	a.Attribute("attributes", workItemMoveAttributes)
+	a.Required("type", "attributes")
+})
+
+var workItemMoveAttributes = a.Type("WorkItemMoveAttributes", func() {
+	a.Attribute("version", d.Integer, "Version of the work item for optimistic concurrency control", func() {
+		a.Example(3)
+	})
+	a.Attribute("space", d.UUID, "ID of the space to move the work item to")
+	a.Attribute("type", d.UUID, `ID of the work item type in the target space. By default the current type is kept
+if the target space shares it, otherwise the type with the same name is used.`)
+	a.Attribute("iteration", d.UUID, "ID of the iteration in the target space. Defaults to the root iteration.")
+	a.Attribute("area", d.UUID, "ID of the area in the target space. Defaults to the root area.")
+	a.Attribute("labels", a.HashOf(d.String, d.UUID), `Maps the IDs of the labels of the work item to the IDs of labels
+in the target space. Other labels are replaced by the label with the same name in the target space.`)
+	a.Attribute("releases", a.HashOf(d.String, d.UUID), `Maps the IDs of the releases referenced by fields of the work item
+to the IDs of releases in the target space. Other releases are replaced by the release with the same name in the
+target space.`)
+	a.Attribute("remove-links", d.Boolean, `Links and references to other work items can't cross spaces, so a work item
+that has any can only be moved if they are removed. When set, the links of the work item are removed before it is
+moved; references by fields have to be removed by updating the work items.`)
+	a.Required("version", "space")
+})
+
+var workItemMoveSingle = JSONSingle(
+	"WorkItemMove", "Holds the request to move a work item to another space",
+	workItemMove,
+	nil)
+
+// workItemClone defines the payload to clone a work item and the response
+// with the IDs of the clones
+var workItemClone = a.Type("WorkItemClone", func() {
+	a.Attribute("type", d.String, func() {
+		a.Enum("workitem-clones")
+	})
+	a.Attribute("attributes", workItemCloneAttributes)
+	a.Attribute("relationships", workItemCloneRelationships)
+	a.Required("type")
+})
+
+var workItemCloneAttributes = a.Type("WorkItemCloneAttributes", func() {
+	a.Attribute("children", d.Boolean, "Whether to clone the work items below the work item in tree links as well")
+	a.Attribute("labels", d.Boolean, "Whether to copy the labels (default: true)")
+	a.Attribute("reset-state", d.Boolean, "Whether the clones start in the default state of their type")
+	a.Attribute("reset-assignees", d.Boolean, "Whether the clones are left unassigned")
+	a.Attribute("reset-iteration", d.Boolean, "Whether the clones are put into the root iteration of the space")
+	a.Attribute("mapping", a.HashOf(d.String, d.UUID), "(read-only) The IDs of the clones by the IDs of the original work items")
+})
+
+var workItemCloneRelationships = a.Type("WorkItemCloneRelationships", func() {
+	a.Attribute("clone", relationGeneric, "(read-only) The clone of the work item")
+})
+
+var workItemCloneSingle = JSONSingle(
+	"WorkItemClone", "Holds the request to clone a work item and the IDs of the clones",
+	workItemClone,
+	nil)
+
 // relationBaseType is top level block for WorkItemType relationship
 var relationBaseType = a.Type("RelationBaseType", func() {
 	a.Attribute("data", baseTypeData)
@@ -161,6 +226,48 @@ var _ = a.Resource("workitem", func() {
 		a.Response(d.Forbidden, JSONAPIErrors)
 	})
 
+	a.Action("restore", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:wiID/restore"),
+		)
+		a.Description("Restore the deleted work item with the given id along with its deleted links and comments.")
+		a.Params(func() {
+			a.Param("wiID", d.UUID, "ID of the work item to restore")
+		})
+		a.Response(d.OK, func() {
+			a.Media(workItemSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("revert", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:wiID/revisions/:revisionID/revert"),
+		)
+		a.Description("Set the fields of the work item back to the values of the given revision.")
+		a.Params(func() {
+			a.Param("wiID", d.UUID, "ID of the work item to revert")
+			a.Param("revisionID", d.UUID, "ID of the revision to revert to")
+			a.Param("version", d.Integer, "Current version of the work item")
+			a.Required("version")
+		})
+		a.Response(d.OK, func() {
+			a.Media(workItemSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
 	a.Action("update", func() {
 		a.Security("jwt")
 		a.Routing(
@@ -181,6 +288,50 @@ var _ = a.Resource("workitem", func() {
 		a.Response(d.Unauthorized, JSONAPIErrors)
 		a.Response(d.Forbidden, JSONAPIErrors)
 	})
+
+	a.Action("move", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:wiID/move"),
+		)
+		a.Description(`Move the work item with the given ID to another space. The work item gets a new number in the
+target space and its former number remains resolvable. Comments, attachments and the history of the work item
+are kept.`)
+		a.Params(func() {
+			a.Param("wiID", d.UUID, "ID of the work item to move")
+		})
+		a.Payload(workItemMoveSingle)
+		a.Response(d.OK, func() {
+			a.Media(workItemSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("clone", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:wiID/clone"),
+		)
+		a.Description(`Clone the work item with the given ID and optionally the work items below it in tree links.
+Each clone is linked to its original.`)
+		a.Params(func() {
+			a.Param("wiID", d.UUID, "ID of the work item to clone")
+		})
+		a.Payload(workItemCloneSingle)
+		a.Response(d.Created, "/workitems/.*", func() {
+			a.Media(workItemCloneSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
 })
 
 // endpoints that depend on the space id
@@ -296,3 +447,24 @@ var _ = a.Resource("named_work_items", func() {
 		a.Response(d.NotFound, JSONAPIErrors)
 	})
 })
+
+var _ = a.Resource("work_item_keys", func() {
+	a.BasePath("/workitemkeys")
+	a.Action("show", func() {
+		a.Routing(
+			a.GET("/:key"),
+		)
+		a.Description(`Retrieve a work item from its human-readable key (e.g. PLAT-123). Keys using a former key
+of the space are resolved as well.`)
+		a.Params(func() {
+			a.Param("key", d.String, "Key of the work item to show", func() {
+				a.Pattern("^[A-Za-z][A-Za-z0-9]{1,9}-[0-9]+$")
+				a.Example("PLAT-123")
+			})
+		})
+		a.Response(d.TemporaryRedirect)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+})
@@ -10,12 +10,36 @@ var fieldType = a.Type("fieldType", func() {
 	a.Description("A fieldType describes the values a particular field can hold")
 	a.Attribute("kind", d.String, "The constant indicating the kind of type, for example 'string' or 'enum' or 'instant'")
 	a.Attribute("componentType", d.String, "The kind of type of the individual elements for a list type. Required for list types. Must be a simple type, not  enum or list")
-	a.Attribute("baseType", d.String, "The kind of type of the enumeration values for an enum type. Required for enum types. Must be a simple type, not  enum or list")
+	a.Attribute("baseType", d.String, "The kind of type of the enumeration values for an enum type or of the calculated value for a computed type. Required for enum and computed types. Must be a simple type, not  enum or list")
 	a.Attribute("values", a.ArrayOf(d.Any), "The possible values for an enum type. The values must be of a type convertible to the base type")
 	a.Attribute("defaultValue", d.Any, "Optional default value (if any)")
+	a.Attribute("expression", d.String, `The formula of a computed type, for example "estimate - spent" or "sum(storypoints)". Required for computed types`)
 	a.Required("kind")
 })
 
+// fieldConstraints restrict the values of a field beyond its type
+var fieldConstraints = a.Type("fieldConstraints", func() {
+	a.Description("Optional constraints that the value of a field must satisfy")
+	a.Attribute("min", d.Number, "Inclusive lower bound of an integer or float field")
+	a.Attribute("max", d.Number, "Inclusive upper bound of an integer or float field")
+	a.Attribute("min-length", d.Integer, "Minimal number of characters of a string or URL field")
+	a.Attribute("max-length", d.Integer, "Maximal number of characters of a string or URL field")
+	a.Attribute("pattern", d.String, "Regular expression that the value of a string or URL field must match")
+	a.Attribute("min-date", d.DateTime, "Earliest allowed value of an instant field")
+	a.Attribute("max-date", d.DateTime, "Latest allowed value of an instant field")
+	a.Attribute("max-items", d.Integer, "Maximal number of elements of a list field")
+	a.Attribute("required-when", fieldCondition, "The field is required when this condition holds")
+})
+
+// fieldCondition is true if a field has one of the given values
+var fieldCondition = a.Type("fieldCondition", func() {
+	a.Attribute("field", d.String, "Name of the field to check", func() {
+		a.Example("system.state")
+	})
+	a.Attribute("in", a.ArrayOf(d.Any), "The values for which the condition holds")
+	a.Required("field", "in")
+})
+
 // fieldDefinition defines the possible values for a field in a work item type
 var fieldDefinition = a.Type("fieldDefinition", func() {
 	a.Description("A fieldDefinition aggregates a fieldType and additional field metadata")
@@ -29,6 +53,7 @@ var fieldDefinition = a.Type("fieldDefinition", func() {
 		a.Example("The iteration field tells to which iteration a work item belongs.")
 		a.MinLength(1)
 	})
+	a.Attribute("constraints", fieldConstraints)
 	a.Required("required", "type", "label", "description")
 })
 
@@ -57,6 +82,8 @@ var workItemTypeAttributes = a.Type("WorkItemTypeAttributes", func() {
 	// TODO: Maybe this needs to be abandoned at some point
 	a.Attribute("extendedTypeName", d.UUID, "If newly created type extends any existing type (This is never present in any response and is only optional when creating.)")
 
+	a.Attribute("transitions", a.ArrayOf(workItemTypeTransition), "The allowed changes of the state of work items of this type. If empty, the state can be changed to any value of the state field.")
+
 	a.Attribute("icon", d.String, "CSS class string for an icon to use. See http://fontawesome.io/icons/ or http://www.patternfly.org/styles/icons/#_ for examples.", func() {
 		a.Example("fa-bug")
 		a.MinLength(1)
@@ -0,0 +1,219 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var worklog = a.Type("Worklog", func() {
+	a.Description(`JSONAPI store for the data of a worklog. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("worklogs")
+	})
+	a.Attribute("id", d.UUID, "ID of the worklog", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", worklogAttributes)
+	a.Attribute("relationships", worklogRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var worklogAttributes = a.Type("WorklogAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a worklog. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("duration", d.Integer, "The time spent in minutes", func() {
+		a.Minimum(1)
+		a.Example(90)
+	})
+	a.Attribute("work-date", d.DateTime, "When the work was done (defaults to the time of creation)", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("comment", d.String, "What the time was spent on", func() {
+		a.Example("Reproduced the bug")
+	})
+	a.Attribute("remaining-estimate", d.Integer, `The new remaining estimate of the work item in minutes.
+Only used on create and update; if it isn't given, the remaining estimate is reduced by the duration.`, func() {
+		a.Minimum(0)
+		a.Example(240)
+	})
+	a.Attribute("created-at", d.DateTime, "When the worklog was created", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("updated-at", d.DateTime, "When the worklog was updated", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+})
+
+var worklogRelationships = a.Type("WorklogRelationships", func() {
+	a.Attribute("work-item", relationGeneric, "The work item on which the time was spent")
+	a.Attribute("identity", relationGeneric, "The user who spent the time")
+})
+
+var worklogListMeta = a.Type("WorklogListMeta", func() {
+	a.Attribute("totalCount", d.Integer)
+	a.Required("totalCount")
+})
+
+var worklogSingle = JSONSingle(
+	"Worklog", "Holds a single worklog",
+	worklog,
+	nil)
+
+var worklogList = JSONList(
+	"Worklog", "Holds the list of worklogs",
+	worklog,
+	pagingLinks,
+	worklogListMeta)
+
+var worklogTotals = a.Type("WorklogTotals", func() {
+	a.Description(`The time logged on a work item or in an iteration`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("worklog-totals")
+	})
+	a.Attribute("id", d.UUID, "ID of the work item or iteration")
+	a.Attribute("attributes", worklogTotalsAttributes)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "id", "attributes")
+})
+
+var worklogTotalsAttributes = a.Type("WorklogTotalsAttributes", func() {
+	a.Attribute("time-spent", d.Integer, "The time logged in minutes", func() {
+		a.Example(90)
+	})
+	a.Attribute("time-spent-total", d.Integer, "The time logged on the work item and its children in minutes", func() {
+		a.Example(360)
+	})
+	a.Attribute("users", a.ArrayOf(worklogUserTotal), "The time logged per user")
+	a.Required("time-spent")
+})
+
+var worklogUserTotal = a.Type("WorklogUserTotal", func() {
+	a.Attribute("identity", relationGeneric, "The user who logged the time")
+	a.Attribute("time-spent", d.Integer, "The time logged by the user in minutes", func() {
+		a.Example(90)
+	})
+	a.Required("identity", "time-spent")
+})
+
+var worklogTotalsSingle = JSONSingle(
+	"WorklogTotals", "Holds the time logged on a work item or in an iteration",
+	worklogTotals,
+	nil)
+
+var _ = a.Resource("worklogs", func() {
+	a.BasePath("/worklogs")
+
+	a.Action("show", func() {
+		a.Routing(
+			a.GET("/:worklogID"),
+		)
+		a.Description("Retrieve the worklog with the given id.")
+		a.Params(func() {
+			a.Param("worklogID", d.UUID, "ID of the worklog")
+		})
+		a.UseTrait("conditional")
+		a.Response(d.OK, worklogSingle)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/:worklogID"),
+		)
+		a.Description("Update the worklog with the given id.")
+		a.Params(func() {
+			a.Param("worklogID", d.UUID, "ID of the worklog")
+		})
+		a.Payload(worklogSingle)
+		a.Response(d.OK, worklogSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:worklogID"),
+		)
+		a.Description("Delete the worklog with the given id.")
+		a.Params(func() {
+			a.Param("worklogID", d.UUID, "ID of the worklog")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
+
+var _ = a.Resource("work_item_worklogs", func() {
+	a.Parent("workitem")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET("worklogs"),
+		)
+		a.Description("List the worklogs of the given work item, latest work first.")
+		a.Params(func() {
+			a.Param("page[offset]", d.String, `Paging start position is a string pointing to
+			the beginning of pagination.  The value starts from 0 onwards.`)
+			a.Param("page[limit]", d.Integer, `Paging size is the number of items in a page`)
+		})
+		a.Response(d.OK, worklogList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("worklogs"),
+		)
+		a.Description("Log time spent on the given work item.")
+		a.Payload(worklogSingle)
+		a.Response(d.Created, "/worklogs/.*", func() {
+			a.Media(worklogSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("totals", func() {
+		a.Routing(
+			a.GET("worklogs/totals"),
+		)
+		a.Description("Sum up the time logged on the given work item and its children.")
+		a.Response(d.OK, worklogTotalsSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+})
+
+var _ = a.Resource("iteration_worklogs", func() {
+	a.Parent("iteration")
+
+	a.Action("totals", func() {
+		a.Routing(
+			a.GET("worklogs/totals"),
+		)
+		a.Description("Sum up the time logged per user on the work items of the given iteration and its child iterations.")
+		a.Response(d.OK, worklogTotalsSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+})
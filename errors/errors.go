@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"errors"
 
@@ -164,10 +165,20 @@ type BadParameterError struct {
 	expectedValue          interface{}
 	hasExpectedValue       bool
 	preDefinedErrorMessage *string
+	// violations contains one error for every invalid parameter if more than
+	// one parameter was checked at once
+	violations []BadParameterError
 }
 
 // Error implements the error interface
 func (err BadParameterError) Error() string {
+	if len(err.violations) > 0 {
+		msgs := make([]string, len(err.violations))
+		for i, v := range err.violations {
+			msgs[i] = v.Error()
+		}
+		return strings.Join(msgs, "; ")
+	}
 	if err.hasExpectedValue {
 		return fmt.Sprintf(stBadParameterErrorExpectedMsg, err.parameter, err.value, err.expectedValue)
 	}
@@ -195,6 +206,30 @@ func NewBadParameterErrorFromString(errMessage string) BadParameterError {
 	return BadParameterError{preDefinedErrorMessage: &errMessage}
 }
 
+// NewBadParameterErrors returns a BadParameterError that combines the given
+// errors, e.g. to report every invalid field of a work item at once.
+func NewBadParameterErrors(violations ...BadParameterError) BadParameterError {
+	if len(violations) == 1 {
+		return violations[0]
+	}
+	return BadParameterError{violations: violations}
+}
+
+// Parameter returns the name of the invalid parameter. It is empty for errors
+// created from a string or from multiple errors.
+func (err BadParameterError) Parameter() string {
+	return err.parameter
+}
+
+// Violations returns the errors combined with NewBadParameterErrors or the
+// error itself if it is a single error.
+func (err BadParameterError) Violations() []BadParameterError {
+	if len(err.violations) > 0 {
+		return err.violations
+	}
+	return []BadParameterError{err}
+}
+
 // IsBadParameterError returns true if the cause of the given error can be
 // converted to an BadParameterError, which is returned as the second result.
 func IsBadParameterError(err error) (bool, error) {
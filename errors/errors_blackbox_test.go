@@ -44,6 +44,17 @@ func TestNewBadParameterError(t *testing.T) {
 	msg := "this is my predefined message returned from an external source"
 	err = errors.NewBadParameterErrorFromString(msg)
 	assert.Equal(t, msg, err.Error())
+
+	t.Run("multiple violations", func(t *testing.T) {
+		first := errors.NewBadParameterError("a", 1)
+		second := errors.NewBadParameterError("b", 2).Expected(3)
+		err := errors.NewBadParameterErrors(first, second)
+		assert.Equal(t, "Bad value for parameter 'a': '1'; Bad value for parameter 'b': '2' (expected: '3')", err.Error())
+		assert.Equal(t, []errors.BadParameterError{first, second}, err.Violations())
+		assert.Equal(t, []errors.BadParameterError{first}, first.Violations())
+		assert.Equal(t, "b", err.Violations()[1].Parameter())
+		assert.Equal(t, first, errors.NewBadParameterErrors(first))
+	})
 }
 
 func TestNewNotFoundError(t *testing.T) {
@@ -102,6 +102,7 @@ func init() {
 		"Query":            "querydsl",
 		"SpaceTemplate":    "spacetemplatedsl",
 		"Event":            "eventdsl",
+		"Activity":         "eventdsl",
 	}
 	// structures to ignore during code generation (mostly because they correspond to model structures which were already taken into account)
 	ignoredStructs = []string{
@@ -5,20 +5,27 @@ import (
 	"strconv"
 
 	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/analytics"
 	"github.com/fabric8-services/fabric8-wit/application"
 	"github.com/fabric8-services/fabric8-wit/area"
+	"github.com/fabric8-services/fabric8-wit/attachment"
 	"github.com/fabric8-services/fabric8-wit/codebase"
+	"github.com/fabric8-services/fabric8-wit/codebase/scm"
 	"github.com/fabric8-services/fabric8-wit/comment"
 	"github.com/fabric8-services/fabric8-wit/iteration"
 	"github.com/fabric8-services/fabric8-wit/label"
 	"github.com/fabric8-services/fabric8-wit/query"
+	"github.com/fabric8-services/fabric8-wit/release"
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
 	"github.com/fabric8-services/fabric8-wit/search"
 	"github.com/fabric8-services/fabric8-wit/space"
 	"github.com/fabric8-services/fabric8-wit/spacetemplate"
+	"github.com/fabric8-services/fabric8-wit/watcher"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/event"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/fabric8-services/fabric8-wit/workitem/recurrence"
+	"github.com/fabric8-services/fabric8-wit/workitem/worklog"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
 )
@@ -87,6 +94,9 @@ func (g *GormBase) Trackers() remoteworkitem.TrackerRepository {
 func (g *GormBase) TrackerQueries() remoteworkitem.TrackerQueryRepository {
 	return remoteworkitem.NewTrackerQueryRepository(g.db)
 }
+func (g *GormBase) TrackerQuerySyncStates() remoteworkitem.SyncStateRepository {
+	return remoteworkitem.NewSyncStateRepository(g.db)
+}
 
 func (g *GormBase) SearchItems() application.SearchRepository {
 	return search.NewGormSearchRepository(g.db)
@@ -117,6 +127,36 @@ func (g *GormBase) Comments() comment.Repository {
 	return comment.NewRepository(g.db)
 }
 
+// CommentReactions returns a comment reaction repository
+func (g *GormBase) CommentReactions() comment.ReactionRepository {
+	return comment.NewReactionRepository(g.db)
+}
+
+// CommentRevisions returns a comment revision repository
+func (g *GormBase) CommentRevisions() comment.RevisionRepository {
+	return comment.NewRevisionRepository(g.db)
+}
+
+// Attachments returns an attachment repository
+func (g *GormBase) Attachments() attachment.Repository {
+	return attachment.NewRepository(g.db)
+}
+
+// Worklogs returns a worklog repository
+func (g *GormBase) Worklogs() worklog.Repository {
+	return worklog.NewRepository(g.db)
+}
+
+// Watchers returns a watcher repository
+func (g *GormBase) Watchers() watcher.Repository {
+	return watcher.NewRepository(g.db)
+}
+
+// Recurrences returns a recurrence repository
+func (g *GormBase) Recurrences() recurrence.Repository {
+	return recurrence.NewRepository(g.db)
+}
+
 // Iterations returns a iteration repository
 func (g *GormBase) Iterations() iteration.Repository {
 	return iteration.NewIterationRepository(g.db)
@@ -132,11 +172,26 @@ func (g *GormBase) Labels() label.Repository {
 	return label.NewLabelRepository(g.db)
 }
 
+// Releases returns a release repository
+func (g *GormBase) Releases() release.Repository {
+	return release.NewRepository(g.db)
+}
+
 // Events returns a events repository
 func (g *GormBase) Events() event.Repository {
 	return event.NewEventRepository(g.db)
 }
 
+// Activities returns an activities repository
+func (g *GormBase) Activities() event.ActivityRepository {
+	return event.NewActivityRepository(g.db)
+}
+
+// Analytics returns an analytics repository
+func (g *GormBase) Analytics() analytics.Repository {
+	return analytics.NewRepository(g.db)
+}
+
 // Queries returns a queries repository
 func (g *GormBase) Queries() query.Repository {
 	return query.NewQueryRepository(g.db)
@@ -147,6 +202,11 @@ func (g *GormBase) Codebases() codebase.Repository {
 	return codebase.NewCodebaseRepository(g.db)
 }
 
+// SCMActivities returns an SCM activity repository
+func (g *GormBase) SCMActivities() scm.Repository {
+	return scm.NewRepository(g.db)
+}
+
 // SpaceTemplates returns a space template repository
 func (g *GormBase) SpaceTemplates() spacetemplate.Repository {
 	return spacetemplate.NewRepository(g.db)
@@ -106,6 +106,22 @@ func ErrorToJSONAPIError(ctx context.Context, err error) (app.JSONAPIError, int)
 // just want to return one error from the models package as a JSONAPI errors
 // array.
 func ErrorToJSONAPIErrors(ctx context.Context, err error) (*app.JSONAPIErrors, int) {
+	// report every violation of a combined bad parameter error separately
+	if badParamErr, ok := errs.Cause(err).(errors.BadParameterError); ok && len(badParamErr.Violations()) > 1 {
+		jerrors := app.JSONAPIErrors{}
+		var httpStatusCode int
+		for _, violation := range badParamErr.Violations() {
+			var jerr app.JSONAPIError
+			jerr, httpStatusCode = ErrorToJSONAPIError(ctx, violation)
+			if violation.Parameter() != "" {
+				jerr.Source = map[string]interface{}{
+					"pointer": "/data/attributes/" + violation.Parameter(),
+				}
+			}
+			jerrors.Errors = append(jerrors.Errors, &jerr)
+		}
+		return &jerrors, httpStatusCode
+	}
 	jerr, httpStatusCode := ErrorToJSONAPIError(ctx, err)
 	jerrors := app.JSONAPIErrors{}
 	jerrors.Errors = append(jerrors.Errors, &jerr)
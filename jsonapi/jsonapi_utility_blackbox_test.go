@@ -79,6 +79,32 @@ func TestErrorToJSONAPIError(t *testing.T) {
 	require.Equal(t, strconv.Itoa(httpStatus), *jerr.Status)
 }
 
+func TestErrorToJSONAPIErrors(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	t.Run("single error", func(t *testing.T) {
+		jerrs, httpStatus := jsonapi.ErrorToJSONAPIErrors(nil, errors.NewBadParameterError("foo", "bar"))
+		require.Equal(t, http.StatusBadRequest, httpStatus)
+		require.Len(t, jerrs.Errors, 1)
+		require.Nil(t, jerrs.Errors[0].Source)
+	})
+
+	t.Run("multiple bad parameters", func(t *testing.T) {
+		err := errs.Wrap(errors.NewBadParameterErrors(
+			errors.NewBadParameterError("foo", 1),
+			errors.NewBadParameterError("bar", 2),
+		), "invalid work item")
+		jerrs, httpStatus := jsonapi.ErrorToJSONAPIErrors(nil, err)
+		require.Equal(t, http.StatusBadRequest, httpStatus)
+		require.Len(t, jerrs.Errors, 2)
+		for i, name := range []string{"foo", "bar"} {
+			require.Equal(t, jsonapi.ErrorCodeBadParameter, *jerrs.Errors[i].Code)
+			require.Equal(t, "/data/attributes/"+name, jerrs.Errors[i].Source["pointer"])
+		}
+	})
+}
+
 func ExampleFormatMemberName() {
 	formatAndPrint := func(name string) {
 		fmt.Printf("%q\n", jsonapi.FormatMemberName(name))
@@ -20,6 +20,7 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	v1 "k8s.io/client-go/pkg/api/v1"
+	v1beta1 "k8s.io/client-go/pkg/apis/extensions/v1beta1"
 	rest "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 
@@ -100,6 +101,13 @@ type kubeClient struct {
 // KubeRESTAPI collects methods that call out to the Kubernetes API server over the network
 type KubeRESTAPI interface {
 	corev1.CoreV1Interface
+	// The methods below use the apps/v1 API group, which our version of client-go
+	// does not provide a typed client for
+	GetKubeDeployment(namespace string, name string) (map[string]interface{}, error)
+	DeleteKubeDeployment(namespace string, name string, opts *metaV1.DeleteOptions) error
+	GetKubeDeploymentScale(namespace string, name string) (map[string]interface{}, error)
+	SetKubeDeploymentScale(namespace string, name string, scale map[string]interface{}) (map[string]interface{}, error)
+	GetReplicaSets(namespace string) (*v1beta1.ReplicaSetList, error)
 }
 
 type kubeAPIClient struct {
@@ -130,6 +138,9 @@ type deployment struct {
 	dcUID      types.UID
 	appVersion string
 	current    *v1.ReplicationController
+	// Set if this is a Kubernetes Deployment instead of an OpenShift DeploymentConfig,
+	// in which case current is derived from the Deployment's newest ReplicaSet
+	kubeDeployment bool
 }
 
 type route struct {
@@ -359,8 +370,19 @@ func (kc *kubeClient) ScaleDeployment(spaceName string, appName string, envName
 		return nil, err
 	}
 
-	// Look up the Scale for the DeploymentConfig corresponding to the application name in the provided environment
-	scale, err := kc.GetDeploymentConfigScale(envNS, dcName)
+	// Applications may be deployed using either a DeploymentConfig or a Deployment,
+	// prefer the former if both exist
+	getScale, setScale := kc.GetDeploymentConfigScale, kc.SetDeploymentConfigScale
+	dc, err := kc.GetDeploymentConfig(envNS, dcName)
+	if err != nil {
+		return nil, err
+	} else if dc == nil {
+		getScale, setScale = kc.GetKubeDeploymentScale, kc.SetKubeDeploymentScale
+	}
+
+	// Look up the Scale for the DeploymentConfig or Deployment corresponding to the application name
+	// in the provided environment
+	scale, err := getScale(envNS, dcName)
 	if err != nil {
 		return nil, err
 	}
@@ -387,7 +409,7 @@ func (kc *kubeClient) ScaleDeployment(spaceName string, appName string, envName
 	}
 	spec["replicas"] = deployNumber
 
-	_, err = kc.SetDeploymentConfigScale(envNS, dcName, scale)
+	_, err = setScale(envNS, dcName, scale)
 	if err != nil {
 		return nil, err
 	}
@@ -655,7 +677,7 @@ func (kc *kubeClient) DeleteDeployment(spaceName string, appName string, envName
 		}, "could not delete services in deploymentConfig "+dcName)
 	}
 
-	// Delete DC (will also delete RCs and pods)
+	// Delete DC or Deployment (will also delete RCs or ReplicaSets, and pods)
 	err = kc.deleteDeploymentConfig(spaceName, dcName, envNS)
 	if err != nil {
 		log.Error(nil, map[string]interface{}{
@@ -883,6 +905,16 @@ func (oc *openShiftAPIClient) sendResource(path string, method string, reqBody i
 	return respJSON, nil
 }
 
+// getAndParseDeployment looks up the OpenShift DeploymentConfig with the given name, or
+// failing that, the Kubernetes Deployment with the same name. Returns nil if neither exists.
+func (kc *kubeClient) getAndParseDeployment(namespace string, name string, space string) (*deployment, error) {
+	result, err := kc.getAndParseDeploymentConfig(namespace, name, space)
+	if err != nil || result != nil {
+		return result, err
+	}
+	return kc.getAndParseKubeDeployment(namespace, name, space)
+}
+
 func (kc *kubeClient) getAndParseDeploymentConfig(namespace string, dcName string, space string) (*deployment, error) {
 	result, err := kc.GetDeploymentConfig(namespace, dcName)
 	if err != nil {
@@ -896,6 +928,32 @@ func (kc *kubeClient) getAndParseDeploymentConfig(namespace string, dcName strin
 	if !ok || kind != "DeploymentConfig" {
 		return nil, errs.New("no deployment config returned from endpoint")
 	}
+	return parseDeployment(result, namespace, dcName, space)
+}
+
+func (kc *kubeClient) getAndParseKubeDeployment(namespace string, name string, space string) (*deployment, error) {
+	result, err := kc.GetKubeDeployment(namespace, name)
+	if err != nil {
+		return nil, err
+	} else if result == nil {
+		return nil, nil
+	}
+
+	// Parse deployment from result
+	kind, ok := result["kind"].(string)
+	if !ok || kind != "Deployment" {
+		return nil, errs.New("no deployment returned from endpoint")
+	}
+	deploy, err := parseDeployment(result, namespace, name, space)
+	if err != nil {
+		return nil, err
+	}
+	deploy.kubeDeployment = true
+	return deploy, nil
+}
+
+// parseDeployment reads the metadata common to DeploymentConfigs and Deployments
+func parseDeployment(result map[string]interface{}, namespace string, dcName string, space string) (*deployment, error) {
 	metadata, ok := result["metadata"].(map[string]interface{})
 	if !ok {
 		return nil, errs.Errorf("metadata missing from deployment config %s: %+v", dcName, result)
@@ -942,7 +1000,7 @@ func (kc *kubeClient) getAndParseDeploymentConfig(namespace string, dcName strin
 		return nil, errs.Errorf("malformed metadata in deployment config %s: %+v", dcName, metadata)
 	}
 	// Read application version from label
-	version := labels["version"].(string)
+	version, ok := labels["version"].(string)
 	if !ok || len(version) == 0 {
 		return nil, errs.Errorf("version missing from deployment config %s: %+v", dcName, metadata)
 	}
@@ -1076,12 +1134,12 @@ func (oc *openShiftAPIClient) GetBuilds(namespace string, labelSelector string)
 }
 
 func (kc *kubeClient) deleteDeploymentConfig(spaceName string, dcName string, namespace string) error {
-	// Check that the deployment config exists and belongs to the expected space
-	dc, err := kc.getAndParseDeploymentConfig(namespace, dcName, spaceName)
+	// Check that the deployment config or deployment exists and belongs to the expected space
+	dc, err := kc.getAndParseDeployment(namespace, dcName, spaceName)
 	if err != nil {
 		return err
 	} else if dc == nil {
-		return errors.NewNotFoundErrorFromString(fmt.Sprintf("deployment config %s does not exist in %s", dcName, namespace))
+		return errors.NewNotFoundErrorFromString(fmt.Sprintf("deployment config or deployment %s does not exist in %s", dcName, namespace))
 	}
 
 	// Delete all dependent objects and then this DC
@@ -1093,6 +1151,9 @@ func (kc *kubeClient) deleteDeploymentConfig(spaceName string, dcName string, na
 		},
 		PropagationPolicy: &policy,
 	}
+	if dc.kubeDeployment {
+		return kc.DeleteKubeDeployment(namespace, dcName, opts)
+	}
 	// API states this should return a Status object, but it returns the DC instead,
 	// just check for no HTTP error
 	_, err = kc.DeleteDeploymentConfig(namespace, dcName, opts)
@@ -1119,12 +1180,14 @@ func (kc *kubeClient) getCurrentDeployment(space string, appName string, namespa
 		return nil, err
 	}
 
-	// Look up DeploymentConfig corresponding to the application name in the provided environment
-	result, err := kc.getAndParseDeploymentConfig(namespace, dcName, space)
+	// Look up DeploymentConfig or Deployment corresponding to the application name in the provided environment
+	result, err := kc.getAndParseDeployment(namespace, dcName, space)
 	if err != nil {
 		return nil, err
 	} else if result == nil {
 		return nil, nil
+	} else if result.kubeDeployment {
+		return kc.getCurrentReplicaSet(namespace, result)
 	}
 	// Find the current deployment for the DC we just found. This should correspond to the deployment
 	// shown in the OpenShift web console's overview page
@@ -1253,6 +1316,151 @@ func (kc *kubeClient) getReplicationControllers(namespace string, deploy *deploy
 	return rcsForDc, nil
 }
 
+const deploymentRevisionAnnotation string = "deployment.kubernetes.io/revision"
+
+// getCurrentReplicaSet finds the ReplicaSet for the latest revision of a Kubernetes Deployment,
+// which is the equivalent of the current ReplicationController of a DeploymentConfig
+func (kc *kubeClient) getCurrentReplicaSet(namespace string, deploy *deployment) (*deployment, error) {
+	rss, err := kc.GetReplicaSets(namespace)
+	if err != nil {
+		log.Error(nil, map[string]interface{}{
+			"err":        err,
+			"namespace":  namespace,
+			"deployment": deploy.dcName,
+		}, "failed to list replica sets")
+		return nil, err
+	}
+
+	var current *v1beta1.ReplicaSet
+	var newestRevision int64
+	for idx := range rss.Items {
+		rs := &rss.Items[idx]
+		// Use OwnerReferences to map ReplicaSet to the Deployment that created it
+		match := false
+		for _, ref := range rs.OwnerReferences {
+			if ref.UID == deploy.dcUID && ref.Controller != nil && *ref.Controller {
+				match = true
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		var revision int64
+		revisionStr, pres := rs.Annotations[deploymentRevisionAnnotation]
+		if pres {
+			revision, err = strconv.ParseInt(revisionStr, 10, 64)
+			if err != nil {
+				return nil, errs.Wrapf(err, "deployment revision for %s is not a valid integer", rs.Name)
+			}
+		}
+		if current == nil || revision > newestRevision ||
+			(revision == newestRevision && current.CreationTimestamp.Before(rs.CreationTimestamp)) {
+			current = rs
+			newestRevision = revision
+		}
+	}
+	if current != nil {
+		deploy.current = replicaSetToReplicationController(current)
+	}
+	return deploy, nil
+}
+
+// replicaSetToReplicationController copies the parts of a ReplicaSet we rely on into a
+// ReplicationController, so that pods, services and routes are found the same way for
+// both kinds of deployment
+func replicaSetToReplicationController(rs *v1beta1.ReplicaSet) *v1.ReplicationController {
+	template := rs.Spec.Template
+	var selector map[string]string
+	if rs.Spec.Selector != nil {
+		selector = rs.Spec.Selector.MatchLabels
+	}
+	return &v1.ReplicationController{
+		ObjectMeta: rs.ObjectMeta,
+		Spec: v1.ReplicationControllerSpec{
+			Replicas: rs.Spec.Replicas,
+			Selector: selector,
+			Template: &template,
+		},
+		Status: v1.ReplicationControllerStatus{
+			Replicas:          rs.Status.Replicas,
+			ReadyReplicas:     rs.Status.ReadyReplicas,
+			AvailableReplicas: rs.Status.AvailableReplicas,
+		},
+	}
+}
+
+// Base path of the apps/v1 API group, which is not covered by our version of client-go
+const appsV1NamespacesPath = "/apis/apps/v1/namespaces"
+
+func (kc *kubeAPIClient) GetKubeDeployment(namespace string, name string) (map[string]interface{}, error) {
+	req := kc.RESTClient().Get().AbsPath(appsV1NamespacesPath, namespace, "deployments", name)
+	return doAppsRequest(req, true)
+}
+
+func (kc *kubeAPIClient) DeleteKubeDeployment(namespace string, name string, opts *metaV1.DeleteOptions) error {
+	body, err := json.Marshal(opts)
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	req := kc.RESTClient().Delete().AbsPath(appsV1NamespacesPath, namespace, "deployments", name).
+		SetHeader("Content-Type", "application/json").Body(body)
+	_, err = doAppsRequest(req, false)
+	return err
+}
+
+func (kc *kubeAPIClient) GetKubeDeploymentScale(namespace string, name string) (map[string]interface{}, error) {
+	req := kc.RESTClient().Get().AbsPath(appsV1NamespacesPath, namespace, "deployments", name, "scale")
+	return doAppsRequest(req, false)
+}
+
+func (kc *kubeAPIClient) SetKubeDeploymentScale(namespace string, name string,
+	scale map[string]interface{}) (map[string]interface{}, error) {
+	body, err := json.Marshal(scale)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	req := kc.RESTClient().Put().AbsPath(appsV1NamespacesPath, namespace, "deployments", name, "scale").
+		SetHeader("Content-Type", "application/json").Body(body)
+	return doAppsRequest(req, false)
+}
+
+func (kc *kubeAPIClient) GetReplicaSets(namespace string) (*v1beta1.ReplicaSetList, error) {
+	req := kc.RESTClient().Get().AbsPath(appsV1NamespacesPath, namespace, "replicasets")
+	body, err := req.DoRaw()
+	if err != nil {
+		return nil, convertError(errs.WithStack(err), "failed to list replica sets in %s", namespace)
+	}
+	// The apps/v1 representation of a ReplicaSet is compatible with extensions/v1beta1
+	var result v1beta1.ReplicaSetList
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to parse replica sets in %s", namespace)
+	}
+	return &result, nil
+}
+
+// doAppsRequest sends a request to the apps/v1 API group and parses the JSON response
+func doAppsRequest(req *rest.Request, allowMissing bool) (map[string]interface{}, error) {
+	body, err := req.DoRaw()
+	if err != nil {
+		if allowMissing && kubeErrors.IsNotFound(err) {
+			return nil, nil
+		}
+		log.Error(nil, map[string]interface{}{
+			"err": err,
+			"url": req.URL().String(),
+		}, "error returned from apps/v1 request")
+		return nil, convertError(errs.WithStack(err), "failed to access url %s", req.URL().String())
+	}
+	var result map[string]interface{}
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	return result, nil
+}
+
 func (kc *kubeClient) getResourceQuota(namespace string) (*app.EnvStats, error) {
 	// Get both resource quotas in one API call
 	const computeResources string = "compute-resources"
@@ -1501,7 +1709,13 @@ func (kc *kubeClient) GetDeploymentPodQuota(spaceName string, appName string, en
 	if err != nil {
 		return nil, errs.Errorf("could not retrieve deployment config with name %s for namespace %s", dcName, namespace)
 	} else if deploymentConfig == nil {
-		return nil, errors.NewNotFoundErrorFromString(fmt.Sprintf("no deployment config found named %s in %s", dcName, namespace))
+		// The pod template of a Deployment has the same structure as that of a DeploymentConfig
+		deploymentConfig, err = kc.GetKubeDeployment(namespace, dcName)
+		if err != nil {
+			return nil, errs.Errorf("could not retrieve deployment with name %s for namespace %s", dcName, namespace)
+		} else if deploymentConfig == nil {
+			return nil, errors.NewNotFoundErrorFromString(fmt.Sprintf("no deployment config or deployment found named %s in %s", dcName, namespace))
+		}
 	}
 
 	spec, ok := deploymentConfig["spec"].(map[string]interface{})
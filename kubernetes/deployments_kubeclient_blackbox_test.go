@@ -22,9 +22,11 @@ import (
 
 	errs "github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	v1 "k8s.io/client-go/pkg/api/v1"
+	v1beta1 "k8s.io/client-go/pkg/apis/extensions/v1beta1"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -1854,15 +1856,18 @@ type testKube struct {
 	getter                 *testKubeGetter
 	eventsHolder           *testEvents
 	configMapHolder        *testConfigMap
+	deploymentOutput       *kubeDeploymentOutput
 }
 
 type testKubeGetter struct {
-	result      *testKube
-	eventsInput *eventsInput
+	result          *testKube
+	eventsInput     *eventsInput
+	deploymentInput *kubeDeploymentInput
 }
 
 func (getter *testKubeGetter) GetKubeRESTAPI(config *kubernetes.KubeClientConfig) (kubernetes.KubeRESTAPI, error) {
 	mock := new(testKube)
+	mock.deploymentOutput = &kubeDeploymentOutput{}
 	// Doubly-linked for access by tests
 	mock.getter = getter
 	getter.result = mock
@@ -2136,3 +2141,271 @@ func validateEventsItems(t *testing.T, eventList *v1.EventList, store *cache.FIF
 		}
 	}
 }
+
+// Kubernetes Deployment fakes
+
+type kubeDeploymentInput struct {
+	namespace   string
+	deployment  map[string]interface{}
+	scale       map[string]interface{}
+	replicaSets *v1beta1.ReplicaSetList
+	pods        *v1.PodList
+	services    *v1.ServiceList
+}
+
+type kubeDeploymentOutput struct {
+	scale              map[string]interface{}
+	deletedDeployments []string
+	deletedServices    []string
+}
+
+func (tk *testKube) GetKubeDeployment(namespace string, name string) (map[string]interface{}, error) {
+	input := tk.getter.deploymentInput
+	if input.deployment == nil || namespace != input.namespace {
+		return nil, nil
+	}
+	metadata := input.deployment["metadata"].(map[string]interface{})
+	if metadata["name"] != name {
+		return nil, nil
+	}
+	return input.deployment, nil
+}
+
+func (tk *testKube) DeleteKubeDeployment(namespace string, name string, opts *metav1.DeleteOptions) error {
+	tk.deploymentOutput.deletedDeployments = append(tk.deploymentOutput.deletedDeployments, name)
+	return nil
+}
+
+func (tk *testKube) GetKubeDeploymentScale(namespace string, name string) (map[string]interface{}, error) {
+	return tk.getter.deploymentInput.scale, nil
+}
+
+func (tk *testKube) SetKubeDeploymentScale(namespace string, name string,
+	scale map[string]interface{}) (map[string]interface{}, error) {
+	tk.deploymentOutput.scale = scale
+	return scale, nil
+}
+
+func (tk *testKube) GetReplicaSets(namespace string) (*v1beta1.ReplicaSetList, error) {
+	return tk.getter.deploymentInput.replicaSets, nil
+}
+
+type testPods struct {
+	corev1.PodInterface
+	list *v1.PodList
+}
+
+func (tk *testKube) Pods(ns string) corev1.PodInterface {
+	return &testPods{
+		list: tk.getter.deploymentInput.pods,
+	}
+}
+
+func (tp *testPods) List(options metav1.ListOptions) (*v1.PodList, error) {
+	return tp.list, nil
+}
+
+type testServices struct {
+	corev1.ServiceInterface
+	kube *testKube
+	list *v1.ServiceList
+}
+
+func (tk *testKube) Services(ns string) corev1.ServiceInterface {
+	return &testServices{
+		kube: tk,
+		list: tk.getter.deploymentInput.services,
+	}
+}
+
+func (ts *testServices) List(options metav1.ListOptions) (*v1.ServiceList, error) {
+	return ts.list, nil
+}
+
+func (ts *testServices) Delete(name string, options *metav1.DeleteOptions) error {
+	output := ts.kube.deploymentOutput
+	output.deletedServices = append(output.deletedServices, name)
+	return nil
+}
+
+func getKubeDeploymentInput() *kubeDeploymentInput {
+	controller := true
+	ownedBy := func(uid string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{
+			{
+				Kind:       "Deployment",
+				Name:       "myApp",
+				UID:        types.UID(uid),
+				Controller: &controller,
+			},
+		}
+	}
+	replicaSet := func(name string, revision string, owner string) v1beta1.ReplicaSet {
+		return v1beta1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				UID:             types.UID(name + "-uid"),
+				Annotations:     map[string]string{"deployment.kubernetes.io/revision": revision},
+				OwnerReferences: ownedBy(owner),
+			},
+			Spec: v1beta1.ReplicaSetSpec{
+				Template: v1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{"app": "myApp"},
+					},
+				},
+			},
+		}
+	}
+	pod := func(name string, owner string) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				OwnerReferences: ownedBy(owner),
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+			},
+		}
+	}
+	return &kubeDeploymentInput{
+		namespace: "my-run",
+		deployment: map[string]interface{}{
+			"kind": "Deployment",
+			"metadata": map[string]interface{}{
+				"name": "myApp",
+				"uid":  "myApp-uid",
+				"labels": map[string]interface{}{
+					"app":     "myApp",
+					"space":   "mySpace",
+					"version": "1.0.4",
+				},
+			},
+		},
+		scale: map[string]interface{}{
+			"kind": "Scale",
+			"spec": map[string]interface{}{
+				"replicas": float64(2),
+			},
+		},
+		replicaSets: &v1beta1.ReplicaSetList{
+			Items: []v1beta1.ReplicaSet{
+				replicaSet("myApp-1", "1", "myApp-uid"),
+				replicaSet("myApp-2", "2", "myApp-uid"),
+				replicaSet("myOtherApp-3", "3", "myOtherApp-uid"),
+			},
+		},
+		pods: &v1.PodList{
+			Items: []v1.Pod{
+				pod("myApp-1-a", "myApp-1-uid"),
+				pod("myApp-2-a", "myApp-2-uid"),
+				pod("myApp-2-b", "myApp-2-uid"),
+				pod("myOtherApp-3-a", "myOtherApp-3-uid"),
+			},
+		},
+		services: &v1.ServiceList{
+			Items: []v1.Service{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "myApp",
+						Labels: map[string]string{"app": "myApp"},
+					},
+					Spec: v1.ServiceSpec{
+						Selector: map[string]string{"app": "myApp"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestKubeDeployment(t *testing.T) {
+	setup := func(t *testing.T) (kubernetes.KubeClientInterface, *testFixture, *testKubeGetter, func()) {
+		r, err := recorder.New(pathToTestJSON + "kubedeployment")
+		require.NoError(t, err, "Failed to open cassette")
+
+		fixture := &testFixture{
+			metricsInput: defaultMetricsInput,
+		}
+		kubeGetter := &testKubeGetter{
+			deploymentInput: getKubeDeploymentInput(),
+		}
+		config := &kubernetes.KubeClientConfig{
+			BaseURLProvider:   getDefaultURLProvider("http://api.myCluster", "myToken"),
+			UserNamespace:     "myNamespace",
+			KubeRESTAPIGetter: kubeGetter,
+			MetricsGetter:     fixture,
+			Transport:         r.Transport,
+		}
+		kc, err := kubernetes.NewKubeClient(config)
+		require.NoError(t, err)
+		return kc, fixture, kubeGetter, func() { r.Stop() }
+	}
+
+	t.Run("get deployment", func(t *testing.T) {
+		kc, _, _, cleanup := setup(t)
+		defer cleanup()
+
+		dep, err := kc.GetDeployment("mySpace", "myApp", "run")
+		require.NoError(t, err)
+		verifyDeployment(dep, &deployTestData{
+			envName:          "run",
+			expectVersion:    "1.0.4",
+			expectPodStatus:  [][]string{{"Running", "2"}},
+			expectPodsTotal:  2,
+			expectConsoleURL: "http://console.myCluster/console/project/my-run",
+			expectLogURL:     "http://console.myCluster/console/project/my-run/browse/rc/myApp-2?tab=logs",
+			expectAppURL:     "http://myApp-my-run.example.com",
+		}, t)
+	})
+
+	t.Run("deployment stats use pods of newest replica set", func(t *testing.T) {
+		kc, fixture, _, cleanup := setup(t)
+		defer cleanup()
+
+		stats, err := kc.GetDeploymentStats("mySpace", "myApp", "run", time.Unix(0, 1517867612000*int64(time.Millisecond)))
+		require.NoError(t, err)
+		require.NotNil(t, stats)
+		require.NotNil(t, fixture.metrics, "Metrics API not called")
+		podNames := []string{}
+		for _, pod := range fixture.metrics.cpuParams.pods {
+			podNames = append(podNames, pod.Name)
+		}
+		require.ElementsMatch(t, []string{"myApp-2-a", "myApp-2-b"}, podNames)
+		require.Equal(t, "my-run", fixture.metrics.cpuParams.namespace)
+	})
+
+	t.Run("scale deployment", func(t *testing.T) {
+		kc, _, kubeGetter, cleanup := setup(t)
+		defer cleanup()
+
+		old, err := kc.ScaleDeployment("mySpace", "myApp", "run", 3)
+		require.NoError(t, err)
+		require.NotNil(t, old)
+		require.Equal(t, 2, *old)
+		scale := kubeGetter.result.deploymentOutput.scale
+		require.NotNil(t, scale, "Scale was not updated")
+		require.Equal(t, 3, scale["spec"].(map[string]interface{})["replicas"])
+	})
+
+	t.Run("delete deployment", func(t *testing.T) {
+		kc, _, kubeGetter, cleanup := setup(t)
+		defer cleanup()
+
+		err := kc.DeleteDeployment("mySpace", "myApp", "run")
+		require.NoError(t, err)
+		output := kubeGetter.result.deploymentOutput
+		require.Equal(t, []string{"myApp"}, output.deletedDeployments)
+		require.Equal(t, []string{"myApp"}, output.deletedServices)
+	})
+
+	t.Run("unknown application", func(t *testing.T) {
+		kc, _, kubeGetter, cleanup := setup(t)
+		defer cleanup()
+		kubeGetter.deploymentInput.deployment = nil
+
+		dep, err := kc.GetDeployment("mySpace", "myApp", "run")
+		require.NoError(t, err)
+		require.Nil(t, dep)
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/attachment"
 	"github.com/fabric8-services/fabric8-wit/auth"
 	"github.com/fabric8-services/fabric8-wit/closeable"
 	"github.com/fabric8-services/fabric8-wit/configuration"
@@ -33,6 +34,8 @@ import (
 	"github.com/fabric8-services/fabric8-wit/space/authz"
 	"github.com/fabric8-services/fabric8-wit/swagger"
 	"github.com/fabric8-services/fabric8-wit/token"
+	"github.com/fabric8-services/fabric8-wit/watcher"
+	"github.com/fabric8-services/fabric8-wit/workitem/recurrence"
 	"github.com/goadesign/goa"
 	"github.com/goadesign/goa/logging/logrus"
 	"github.com/goadesign/goa/middleware"
@@ -182,7 +185,8 @@ func main() {
 				"url": config.GetNotificationServiceURL(),
 			}, "failed to parse notification service url")
 		}
-		notificationChannel = channel
+		// let the notification service know who is watching the target
+		notificationChannel = notification.NewWatcherChannel(watcher.NewRepository(db), channel)
 	}
 
 	appDB := gormapplication.NewGormDB(db)
@@ -228,6 +232,10 @@ func main() {
 	namedWorkitemsCtrl := controller.NewNamedWorkItemsController(service, appDB)
 	app.MountNamedWorkItemsController(service, namedWorkitemsCtrl)
 
+	// Mount "work item keys" controller
+	workItemKeysCtrl := controller.NewWorkItemKeysController(service, appDB)
+	app.MountWorkItemKeysController(service, workItemKeysCtrl)
+
 	// Mount "workitems" controller
 	//workitemsCtrl := controller.NewWorkitemsController(service, appDB, config)
 	workitemsCtrl := controller.NewNotifyingWorkitemsController(service, appDB, notificationChannel, config)
@@ -275,6 +283,64 @@ func main() {
 	workItemEventsCtrl := controller.NewEventsController(service, appDB, config)
 	app.MountWorkItemEventsController(service, workItemEventsCtrl)
 
+	// Mount "work_item_transitions" controller
+	workItemTransitionsCtrl := controller.NewWorkItemTransitionsController(service, appDB)
+	app.MountWorkItemTransitionsController(service, workItemTransitionsCtrl)
+
+	// Mount "attachments" and "work_item_attachments" controllers
+	attachmentsPath := config.GetAttachmentsStoragePath()
+	if attachmentsPath == "" {
+		log.Panic(nil, map[string]interface{}{
+			"config_key": "attachments.storage.path",
+		}, "the attachments storage path must be set to a persistent directory")
+	}
+	if err := os.MkdirAll(attachmentsPath, 0700); err != nil {
+		log.Panic(nil, map[string]interface{}{
+			"path": attachmentsPath,
+			"err":  err,
+		}, "failed to create the attachments storage directory")
+	}
+	attachmentStore := attachment.NewFileSystemBlobStore(attachmentsPath)
+	attachmentsCtrl := controller.NewAttachmentsController(service, appDB, attachmentStore, config)
+	app.MountAttachmentsController(service, attachmentsCtrl)
+	workItemAttachmentsCtrl := controller.NewWorkItemAttachmentsController(service, appDB, attachmentStore, config)
+	app.MountWorkItemAttachmentsController(service, workItemAttachmentsCtrl)
+
+	// Mount "worklogs" controllers
+	worklogsCtrl := controller.NewWorklogsController(service, appDB, config)
+	app.MountWorklogsController(service, worklogsCtrl)
+	workItemWorklogsCtrl := controller.NewWorkItemWorklogsController(service, appDB)
+	app.MountWorkItemWorklogsController(service, workItemWorklogsCtrl)
+	iterationWorklogsCtrl := controller.NewIterationWorklogsController(service, appDB)
+	app.MountIterationWorklogsController(service, iterationWorklogsCtrl)
+
+	// Mount "work_item_scm_activities" controller
+	workItemScmActivitiesCtrl := controller.NewWorkItemScmActivitiesController(service, appDB)
+	app.MountWorkItemScmActivitiesController(service, workItemScmActivitiesCtrl)
+
+	// Mount "watchers" controller
+	watchersCtrl := controller.NewWatchersController(service, appDB)
+	app.MountWatchersController(service, watchersCtrl)
+
+	// Mount "notification_preferences" controller
+	notificationPreferencesCtrl := controller.NewNotificationPreferencesController(service, appDB)
+	app.MountNotificationPreferencesController(service, notificationPreferencesCtrl)
+
+	// Scheduler to create the work items of recurrences
+	recurrenceScheduler := recurrence.NewScheduler(db)
+	defer recurrenceScheduler.Stop()
+	recurrenceScheduler.Start(service.Context, config.GetRecurrenceReloadInterval())
+
+	// Mount "recurrences" controller
+	recurrencesCtrl := controller.NewRecurrencesController(service, appDB, recurrenceScheduler, config)
+	app.MountRecurrencesController(service, recurrencesCtrl)
+	spaceRecurrencesCtrl := controller.NewSpaceRecurrencesController(service, appDB, recurrenceScheduler)
+	app.MountSpaceRecurrencesController(service, spaceRecurrencesCtrl)
+
+	// Mount "space_activities" controller
+	spaceActivitiesCtrl := controller.NewSpaceActivitiesController(service, appDB, config)
+	app.MountSpaceActivitiesController(service, spaceActivitiesCtrl)
+
 	if config.GetFeatureWorkitemRemote() {
 		// Scheduler to fetch and import remote tracker items
 		scheduler = remoteworkitem.NewScheduler(db)
@@ -326,6 +392,14 @@ func main() {
 	labelCtrl := controller.NewLabelController(service, appDB, config)
 	app.MountLabelController(service, labelCtrl)
 
+	// Mount "releases" controller
+	releaseCtrl := controller.NewReleaseController(service, appDB)
+	app.MountReleaseController(service, releaseCtrl)
+
+	// Mount "analytics" controller
+	analyticsCtrl := controller.NewAnalyticsController(service, appDB)
+	app.MountAnalyticsController(service, analyticsCtrl)
+
 	// Mount "endpoints" controller
 	endpointsCtrl := controller.NewEndpointsController(service)
 	app.MountEndpointsController(service, endpointsCtrl)
@@ -465,6 +465,51 @@ func GetMigrations() Migrations {
 	// Version 109
 	m = append(m, steps{ExecuteSQLFile("109-number-column-for-iteration.sql")})
 
+	// Version 110
+	m = append(m, steps{ExecuteSQLFile("110-activity-stream-indexes.sql")})
+
+	// Version 111
+	m = append(m, steps{ExecuteSQLFile("111-comment-reactions.sql")})
+
+	// Version 112
+	m = append(m, steps{ExecuteSQLFile("112-work-item-type-transitions.sql")})
+
+	// Version 113
+	m = append(m, steps{ExecuteSQLFile("113-attachments.sql")})
+
+	// Version 114
+	m = append(m, steps{ExecuteSQLFile("114-worklogs.sql")})
+
+	// Version 115
+	m = append(m, steps{ExecuteSQLFile("115-watchers.sql")})
+
+	// Version 116
+	m = append(m, steps{ExecuteSQLFile("116-space-keys.sql")})
+
+	// Version 117
+	m = append(m, steps{ExecuteSQLFile("117-work-item-aliases.sql")})
+
+	// Version 118
+	m = append(m, steps{ExecuteSQLFile("118-work-item-recurrences.sql")})
+
+	// Version 119
+	m = append(m, steps{ExecuteSQLFile("119-tracker-write-back.sql")})
+
+	// Version 120
+	m = append(m, steps{ExecuteSQLFile("120-tracker-query-sync-states.sql")})
+
+	// Version 121
+	m = append(m, steps{ExecuteSQLFile("121-tracker-webhook-secret.sql")})
+
+	// Version 122
+	m = append(m, steps{ExecuteSQLFile("122-tracker-field-mappings.sql")})
+
+	// Version 123
+	m = append(m, steps{ExecuteSQLFile("123-scm-activities.sql")})
+
+	// Version 124
+	m = append(m, steps{ExecuteSQLFile("124-releases.sql")})
+
 	// Version N
 	//
 	// In order to add an upgrade, simply append an array of MigrationFunc to the
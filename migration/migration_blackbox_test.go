@@ -159,6 +159,21 @@ func TestMigrations(t *testing.T) {
 	t.Run("TestMirgraion107", testMigration107NumberSequencesTable)
 	t.Run("TestMirgraion108", testMigration108NumberColumnForArea)
 	t.Run("TestMirgraion109", testMigration109NumberColumnForIteration)
+	t.Run("TestMigration110", testMigration110ActivityStreamIndexes)
+	t.Run("TestMigration111", testMigration111CommentReactions)
+	t.Run("TestMigration112", testMigration112WorkItemTypeTransitions)
+	t.Run("TestMigration113", testMigration113Attachments)
+	t.Run("TestMigration114", testMigration114Worklogs)
+	t.Run("TestMigration115", testMigration115Watchers)
+	t.Run("TestMigration116", testMigration116SpaceKeys)
+	t.Run("TestMigration117", testMigration117WorkItemAliases)
+	t.Run("TestMigration118", testMigration118WorkItemRecurrences)
+	t.Run("TestMigration119", testMigration119TrackerWriteBack)
+	t.Run("TestMigration120", testMigration120TrackerQuerySyncStates)
+	t.Run("TestMigration121", testMigration121TrackerWebhookSecret)
+	t.Run("TestMigration122", testMigration122TrackerFieldMappings)
+	t.Run("TestMigration123", testMigration123SCMActivities)
+	t.Run("TestMigration124", testMigration124Releases)
 
 	// Perform the migration
 	err = migration.Migrate(sqlDB, databaseName)
@@ -1383,6 +1398,155 @@ func testMigration109NumberColumnForIteration(t *testing.T) {
 	require.True(t, dialect.HasColumn("iterations", "number"))
 }
 
+func testMigration110ActivityStreamIndexes(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:111], 111)
+
+	assert.True(t, dialect.HasIndex("work_item_revisions", "ix_work_item_revisions_revision_time"))
+	assert.True(t, dialect.HasIndex("comment_revisions", "ix_comment_revisions_comment_parent_id"))
+	assert.True(t, dialect.HasIndex("work_item_link_revisions", "ix_work_item_link_revisions_source_id"))
+}
+
+func testMigration111CommentReactions(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:112], 112)
+
+	assert.True(t, dialect.HasIndex("comments", "ix_comments_parent_comment_id"))
+	assert.True(t, dialect.HasTable("comment_reactions"))
+	assert.True(t, dialect.HasColumn("comment_reactions", "comment_id"))
+	assert.True(t, dialect.HasColumn("comment_reactions", "identity_id"))
+	assert.True(t, dialect.HasColumn("comment_reactions", "emoji"))
+	assert.True(t, dialect.HasIndex("comment_reactions", "ix_comment_reactions_comment_id"))
+}
+
+func testMigration112WorkItemTypeTransitions(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:113], 113)
+
+	assert.True(t, dialect.HasColumn("work_item_types", "transitions"))
+}
+
+func testMigration113Attachments(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:114], 114)
+
+	assert.True(t, dialect.HasTable("attachments"))
+	assert.True(t, dialect.HasColumn("attachments", "work_item_id"))
+	assert.True(t, dialect.HasColumn("attachments", "comment_id"))
+	assert.True(t, dialect.HasColumn("attachments", "checksum"))
+	assert.True(t, dialect.HasColumn("attachments", "storage_key"))
+	assert.True(t, dialect.HasIndex("attachments", "ix_attachments_work_item_id"))
+	assert.True(t, dialect.HasIndex("attachments", "ix_attachments_comment_id"))
+}
+
+func testMigration114Worklogs(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:115], 115)
+
+	assert.True(t, dialect.HasTable("worklogs"))
+	assert.True(t, dialect.HasColumn("worklogs", "work_item_id"))
+	assert.True(t, dialect.HasColumn("worklogs", "identity_id"))
+	assert.True(t, dialect.HasColumn("worklogs", "duration"))
+	assert.True(t, dialect.HasColumn("worklogs", "work_date"))
+	assert.True(t, dialect.HasIndex("worklogs", "ix_worklogs_work_item_id"))
+	assert.True(t, dialect.HasIndex("worklogs", "ix_worklogs_identity_id"))
+}
+
+func testMigration115Watchers(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:116], 116)
+
+	assert.True(t, dialect.HasTable("watchers"))
+	assert.True(t, dialect.HasColumn("watchers", "identity_id"))
+	assert.True(t, dialect.HasColumn("watchers", "target_type"))
+	assert.True(t, dialect.HasColumn("watchers", "target_id"))
+	assert.True(t, dialect.HasIndex("watchers", "uix_watchers_target_identity"))
+	assert.True(t, dialect.HasIndex("watchers", "ix_watchers_identity_id"))
+	assert.True(t, dialect.HasTable("notification_preferences"))
+	assert.True(t, dialect.HasColumn("notification_preferences", "message_type"))
+	assert.True(t, dialect.HasColumn("notification_preferences", "enabled"))
+}
+
+func testMigration116SpaceKeys(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:117], 117)
+
+	assert.True(t, dialect.HasColumn("spaces", "key"))
+	assert.True(t, dialect.HasIndex("spaces", "uix_spaces_key"))
+	assert.True(t, dialect.HasTable("space_key_aliases"))
+	assert.True(t, dialect.HasColumn("space_key_aliases", "space_id"))
+}
+
+func testMigration117WorkItemAliases(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:118], 118)
+
+	assert.True(t, dialect.HasTable("work_item_aliases"))
+	assert.True(t, dialect.HasColumn("work_item_aliases", "space_id"))
+	assert.True(t, dialect.HasColumn("work_item_aliases", "number"))
+	assert.True(t, dialect.HasColumn("work_item_aliases", "work_item_id"))
+	assert.True(t, dialect.HasIndex("work_item_aliases", "ix_work_item_aliases_work_item_id"))
+}
+
+func testMigration118WorkItemRecurrences(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:119], 119)
+
+	assert.True(t, dialect.HasTable("work_item_recurrences"))
+	assert.True(t, dialect.HasColumn("work_item_recurrences", "fields"))
+	assert.True(t, dialect.HasColumn("work_item_recurrences", "schedule"))
+	assert.True(t, dialect.HasColumn("work_item_recurrences", "current_iteration"))
+	assert.True(t, dialect.HasColumn("work_item_recurrences", "skip_if_open"))
+	assert.True(t, dialect.HasIndex("work_item_recurrences", "ix_work_item_recurrences_space_id"))
+	assert.True(t, dialect.HasTable("work_item_occurrences"))
+	assert.True(t, dialect.HasColumn("work_item_occurrences", "recurrence_id"))
+	assert.True(t, dialect.HasColumn("work_item_occurrences", "work_item_id"))
+	assert.True(t, dialect.HasIndex("work_item_occurrences", "ix_work_item_occurrences_recurrence_id"))
+}
+
+func testMigration119TrackerWriteBack(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:120], 120)
+
+	assert.True(t, dialect.HasColumn("trackers", "write_back"))
+	assert.True(t, dialect.HasColumn("trackers", "conflict_policy"))
+	assert.True(t, dialect.HasColumn("tracker_items", "work_item_id"))
+	assert.True(t, dialect.HasColumn("tracker_items", "remote_updated_at"))
+	assert.True(t, dialect.HasColumn("tracker_items", "work_item_version"))
+	assert.True(t, dialect.HasColumn("tracker_items", "pushed_at"))
+	assert.True(t, dialect.HasColumn("tracker_items", "conflict"))
+}
+
+func testMigration120TrackerQuerySyncStates(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:121], 121)
+
+	assert.True(t, dialect.HasTable("tracker_query_sync_states"))
+	assert.True(t, dialect.HasColumn("tracker_query_sync_states", "tracker_query_id"))
+	assert.True(t, dialect.HasColumn("tracker_query_sync_states", "last_success_at"))
+	assert.True(t, dialect.HasColumn("tracker_query_sync_states", "high_water_mark"))
+	assert.True(t, dialect.HasColumn("tracker_query_sync_states", "failed_count"))
+	assert.True(t, dialect.HasColumn("tracker_query_sync_states", "last_error"))
+}
+
+func testMigration121TrackerWebhookSecret(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:122], 122)
+
+	assert.True(t, dialect.HasColumn("trackers", "webhook_secret"))
+}
+
+func testMigration122TrackerFieldMappings(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:123], 123)
+
+	assert.True(t, dialect.HasColumn("trackers", "work_item_type_id"))
+	assert.True(t, dialect.HasColumn("trackers", "field_mappings"))
+}
+
+func testMigration123SCMActivities(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:124], 124)
+
+	assert.True(t, dialect.HasTable("scm_activities"))
+	assert.True(t, dialect.HasIndex("scm_activities", "scm_activities_ref_idx"))
+	assert.True(t, dialect.HasColumn("codebases", "webhook_secret"))
+	assert.True(t, dialect.HasColumn("codebases", "merge_state"))
+}
+
+func testMigration124Releases(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:125], 125)
+
+	assert.True(t, dialect.HasTable("releases"))
+	assert.True(t, dialect.HasIndex("releases", "releases_name_space_id_unique_idx"))
+}
+
 // runSQLscript loads the given filename from the packaged SQL test files and
 // executes it on the given database. Golang text/template module is used
 // to handle all the optional arguments passed to the sql test files
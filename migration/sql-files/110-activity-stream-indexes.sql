@@ -0,0 +1,4 @@
+-- Indexes to efficiently merge the revisions of a space into an activity stream
+CREATE INDEX ix_work_item_revisions_revision_time ON work_item_revisions USING BTREE (revision_time);
+CREATE INDEX ix_comment_revisions_comment_parent_id ON comment_revisions USING BTREE (comment_parent_id);
+CREATE INDEX ix_work_item_link_revisions_source_id ON work_item_link_revisions USING BTREE (work_item_link_source_id);
@@ -0,0 +1,14 @@
+-- index the parent comment to efficiently list and count the replies of a comment
+CREATE INDEX ix_comments_parent_comment_id ON comments USING BTREE (parent_comment_id);
+
+-- Create the comment_reactions table
+CREATE TABLE comment_reactions (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone,
+    comment_id uuid NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    identity_id uuid NOT NULL REFERENCES identities(id) ON DELETE CASCADE,
+    emoji text NOT NULL CHECK (trim(emoji) <> ''),
+    -- an identity can react only once with the same emoji on a comment
+    CONSTRAINT comment_reactions_comment_identity_emoji_uniq UNIQUE (comment_id, identity_id, emoji)
+);
+CREATE INDEX ix_comment_reactions_comment_id ON comment_reactions USING BTREE (comment_id);
@@ -0,0 +1,3 @@
+-- the allowed state transitions of work items of a type (null means that all
+-- transitions between the values of the state field are allowed)
+ALTER TABLE work_item_types ADD COLUMN transitions jsonb;
@@ -0,0 +1,18 @@
+-- Create the attachments table. The content of an attachment is kept in a
+-- blob store under the given storage key; only the metadata lives here.
+CREATE TABLE attachments (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    work_item_id uuid NOT NULL REFERENCES work_items(id) ON DELETE CASCADE,
+    comment_id uuid REFERENCES comments(id) ON DELETE CASCADE,
+    name text NOT NULL CHECK (trim(name) <> ''),
+    size bigint NOT NULL CHECK (size >= 0),
+    mime_type text NOT NULL,
+    checksum text NOT NULL,
+    storage_key text NOT NULL,
+    creator uuid NOT NULL REFERENCES identities(id) ON DELETE CASCADE
+);
+CREATE INDEX ix_attachments_work_item_id ON attachments USING BTREE (work_item_id);
+CREATE INDEX ix_attachments_comment_id ON attachments USING BTREE (comment_id);
@@ -0,0 +1,17 @@
+-- Create the worklogs table that records the time an identity spent on a
+-- work item
+CREATE TABLE worklogs (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    work_item_id uuid NOT NULL REFERENCES work_items(id) ON DELETE CASCADE,
+    identity_id uuid NOT NULL REFERENCES identities(id) ON DELETE CASCADE,
+    -- the time spent in minutes
+    duration integer NOT NULL CHECK (duration > 0),
+    -- when the work was done
+    work_date timestamp with time zone NOT NULL,
+    comment text
+);
+CREATE INDEX ix_worklogs_work_item_id ON worklogs USING BTREE (work_item_id);
+CREATE INDEX ix_worklogs_identity_id ON worklogs USING BTREE (identity_id);
@@ -0,0 +1,25 @@
+-- Create the watchers table that records who is interested in the changes of
+-- a work item, space, area or iteration
+CREATE TABLE watchers (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    identity_id uuid NOT NULL REFERENCES identities(id) ON DELETE CASCADE,
+    target_type text NOT NULL CHECK (target_type IN ('workitem', 'space', 'area', 'iteration')),
+    target_id uuid NOT NULL
+);
+CREATE UNIQUE INDEX uix_watchers_target_identity ON watchers USING BTREE (target_type, target_id, identity_id) WHERE deleted_at IS NULL;
+CREATE INDEX ix_watchers_identity_id ON watchers USING BTREE (identity_id);
+
+-- Create the notification_preferences table that records which types of
+-- notification messages an identity wants to receive. Types without a row are
+-- received.
+CREATE TABLE notification_preferences (
+    identity_id uuid NOT NULL REFERENCES identities(id) ON DELETE CASCADE,
+    message_type text NOT NULL,
+    enabled boolean NOT NULL,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    PRIMARY KEY (identity_id, message_type)
+);
@@ -0,0 +1,13 @@
+-- Add the short key of a space (e.g. "PLAT") that makes its work items
+-- addressable as PLAT-123. Spaces without a key have an empty one.
+ALTER TABLE spaces ADD COLUMN key text NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX uix_spaces_key ON spaces USING BTREE (key) WHERE deleted_at IS NULL AND key <> '';
+
+-- Keep the former keys of spaces so that work item keys which were handed
+-- out before a space key was renamed can still be resolved
+CREATE TABLE space_key_aliases (
+    key text PRIMARY KEY,
+    space_id uuid NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    created_at timestamp with time zone DEFAULT current_timestamp
+);
+CREATE INDEX ix_space_key_aliases_space_id ON space_key_aliases USING BTREE (space_id);
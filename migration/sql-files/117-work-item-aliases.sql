@@ -0,0 +1,10 @@
+-- Keep the former space and number of work items that were moved to another
+-- space so that their old keys and URLs can still be resolved
+CREATE TABLE work_item_aliases (
+    space_id uuid NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    number integer NOT NULL,
+    work_item_id uuid NOT NULL REFERENCES work_items(id) ON DELETE CASCADE,
+    created_at timestamp with time zone DEFAULT current_timestamp,
+    PRIMARY KEY (space_id, number)
+);
+CREATE INDEX ix_work_item_aliases_work_item_id ON work_item_aliases USING BTREE (work_item_id);
@@ -0,0 +1,30 @@
+-- Create the table of recurring work item definitions: a set of field values
+-- for a new work item of a type and the cron schedule to create it on
+CREATE TABLE work_item_recurrences (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    space_id uuid NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    type_id uuid NOT NULL REFERENCES work_item_types(id) ON DELETE CASCADE,
+    creator_id uuid NOT NULL REFERENCES identities(id) ON DELETE CASCADE,
+    name text NOT NULL CHECK (name <> ''),
+    fields jsonb NOT NULL DEFAULT '{}',
+    schedule text NOT NULL,
+    -- put the new work items into the active iteration of the space
+    current_iteration boolean NOT NULL DEFAULT FALSE,
+    -- don't create a new work item while the previous one is still open
+    skip_if_open boolean NOT NULL DEFAULT FALSE,
+    enabled boolean NOT NULL DEFAULT TRUE,
+    last_run_at timestamp with time zone
+);
+CREATE INDEX ix_work_item_recurrences_space_id ON work_item_recurrences USING BTREE (space_id);
+
+-- Create the history of the work items created for a recurrence
+CREATE TABLE work_item_occurrences (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    recurrence_id uuid NOT NULL REFERENCES work_item_recurrences(id) ON DELETE CASCADE,
+    work_item_id uuid NOT NULL REFERENCES work_items(id) ON DELETE CASCADE
+);
+CREATE INDEX ix_work_item_occurrences_recurrence_id ON work_item_occurrences USING BTREE (recurrence_id);
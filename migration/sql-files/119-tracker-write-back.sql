@@ -0,0 +1,11 @@
+-- write-back of local changes and the conflict policy of a tracker
+ALTER TABLE trackers ADD COLUMN write_back boolean NOT NULL DEFAULT FALSE;
+ALTER TABLE trackers ADD COLUMN conflict_policy text NOT NULL DEFAULT 'remote-wins'
+    CHECK (conflict_policy IN ('remote-wins', 'local-wins', 'flag'));
+
+-- sync state of the tracker items
+ALTER TABLE tracker_items ADD COLUMN work_item_id uuid REFERENCES work_items(id) ON DELETE SET NULL;
+ALTER TABLE tracker_items ADD COLUMN remote_updated_at text;
+ALTER TABLE tracker_items ADD COLUMN work_item_version integer;
+ALTER TABLE tracker_items ADD COLUMN pushed_at timestamp with time zone;
+ALTER TABLE tracker_items ADD COLUMN conflict boolean NOT NULL DEFAULT FALSE;
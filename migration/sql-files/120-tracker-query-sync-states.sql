@@ -0,0 +1,14 @@
+-- sync state of the tracker queries
+CREATE TABLE tracker_query_sync_states (
+    tracker_query_id bigint PRIMARY KEY REFERENCES tracker_queries(id) ON DELETE CASCADE,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    last_run_at timestamp with time zone,
+    last_success_at timestamp with time zone,
+    high_water_mark timestamp with time zone,
+    fetched_count integer NOT NULL DEFAULT 0,
+    synced_count integer NOT NULL DEFAULT 0,
+    failed_count integer NOT NULL DEFAULT 0,
+    last_error text
+);
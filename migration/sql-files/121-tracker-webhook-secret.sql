@@ -0,0 +1,2 @@
+-- secret used to verify the webhook requests sent by a tracker
+ALTER TABLE trackers ADD COLUMN webhook_secret text;
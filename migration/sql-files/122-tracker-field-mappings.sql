@@ -0,0 +1,3 @@
+-- work item type and field mappings of the remote items of a tracker
+ALTER TABLE trackers ADD COLUMN work_item_type_id uuid REFERENCES work_item_types(id) ON DELETE SET NULL;
+ALTER TABLE trackers ADD COLUMN field_mappings jsonb NOT NULL DEFAULT '[]';
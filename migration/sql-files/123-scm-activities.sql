@@ -0,0 +1,24 @@
+-- commits, branches and pull requests of codebases referencing work items
+CREATE TABLE scm_activities (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    work_item_id uuid NOT NULL REFERENCES work_items(id) ON DELETE CASCADE,
+    codebase_id uuid NOT NULL REFERENCES codebases(id) ON DELETE CASCADE,
+    kind text NOT NULL CHECK (kind IN ('commit', 'branch', 'pull-request')),
+    ref text NOT NULL,
+    title text,
+    url text,
+    author text,
+    state text,
+    closes boolean NOT NULL DEFAULT FALSE,
+    occurred_at timestamp with time zone
+);
+CREATE UNIQUE INDEX scm_activities_ref_idx ON scm_activities (work_item_id, codebase_id, kind, ref) WHERE deleted_at IS NULL;
+CREATE INDEX scm_activities_work_item_id_idx ON scm_activities (work_item_id);
+
+-- secret used to verify the push and pull request webhooks of a codebase
+ALTER TABLE codebases ADD COLUMN webhook_secret text;
+-- state the work items closed by a merged pull request are moved to
+ALTER TABLE codebases ADD COLUMN merge_state text;
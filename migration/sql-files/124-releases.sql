@@ -0,0 +1,16 @@
+-- releases of a space that work items are planned for
+CREATE TABLE releases (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    space_id uuid NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    name text NOT NULL CHECK (trim(name) <> ''),
+    description text,
+    target_date timestamp with time zone,
+    status text NOT NULL DEFAULT 'planned' CHECK (status IN ('planned', 'active', 'released')),
+    released_at timestamp with time zone,
+    archived_at timestamp with time zone,
+    version integer NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX releases_name_space_id_unique_idx ON releases (space_id, name) WHERE deleted_at IS NULL;
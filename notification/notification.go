@@ -22,6 +22,22 @@ type Channel interface {
 	Send(context.Context, Message)
 }
 
+// The types of messages
+const (
+	MessageTypeWorkItemCreate = "workitem.create"
+	MessageTypeWorkItemUpdate = "workitem.update"
+	MessageTypeCommentCreate  = "comment.create"
+	MessageTypeCommentUpdate  = "comment.update"
+)
+
+// MessageTypes lists all types of messages
+var MessageTypes = []string{
+	MessageTypeWorkItemCreate,
+	MessageTypeWorkItemUpdate,
+	MessageTypeCommentCreate,
+	MessageTypeCommentUpdate,
+}
+
 // Message represents a new event of a Type for a Target performed by a User
 // See helper constructors like NewWorkItemCreated, NewCommentUpdated
 type Message struct {
@@ -30,17 +46,20 @@ type Message struct {
 	TargetID    string
 	MessageType string
 	Custom      map[string]interface{}
+	// Recipients are the identities that want to receive the message. It is
+	// resolved from the watchers of the target (see NewWatcherChannel).
+	Recipients []uuid.UUID
 }
 
 func (m Message) String() string {
-	return fmt.Sprintf("id:%v type:%v by:%v for:%v custom:%+v", m.MessageID, m.MessageType, m.UserID, m.TargetID, m.Custom)
+	return fmt.Sprintf("id:%v type:%v by:%v for:%v to:%v custom:%+v", m.MessageID, m.MessageType, m.UserID, m.TargetID, m.Recipients, m.Custom)
 }
 
 // NewWorkItemCreated creates a new message instance for the newly created WorkItemID
 func NewWorkItemCreated(workitemID string, revisionID uuid.UUID) Message {
 	return Message{
 		MessageID:   uuid.NewV4(),
-		MessageType: "workitem.create",
+		MessageType: MessageTypeWorkItemCreate,
 		TargetID:    workitemID,
 		Custom:      map[string]interface{}{"revision_id": revisionID},
 	}
@@ -50,7 +69,7 @@ func NewWorkItemCreated(workitemID string, revisionID uuid.UUID) Message {
 func NewWorkItemUpdated(workitemID string, revisionID uuid.UUID) Message {
 	return Message{
 		MessageID:   uuid.NewV4(),
-		MessageType: "workitem.update",
+		MessageType: MessageTypeWorkItemUpdate,
 		TargetID:    workitemID,
 		Custom:      map[string]interface{}{"revision_id": revisionID},
 	}
@@ -58,12 +77,12 @@ func NewWorkItemUpdated(workitemID string, revisionID uuid.UUID) Message {
 
 // NewCommentCreated creates a new message instance for the newly created CommentID
 func NewCommentCreated(commentID string) Message {
-	return Message{MessageID: uuid.NewV4(), MessageType: "comment.create", TargetID: commentID}
+	return Message{MessageID: uuid.NewV4(), MessageType: MessageTypeCommentCreate, TargetID: commentID}
 }
 
 // NewCommentUpdated creates a new message instance for the updated CommentID
 func NewCommentUpdated(commentID string) Message {
-	return Message{MessageID: uuid.NewV4(), MessageType: "comment.update", TargetID: commentID}
+	return Message{MessageID: uuid.NewV4(), MessageType: MessageTypeCommentUpdate, TargetID: commentID}
 }
 
 func setCurrentIdentity(ctx context.Context, msg *Message) {
@@ -126,6 +145,14 @@ func (s *Service) Send(ctx context.Context, msg Message) {
 		cl.SetJWTSigner(goasupport.NewForwardSigner(ctx))
 
 		msgID := goauuid.UUID(msg.MessageID)
+		custom := msg.Custom
+		if msg.Recipients != nil {
+			custom = make(map[string]interface{}, len(msg.Custom)+1)
+			for k, v := range msg.Custom {
+				custom[k] = v
+			}
+			custom["recipients"] = msg.Recipients
+		}
 
 		resp, err := cl.SendNotify(
 			goasupport.ForwardContextRequestID(ctx),
@@ -137,7 +164,7 @@ func (s *Service) Send(ctx context.Context, msg Message) {
 					Attributes: &client.NotificationAttributes{
 						Type:   msg.MessageType,
 						ID:     msg.TargetID,
-						Custom: msg.Custom,
+						Custom: custom,
 					},
 				},
 			},
@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"context"
+	"strings"
+
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/watcher"
+	uuid "github.com/satori/go.uuid"
+)
+
+// WatcherChannel resolves the recipients of a message from the watchers of
+// its target before passing it on to the next channel
+type WatcherChannel struct {
+	watchers watcher.Repository
+	next     Channel
+}
+
+// NewWatcherChannel creates a channel that sets the recipients of the messages
+// sent to the given channel
+func NewWatcherChannel(watchers watcher.Repository, next Channel) Channel {
+	return &WatcherChannel{watchers: watchers, next: next}
+}
+
+// Send resolves the recipients of the message and sends it to the next
+// channel. The user who caused the message is not notified about it.
+func (c *WatcherChannel) Send(ctx context.Context, msg Message) {
+	targetID, err := uuid.FromString(msg.TargetID)
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"message_id": msg.MessageID,
+			"target_id":  msg.TargetID,
+			"err":        err,
+		}, "unable to resolve the recipients of a message with an invalid target")
+		c.next.Send(ctx, msg)
+		return
+	}
+	var recipients []uuid.UUID
+	if strings.HasPrefix(msg.MessageType, "comment.") {
+		recipients, err = c.watchers.CommentRecipients(ctx, targetID, msg.MessageType)
+	} else {
+		recipients, err = c.watchers.Recipients(ctx, targetID, msg.MessageType)
+	}
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"message_id": msg.MessageID,
+			"type":       msg.MessageType,
+			"target_id":  msg.TargetID,
+			"err":        err,
+		}, "unable to resolve the recipients of the message")
+		c.next.Send(ctx, msg)
+		return
+	}
+	msg.Recipients = []uuid.UUID{}
+	currentIdentityID, _ := login.ContextIdentity(ctx)
+	for _, r := range recipients {
+		if currentIdentityID == nil || r != *currentIdentityID {
+			msg.Recipients = append(msg.Recipients, r)
+		}
+	}
+	c.next.Send(ctx, msg)
+}
@@ -0,0 +1,248 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/application/repository"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	"github.com/fabric8-services/fabric8-wit/log"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeReleases helps to avoid string literal
+const APIStringTypeReleases = "releases"
+
+// Statuses of a release
+const (
+	StatusPlanned  = "planned"
+	StatusActive   = "active"
+	StatusReleased = "released"
+)
+
+// Release is a version of the product of a space that work items are
+// planned for through fields of kind "release". Releases belong to a space,
+// so moving a work item to another space replaces the releases it references
+// with releases of the target space (see workitem.MoveMapping).
+type Release struct {
+	gormsupport.Lifecycle
+	ID          uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	SpaceID     uuid.UUID `sql:"type:uuid"`
+	Name        string
+	Description string
+	TargetDate  *time.Time
+	Status      string `sql:"DEFAULT:planned"`
+	// ReleasedAt is set when the status changes to "released"
+	ReleasedAt *time.Time
+	// ArchivedAt is set when a released release is archived, archived
+	// releases are hidden from the list of releases by default
+	ArchivedAt *time.Time
+	Version    int
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (m Release) GetETagData() []interface{} {
+	return []interface{}{m.ID, m.Version}
+}
+
+// GetLastModified returns the last modification time
+func (m Release) GetLastModified() time.Time {
+	return m.UpdatedAt.Truncate(time.Second)
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (m Release) TableName() string {
+	return releaseTableName
+}
+
+const releaseTableName = "releases"
+
+// Repository describes interactions with releases
+type Repository interface {
+	repository.Exister
+	Create(ctx context.Context, r *Release) error
+	Save(ctx context.Context, r Release) (*Release, error)
+	Load(ctx context.Context, id uuid.UUID) (*Release, error)
+	List(ctx context.Context, spaceID uuid.UUID, includeArchived bool) ([]Release, error)
+	Archive(ctx context.Context, id uuid.UUID) (*Release, error)
+	Readiness(ctx context.Context, r Release) (*Readiness, error)
+	Notes(ctx context.Context, r Release) (string, error)
+}
+
+// NewRepository creates a new storage type.
+func NewRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+// GormRepository is the implementation of the storage interface for releases.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+// CheckExists returns nil if the given ID exists otherwise returns an error
+func (m *GormRepository) CheckExists(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "release", "exists"}, time.Now())
+	return repository.CheckExists(ctx, m.db, releaseTableName, id)
+}
+
+// validate returns a BadParameterError if the given release is invalid
+func validate(r Release) error {
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.NewBadParameterError("name", r.Name).Expected("non empty string")
+	}
+	switch r.Status {
+	case StatusPlanned, StatusActive, StatusReleased:
+	default:
+		return errors.NewBadParameterError("status", r.Status).Expected(fmt.Sprintf("%s, %s or %s", StatusPlanned, StatusActive, StatusReleased))
+	}
+	if r.ArchivedAt != nil && r.Status != StatusReleased {
+		return errors.NewBadParameterError("status", r.Status).Expected("released for an archived release")
+	}
+	return nil
+}
+
+// nameConflict returns a DataConflictError if the given error is a violation
+// of the unique name of the releases of a space
+func nameConflict(ctx context.Context, err error, r Release) error {
+	if gormsupport.IsUniqueViolation(err, "releases_name_space_id_unique_idx") {
+		log.Error(ctx, map[string]interface{}{
+			"err":      err,
+			"name":     r.Name,
+			"space_id": r.SpaceID,
+		}, "a release with the same name already exists in the space")
+		return errors.NewDataConflictError(fmt.Sprintf("release already exists with name = %s , space_id = %s", r.Name, r.SpaceID))
+	}
+	return nil
+}
+
+// Create creates a new release
+func (m *GormRepository) Create(ctx context.Context, r *Release) error {
+	defer goa.MeasureSince([]string{"goa", "db", "release", "create"}, time.Now())
+	r.ID = uuid.NewV4()
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Status == "" {
+		r.Status = StatusPlanned
+	}
+	if err := validate(*r); err != nil {
+		return err
+	}
+	if r.Status == StatusReleased && r.ReleasedAt == nil {
+		now := time.Now()
+		r.ReleasedAt = &now
+	}
+	if err := m.db.Create(r).Error; err != nil {
+		if conflict := nameConflict(ctx, err, *r); conflict != nil {
+			return conflict
+		}
+		log.Error(ctx, map[string]interface{}{
+			"err":      err,
+			"space_id": r.SpaceID,
+		}, "unable to create the release")
+		return errors.NewInternalError(ctx, err)
+	}
+	return nil
+}
+
+// Save updates the given release. The release date is recorded when the
+// status changes to "released".
+func (m *GormRepository) Save(ctx context.Context, r Release) (*Release, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "release", "save"}, time.Now())
+	r.Name = strings.TrimSpace(r.Name)
+	if err := validate(r); err != nil {
+		return nil, err
+	}
+	existing, err := m.Load(ctx, r.ID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case r.Status == StatusReleased && existing.Status != StatusReleased:
+		now := time.Now()
+		r.ReleasedAt = &now
+	case r.Status != StatusReleased:
+		r.ReleasedAt = nil
+	}
+	oldVersion := r.Version
+	r.Version = existing.Version + 1
+	r.CreatedAt = existing.CreatedAt
+	tx := m.db.Where("version = ?", oldVersion).Save(&r)
+	if err := tx.Error; err != nil {
+		if conflict := nameConflict(ctx, err, r); conflict != nil {
+			return nil, conflict
+		}
+		log.Error(ctx, map[string]interface{}{
+			"release_id": r.ID,
+			"err":        err,
+		}, "unable to save the release")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	if tx.RowsAffected == 0 {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"release_id": r.ID,
+	}, "release updated successfully")
+	return &r, nil
+}
+
+// Load returns the release with the given ID
+func (m *GormRepository) Load(ctx context.Context, id uuid.UUID) (*Release, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "release", "load"}, time.Now())
+	r := Release{}
+	tx := m.db.Where("id = ?", id).First(&r)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("release", id.String())
+	}
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":        tx.Error,
+			"release_id": id,
+		}, "unable to load the release by ID")
+		return nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	return &r, nil
+}
+
+// List returns the releases of the given space ordered by target date, the
+// archived releases only if asked for
+func (m *GormRepository) List(ctx context.Context, spaceID uuid.UUID, includeArchived bool) ([]Release, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "release", "list"}, time.Now())
+	db := m.db.Where("space_id = ?", spaceID)
+	if !includeArchived {
+		db = db.Where("archived_at IS NULL")
+	}
+	var res []Release
+	if err := db.Order("target_date ASC NULLS LAST, name ASC").Find(&res).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":      err,
+			"space_id": spaceID,
+		}, "unable to list the releases of the space")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return res, nil
+}
+
+// Archive archives the given release, which must have been released
+func (m *GormRepository) Archive(ctx context.Context, id uuid.UUID) (*Release, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "release", "archive"}, time.Now())
+	r, err := m.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if r.ArchivedAt != nil {
+		return r, nil
+	}
+	if r.Status != StatusReleased {
+		return nil, errors.NewBadParameterError("status", r.Status).Expected("only released releases can be archived")
+	}
+	now := time.Now()
+	r.ArchivedAt = &now
+	return m.Save(ctx, *r)
+}
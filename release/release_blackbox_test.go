@@ -0,0 +1,223 @@
+package release_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/release"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type releaseBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	repo release.Repository
+}
+
+func TestRunReleaseBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &releaseBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *releaseBlackBoxTest) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.repo = release.NewRepository(s.DB)
+}
+
+func (s *releaseBlackBoxTest) TestCreate() {
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Spaces(1))
+
+	s.T().Run("ok", func(t *testing.T) {
+		r := release.Release{SpaceID: fxt.Spaces[0].ID, Name: " 1.0 "}
+		err := s.repo.Create(s.Ctx, &r)
+		require.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, r.ID)
+		assert.Equal(t, "1.0", r.Name)
+		assert.Equal(t, release.StatusPlanned, r.Status)
+		assert.Nil(t, r.ReleasedAt)
+	})
+
+	s.T().Run("duplicate name", func(t *testing.T) {
+		r := release.Release{SpaceID: fxt.Spaces[0].ID, Name: "1.0"}
+		err := s.repo.Create(s.Ctx, &r)
+		require.Error(t, err)
+		assert.IsType(t, errors.DataConflictError{}, err)
+	})
+
+	s.T().Run("empty name", func(t *testing.T) {
+		r := release.Release{SpaceID: fxt.Spaces[0].ID, Name: " "}
+		err := s.repo.Create(s.Ctx, &r)
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, err)
+	})
+
+	s.T().Run("unknown status", func(t *testing.T) {
+		r := release.Release{SpaceID: fxt.Spaces[0].ID, Name: "2.0", Status: "shipped"}
+		err := s.repo.Create(s.Ctx, &r)
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, err)
+	})
+}
+
+func (s *releaseBlackBoxTest) TestSaveAndArchive() {
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Spaces(1))
+	r := release.Release{SpaceID: fxt.Spaces[0].ID, Name: "1.0"}
+	require.NoError(s.T(), s.repo.Create(s.Ctx, &r))
+
+	s.T().Run("archive unreleased", func(t *testing.T) {
+		_, err := s.repo.Archive(s.Ctx, r.ID)
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, err)
+	})
+
+	s.T().Run("release", func(t *testing.T) {
+		r.Status = release.StatusReleased
+		saved, err := s.repo.Save(s.Ctx, r)
+		require.NoError(t, err)
+		assert.Equal(t, r.Version+1, saved.Version)
+		require.NotNil(t, saved.ReleasedAt)
+		r = *saved
+	})
+
+	s.T().Run("version conflict", func(t *testing.T) {
+		stale := r
+		stale.Version--
+		_, err := s.repo.Save(s.Ctx, stale)
+		require.Error(t, err)
+		assert.IsType(t, errors.VersionConflictError{}, err)
+	})
+
+	s.T().Run("archive", func(t *testing.T) {
+		archived, err := s.repo.Archive(s.Ctx, r.ID)
+		require.NoError(t, err)
+		require.NotNil(t, archived.ArchivedAt)
+		releases, err := s.repo.List(s.Ctx, fxt.Spaces[0].ID, false)
+		require.NoError(t, err)
+		assert.Empty(t, releases)
+		releases, err = s.repo.List(s.Ctx, fxt.Spaces[0].ID, true)
+		require.NoError(t, err)
+		require.Len(t, releases, 1)
+		assert.Equal(t, r.ID, releases[0].ID)
+	})
+}
+
+func (s *releaseBlackBoxTest) TestReadinessAndNotes() {
+	fieldName := "fixversion"
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.CreateWorkItemEnvironment(),
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].Name = "Bug"
+			fxt.WorkItemTypes[idx].Fields[fieldName] = workitem.FieldDefinition{
+				Label: "Fix version",
+				Type:  workitem.SimpleType{Kind: workitem.KindRelease},
+			}
+			return nil
+		}),
+	)
+	r := release.Release{SpaceID: fxt.Spaces[0].ID, Name: "1.0", Description: "The first one"}
+	require.NoError(s.T(), s.repo.Create(s.Ctx, &r))
+	wir := workitem.NewWorkItemRepository(s.DB)
+	create := func(t *testing.T, title, state string, rel interface{}) *workitem.WorkItem {
+		fields := map[string]interface{}{
+			workitem.SystemTitle: title,
+			workitem.SystemState: state,
+		}
+		if rel != nil {
+			fields[fieldName] = rel
+		}
+		wi, _, err := wir.Create(s.Ctx, fxt.Spaces[0].ID, fxt.WorkItemTypes[0].ID, fields, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		return wi
+	}
+	done := create(s.T(), "Fix the login", workitem.SystemStateClosed, r.ID.String())
+	blocker := create(s.T(), "Fix the logout", workitem.SystemStateOpen, r.ID.String())
+	blocked := create(s.T(), "Unplanned", workitem.SystemStateNew, nil)
+	_, err := link.NewWorkItemLinkRepository(s.DB).Create(s.Ctx, blocker.ID, blocked.ID, link.SystemWorkItemLinkTypeBugBlockerID, fxt.Identities[0].ID)
+	require.NoError(s.T(), err)
+
+	s.T().Run("unknown release", func(t *testing.T) {
+		_, _, err := wir.Create(s.Ctx, fxt.Spaces[0].ID, fxt.WorkItemTypes[0].ID, map[string]interface{}{
+			workitem.SystemTitle: "Unknown release",
+			workitem.SystemState: workitem.SystemStateNew,
+			fieldName:            uuid.NewV4().String(),
+		}, fxt.Identities[0].ID)
+		require.Error(t, err)
+	})
+
+	s.T().Run("readiness", func(t *testing.T) {
+		readiness, err := s.repo.Readiness(s.Ctx, r)
+		require.NoError(t, err)
+		assert.Equal(t, 2, readiness.Total)
+		assert.Equal(t, 1, readiness.Open)
+		assert.Equal(t, 1, readiness.Closed)
+		require.Len(t, readiness.Types, 1)
+		assert.Equal(t, release.TypeCount{TypeID: fxt.WorkItemTypes[0].ID, TypeName: "Bug", Open: 1, Closed: 1}, readiness.Types[0])
+		require.Len(t, readiness.Blocking, 1)
+		assert.Equal(t, blocker.ID, readiness.Blocking[0].ID)
+	})
+
+	s.T().Run("notes", func(t *testing.T) {
+		notes, err := s.repo.Notes(s.Ctx, r)
+		require.NoError(t, err)
+		assert.Equal(t, "# 1.0\n\nThe first one\n\n## Bug\n\n- #"+strconv.Itoa(done.Number)+" Fix the login\n", notes)
+	})
+}
+
+func (s *releaseBlackBoxTest) TestReadinessClosedMetaState() {
+	// given a type whose "done" state has the closed meta-state
+	fieldName := "fixversion"
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.CreateWorkItemEnvironment(),
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].Name = "Task"
+			fxt.WorkItemTypes[idx].Fields[workitem.SystemState] = workitem.FieldDefinition{
+				Label: "State",
+				Type: workitem.EnumType{
+					SimpleType: workitem.SimpleType{Kind: workitem.KindEnum},
+					BaseType:   workitem.SimpleType{Kind: workitem.KindString},
+					Values:     []interface{}{"todo", "done"},
+				},
+			}
+			fxt.WorkItemTypes[idx].Fields[workitem.SystemMetaState] = workitem.FieldDefinition{
+				Label: "Meta-state",
+				Type: workitem.EnumType{
+					SimpleType: workitem.SimpleType{Kind: workitem.KindEnum},
+					BaseType:   workitem.SimpleType{Kind: workitem.KindString},
+					Values:     []interface{}{"mNew", workitem.SystemMetaStateClosed},
+				},
+			}
+			fxt.WorkItemTypes[idx].Fields[fieldName] = workitem.FieldDefinition{
+				Label: "Fix version",
+				Type:  workitem.SimpleType{Kind: workitem.KindRelease},
+			}
+			return nil
+		}),
+	)
+	r := release.Release{SpaceID: fxt.Spaces[0].ID, Name: "1.0"}
+	require.NoError(s.T(), s.repo.Create(s.Ctx, &r))
+	wir := workitem.NewWorkItemRepository(s.DB)
+	for state, metaState := range map[string]string{"todo": "mNew", "done": workitem.SystemMetaStateClosed} {
+		_, _, err := wir.Create(s.Ctx, fxt.Spaces[0].ID, fxt.WorkItemTypes[0].ID, map[string]interface{}{
+			workitem.SystemTitle:     state,
+			workitem.SystemState:     state,
+			workitem.SystemMetaState: metaState,
+			fieldName:                r.ID.String(),
+		}, fxt.Identities[0].ID)
+		require.NoError(s.T(), err)
+	}
+	// when
+	readiness, err := s.repo.Readiness(s.Ctx, r)
+	// then
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, readiness.Total)
+	assert.Equal(s.T(), 1, readiness.Open)
+	assert.Equal(s.T(), 1, readiness.Closed)
+}
@@ -0,0 +1,187 @@
+package release
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Item is a work item planned for a release
+type Item struct {
+	ID       uuid.UUID
+	Number   int
+	Title    string
+	TypeID   uuid.UUID `gorm:"column:type"`
+	TypeName string
+	Closed   bool
+}
+
+// TypeCount holds the number of open and closed items of a work item type
+type TypeCount struct {
+	TypeID   uuid.UUID
+	TypeName string
+	Open     int
+	Closed   int
+}
+
+// Readiness tells how far a release is from being shipped
+type Readiness struct {
+	Total  int
+	Open   int
+	Closed int
+	// Types holds the counts per work item type, ordered by type name
+	Types []TypeCount
+	// Blocking holds the open items that block other work items
+	Blocking []Item
+}
+
+// listItems returns the work items of the space of the given release with a
+// field that references it, ordered by number. Items are closed as defined
+// by workitem.IsClosed.
+func (m *GormRepository) listItems(ctx context.Context, r Release) ([]Item, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "release", "items"}, time.Now())
+	var items []Item
+	id := r.ID.String()
+	// release fields hold the ID of the release, list fields contain it
+	err := m.db.Raw(`
+		SELECT
+			wi.id,
+			wi.number,
+			wi.fields->>'system.title' AS title,
+			wi.type,
+			wit.name AS type_name,
+			`+workitem.ClosedCondition("wi")+` AS closed
+		FROM work_items wi
+		JOIN work_item_types wit ON wit.id = wi.type
+		WHERE
+			wi.space_id = ?
+			AND wi.deleted_at IS NULL
+			AND EXISTS (
+				SELECT 1 FROM jsonb_each(wi.fields) f
+				WHERE f.value = to_jsonb(?::text)
+				OR (jsonb_typeof(f.value) = 'array' AND f.value @> jsonb_build_array(?::text))
+			)
+		ORDER BY wi.number`, r.SpaceID, id, id).Scan(&items).Error
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":        err,
+			"release_id": r.ID,
+		}, "unable to list the work items of the release")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return items, nil
+}
+
+// Readiness returns the readiness report of the given release
+func (m *GormRepository) Readiness(ctx context.Context, r Release) (*Readiness, error) {
+	items, err := m.listItems(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	var open []uuid.UUID
+	for _, item := range items {
+		if !item.Closed {
+			open = append(open, item.ID)
+		}
+	}
+	blocking := map[uuid.UUID]struct{}{}
+	if len(open) > 0 {
+		var ids []uuid.UUID
+		err := m.db.Table("work_item_links").
+			Where("link_type_id = ? AND source_id IN (?) AND deleted_at IS NULL", link.SystemWorkItemLinkTypeBugBlockerID, open).
+			Pluck("DISTINCT source_id", &ids).Error
+		if err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"err":        err,
+				"release_id": r.ID,
+			}, "unable to find the blocking work items of the release")
+			return nil, errors.NewInternalError(ctx, err)
+		}
+		for _, id := range ids {
+			blocking[id] = struct{}{}
+		}
+	}
+	return newReadiness(items, blocking), nil
+}
+
+// newReadiness counts the given items of a release
+func newReadiness(items []Item, blocking map[uuid.UUID]struct{}) *Readiness {
+	res := Readiness{Types: []TypeCount{}, Blocking: []Item{}}
+	counts := map[uuid.UUID]*TypeCount{}
+	for _, item := range items {
+		c, ok := counts[item.TypeID]
+		if !ok {
+			c = &TypeCount{TypeID: item.TypeID, TypeName: item.TypeName}
+			counts[item.TypeID] = c
+		}
+		res.Total++
+		if item.Closed {
+			res.Closed++
+			c.Closed++
+			continue
+		}
+		res.Open++
+		c.Open++
+		if _, ok := blocking[item.ID]; ok {
+			res.Blocking = append(res.Blocking, item)
+		}
+	}
+	for _, c := range counts {
+		res.Types = append(res.Types, *c)
+	}
+	sort.Slice(res.Types, func(i, j int) bool {
+		return res.Types[i].TypeName < res.Types[j].TypeName
+	})
+	return &res
+}
+
+// Notes returns the release notes of the given release in Markdown, listing
+// its closed work items grouped by type
+func (m *GormRepository) Notes(ctx context.Context, r Release) (string, error) {
+	items, err := m.listItems(ctx, r)
+	if err != nil {
+		return "", err
+	}
+	return notes(r, items), nil
+}
+
+// notes renders the release notes of the given items of a release
+func notes(r Release, items []Item) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", r.Name)
+	if r.ReleasedAt != nil {
+		fmt.Fprintf(&buf, "\nReleased on %s\n", r.ReleasedAt.UTC().Format("2006-01-02"))
+	}
+	if r.Description != "" {
+		fmt.Fprintf(&buf, "\n%s\n", r.Description)
+	}
+	byType := map[string][]Item{}
+	var typeNames []string
+	for _, item := range items {
+		if !item.Closed {
+			continue
+		}
+		if _, ok := byType[item.TypeName]; !ok {
+			typeNames = append(typeNames, item.TypeName)
+		}
+		byType[item.TypeName] = append(byType[item.TypeName], item)
+	}
+	sort.Strings(typeNames)
+	for _, typeName := range typeNames {
+		fmt.Fprintf(&buf, "\n## %s\n\n", typeName)
+		for _, item := range byType[typeName] {
+			fmt.Fprintf(&buf, "- #%d %s\n", item.Number, item.Title)
+		}
+	}
+	return buf.String()
+}
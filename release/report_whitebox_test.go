@@ -0,0 +1,48 @@
+package release
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReadiness(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	bug, feature := uuid.NewV4(), uuid.NewV4()
+	items := []Item{
+		{ID: uuid.NewV4(), Number: 1, TypeID: feature, TypeName: "Feature", Closed: true},
+		{ID: uuid.NewV4(), Number: 2, TypeID: bug, TypeName: "Bug"},
+		{ID: uuid.NewV4(), Number: 3, TypeID: bug, TypeName: "Bug"},
+		{ID: uuid.NewV4(), Number: 4, TypeID: bug, TypeName: "Bug", Closed: true},
+	}
+	// closed items never block the release
+	blocking := map[uuid.UUID]struct{}{items[0].ID: {}, items[2].ID: {}}
+
+	r := newReadiness(items, blocking)
+	assert.Equal(t, 4, r.Total)
+	assert.Equal(t, 2, r.Open)
+	assert.Equal(t, 2, r.Closed)
+	assert.Equal(t, []TypeCount{
+		{TypeID: bug, TypeName: "Bug", Open: 2, Closed: 1},
+		{TypeID: feature, TypeName: "Feature", Open: 0, Closed: 1},
+	}, r.Types)
+	require.Len(t, r.Blocking, 1)
+	assert.Equal(t, 3, r.Blocking[0].Number)
+}
+
+func TestNotes(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	releasedAt := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+	r := Release{Name: "1.0", ReleasedAt: &releasedAt}
+	items := []Item{
+		{Number: 1, Title: "Login", TypeName: "Feature", Closed: true},
+		{Number: 2, Title: "Crash on logout", TypeName: "Bug", Closed: true},
+		{Number: 3, Title: "Still open", TypeName: "Bug"},
+	}
+	expected := "# 1.0\n\nReleased on 2018-06-01\n\n## Bug\n\n- #2 Crash on logout\n\n## Feature\n\n- #1 Login\n"
+	assert.Equal(t, expected, notes(r, items))
+}
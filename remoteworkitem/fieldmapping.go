@@ -0,0 +1,281 @@
+package remoteworkitem
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Converters of the field mappings
+const (
+	// MappingConverterString copies the remote value
+	MappingConverterString = "string"
+	// MappingConverterList turns the remote value into a list
+	MappingConverterList = "list"
+	// MappingConverterEnum translates the remote value with the value map of
+	// the mapping
+	MappingConverterEnum = "enum"
+	// MappingConverterUser looks up the users with the remote logins or
+	// emails
+	MappingConverterUser = "user"
+	// MappingConverterMarkup turns the remote value into markup content
+	MappingConverterMarkup = "markup"
+)
+
+// FieldMapping maps an attribute of the remote items of a tracker to a field
+// of the work item type of the tracker
+type FieldMapping struct {
+	// Expression is the key of the attribute in the flattened remote item,
+	// e.g. "fields.priority.name". A "?" stands for every index of an array,
+	// e.g. "fields.components.?.name".
+	Expression string `json:"expression"`
+	// Field is the name of the work item field
+	Field string `json:"field"`
+	// Converter is the name of the converter of the remote value
+	Converter string `json:"converter"`
+	// Values maps the remote values to the local values of the enum converter
+	Values map[string]string `json:"values,omitempty"`
+	// Markup is the markup of the markup converter, "Markdown" by default
+	Markup string `json:"markup,omitempty"`
+}
+
+// FieldMappings is the list of the field mappings of a tracker
+type FieldMappings []FieldMapping
+
+// Ensure FieldMappings implements the Scanner and Valuer interfaces
+var _ driver.Valuer = FieldMappings{}
+var _ sql.Scanner = (*FieldMappings)(nil)
+
+// Value implements the driver.Valuer interface
+func (m FieldMappings) Value() (driver.Value, error) {
+	if m == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(m)
+}
+
+// Scan implements the sql.Scanner interface
+func (m *FieldMappings) Scan(src interface{}) error {
+	if src == nil {
+		*m = nil
+		return nil
+	}
+	b, ok := src.([]byte)
+	if !ok {
+		return errs.Errorf("scan source was not []byte but %T", src)
+	}
+	return json.Unmarshal(b, m)
+}
+
+// EnumConverter translates remote values with a value map. Values that are
+// not in the map are kept.
+type EnumConverter struct {
+	values map[string]string
+}
+
+// Convert translates the given value
+func (converter *EnumConverter) Convert(value interface{}, item AttributeAccessor) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if v, ok := converter.values[fmt.Sprint(value)]; ok {
+		return v, nil
+	}
+	return value, nil
+}
+
+// remoteEnumValue translates the given local value of the given field back
+// to the remote value with the enum mappings of the field. The current value
+// of the remote item is preferred if several remote values map to the local
+// value. Values that are not mapped are kept.
+func (m FieldMappings) remoteEnumValue(field, local string, item AttributeAccessor) string {
+	for _, fm := range m {
+		if fm.Field != field || fm.Converter != MappingConverterEnum {
+			continue
+		}
+		if current := item.Get(AttributeExpression(fm.Expression)); current != nil {
+			if v, ok := fm.Values[fmt.Sprint(current)]; ok && v == local {
+				return fmt.Sprint(current)
+			}
+		}
+		remoteValues := make([]string, 0, len(fm.Values))
+		for remote, v := range fm.Values {
+			if v == local {
+				remoteValues = append(remoteValues, remote)
+			}
+		}
+		if len(remoteValues) > 0 {
+			sort.Strings(remoteValues)
+			return remoteValues[0]
+		}
+	}
+	return local
+}
+
+// listMapper returns the mapper of the list of values matching the given
+// expression
+func listMapper(expression string) AttributeMapper {
+	if strings.Contains(expression, "?") {
+		return AttributeMapper{AttributeExpression(strings.Replace(expression, "?", "0", 1)), PatternToListConverter{pattern: expression}}
+	}
+	return AttributeMapper{AttributeExpression(expression), ListConverter{}}
+}
+
+// keyMap returns the mapping of the remote attributes to the work item
+// fields. The users are mapped to their logins or emails which are resolved
+// by resolveUsers.
+func (m FieldMappings) keyMap() RemoteWorkItemMap {
+	keyMap := RemoteWorkItemMap{}
+	for _, fm := range m {
+		switch fm.Converter {
+		case MappingConverterString:
+			keyMap[AttributeMapper{AttributeExpression(fm.Expression), StringConverter{}}] = fm.Field
+		case MappingConverterList, MappingConverterUser:
+			keyMap[listMapper(fm.Expression)] = fm.Field
+		case MappingConverterEnum:
+			keyMap[AttributeMapper{AttributeExpression(fm.Expression), &EnumConverter{values: fm.Values}}] = fm.Field
+		case MappingConverterMarkup:
+			markup := fm.Markup
+			if markup == "" {
+				markup = rendering.SystemMarkupMarkdown
+			}
+			keyMap[AttributeMapper{AttributeExpression(fm.Expression), MarkupConverter{markup: markup}}] = fm.Field
+		}
+	}
+	return keyMap
+}
+
+// Validate checks that the mappings target existing fields of the given
+// work item type with a matching converter
+func (m FieldMappings) Validate(wit workitem.WorkItemType) error {
+	for i, fm := range m {
+		param := fmt.Sprintf("field_mappings[%d]", i)
+		if fm.Expression == "" {
+			return errors.NewBadParameterError(param+".expression", fm.Expression).Expected("not empty")
+		}
+		fd, ok := wit.Fields[fm.Field]
+		if !ok {
+			return errors.NewBadParameterError(param+".field", fm.Field).Expected(fmt.Sprintf("a field of the work item type %s", wit.Name))
+		}
+		kind := fd.Type.GetKind()
+		componentKind := kind
+		switch t := fd.Type.(type) {
+		case workitem.ListType:
+			componentKind = t.ComponentType.GetKind()
+		case workitem.EnumType:
+			componentKind = t.BaseType.GetKind()
+		}
+		switch fm.Converter {
+		case MappingConverterString:
+		case MappingConverterList:
+			if kind != workitem.KindList {
+				return errors.NewBadParameterError(param+".converter", fm.Converter).Expected(fmt.Sprintf("a converter of a field of kind %s", kind))
+			}
+		case MappingConverterEnum:
+			if len(fm.Values) == 0 {
+				return errors.NewBadParameterError(param+".values", fm.Values).Expected("not empty")
+			}
+		case MappingConverterUser:
+			if componentKind != workitem.KindUser {
+				return errors.NewBadParameterError(param+".converter", fm.Converter).Expected(fmt.Sprintf("a converter of a field of kind %s", kind))
+			}
+		case MappingConverterMarkup:
+			if kind != workitem.KindMarkup {
+				return errors.NewBadParameterError(param+".converter", fm.Converter).Expected(fmt.Sprintf("a converter of a field of kind %s", kind))
+			}
+		default:
+			return errors.NewBadParameterError(param+".converter", fm.Converter).Expected("string, list, enum, user or markup")
+		}
+	}
+	return nil
+}
+
+// applyFieldMappings maps the given remote item with the mappings of the
+// tracker and copies the results to the fields of the work item, resolving
+// the users
+func applyFieldMappings(ctx context.Context, db *gorm.DB, remoteItem AttributeAccessor, mappings FieldMappings, wit workitem.WorkItemType, providerType string, workItem *workitem.WorkItem) error {
+	if len(mappings) == 0 {
+		return nil
+	}
+	mapped, err := Map(remoteItem, mappings.keyMap())
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	for _, fm := range mappings {
+		value, ok := mapped.Fields[fm.Field]
+		if !ok {
+			continue
+		}
+		if fm.Converter == MappingConverterUser {
+			ids, err := resolveUsers(ctx, db, value.([]string), providerType)
+			if err != nil {
+				return err
+			}
+			if fd, ok := wit.Fields[fm.Field]; ok && fd.Type.GetKind() == workitem.KindUser {
+				// a single user field takes the first user
+				if len(ids) == 0 {
+					value = nil
+				} else {
+					value = ids[0]
+				}
+			} else {
+				value = ids
+			}
+		}
+		workItem.Fields[fm.Field] = value
+	}
+	return nil
+}
+
+// resolveUsers returns the IDs of the identities with the given logins on
+// the tracker or with the given emails. Identities of unknown logins are
+// created, unknown emails are skipped.
+func resolveUsers(ctx context.Context, db *gorm.DB, loginsOrEmails []string, providerType string) ([]string, error) {
+	identityRepository := account.NewIdentityRepository(db)
+	ids := make([]string, 0, len(loginsOrEmails))
+	for _, v := range loginsOrEmails {
+		if strings.Contains(v, "@") {
+			var identityID []uuid.UUID
+			err := db.Table("identities").Joins("join users on users.id = identities.user_id").
+				Where("lower(users.email) = lower(?) AND identities.deleted_at IS NULL AND users.deleted_at IS NULL", v).
+				Limit(1).Pluck("identities.id", &identityID).Error
+			if err != nil {
+				return nil, errs.Wrapf(err, "failed to look up the user with email %s", v)
+			}
+			if len(identityID) == 0 {
+				log.Info(ctx, map[string]interface{}{
+					"email": v,
+				}, "no user with the email of the remote item")
+				continue
+			}
+			ids = append(ids, identityID[0].String())
+			continue
+		}
+		identity, err := identityRepository.First(account.IdentityFilterByUsername(v), account.IdentityFilterByProviderType(providerType))
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to look up the identity of %s", v)
+		}
+		if identity == nil {
+			identity = &account.Identity{Username: v, ProviderType: providerType}
+			if err := identityRepository.Create(ctx, identity); err != nil {
+				return nil, errs.Wrapf(err, "failed to create the identity of %s", v)
+			}
+		}
+		ids = append(ids, identity.ID.String())
+	}
+	return ids, nil
+}
@@ -0,0 +1,123 @@
+package remoteworkitem
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mappingTestType is a work item type with a field of every kind the
+// converters support
+var mappingTestType = workitem.WorkItemType{
+	Name: "Defect",
+	Fields: workitem.FieldDefinitions{
+		"priority": {Type: workitem.EnumType{
+			SimpleType: workitem.SimpleType{Kind: workitem.KindEnum},
+			BaseType:   workitem.SimpleType{Kind: workitem.KindString},
+			Values:     []interface{}{"P1", "P2"},
+		}},
+		"components": {Type: workitem.ListType{
+			SimpleType:    workitem.SimpleType{Kind: workitem.KindList},
+			ComponentType: workitem.SimpleType{Kind: workitem.KindString},
+		}},
+		"reviewer":   {Type: workitem.SimpleType{Kind: workitem.KindUser}},
+		"notes":      {Type: workitem.SimpleType{Kind: workitem.KindMarkup}},
+		"fixversion": {Type: workitem.SimpleType{Kind: workitem.KindString}},
+	},
+}
+
+func TestFieldMappingsValidate(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Run("ok", func(t *testing.T) {
+		mappings := FieldMappings{
+			{Expression: "fields.priority.name", Field: "priority", Converter: MappingConverterEnum, Values: map[string]string{"Blocker": "P1"}},
+			{Expression: "fields.components.?.name", Field: "components", Converter: MappingConverterList},
+			{Expression: "fields.customfield_10000.emailAddress", Field: "reviewer", Converter: MappingConverterUser},
+			{Expression: "fields.customfield_10001", Field: "notes", Converter: MappingConverterMarkup, Markup: rendering.SystemMarkupJiraWiki},
+			{Expression: "fields.fixVersions.0.name", Field: "fixversion", Converter: MappingConverterString},
+		}
+		assert.NoError(t, mappings.Validate(mappingTestType))
+	})
+	for name, m := range map[string]FieldMapping{
+		"unknown field":     {Expression: "x", Field: "severity", Converter: MappingConverterString},
+		"unknown converter": {Expression: "x", Field: "priority", Converter: "magic"},
+		"missing values":    {Expression: "x", Field: "priority", Converter: MappingConverterEnum},
+		"list to string":    {Expression: "x", Field: "fixversion", Converter: MappingConverterList},
+		"user to string":    {Expression: "x", Field: "fixversion", Converter: MappingConverterUser},
+		"markup to list":    {Expression: "x", Field: "components", Converter: MappingConverterMarkup},
+		"empty expression":  {Field: "fixversion", Converter: MappingConverterString},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := FieldMappings{m}.Validate(mappingTestType)
+			require.Error(t, err)
+			assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		})
+	}
+}
+
+func TestApplyFieldMappings(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	// given
+	item, err := NewJiraRemoteWorkItem(TrackerItem{Item: `{
+		"key": "ARQ-1",
+		"fields": {
+			"priority": {"name": "Blocker"},
+			"components": [{"name": "core"}, {"name": "ui"}],
+			"fixVersions": [{"name": "1.0"}],
+			"customfield_10001": "h1. Notes"
+		}
+	}`})
+	require.NoError(t, err)
+	mappings := FieldMappings{
+		{Expression: "fields.priority.name", Field: "priority", Converter: MappingConverterEnum, Values: map[string]string{"Blocker": "P1"}},
+		{Expression: "fields.components.?.name", Field: "components", Converter: MappingConverterList},
+		{Expression: "fields.customfield_10001", Field: "notes", Converter: MappingConverterMarkup, Markup: rendering.SystemMarkupJiraWiki},
+		{Expression: "fields.fixVersions.0.name", Field: "fixversion", Converter: MappingConverterString},
+	}
+	wi := workitem.WorkItem{Fields: workitem.Fields{}}
+	// when
+	err = applyFieldMappings(context.Background(), nil, item, mappings, mappingTestType, ProviderJira, &wi)
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "P1", wi.Fields["priority"])
+	assert.Equal(t, []string{"core", "ui"}, wi.Fields["components"])
+	assert.Equal(t, rendering.NewMarkupContent("h1. Notes", rendering.SystemMarkupJiraWiki), wi.Fields["notes"])
+	assert.Equal(t, "1.0", wi.Fields["fixversion"])
+}
+
+func TestRemoteEnumValue(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	// given
+	item, err := NewJiraRemoteWorkItem(TrackerItem{Item: `{
+		"key": "ARQ-1",
+		"fields": {"status": {"name": "Reopened"}}
+	}`})
+	require.NoError(t, err)
+	mappings := FieldMappings{
+		{Expression: "fields.status.name", Field: workitem.SystemState, Converter: MappingConverterEnum, Values: map[string]string{
+			"To Do":       workitem.SystemStateOpen,
+			"Reopened":    workitem.SystemStateOpen,
+			"In Progress": workitem.SystemStateInProgress,
+			"Done":        workitem.SystemStateClosed,
+			"Rejected":    workitem.SystemStateClosed,
+		}},
+	}
+	t.Run("current remote value", func(t *testing.T) {
+		assert.Equal(t, "Reopened", mappings.remoteEnumValue(workitem.SystemState, workitem.SystemStateOpen, item))
+	})
+	t.Run("first remote value", func(t *testing.T) {
+		assert.Equal(t, "Done", mappings.remoteEnumValue(workitem.SystemState, workitem.SystemStateClosed, item))
+		assert.Equal(t, "In Progress", mappings.remoteEnumValue(workitem.SystemState, workitem.SystemStateInProgress, item))
+	})
+	t.Run("unmapped value", func(t *testing.T) {
+		assert.Equal(t, workitem.SystemStateResolved, mappings.remoteEnumValue(workitem.SystemState, workitem.SystemStateResolved, item))
+		assert.Equal(t, "P1", mappings.remoteEnumValue("priority", "P1", item))
+	})
+}
@@ -2,6 +2,7 @@ package remoteworkitem
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/fabric8-services/fabric8-wit/log"
 
@@ -18,6 +19,9 @@ type githubFetcher interface {
 type GithubTracker struct {
 	URL   string
 	Query string
+	// Since restricts the fetch to the issues updated after the given time
+	Since *time.Time
+	err   error
 }
 
 // GithubIssueFetcher fetch issues from github
@@ -41,6 +45,21 @@ func (g *GithubTracker) Fetch(githubAuthToken string) chan TrackerItemContent {
 	return g.fetch(&f)
 }
 
+// Err returns the error that stopped the last fetch, if any. It must only be
+// called once the channel returned by Fetch was closed.
+func (g *GithubTracker) Err() error {
+	return g.err
+}
+
+// query returns the search query restricted to the issues updated since the
+// last sync
+func (g *GithubTracker) query() string {
+	if g.Since == nil {
+		return g.Query
+	}
+	return g.Query + " updated:>" + g.Since.UTC().Format("2006-01-02T15:04:05-07:00")
+}
+
 func (g *GithubTracker) fetch(f githubFetcher) chan TrackerItemContent {
 	item := make(chan TrackerItemContent)
 	go func() {
@@ -50,12 +69,21 @@ func (g *GithubTracker) fetch(f githubFetcher) chan TrackerItemContent {
 			},
 		}
 		for {
-			result, response, err := f.listIssues(g.Query, opts)
+			result, response, err := f.listIssues(g.query(), opts)
 			if _, ok := err.(*github.RateLimitError); ok {
 				log.Warn(nil, map[string]interface{}{
 					"query": g.Query,
 					"opts":  opts,
 				}, "reached rate limit when listing Github issues")
+				g.err = err
+				break
+			}
+			if err != nil {
+				log.Error(nil, map[string]interface{}{
+					"query": g.Query,
+					"err":   err,
+				}, "unable to list Github issues")
+				g.err = err
 				break
 			}
 			issues := result.Issues
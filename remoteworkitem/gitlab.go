@@ -0,0 +1,182 @@
+package remoteworkitem
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/pkg/errors"
+)
+
+// gitlabFetcher provides issue listing
+type gitlabFetcher interface {
+	// listIssues returns the issues on the given page and the number of the
+	// next page or 0 if it was the last one
+	listIssues(query string, page int) ([]json.RawMessage, int, error)
+}
+
+// GitLabTracker represents the GitLab tracker provider. The URL is the base
+// URL of the GitLab instance, e.g. https://gitlab.com, and the query is the
+// path of an issues endpoint of the GitLab API v4 together with its
+// parameters, e.g. "projects/42/issues?state=opened" or
+// "issues?scope=all&labels=bug".
+type GitLabTracker struct {
+	URL   string
+	Query string
+	// Since restricts the fetch to the issues updated after the given time
+	Since *time.Time
+	err   error
+}
+
+// gitlabIssueFetcher fetch issues from GitLab
+type gitlabIssueFetcher struct {
+	client    *http.Client
+	url       string
+	authToken string
+}
+
+// listIssues list the issues on the given page
+func (f *gitlabIssueFetcher) listIssues(query string, page int) ([]json.RawMessage, int, error) {
+	u, err := url.Parse(strings.TrimSuffix(f.url, "/") + "/api/v4/" + strings.TrimPrefix(query, "/"))
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "invalid GitLab query %q", query)
+	}
+	params := u.Query()
+	params.Set("per_page", "20")
+	params.Set("page", strconv.Itoa(page))
+	u.RawQuery = params.Encode()
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, 0, errors.WithStack(err)
+	}
+	if f.authToken != "" {
+		req.Header.Set("PRIVATE-TOKEN", f.authToken)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to list GitLab issues")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, errors.Errorf("failed to list GitLab issues: unexpected response status %s", resp.Status)
+	}
+	var issues []json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&issues); err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to decode GitLab issues")
+	}
+	nextPage := 0
+	if next := resp.Header.Get("X-Next-Page"); next != "" {
+		if nextPage, err = strconv.Atoi(next); err != nil {
+			return nil, 0, errors.Wrapf(err, "invalid next page %q", next)
+		}
+	}
+	return issues, nextPage, nil
+}
+
+// Fetch tracker items from GitLab
+func (g *GitLabTracker) Fetch(gitlabAuthToken string) chan TrackerItemContent {
+	f := gitlabIssueFetcher{
+		client:    &http.Client{Timeout: 30 * time.Second},
+		url:       g.URL,
+		authToken: gitlabAuthToken,
+	}
+	return g.fetch(&f)
+}
+
+// Err returns the error that stopped the last fetch, if any. It must only be
+// called once the channel returned by Fetch was closed.
+func (g *GitLabTracker) Err() error {
+	return g.err
+}
+
+// query returns the query restricted to the issues updated since the last
+// sync
+func (g *GitLabTracker) query() string {
+	if g.Since == nil {
+		return g.Query
+	}
+	sep := "?"
+	if strings.Contains(g.Query, "?") {
+		sep = "&"
+	}
+	return g.Query + sep + "updated_after=" + url.QueryEscape(g.Since.UTC().Format(time.RFC3339))
+}
+
+func (g *GitLabTracker) fetch(f gitlabFetcher) chan TrackerItemContent {
+	item := make(chan TrackerItemContent)
+	go func() {
+		page := 1
+		for page != 0 {
+			issues, nextPage, err := f.listIssues(g.query(), page)
+			if err != nil {
+				log.Error(nil, map[string]interface{}{
+					"url":   g.URL,
+					"query": g.Query,
+					"page":  page,
+					"err":   err,
+				}, "unable to list GitLab issues")
+				g.err = err
+				break
+			}
+			for _, issue := range issues {
+				var i struct {
+					WebURL string `json:"web_url"`
+				}
+				if err := json.Unmarshal(issue, &i); err != nil {
+					log.Error(nil, map[string]interface{}{
+						"err": err,
+					}, "unable to decode GitLab issue")
+					continue
+				}
+				id, _ := json.Marshal(i.WebURL)
+				item <- TrackerItemContent{ID: string(id), Content: issue}
+			}
+			page = nextPage
+		}
+		close(item)
+	}()
+	return item
+}
+
+// GitLabStateConverter converts the state of a GitLab issue
+type GitLabStateConverter struct{}
+
+// Convert maps the "opened" and "closed" states of GitLab issues to the
+// "open" and "closed" states of work items
+func (glc GitLabStateConverter) Convert(value interface{}, item AttributeAccessor) (interface{}, error) {
+	switch value {
+	case nil:
+		return nil, nil
+	case "opened", "reopened":
+		return "open", nil
+	case "closed":
+		return "closed", nil
+	}
+	return value, nil
+}
+
+// GitLabRemoteWorkItem knows how to implement a FieldAccessor on a GitLab
+// issue JSON struct
+type GitLabRemoteWorkItem struct {
+	issue map[string]interface{}
+}
+
+// NewGitLabRemoteWorkItem creates a new Decoded AttributeAccessor for a GitLab Issue
+func NewGitLabRemoteWorkItem(item TrackerItem) (AttributeAccessor, error) {
+	var j map[string]interface{}
+	err := json.Unmarshal([]byte(item.Item), &j)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	j = Flatten(j)
+	return GitLabRemoteWorkItem{issue: j}, nil
+}
+
+// Get attribute from issue map
+func (gl GitLabRemoteWorkItem) Get(field AttributeExpression) interface{} {
+	return gl.issue[string(field)]
+}
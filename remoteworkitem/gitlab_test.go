@@ -0,0 +1,131 @@
+package remoteworkitem
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeGitLabIssueFetcher struct{}
+
+// listIssues returns one issue on each of two pages
+func (f *fakeGitLabIssueFetcher) listIssues(query string, page int) ([]json.RawMessage, int, error) {
+	issue := json.RawMessage(fmt.Sprintf(`{"iid":%d,"web_url":"https://gitlab.example.com/g/p/issues/%d"}`, page, page))
+	if page == 1 {
+		return []json.RawMessage{issue}, 2, nil
+	}
+	return []json.RawMessage{issue}, 0, nil
+}
+
+func TestGitLabFetch(t *testing.T) {
+	// given
+	resource.Require(t, resource.UnitTest)
+	f := fakeGitLabIssueFetcher{}
+	g := GitLabTracker{URL: "", Query: ""}
+	// when
+	var items []TrackerItemContent
+	for i := range g.fetch(&f) {
+		items = append(items, i)
+	}
+	// then
+	require.Len(t, items, 2)
+	assert.Equal(t, `"https://gitlab.example.com/g/p/issues/1"`, items[0].ID)
+	assert.Equal(t, `{"iid":1,"web_url":"https://gitlab.example.com/g/p/issues/1"}`, string(items[0].Content))
+	assert.Equal(t, `"https://gitlab.example.com/g/p/issues/2"`, items[1].ID)
+}
+
+func TestGitLabFetchWithServer(t *testing.T) {
+	// given a GitLab stand-in that returns two pages of issues
+	resource.Require(t, resource.UnitTest)
+	var tokens []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("PRIVATE-TOKEN"))
+		if r.URL.Path != "/api/v4/projects/42/issues" || r.URL.Query().Get("state") != "opened" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		page := r.URL.Query().Get("page")
+		if page == "1" {
+			w.Header().Set("X-Next-Page", "2")
+		}
+		fmt.Fprintf(w, `[{"iid":%[1]s,"web_url":"%[2]s/g/p/issues/%[1]s"}]`, page, "https://gitlab.example.com")
+	}))
+	defer ts.Close()
+	g := GitLabTracker{URL: ts.URL, Query: "projects/42/issues?state=opened"}
+	// when
+	var items []TrackerItemContent
+	for i := range g.Fetch("secret") {
+		items = append(items, i)
+	}
+	// then
+	require.Len(t, items, 2)
+	assert.Equal(t, `"https://gitlab.example.com/g/p/issues/1"`, items[0].ID)
+	assert.Equal(t, `"https://gitlab.example.com/g/p/issues/2"`, items[1].ID)
+	assert.Equal(t, []string{"secret", "secret"}, tokens)
+}
+
+func TestGitLabFetchWithError(t *testing.T) {
+	// given
+	resource.Require(t, resource.UnitTest)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+	g := GitLabTracker{URL: ts.URL, Query: "issues"}
+	// when
+	var items []TrackerItemContent
+	for i := range g.Fetch("") {
+		items = append(items, i)
+	}
+	// then
+	assert.Empty(t, items)
+	assert.Error(t, g.Err())
+}
+
+func TestGitLabIssueMapping(t *testing.T) {
+	// given
+	resource.Require(t, resource.UnitTest)
+	content := `{
+		"iid": 42,
+		"title": "Fix the login",
+		"description": "It *fails*",
+		"state": "opened",
+		"web_url": "https://gitlab.example.com/g/p/issues/42",
+		"labels": ["bug", "ui"],
+		"author": {"username": "alice", "web_url": "https://gitlab.example.com/alice"},
+		"assignees": [{"username": "bob", "web_url": "https://gitlab.example.com/bob"}]
+	}`
+	item, err := NewGitLabRemoteWorkItem(TrackerItem{Item: content})
+	require.NoError(t, err)
+	// when
+	remoteWorkItem, err := Map(item, RemoteWorkItemKeyMaps[ProviderGitLab])
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "Fix the login", remoteWorkItem.Fields[workitem.SystemTitle])
+	assert.Equal(t, rendering.NewMarkupContent("It *fails*", rendering.SystemMarkupMarkdown), remoteWorkItem.Fields[workitem.SystemDescription])
+	assert.Equal(t, "open", remoteWorkItem.Fields[workitem.SystemState])
+	assert.Equal(t, "https://gitlab.example.com/g/p/issues/42", remoteWorkItem.Fields[workitem.SystemRemoteItemID])
+	assert.Equal(t, "alice", remoteWorkItem.Fields[remoteCreatorLogin])
+	assert.Equal(t, []string{"bob"}, remoteWorkItem.Fields[RemoteAssigneeLogins])
+	assert.Equal(t, []string{"https://gitlab.example.com/bob"}, remoteWorkItem.Fields[RemoteAssigneeProfileURLs])
+	assert.Equal(t, []string{"bug", "ui"}, remoteWorkItem.Fields[RemoteLabelNames])
+}
+
+func TestGitLabStateConverter(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	for remote, local := range map[string]string{"opened": "open", "reopened": "open", "closed": "closed", "locked": "locked"} {
+		t.Run(remote, func(t *testing.T) {
+			value, err := GitLabStateConverter{}.Convert(remote, nil)
+			require.NoError(t, err)
+			assert.Equal(t, local, value)
+		})
+	}
+}
@@ -2,6 +2,11 @@ package remoteworkitem
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/log"
 
 	jira "github.com/andygrunwald/go-jira"
 )
@@ -10,6 +15,9 @@ import (
 type JiraTracker struct {
 	URL   string
 	Query string
+	// Since restricts the fetch to the issues updated after the given time
+	Since *time.Time
+	err   error
 }
 
 type jiraFetcher interface {
@@ -37,13 +45,52 @@ func (j *JiraTracker) Fetch(authToken string) chan TrackerItemContent {
 	return j.fetch(&f)
 }
 
+// Err returns the error that stopped the last fetch, if any. It must only be
+// called once the channel returned by Fetch was closed.
+func (j *JiraTracker) Err() error {
+	return j.err
+}
+
+// query returns the JQL query restricted to the issues updated since the
+// last sync. Jira compares at minute precision, hence the ">=". The time is
+// given in UTC so that it does not depend on the time zone of the server.
+func (j *JiraTracker) query() string {
+	if j.Since == nil {
+		return j.Query
+	}
+	query, orderBy := j.Query, ""
+	if i := strings.Index(strings.ToUpper(query), "ORDER BY"); i >= 0 {
+		query, orderBy = strings.TrimSpace(query[:i]), " "+query[i:]
+	}
+	since := fmt.Sprintf(`updated >= "%s"`, j.Since.UTC().Format("2006/01/02 15:04"))
+	if query == "" {
+		return since + orderBy
+	}
+	return fmt.Sprintf("(%s) AND %s%s", query, since, orderBy)
+}
+
 func (j *JiraTracker) fetch(f jiraFetcher) chan TrackerItemContent {
 	item := make(chan TrackerItemContent)
 	go func() {
-		issues, _, _ := f.listIssues(j.Query, nil)
+		issues, _, err := f.listIssues(j.query(), nil)
+		if err != nil {
+			log.Error(nil, map[string]interface{}{
+				"query": j.Query,
+				"err":   err,
+			}, "unable to list Jira issues")
+			j.err = err
+		}
 		for _, l := range issues {
 			id, _ := json.Marshal(l.Key)
-			issue, _, _ := f.getIssue(l.Key)
+			issue, _, err := f.getIssue(l.Key)
+			if err != nil {
+				log.Error(nil, map[string]interface{}{
+					"issue": l.Key,
+					"err":   err,
+				}, "unable to get Jira issue")
+				j.err = err
+				continue
+			}
 			content, _ := json.Marshal(issue)
 			item <- TrackerItemContent{ID: string(id), Content: content}
 		}
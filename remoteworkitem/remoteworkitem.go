@@ -15,6 +15,7 @@ import (
 const (
 	ProviderGithub = "github"
 	ProviderJira   = "jira"
+	ProviderGitLab = "gitlab"
 
 	// The keys in the flattened response JSON of a typical Github issue.
 	GithubTitle                      = "title"
@@ -37,6 +38,21 @@ const (
 	JiraCreatorProfileURL  = "fields.creator.self"
 	JiraAssigneeLogin      = "fields.assignee.key"
 	JiraAssigneeProfileURL = "fields.assignee.self"
+
+	// The keys in the flattened response JSON of a typical GitLab issue.
+	GitLabTitle                      = "title"
+	GitLabDescription                = "description"
+	GitLabState                      = "state"
+	GitLabID                         = "web_url"
+	GitLabIID                        = "iid"
+	GitLabCreatorLogin               = "author.username"
+	GitLabCreatorProfileURL          = "author.web_url"
+	GitLabAssigneesLogin             = "assignees.0.username"
+	GitLabAssigneesLoginPattern      = "assignees.?.username"
+	GitLabAssigneesProfileURL        = "assignees.0.web_url"
+	GitLabAssigneesProfileURLPattern = "assignees.?.web_url"
+	GitLabLabels                     = "labels.0"
+	GitLabLabelsPattern              = "labels.?"
 )
 
 // RemoteWorkItem a temporary structure that holds the relevant field values retrieved from a remote work item
@@ -58,6 +74,9 @@ const (
 	remoteCreatorProfileURL   = "system.creator.profile_url"
 	RemoteAssigneeLogins      = "system.assignees.login"
 	RemoteAssigneeProfileURLs = "system.assignees.profile_url"
+	// RemoteLabelNames are resolved to the labels of the space with these
+	// names
+	RemoteLabelNames = "system.labels.name"
 )
 
 // RemoteWorkItemKeyMaps relate remote attribute keys to internal representation
@@ -82,6 +101,17 @@ var RemoteWorkItemKeyMaps = map[string]RemoteWorkItemMap{
 		AttributeMapper{AttributeExpression(JiraAssigneeLogin), ListConverter{}}:                                RemoteAssigneeLogins,
 		AttributeMapper{AttributeExpression(JiraAssigneeProfileURL), ListConverter{}}:                           RemoteAssigneeProfileURLs,
 	},
+	ProviderGitLab: {
+		AttributeMapper{AttributeExpression(GitLabTitle), StringConverter{}}:                                                               remoteTitle,
+		AttributeMapper{AttributeExpression(GitLabDescription), MarkupConverter{markup: rendering.SystemMarkupMarkdown}}:                   remoteDescription,
+		AttributeMapper{AttributeExpression(GitLabState), GitLabStateConverter{}}:                                                          remoteState,
+		AttributeMapper{AttributeExpression(GitLabID), StringConverter{}}:                                                                  remoteItemID,
+		AttributeMapper{AttributeExpression(GitLabCreatorLogin), StringConverter{}}:                                                        remoteCreatorLogin,
+		AttributeMapper{AttributeExpression(GitLabCreatorProfileURL), StringConverter{}}:                                                   remoteCreatorProfileURL,
+		AttributeMapper{AttributeExpression(GitLabAssigneesLogin), PatternToListConverter{pattern: GitLabAssigneesLoginPattern}}:           RemoteAssigneeLogins,
+		AttributeMapper{AttributeExpression(GitLabAssigneesProfileURL), PatternToListConverter{pattern: GitLabAssigneesProfileURLPattern}}: RemoteAssigneeProfileURLs,
+		AttributeMapper{AttributeExpression(GitLabLabels), PatternToListConverter{pattern: GitLabLabelsPattern}}:                           RemoteLabelNames,
+	},
 }
 
 type AttributeConverter interface {
@@ -206,6 +236,7 @@ type AttributeAccessor interface {
 var RemoteWorkItemImplRegistry = map[string]func(TrackerItem) (AttributeAccessor, error){
 	ProviderGithub: NewGitHubRemoteWorkItem,
 	ProviderJira:   NewJiraRemoteWorkItem,
+	ProviderGitLab: NewGitLabRemoteWorkItem,
 }
 
 // GitHubRemoteWorkItem knows how to implement a FieldAccessor on a GitHub Issue JSON struct
@@ -1,8 +1,14 @@
 package remoteworkitem
 
 import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
 	"github.com/fabric8-services/fabric8-wit/log"
 	"github.com/fabric8-services/fabric8-wit/models"
+	"github.com/fabric8-services/fabric8-wit/ptr"
 
 	"context"
 
@@ -14,24 +20,33 @@ import (
 
 // TrackerSchedule capture all configuration
 type trackerSchedule struct {
-	TrackerID   uuid.UUID
-	URL         string
-	TrackerType string
-	Query       string
-	Schedule    string
-	SpaceID     uuid.UUID
+	TrackerQueryID uint64
+	TrackerID      uuid.UUID
+	URL            string
+	TrackerType    string
+	Query          string
+	Schedule       string
+	SpaceID        uuid.UUID
+	// WriteBack and ConflictPolicy control the two-way sync of the tracker
+	WriteBack      bool
+	ConflictPolicy string
 }
 
 // Scheduler represents scheduler
 type Scheduler struct {
 	db *gorm.DB
+	// lock guards running
+	lock sync.Mutex
+	// running holds the tracker queries that are currently run so that their
+	// sync states are not overwritten by concurrent runs
+	running map[uint64]bool
 }
 
 var cr *cron.Cron
 
 // NewScheduler creates a new Scheduler
 func NewScheduler(db *gorm.DB) *Scheduler {
-	s := Scheduler{db: db}
+	s := Scheduler{db: db, running: map[uint64]bool{}}
 	return &s
 }
 
@@ -52,33 +67,214 @@ func (s *Scheduler) ScheduleAllQueries(ctx context.Context, accessTokens map[str
 
 	trackerQueries := fetchTrackerQueries(s.db)
 	for _, tq := range trackerQueries {
+		tq := tq
 		cr.AddFunc(tq.Schedule, func() {
-			tr := lookupProvider(tq)
-			authToken := accessTokens[tq.TrackerType]
-
-			// In case of Jira, no auth token is needed hence the map wouldnt
-			// return anything. So effectively the authToken is optional.
-
-			for i := range tr.Fetch(authToken) {
-				models.Transactional(s.db, func(tx *gorm.DB) error {
-					// Save the remote items in a 'temporary' table.
-					err := Upload(tx, tq.TrackerID, i)
-					if err != nil {
-						return errors.WithStack(err)
-					}
-					// Convert the remote item into a local work item and persist in the DB.
-					_, err = ConvertToWorkItemModel(ctx, tx, tq.TrackerID, i, tq.TrackerType, tq.SpaceID)
-					return errors.WithStack(err)
-				})
-			}
+			s.run(ctx, tq, accessTokens)
 		})
 	}
 	cr.Start()
 }
 
+// RunQuery fetches and imports the remote tracker items of the given tracker
+// query immediately and returns the resulting sync state
+func (s *Scheduler) RunQuery(ctx context.Context, trackerQueryID string, accessTokens map[string]string) (*SyncState, error) {
+	id, err := strconv.ParseUint(trackerQueryID, 10, 64)
+	if err != nil || id == 0 {
+		return nil, NotFoundError{"tracker query", trackerQueryID}
+	}
+	var tsList []trackerSchedule
+	err = trackerQueriesQuery(s.db).Where("tracker_queries.id = ?", id).Scan(&tsList).Error
+	if err != nil {
+		return nil, InternalError{simpleError{err.Error()}}
+	}
+	if len(tsList) == 0 {
+		return nil, NotFoundError{"tracker query", trackerQueryID}
+	}
+	return s.run(ctx, tsList[0], accessTokens)
+}
+
+// run fetches the items of the given tracker query that were updated since
+// the last successful run, syncs them and records the outcome in the sync
+// state of the query. The high-water mark only advances when all the items
+// were synced so that failed items are fetched again by the next run. Only
+// one run of a query is allowed at a time, the local changes are pushed to the
+// tracker after each item was synced.
+func (s *Scheduler) run(ctx context.Context, tq trackerSchedule, accessTokens map[string]string) (*SyncState, error) {
+	if !s.begin(tq.TrackerQueryID) {
+		log.Info(ctx, map[string]interface{}{
+			"tracker_query_id": tq.TrackerQueryID,
+		}, "the tracker query is already being run")
+		return nil, VersionConflictError{simpleError{fmt.Sprintf("tracker query %d is already being run", tq.TrackerQueryID)}}
+	}
+	defer s.end(tq.TrackerQueryID)
+	syncStates := NewSyncStateRepository(s.db)
+	state, err := syncStates.Load(ctx, strconv.FormatUint(tq.TrackerQueryID, 10))
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":              err,
+			"tracker_query_id": tq.TrackerQueryID,
+		}, "unable to load the sync state of the tracker query")
+		return nil, errors.WithStack(err)
+	}
+	tr := lookupProvider(tq, state.HighWaterMark)
+	if tr == nil {
+		return nil, BadParameterError{parameter: "type", value: tq.TrackerType}
+	}
+	// In case of Jira, no auth token is needed hence the map wouldnt
+	// return anything. So effectively the authToken is optional.
+	authToken := accessTokens[tq.TrackerType]
+
+	now := time.Now()
+	state.LastRunAt = &now
+	state.FetchedCount, state.SyncedCount, state.FailedCount = 0, 0, 0
+	highWaterMark := state.HighWaterMark
+	var lastErr error
+	fetched := map[string]bool{}
+	for i := range tr.Fetch(authToken) {
+		state.FetchedCount++
+		fetched[i.ID] = true
+		var push *RemotePush
+		err := models.Transactional(s.db, func(tx *gorm.DB) error {
+			// Save the remote item in a 'temporary' table and convert it
+			// into a local work item.
+			var err error
+			push, err = Sync(ctx, tx, tq, i)
+			return errors.WithStack(err)
+		})
+		if err == nil && push != nil {
+			// write the local changes back once they are committed
+			err = Push(ctx, s.db, tq, *push, authToken)
+		}
+		if err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"err":              err,
+				"tracker_query_id": tq.TrackerQueryID,
+				"remote_id":        i.ID,
+			}, "failed to sync remote item")
+			state.FailedCount++
+			lastErr = err
+			continue
+		}
+		state.SyncedCount++
+		if updatedAt, ok := remoteUpdatedAt(tq.TrackerType, i); ok && (highWaterMark == nil || updatedAt.After(*highWaterMark)) {
+			highWaterMark = &updatedAt
+		}
+	}
+	if err := tr.Err(); err != nil {
+		lastErr = err
+	}
+	// items that were only changed locally are not fetched
+	pushErr := s.pushLocalChanges(ctx, tq, authToken, fetched, state)
+	switch {
+	case lastErr != nil:
+		state.LastError = ptr.String(lastErr.Error())
+	case pushErr != nil:
+		// the fetched items were synced, the local changes are selected
+		// again by the next run
+		state.LastError = ptr.String(pushErr.Error())
+		state.HighWaterMark = highWaterMark
+	default:
+		state.LastError = nil
+		state.LastSuccessAt = &now
+		state.HighWaterMark = highWaterMark
+	}
+	if err := syncStates.Save(ctx, state); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return state, nil
+}
+
+// pushLocalChanges writes the work items of the given tracker query that
+// were changed since their last sync back to the tracker, if write-back is
+// enabled. The given fetched items were already synced by the run and items
+// flagged as conflicting are left to the next fetch. The
+// outcome is added to the counts of the given state and the last error is
+// returned.
+func (s *Scheduler) pushLocalChanges(ctx context.Context, tq trackerSchedule, authToken string, fetched map[string]bool, state *SyncState) error {
+	if !tq.WriteBack {
+		return nil
+	}
+	var items []TrackerItem
+	err := s.db.Joins("JOIN work_items ON work_items.id = tracker_items.work_item_id").
+		Where("tracker_items.tracker_id = ? AND work_items.space_id = ? AND work_items.version <> tracker_items.work_item_version AND NOT tracker_items.conflict AND work_items.deleted_at IS NULL", tq.TrackerID, tq.SpaceID).
+		Find(&items).Error
+	if err != nil {
+		return errors.Wrapf(err, "failed to look up the local changes of tracker query %d", tq.TrackerQueryID)
+	}
+	var lastErr error
+	for _, ti := range items {
+		if fetched[ti.RemoteItemID] {
+			continue
+		}
+		var push *RemotePush
+		err := models.Transactional(s.db, func(tx *gorm.DB) error {
+			// sync the last fetched content, which only finds the local
+			// changes
+			var err error
+			push, err = Sync(ctx, tx, tq, TrackerItemContent{ID: ti.RemoteItemID, Content: []byte(ti.Item)})
+			return errors.WithStack(err)
+		})
+		if err == nil && push != nil {
+			err = Push(ctx, s.db, tq, *push, authToken)
+		}
+		if err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"err":              err,
+				"tracker_query_id": tq.TrackerQueryID,
+				"remote_id":        ti.RemoteItemID,
+			}, "failed to push the local changes of the remote item")
+			state.FailedCount++
+			lastErr = err
+			continue
+		}
+		state.SyncedCount++
+	}
+	return lastErr
+}
+
+// begin marks the given tracker query as running and returns false if it
+// already is
+func (s *Scheduler) begin(trackerQueryID uint64) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.running[trackerQueryID] {
+		return false
+	}
+	s.running[trackerQueryID] = true
+	return true
+}
+
+// end marks the given tracker query as no longer running
+func (s *Scheduler) end(trackerQueryID uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.running, trackerQueryID)
+}
+
+// remoteUpdatedAt returns the time the given remote item was last updated
+func remoteUpdatedAt(trackerType string, item TrackerItemContent) (time.Time, bool) {
+	remoteItem, err := RemoteWorkItemImplRegistry[trackerType](TrackerItem{Item: string(item.Content)})
+	if err != nil {
+		return time.Time{}, false
+	}
+	value, _ := remoteItem.Get(RemoteUpdatedAtKeys[trackerType]).(string)
+	// Github and GitLab use RFC 3339, Jira omits the colon of the zone offset
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.000-0700"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// trackerQueriesQuery selects the tracker queries along with their tracker
+func trackerQueriesQuery(db *gorm.DB) *gorm.DB {
+	return db.Table("tracker_queries").Select("tracker_queries.id as tracker_query_id, trackers.id as tracker_id, trackers.url, trackers.type as tracker_type, tracker_queries.query, tracker_queries.schedule, tracker_queries.space_id, trackers.write_back, trackers.conflict_policy").Joins("left join trackers on tracker_queries.tracker_id = trackers.id").Where("trackers.deleted_at is NULL AND tracker_queries.deleted_at is NULL")
+}
+
 func fetchTrackerQueries(db *gorm.DB) []trackerSchedule {
 	tsList := []trackerSchedule{}
-	err := db.Table("tracker_queries").Select("trackers.id as tracker_id, trackers.url, trackers.type as tracker_type, tracker_queries.query, tracker_queries.schedule, tracker_queries.space_id").Joins("left join trackers on tracker_queries.tracker_id = trackers.id").Where("trackers.deleted_at is NULL AND tracker_queries.deleted_at is NULL").Scan(&tsList).Error
+	err := trackerQueriesQuery(db).Scan(&tsList).Error
 	if err != nil {
 		log.Error(nil, map[string]interface{}{
 			"err": err,
@@ -88,12 +284,15 @@ func fetchTrackerQueries(db *gorm.DB) []trackerSchedule {
 }
 
 // lookupProvider provides the respective tracker based on the type
-func lookupProvider(ts trackerSchedule) TrackerProvider {
+// and only fetches the items updated since the given time, if any
+func lookupProvider(ts trackerSchedule, since *time.Time) TrackerProvider {
 	switch ts.TrackerType {
 	case ProviderGithub:
-		return &GithubTracker{URL: ts.URL, Query: ts.Query}
+		return &GithubTracker{URL: ts.URL, Query: ts.Query, Since: since}
 	case ProviderJira:
-		return &JiraTracker{URL: ts.URL, Query: ts.Query}
+		return &JiraTracker{URL: ts.URL, Query: ts.Query, Since: since}
+	case ProviderGitLab:
+		return &GitLabTracker{URL: ts.URL, Query: ts.Query, Since: since}
 	}
 	return nil
 }
@@ -107,6 +306,8 @@ type TrackerItemContent struct {
 // TrackerProvider represents a remote tracker
 type TrackerProvider interface {
 	Fetch(authToken string) chan TrackerItemContent // TODO: Change to an interface to enforce the contract
+	// Err returns the error that stopped the last fetch, if any
+	Err() error
 }
 
 func init() {
@@ -0,0 +1,131 @@
+package remoteworkitem_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type schedulerBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunSchedulerBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &schedulerBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+// gitlabServer is a GitLab instance that has no updated issues and records
+// the issue updates it receives
+type gitlabServer struct {
+	*httptest.Server
+	lock    sync.Mutex
+	updates []map[string]interface{}
+}
+
+func newGitLabServer() *gitlabServer {
+	s := &gitlabServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			var update map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			s.lock.Lock()
+			s.updates = append(s.updates, update)
+			s.lock.Unlock()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, "[]")
+	}))
+	return s
+}
+
+// received returns the issue updates received so far
+func (s *gitlabServer) received() []map[string]interface{} {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.updates
+}
+
+func (s *schedulerBlackBoxTest) TestRunQueryPushesLocalChanges() {
+	// given a work item that was imported from a GitLab issue
+	server := newGitLabServer()
+	defer server.Close()
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.CreateWorkItemEnvironment(),
+		tf.WorkItems(1),
+		tf.Trackers(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.Trackers[idx].URL = server.URL
+			fxt.Trackers[idx].Type = remoteworkitem.ProviderGitLab
+			fxt.Trackers[idx].WriteBack = true
+			fxt.Trackers[idx].WorkItemTypeID = &fxt.WorkItemTypes[0].ID
+			return nil
+		}),
+	)
+	tq, err := remoteworkitem.NewTrackerQueryRepository(s.DB).Create(s.Ctx, "projects/42/issues", "0 0 0 * * *", fxt.Trackers[0].ID, fxt.Spaces[0].ID)
+	require.NoError(s.T(), err)
+	remoteID := `"` + server.URL + `/group/project/issues/7"`
+	err = remoteworkitem.Upload(s.DB, fxt.Trackers[0].ID, remoteworkitem.TrackerItemContent{
+		ID:      remoteID,
+		Content: []byte(`{"web_url": "` + server.URL + `/group/project/issues/7", "project_id": 42, "iid": 7, "title": "imported", "state": "opened", "updated_at": "2018-03-04T05:06:07.000Z"}`),
+	})
+	require.NoError(s.T(), err)
+	err = s.DB.Model(&remoteworkitem.TrackerItem{}).Where("remote_item_id = ?", remoteID).UpdateColumns(map[string]interface{}{
+		"work_item_id":      fxt.WorkItems[0].ID,
+		"work_item_version": fxt.WorkItems[0].Version,
+		"remote_updated_at": "2018-03-04T05:06:07.000Z",
+	}).Error
+	require.NoError(s.T(), err)
+	scheduler := remoteworkitem.NewScheduler(s.DB)
+
+	s.T().Run("nothing to push", func(t *testing.T) {
+		// when
+		state, err := scheduler.RunQuery(s.Ctx, tq.ID, map[string]string{})
+		// then
+		require.NoError(t, err)
+		assert.Nil(t, state.LastError)
+		assert.Equal(t, 0, state.SyncedCount)
+		assert.Empty(t, server.received())
+	})
+
+	s.T().Run("local-only edit", func(t *testing.T) {
+		// given the work item was edited locally only
+		wi := *fxt.WorkItems[0]
+		wi.Fields[workitem.SystemTitle] = "edited locally"
+		updated, _, err := workitem.NewWorkItemRepository(s.DB).Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		// when
+		state, err := scheduler.RunQuery(s.Ctx, tq.ID, map[string]string{})
+		// then the edit is pushed although the issue was not fetched
+		require.NoError(t, err)
+		assert.Nil(t, state.LastError)
+		assert.Equal(t, 0, state.FetchedCount)
+		assert.Equal(t, 1, state.SyncedCount)
+		updates := server.received()
+		require.Len(t, updates, 1)
+		assert.Equal(t, "edited locally", updates[0]["title"])
+		var ti remoteworkitem.TrackerItem
+		require.NoError(t, s.DB.Where("remote_item_id = ?", remoteID).Find(&ti).Error)
+		assert.Equal(t, updated.Version, ti.WorkItemVersion)
+		// and it is pushed only once
+		state, err = scheduler.RunQuery(s.Ctx, tq.ID, map[string]string{})
+		require.NoError(t, err)
+		assert.Equal(t, 0, state.SyncedCount)
+		assert.Len(t, server.received(), 1)
+	})
+}
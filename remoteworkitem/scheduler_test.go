@@ -2,9 +2,11 @@ package remoteworkitem
 
 import (
 	"testing"
+	"time"
 
 	"github.com/fabric8-services/fabric8-wit/resource"
 	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,14 +14,73 @@ func TestLookupProvider(t *testing.T) {
 	resource.Require(t, resource.UnitTest)
 
 	ts1 := trackerSchedule{TrackerType: ProviderGithub}
-	tp1 := lookupProvider(ts1)
+	tp1 := lookupProvider(ts1, nil)
 	require.NotNil(t, tp1)
 
 	ts2 := trackerSchedule{TrackerType: ProviderJira}
-	tp2 := lookupProvider(ts2)
+	tp2 := lookupProvider(ts2, nil)
 	require.NotNil(t, tp2)
 
 	ts3 := trackerSchedule{TrackerType: "unknown"}
-	tp3 := lookupProvider(ts3)
+	tp3 := lookupProvider(ts3, nil)
 	require.Nil(t, tp3)
 }
+
+func TestIncrementalQueries(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	since := time.Date(2018, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	t.Run("github", func(t *testing.T) {
+		g := GithubTracker{Query: "is:open is:issue user:fabric8-services"}
+		assert.Equal(t, "is:open is:issue user:fabric8-services", g.query())
+		g.Since = &since
+		assert.Equal(t, "is:open is:issue user:fabric8-services updated:>2018-03-04T05:06:07+00:00", g.query())
+	})
+	t.Run("jira", func(t *testing.T) {
+		j := JiraTracker{Query: "project = ARQ ORDER BY key", Since: &since}
+		assert.Equal(t, `(project = ARQ) AND updated >= "2018/03/04 05:06" ORDER BY key`, j.query())
+		j = JiraTracker{Query: "", Since: &since}
+		assert.Equal(t, `updated >= "2018/03/04 05:06"`, j.query())
+		local := since.In(time.FixedZone("CET", 3600))
+		j = JiraTracker{Query: "", Since: &local}
+		assert.Equal(t, `updated >= "2018/03/04 05:06"`, j.query())
+	})
+	t.Run("gitlab", func(t *testing.T) {
+		g := GitLabTracker{Query: "projects/42/issues?state=opened", Since: &since}
+		assert.Equal(t, "projects/42/issues?state=opened&updated_after=2018-03-04T05%3A06%3A07Z", g.query())
+		g = GitLabTracker{Query: "issues", Since: &since}
+		assert.Equal(t, "issues?updated_after=2018-03-04T05%3A06%3A07Z", g.query())
+	})
+}
+
+func TestRemoteUpdatedAt(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	for name, td := range map[string]struct {
+		trackerType string
+		content     string
+	}{
+		"github": {ProviderGithub, `{"updated_at": "2018-03-04T05:06:07Z"}`},
+		"gitlab": {ProviderGitLab, `{"updated_at": "2018-03-04T05:06:07.000Z"}`},
+		"jira":   {ProviderJira, `{"fields": {"updated": "2018-03-04T06:06:07.000+0100"}}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			updatedAt, ok := remoteUpdatedAt(td.trackerType, TrackerItemContent{Content: []byte(td.content)})
+			require.True(t, ok)
+			assert.True(t, time.Date(2018, 3, 4, 5, 6, 7, 0, time.UTC).Equal(updatedAt))
+		})
+	}
+	t.Run("missing", func(t *testing.T) {
+		_, ok := remoteUpdatedAt(ProviderGithub, TrackerItemContent{Content: []byte(`{}`)})
+		assert.False(t, ok)
+	})
+}
+
+func TestRunQueryOnce(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	s := NewScheduler(nil)
+	require.True(t, s.begin(1))
+	assert.False(t, s.begin(1), "a query is run only once at a time")
+	assert.True(t, s.begin(2), "other queries are run concurrently")
+	s.end(1)
+	assert.True(t, s.begin(1))
+}
@@ -0,0 +1,285 @@
+package remoteworkitem
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/comment"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// RemoteUpdatedAtKeys are the keys of the "updated at" value in the
+// flattened response JSON of the remote items
+var RemoteUpdatedAtKeys = map[string]AttributeExpression{
+	ProviderGithub: "updated_at",
+	ProviderJira:   "fields.updated",
+	ProviderGitLab: "updated_at",
+}
+
+// syncAction is what is done with a remote item and its work item
+type syncAction struct {
+	// applyRemote overwrites the work item with the remote item
+	applyRemote bool
+	// push writes the work item back to the remote item
+	push bool
+	// conflict flags the item as conflicting
+	conflict bool
+}
+
+// decideSync decides how to reconcile a remote item with its work item
+// depending on which side changed since the last sync
+func decideSync(remoteChanged, localChanged, writeBack bool, policy string) syncAction {
+	switch {
+	case !localChanged:
+		return syncAction{applyRemote: remoteChanged}
+	case !remoteChanged && writeBack:
+		return syncAction{push: true}
+	case !remoteChanged:
+		// without write-back the local change can only be kept or dropped
+		return syncAction{applyRemote: policy == ConflictPolicyRemoteWins}
+	}
+	switch policy {
+	case ConflictPolicyLocalWins:
+		return syncAction{push: writeBack}
+	case ConflictPolicyFlag:
+		return syncAction{conflict: true}
+	}
+	return syncAction{applyRemote: true}
+}
+
+// RemotePush holds the local changes of a work item that are written back to
+// its remote item. It is decided while syncing in a transaction but sent to
+// the tracker after the transaction was committed, so that no locks are held
+// during the requests to the tracker. The tracker item only records the push
+// once it was sent, so a failed push is retried by the next sync.
+type RemotePush struct {
+	trackerItemID uint64
+	remoteItemID  string
+	remoteItem    AttributeAccessor
+	workItemID    uuid.UUID
+	// update is nil if only comments are pushed
+	update *RemoteUpdate
+	// workItemVersion is the version of the work item the update was made of
+	workItemVersion int
+	// comments are the comments added to the work item since the last push,
+	// oldest first
+	comments []comment.Comment
+	// pushedAt is the time the comments were listed
+	pushedAt time.Time
+}
+
+// Sync imports the given remote item into the database and reconciles it
+// with the work item it was converted into. Changes on both sides are
+// resolved according to the conflict policy of the tracker. The local
+// changes to write back to the tracker, if write-back is enabled, are
+// returned and have to be sent with Push once the transaction of the given db
+// was committed.
+func Sync(ctx context.Context, db *gorm.DB, tq trackerSchedule, item TrackerItemContent) (*RemotePush, error) {
+	var previous TrackerItem
+	found := true
+	if db.Where("remote_item_id = ? AND tracker_id = ?", item.ID, tq.TrackerID).Find(&previous).RecordNotFound() {
+		found = false
+	}
+	if err := Upload(db, tq.TrackerID, item); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var ti TrackerItem
+	if err := db.Where("remote_item_id = ? AND tracker_id = ?", item.ID, tq.TrackerID).Find(&ti).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+	remoteItem, err := RemoteWorkItemImplRegistry[tq.TrackerType](ti)
+	if err != nil {
+		return nil, InternalError{simpleError{message: fmt.Sprintf(" Error parsing the tracker data: %s", err.Error())}}
+	}
+	remoteUpdatedAt, _ := remoteItem.Get(RemoteUpdatedAtKeys[tq.TrackerType]).(string)
+
+	var wi *workitem.WorkItem
+	if found && previous.WorkItemID != nil {
+		wi, err = workitem.NewWorkItemRepository(db).LoadByID(ctx, *previous.WorkItemID)
+		if err != nil {
+			// the work item was deleted, it will be converted again
+			wi = nil
+		}
+	}
+	remoteChanged := wi == nil || remoteUpdatedAt == "" || remoteUpdatedAt != previous.RemoteUpdatedAt
+	localChanged := wi != nil && wi.Version != previous.WorkItemVersion
+	action := decideSync(remoteChanged, localChanged, tq.WriteBack, tq.ConflictPolicy)
+	log.Debug(ctx, map[string]interface{}{
+		"remote_item_id": item.ID,
+		"remote_changed": remoteChanged,
+		"local_changed":  localChanged,
+		"apply_remote":   action.applyRemote,
+		"push":           action.push,
+		"conflict":       action.conflict,
+	}, "syncing remote item")
+
+	ti.RemoteUpdatedAt = remoteUpdatedAt
+	ti.Conflict = action.conflict
+	var push *RemotePush
+	switch {
+	case action.conflict:
+		log.Warn(ctx, map[string]interface{}{
+			"remote_item_id": item.ID,
+			"wi_id":          wi.ID,
+			"tracker_id":     tq.TrackerID,
+		}, "remote item and work item were both changed, flagging the conflict")
+		// keep the last synced state so that the conflict persists until it
+		// is resolved by another policy
+		ti.RemoteUpdatedAt = previous.RemoteUpdatedAt
+	case action.applyRemote:
+		wi, err = ConvertToWorkItemModel(ctx, db, tq.TrackerID, item, tq.TrackerType, tq.SpaceID)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		ti.WorkItemVersion = wi.Version
+	case action.push:
+		update, err := newRemoteUpdate(ctx, db, *wi, tq, remoteItem)
+		if err != nil {
+			return nil, err
+		}
+		push = &RemotePush{update: update, workItemVersion: wi.Version}
+	}
+	if wi != nil {
+		ti.WorkItemID = &wi.ID
+	}
+	if tq.WriteBack && wi != nil && !action.conflict {
+		comments, pushedAt, err := pendingComments(ctx, db, *wi, ti)
+		if err != nil {
+			return nil, err
+		}
+		if push == nil && len(comments) > 0 {
+			push = &RemotePush{}
+		}
+		if push != nil {
+			push.comments = comments
+			push.pushedAt = pushedAt
+		} else {
+			// nothing to push, the comments up to now are in sync
+			ti.PushedAt = &pushedAt
+		}
+	}
+	if err := db.Save(&ti).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if push != nil {
+		push.trackerItemID = ti.ID
+		push.remoteItemID = ti.RemoteItemID
+		push.remoteItem = remoteItem
+		push.workItemID = wi.ID
+	}
+	return push, nil
+}
+
+// Push writes the given local changes back to the tracker of the given
+// schedule and records them in the tracker item. It must not be called within
+// the transaction that returned the changes.
+func Push(ctx context.Context, db *gorm.DB, tq trackerSchedule, push RemotePush, authToken string) error {
+	writer, err := newRemoteWriter(tq, authToken)
+	if err != nil {
+		return err
+	}
+	if push.update != nil {
+		if err := writer.Update(push.remoteItem, *push.update); err != nil {
+			return errors.Wrapf(err, "failed to write work item %s back to %s", push.workItemID, push.remoteItemID)
+		}
+	}
+	for _, c := range push.comments {
+		if err := writer.Comment(push.remoteItem, c.Body); err != nil {
+			return errors.Wrapf(err, "failed to write comment %s back to %s", c.ID, push.remoteItemID)
+		}
+	}
+	columns := map[string]interface{}{"pushed_at": push.pushedAt}
+	if push.update != nil {
+		columns["work_item_version"] = push.workItemVersion
+	}
+	err = db.Model(&TrackerItem{}).Where("id = ?", push.trackerItemID).UpdateColumns(columns).Error
+	if err != nil {
+		return errors.Wrapf(err, "failed to record the push of work item %s to %s", push.workItemID, push.remoteItemID)
+	}
+	log.Info(ctx, map[string]interface{}{
+		"wi_id":          push.workItemID,
+		"remote_item_id": push.remoteItemID,
+		"comments":       len(push.comments),
+	}, "pushed the local changes to the tracker")
+	return nil
+}
+
+// newRemoteWriter returns the writer for the tracker of the given schedule
+func newRemoteWriter(tq trackerSchedule, authToken string) (TrackerWriter, error) {
+	newWriter, ok := RemoteWriterRegistry[tq.TrackerType]
+	if !ok {
+		return nil, BadParameterError{parameter: "type", value: tq.TrackerType}
+	}
+	return newWriter(tq.URL, authToken), nil
+}
+
+// newRemoteUpdate returns the values of the given work item to write back to
+// the tracker. Values translated by the enum field mappings of the tracker
+// are translated back. Assignees without an identity of the tracker are
+// skipped.
+func newRemoteUpdate(ctx context.Context, db *gorm.DB, wi workitem.WorkItem, tq trackerSchedule, remoteItem AttributeAccessor) (*RemoteUpdate, error) {
+	var tracker Tracker
+	if err := db.Where("id = ?", tq.TrackerID).First(&tracker).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.Wrapf(err, "failed to load tracker %s", tq.TrackerID)
+	}
+	update := RemoteUpdate{Assignees: []string{}}
+	update.Title, _ = wi.Fields[workitem.SystemTitle].(string)
+	update.State, _ = wi.Fields[workitem.SystemState].(string)
+	update.State = tracker.FieldMappings.remoteEnumValue(workitem.SystemState, update.State, remoteItem)
+	var assignees []string
+	switch v := wi.Fields[workitem.SystemAssignees].(type) {
+	case []string:
+		assignees = v
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok {
+				assignees = append(assignees, s)
+			}
+		}
+	}
+	identityRepository := account.NewIdentityRepository(db)
+	for _, a := range assignees {
+		id, err := uuid.FromString(a)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid assignee %q", a)
+		}
+		identity, err := identityRepository.Load(ctx, id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load assignee %s", id)
+		}
+		if identity.ProviderType == tq.TrackerType {
+			update.Assignees = append(update.Assignees, identity.Username)
+		}
+	}
+	return &update, nil
+}
+
+// pendingComments returns the comments that were added to the work item since
+// the last push of the given tracker item, oldest first, and the time they
+// were listed
+func pendingComments(ctx context.Context, db *gorm.DB, wi workitem.WorkItem, ti TrackerItem) ([]comment.Comment, time.Time, error) {
+	since := ti.CreatedAt
+	if ti.PushedAt != nil {
+		since = *ti.PushedAt
+	}
+	now := time.Now()
+	comments, _, err := comment.NewRepository(db).List(ctx, wi.ID, nil, nil)
+	if err != nil {
+		return nil, now, errors.Wrapf(err, "failed to list the comments of work item %s", wi.ID)
+	}
+	var result []comment.Comment
+	// comments are listed newest first
+	for i := len(comments) - 1; i >= 0; i-- {
+		if comments[i].CreatedAt.After(since) {
+			result = append(result, comments[i])
+		}
+	}
+	return result, now, nil
+}
@@ -0,0 +1,152 @@
+package remoteworkitem
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecideSync(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	testData := []struct {
+		name          string
+		remoteChanged bool
+		localChanged  bool
+		writeBack     bool
+		policy        string
+		expected      syncAction
+	}{
+		{"unchanged", false, false, true, ConflictPolicyRemoteWins, syncAction{}},
+		{"remote changed", true, false, true, ConflictPolicyLocalWins, syncAction{applyRemote: true}},
+		{"local changed with write-back", false, true, true, ConflictPolicyRemoteWins, syncAction{push: true}},
+		{"local changed without write-back", false, true, false, ConflictPolicyRemoteWins, syncAction{applyRemote: true}},
+		{"local changed without write-back kept", false, true, false, ConflictPolicyLocalWins, syncAction{}},
+		{"both changed remote wins", true, true, true, ConflictPolicyRemoteWins, syncAction{applyRemote: true}},
+		{"both changed local wins", true, true, true, ConflictPolicyLocalWins, syncAction{push: true}},
+		{"both changed local wins without write-back", true, true, false, ConflictPolicyLocalWins, syncAction{}},
+		{"both changed flag", true, true, true, ConflictPolicyFlag, syncAction{conflict: true}},
+	}
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			assert.Equal(t, td.expected, decideSync(td.remoteChanged, td.localChanged, td.writeBack, td.policy))
+		})
+	}
+}
+
+// recordedRequest is a request received by a tracker stand-in
+type recordedRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   map[string]interface{}
+}
+
+// newRecordingServer returns a tracker stand-in that records the requests
+// and answers them with the given responses by path
+func newRecordingServer(t *testing.T, responses map[string]string) (*httptest.Server, *[]recordedRequest) {
+	var requests []recordedRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := recordedRequest{Method: r.Method, Path: r.URL.Path, Header: r.Header}
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		if len(body) > 0 {
+			require.NoError(t, json.Unmarshal(body, &req.Body))
+		}
+		requests = append(requests, req)
+		if resp, ok := responses[r.URL.Path]; ok && r.Method == http.MethodGet {
+			w.Write([]byte(resp))
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	return ts, &requests
+}
+
+func TestGithubWriter(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	ts, requests := newRecordingServer(t, nil)
+	defer ts.Close()
+	item := GitHubRemoteWorkItem{issue: map[string]interface{}{GithubID: ts.URL + "/repos/o/r/issues/1"}}
+	w := NewGithubWriter("https://api.github.com", "secret")
+	t.Run("update", func(t *testing.T) {
+		*requests = nil
+		err := w.Update(item, RemoteUpdate{Title: "Fixed title", State: "closed", Assignees: []string{"alice"}})
+		require.NoError(t, err)
+		require.Len(t, *requests, 1)
+		r := (*requests)[0]
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/repos/o/r/issues/1", r.Path)
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "Fixed title", r.Body["title"])
+		assert.Equal(t, "closed", r.Body["state"])
+		assert.Equal(t, []interface{}{"alice"}, r.Body["assignees"])
+	})
+	t.Run("comment", func(t *testing.T) {
+		*requests = nil
+		err := w.Comment(item, "hello")
+		require.NoError(t, err)
+		require.Len(t, *requests, 1)
+		assert.Equal(t, http.MethodPost, (*requests)[0].Method)
+		assert.Equal(t, "/repos/o/r/issues/1/comments", (*requests)[0].Path)
+		assert.Equal(t, "hello", (*requests)[0].Body["body"])
+	})
+}
+
+func TestGitLabWriter(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	ts, requests := newRecordingServer(t, map[string]string{
+		"/api/v4/users": `[{"id": 7}]`,
+	})
+	defer ts.Close()
+	item := GitLabRemoteWorkItem{issue: map[string]interface{}{"project_id": float64(42), GitLabIID: float64(3)}}
+	w := NewGitLabWriter(ts.URL, "secret")
+	t.Run("update", func(t *testing.T) {
+		*requests = nil
+		err := w.Update(item, RemoteUpdate{Title: "Fixed title", State: "open", Assignees: []string{"bob"}})
+		require.NoError(t, err)
+		require.Len(t, *requests, 2)
+		r := (*requests)[1]
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/v4/projects/42/issues/3", r.Path)
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+		assert.Equal(t, "reopen", r.Body["state_event"])
+		assert.Equal(t, []interface{}{float64(7)}, r.Body["assignee_ids"])
+	})
+	t.Run("comment", func(t *testing.T) {
+		*requests = nil
+		err := w.Comment(item, "hello")
+		require.NoError(t, err)
+		require.Len(t, *requests, 1)
+		assert.Equal(t, "/api/v4/projects/42/issues/3/notes", (*requests)[0].Path)
+	})
+}
+
+func TestJiraWriter(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	ts, requests := newRecordingServer(t, map[string]string{
+		"/rest/api/2/issue/10/transitions": `{"transitions": [{"id": "11", "to": {"name": "In Progress"}}, {"id": "31", "to": {"name": "Closed"}}]}`,
+	})
+	defer ts.Close()
+	item := JiraRemoteWorkItem{issue: map[string]interface{}{JiraID: ts.URL + "/rest/api/2/issue/10", JiraState: "Open"}}
+	w := NewJiraWriter(ts.URL, "")
+	t.Run("update with transition", func(t *testing.T) {
+		*requests = nil
+		err := w.Update(item, RemoteUpdate{Title: "Fixed title", State: "closed", Assignees: []string{"carol"}})
+		require.NoError(t, err)
+		require.Len(t, *requests, 3)
+		assert.Equal(t, http.MethodPut, (*requests)[0].Method)
+		assert.Equal(t, map[string]interface{}{"summary": "Fixed title", "assignee": map[string]interface{}{"name": "carol"}}, (*requests)[0].Body["fields"])
+		assert.Equal(t, http.MethodPost, (*requests)[2].Method)
+		assert.Equal(t, map[string]interface{}{"id": "31"}, (*requests)[2].Body["transition"])
+	})
+	t.Run("update without matching transition", func(t *testing.T) {
+		err := w.Update(item, RemoteUpdate{Title: "Fixed title", State: "resolved"})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,35 @@
+package remoteworkitem
+
+import (
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+)
+
+// SyncState holds the outcome of the last runs of a tracker query
+type SyncState struct {
+	gormsupport.Lifecycle
+	// TrackerQueryID is the tracker query that was run
+	TrackerQueryID uint64 `gorm:"primary_key"`
+	// LastRunAt is the time the query was last run
+	LastRunAt *time.Time
+	// LastSuccessAt is the time the query was last run without errors
+	LastSuccessAt *time.Time
+	// HighWaterMark is the latest remote update time of the items synced by
+	// the last successful run. The next run only fetches the items updated
+	// since then.
+	HighWaterMark *time.Time
+	// FetchedCount, SyncedCount and FailedCount count the items of the last
+	// run
+	FetchedCount int
+	SyncedCount  int
+	FailedCount  int
+	// LastError is the error of the last run, if any
+	LastError *string
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (s SyncState) TableName() string {
+	return syncStatesTableName
+}
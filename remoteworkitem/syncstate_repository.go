@@ -0,0 +1,79 @@
+package remoteworkitem
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+)
+
+const syncStatesTableName = "tracker_query_sync_states"
+
+// SyncStateRepository encapsulate storage & retrieval of the sync states of
+// tracker queries
+type SyncStateRepository interface {
+	Load(ctx context.Context, trackerQueryID string) (*SyncState, error)
+	Save(ctx context.Context, s *SyncState) error
+}
+
+// GormSyncStateRepository implements SyncStateRepository using gorm
+type GormSyncStateRepository struct {
+	db *gorm.DB
+}
+
+// NewSyncStateRepository constructs a SyncStateRepository
+func NewSyncStateRepository(db *gorm.DB) *GormSyncStateRepository {
+	return &GormSyncStateRepository{db}
+}
+
+// Load returns the sync state of the given tracker query. A query that was
+// never run has an empty state.
+// returns NotFoundError or InternalError
+func (r *GormSyncStateRepository) Load(ctx context.Context, trackerQueryID string) (*SyncState, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "tracker_query_sync_state", "load"}, time.Now())
+	id, err := strconv.ParseUint(trackerQueryID, 10, 64)
+	if err != nil || id == 0 {
+		// treating this as a not found error: the fact that we're using number internal is implementation detail
+		return nil, NotFoundError{"tracker query", trackerQueryID}
+	}
+	if r.db.First(&TrackerQuery{}, id).RecordNotFound() {
+		return nil, NotFoundError{"tracker query", trackerQueryID}
+	}
+	res := SyncState{}
+	tx := r.db.Where("tracker_query_id = ?", id).First(&res)
+	if tx.RecordNotFound() {
+		return &SyncState{TrackerQueryID: id}, nil
+	}
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":              tx.Error,
+			"tracker_query_id": id,
+		}, "unable to load the sync state of the tracker query")
+		return nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	return &res, nil
+}
+
+// Save creates or updates the given sync state
+// returns InternalError
+func (r *GormSyncStateRepository) Save(ctx context.Context, s *SyncState) error {
+	defer goa.MeasureSince([]string{"goa", "db", "tracker_query_sync_state", "save"}, time.Now())
+	save := r.db.Save
+	if s.CreatedAt.IsZero() {
+		// the state of a query that was never run is not stored yet
+		save = r.db.Create
+	}
+	if err := save(s).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":              err,
+			"tracker_query_id": s.TrackerQueryID,
+		}, "unable to save the sync state of the tracker query")
+		return errors.NewInternalError(ctx, err)
+	}
+	return nil
+}
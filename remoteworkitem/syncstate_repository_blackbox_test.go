@@ -0,0 +1,81 @@
+package remoteworkitem_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type syncStateRepoBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	repo remoteworkitem.SyncStateRepository
+}
+
+func TestRunSyncStateRepoBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &syncStateRepoBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *syncStateRepoBlackBoxTest) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.repo = remoteworkitem.NewSyncStateRepository(s.DB)
+}
+
+func (s *syncStateRepoBlackBoxTest) TestLoadAndSave() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Trackers(1), tf.Spaces(1))
+	tq, err := remoteworkitem.NewTrackerQueryRepository(s.DB).Create(
+		s.Ctx,
+		"is:open is:issue user:fabric8-services",
+		"15 * * * * *",
+		fxt.Trackers[0].ID, fxt.Spaces[0].ID)
+	require.NoError(s.T(), err)
+
+	s.T().Run("never run", func(t *testing.T) {
+		// when
+		state, err := s.repo.Load(s.Ctx, tq.ID)
+		// then
+		require.NoError(t, err)
+		assert.Nil(t, state.LastRunAt)
+		assert.Nil(t, state.HighWaterMark)
+		assert.Equal(t, 0, state.FetchedCount)
+	})
+	s.T().Run("saved twice", func(t *testing.T) {
+		// given
+		state, err := s.repo.Load(s.Ctx, tq.ID)
+		require.NoError(t, err)
+		now := time.Now()
+		state.LastRunAt = &now
+		state.FetchedCount = 2
+		state.FailedCount = 1
+		state.LastError = ptr.String("boom")
+		require.NoError(t, s.repo.Save(s.Ctx, state))
+		state.HighWaterMark = &now
+		state.LastError = nil
+		// when
+		err = s.repo.Save(s.Ctx, state)
+		// then
+		require.NoError(t, err)
+		loaded, err := s.repo.Load(s.Ctx, tq.ID)
+		require.NoError(t, err)
+		require.NotNil(t, loaded.HighWaterMark)
+		assert.Equal(t, 2, loaded.FetchedCount)
+		assert.Equal(t, 1, loaded.FailedCount)
+		assert.Nil(t, loaded.LastError)
+	})
+	s.T().Run("unknown query", func(t *testing.T) {
+		// when
+		_, err := s.repo.Load(s.Ctx, "123456789")
+		// then
+		require.IsType(t, remoteworkitem.NotFoundError{}, err)
+	})
+}
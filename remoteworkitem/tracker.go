@@ -2,6 +2,7 @@ package remoteworkitem
 
 import (
 	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	"github.com/fabric8-services/fabric8-wit/workitem"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -13,6 +14,51 @@ type Tracker struct {
 	URL string
 	// Type of the tracker (jira, github, bugzilla, trello etc.)
 	Type string
+	// WriteBack enables pushing local changes of the linked work items back
+	// to the tracker
+	WriteBack bool
+	// ConflictPolicy decides what happens when an item was changed both
+	// locally and remotely since the last sync
+	ConflictPolicy string `sql:"DEFAULT:remote-wins"`
+	// WebhookSecret verifies the webhook requests sent by the tracker.
+	// Webhooks are rejected unless it is set.
+	WebhookSecret string
+	// WorkItemTypeID is the type of the work items the remote items are
+	// converted into, the bug type if nil
+	WorkItemTypeID *uuid.UUID `sql:"type:uuid"`
+	// FieldMappings map remote attributes to fields of the work item type
+	// on top of the built-in mapping of the tracker type
+	FieldMappings FieldMappings `sql:"type:jsonb"`
+}
+
+// WorkItemType returns the type of the work items the remote items are
+// converted into
+func (t Tracker) WorkItemType() uuid.UUID {
+	if t.WorkItemTypeID == nil {
+		return workitem.SystemBug
+	}
+	return *t.WorkItemTypeID
+}
+
+// Conflict policies of a tracker
+const (
+	// ConflictPolicyRemoteWins overwrites the local changes with the remote ones
+	ConflictPolicyRemoteWins = "remote-wins"
+	// ConflictPolicyLocalWins keeps the local changes and pushes them to the
+	// tracker if write-back is enabled
+	ConflictPolicyLocalWins = "local-wins"
+	// ConflictPolicyFlag keeps both sides unchanged and flags the item as
+	// conflicting until the policy is changed
+	ConflictPolicyFlag = "flag"
+)
+
+// validConflictPolicy returns true if the given policy is known
+func validConflictPolicy(policy string) bool {
+	switch policy {
+	case ConflictPolicyRemoteWins, ConflictPolicyLocalWins, ConflictPolicyFlag:
+		return true
+	}
+	return false
 }
 
 // TableName overrides the table name settings in Gorm to force a specific table name
@@ -8,8 +8,10 @@ import (
 	"github.com/fabric8-services/fabric8-wit/application/repository"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/goadesign/goa"
 	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	govalidator "gopkg.in/asaskevich/govalidator.v4"
 )
@@ -53,6 +55,15 @@ func (r *GormTrackerRepository) Create(ctx context.Context, t *Tracker) error {
 	if present != true {
 		return BadParameterError{parameter: "type", value: t.Type}
 	}
+	if t.ConflictPolicy == "" {
+		t.ConflictPolicy = ConflictPolicyRemoteWins
+	}
+	if !validConflictPolicy(t.ConflictPolicy) {
+		return BadParameterError{parameter: "conflict_policy", value: t.ConflictPolicy}
+	}
+	if err := r.validateFieldMappings(ctx, t); err != nil {
+		return err
+	}
 	if err := r.db.Create(&t).Error; err != nil {
 		return InternalError{simpleError{err.Error()}}
 	}
@@ -63,6 +74,19 @@ func (r *GormTrackerRepository) Create(ctx context.Context, t *Tracker) error {
 	return nil
 }
 
+// validateFieldMappings checks that the field mappings of the tracker match
+// its work item type
+func (r *GormTrackerRepository) validateFieldMappings(ctx context.Context, t *Tracker) error {
+	if t.WorkItemTypeID == nil && len(t.FieldMappings) == 0 {
+		return nil
+	}
+	wit, err := workitem.NewWorkItemTypeRepository(r.db).Load(ctx, t.WorkItemType())
+	if err != nil {
+		return errs.Wrapf(err, "failed to load the work item type of the tracker")
+	}
+	return t.FieldMappings.Validate(*wit)
+}
+
 // Load returns the tracker configuration for the given id
 // returns NotFoundError, ConversionError or InternalError
 func (r *GormTrackerRepository) Load(ctx context.Context, ID uuid.UUID) (*Tracker, error) {
@@ -121,6 +145,15 @@ func (r *GormTrackerRepository) Save(ctx context.Context, t *Tracker) (*Tracker,
 	if present != true {
 		return nil, errors.NewBadParameterError("type", t.Type)
 	}
+	if t.ConflictPolicy == "" {
+		t.ConflictPolicy = ConflictPolicyRemoteWins
+	}
+	if !validConflictPolicy(t.ConflictPolicy) {
+		return nil, errors.NewBadParameterError("conflict_policy", t.ConflictPolicy).Expected("remote-wins, local-wins or flag")
+	}
+	if err := r.validateFieldMappings(ctx, t); err != nil {
+		return nil, err
+	}
 
 	if err := tx.Save(&t).Error; err != nil {
 		log.Error(ctx, map[string]interface{}{
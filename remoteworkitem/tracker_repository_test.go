@@ -58,6 +58,41 @@ func (test *TestTrackerRepository) TestTrackerCreate() {
 	assert.Equal(t, remoteworkitem.ProviderGithub, tracker2.Type)
 }
 
+func (test *TestTrackerRepository) TestTrackerCreateWithConflictPolicy() {
+	t := test.T()
+	resource.Require(t, resource.Database)
+
+	tracker := remoteworkitem.Tracker{
+		URL:            "http://api.github.com",
+		Type:           remoteworkitem.ProviderGithub,
+		ConflictPolicy: "toss-a-coin",
+	}
+	err := test.repo.Create(context.Background(), &tracker)
+	assert.IsType(t, remoteworkitem.BadParameterError{}, err)
+
+	tracker = remoteworkitem.Tracker{
+		URL:            "http://api.github.com",
+		Type:           remoteworkitem.ProviderGithub,
+		WriteBack:      true,
+		ConflictPolicy: remoteworkitem.ConflictPolicyFlag,
+	}
+	err = test.repo.Create(context.Background(), &tracker)
+	require.NoError(t, err)
+	tracker2, err := test.repo.Load(context.Background(), tracker.ID)
+	require.NoError(t, err)
+	assert.True(t, tracker2.WriteBack)
+	assert.Equal(t, remoteworkitem.ConflictPolicyFlag, tracker2.ConflictPolicy)
+
+	// the policy defaults to "remote wins"
+	tracker = remoteworkitem.Tracker{
+		URL:  "http://api.github.com",
+		Type: remoteworkitem.ProviderGithub,
+	}
+	err = test.repo.Create(context.Background(), &tracker)
+	require.NoError(t, err)
+	assert.Equal(t, remoteworkitem.ConflictPolicyRemoteWins, tracker.ConflictPolicy)
+}
+
 func (test *TestTrackerRepository) TestExistsTracker() {
 	t := test.T()
 	resource.Require(t, resource.Database)
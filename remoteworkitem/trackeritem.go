@@ -1,6 +1,8 @@
 package remoteworkitem
 
 import (
+	"time"
+
 	"github.com/fabric8-services/fabric8-wit/gormsupport"
 	uuid "github.com/satori/go.uuid"
 )
@@ -16,4 +18,15 @@ type TrackerItem struct {
 	Item string
 	// FK to tracker
 	TrackerID uuid.UUID `gorm:"ForeignKey:Tracker"`
+	// the work item that the item was converted into
+	WorkItemID *uuid.UUID `sql:"type:uuid"`
+	// the remote "updated at" value of the item at the last sync
+	RemoteUpdatedAt string
+	// the version of the work item at the last sync
+	WorkItemVersion int
+	// the time local changes were last pushed to the tracker
+	PushedAt *time.Time
+	// true if the item was changed both locally and remotely and the
+	// tracker flags conflicts
+	Conflict bool
 }
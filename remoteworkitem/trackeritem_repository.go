@@ -7,6 +7,7 @@ import (
 
 	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/criteria"
+	"github.com/fabric8-services/fabric8-wit/label"
 	"github.com/fabric8-services/fabric8-wit/log"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 
@@ -54,6 +55,21 @@ func ConvertToWorkItemModel(ctx context.Context, db *gorm.DB, tID uuid.UUID, ite
 	if err != nil {
 		return nil, InternalError{simpleError{message: fmt.Sprintf("Error bind assignees: %s", err.Error())}}
 	}
+	// apply the field mappings of the tracker on top of the built-in ones
+	var tracker Tracker
+	if err := db.Where("id = ?", tID).First(&tracker).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return nil, InternalError{simpleError{message: fmt.Sprintf("Error loading the tracker: %s", err.Error())}}
+	}
+	workItem.Type = tracker.WorkItemType()
+	if len(tracker.FieldMappings) > 0 {
+		wit, err := workitem.NewWorkItemTypeRepository(db).Load(ctx, workItem.Type)
+		if err != nil {
+			return nil, InternalError{simpleError{message: fmt.Sprintf("Error loading the work item type: %s", err.Error())}}
+		}
+		if err := applyFieldMappings(ctx, db, remoteTrackerItem, tracker.FieldMappings, *wit, providerType, workItem); err != nil {
+			return nil, ConversionError{simpleError{message: fmt.Sprintf("Error applying the field mappings: %s", err.Error())}}
+		}
+	}
 	return upsert(ctx, db, *workItem)
 }
 
@@ -107,6 +123,18 @@ func lookupIdentities(ctx context.Context, db *gorm.DB, remoteWorkItem RemoteWor
 			workItem.Fields[workitem.SystemAssignees] = identities
 		} else if fieldName == RemoteAssigneeProfileURLs {
 			// ignore here, it is being processed above
+		} else
+		// labels
+		if fieldName == RemoteLabelNames {
+			if fieldValue == nil {
+				workItem.Fields[workitem.SystemLabels] = make([]string, 0)
+				continue
+			}
+			labelIDs, err := lookupLabels(ctx, db, fieldValue.([]string), spaceID)
+			if err != nil {
+				return nil, err
+			}
+			workItem.Fields[workitem.SystemLabels] = labelIDs
 		} else {
 			// copy other fields
 			workItem.Fields[fieldName] = fieldValue
@@ -115,6 +143,36 @@ func lookupIdentities(ctx context.Context, db *gorm.DB, remoteWorkItem RemoteWor
 	return &workItem, nil
 }
 
+// lookupLabels looks up the labels of the space with the given names and
+// creates the ones that don't exist yet
+func lookupLabels(ctx context.Context, db *gorm.DB, names []string, spaceID uuid.UUID) ([]string, error) {
+	labelRepository := label.NewLabelRepository(db)
+	labels, err := labelRepository.List(ctx, spaceID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the labels of space %s", spaceID)
+	}
+	labelIDs := make([]string, 0, len(names))
+	for _, name := range names {
+		labelID := uuid.Nil
+		for _, l := range labels {
+			if l.Name == name {
+				labelID = l.ID
+				break
+			}
+		}
+		if labelID == uuid.Nil {
+			l := label.Label{SpaceID: spaceID, Name: name}
+			if err := labelRepository.Create(ctx, &l); err != nil {
+				return nil, errors.Wrapf(err, "failed to create label %q", name)
+			}
+			labels = append(labels, l)
+			labelID = l.ID
+		}
+		labelIDs = append(labelIDs, labelID.String())
+	}
+	return labelIDs, nil
+}
+
 func upsert(ctx context.Context, db *gorm.DB, workItem workitem.WorkItem) (*workitem.WorkItem, error) {
 	wir := workitem.NewWorkItemRepository(db)
 	// Get the remote item identifier ( which is currently the url ) to check if the work item exists in the database.
@@ -149,7 +207,11 @@ func upsert(ctx context.Context, db *gorm.DB, workItem workitem.WorkItem) (*work
 		}
 	} else {
 		log.Info(nil, nil, "Workitem does not exist, will be created")
-		resultWorkItem, _, err = wir.Create(ctx, workItem.SpaceID, workitem.SystemBug, workItem.Fields, creator)
+		typeID := workItem.Type
+		if typeID == uuid.Nil {
+			typeID = workitem.SystemBug
+		}
+		resultWorkItem, _, err = wir.Create(ctx, workItem.SpaceID, typeID, workItem.Fields, creator)
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
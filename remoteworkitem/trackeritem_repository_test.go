@@ -290,3 +290,42 @@ func (s *TrackerItemRepositorySuite) TestConvertGithubIssue() {
 	assert.Equal(s.T(), identity.ID.String(), workItemGithub.Fields[workitem.SystemAssignees].([]interface{})[0])
 	assert.Equal(s.T(), "open", workItemGithub.Fields[workitem.SystemState])
 }
+
+func (s *TrackerItemRepositorySuite) TestConvertGitLabIssue() {
+	// given
+	remoteItemData := remoteworkitem.TrackerItemContent{
+		Content: []byte(`{
+			"iid": 42,
+			"title": "Fix the login",
+			"description": "It *fails*",
+			"state": "opened",
+			"web_url": "https://gitlab.example.com/g/p/issues/42",
+			"labels": ["bug"],
+			"author": {"username": "alice", "web_url": "https://gitlab.example.com/alice"},
+			"assignees": [{"username": "bob", "web_url": "https://gitlab.example.com/bob"}]
+		}`),
+		ID: "https://gitlab.example.com/g/p/issues/42",
+	}
+	// when
+	wi, err := remoteworkitem.ConvertToWorkItemModel(s.Ctx, s.DB, s.trackerQuery.TrackerID, remoteItemData, remoteworkitem.ProviderGitLab, s.trackerQuery.SpaceID)
+	// then every mapped value ends up in a field of the work item
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "Fix the login", wi.Fields[workitem.SystemTitle])
+	assert.Equal(s.T(), rendering.NewMarkupContent("It *fails*", rendering.SystemMarkupMarkdown), wi.Fields[workitem.SystemDescription])
+	assert.Equal(s.T(), "open", wi.Fields[workitem.SystemState])
+	assert.Equal(s.T(), "https://gitlab.example.com/g/p/issues/42", wi.Fields[workitem.SystemRemoteItemID])
+	creator := s.lookupIdentityByID(wi.Fields[workitem.SystemCreator].(string))
+	assert.Equal(s.T(), "alice", creator.Username)
+	require.Len(s.T(), wi.Fields[workitem.SystemAssignees], 1)
+	assignee := s.lookupIdentityByID(wi.Fields[workitem.SystemAssignees].([]interface{})[0].(string))
+	assert.Equal(s.T(), "bob", assignee.Username)
+	require.Len(s.T(), wi.Fields[workitem.SystemLabels], 1)
+	for _, mapping := range remoteworkitem.RemoteWorkItemKeyMaps[remoteworkitem.ProviderGitLab] {
+		switch mapping {
+		case "system.creator.login", "system.creator.profile_url", remoteworkitem.RemoteAssigneeLogins, remoteworkitem.RemoteAssigneeProfileURLs, remoteworkitem.RemoteLabelNames:
+			// resolved to identities and labels
+		default:
+			assert.Contains(s.T(), wi.Fields, mapping)
+		}
+	}
+}
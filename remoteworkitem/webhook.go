@@ -0,0 +1,171 @@
+package remoteworkitem
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/models"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+)
+
+// VerifyWebhook checks that the webhook request with the given headers,
+// query parameters and body was sent by the given tracker. Github requests
+// are signed with an HMAC of the body, GitLab requests carry the secret in
+// a header and Jira requests, which cannot be signed, carry it in the
+// "secret" query parameter of the webhook URL.
+func VerifyWebhook(tracker Tracker, header http.Header, query url.Values, body []byte) error {
+	if tracker.WebhookSecret == "" {
+		return errors.NewUnauthorizedError("webhooks are not enabled for this tracker")
+	}
+	secret := []byte(tracker.WebhookSecret)
+	switch tracker.Type {
+	case ProviderGithub, ProviderGitLab:
+		if rest.ValidWebhookSignature(header, secret, body) {
+			return nil
+		}
+	case ProviderJira:
+		if subtle.ConstantTimeCompare([]byte(query.Get("secret")), secret) == 1 {
+			return nil
+		}
+	default:
+		return errors.NewBadParameterError("type", tracker.Type)
+	}
+	return errors.NewUnauthorizedError("invalid webhook signature")
+}
+
+// WebhookItem extracts the remote item from the given webhook request body
+// of the tracker. It returns nil if the event is not about an item, e.g. a
+// Github "ping". GitLab hooks carry a summary of the issue only, hence the
+// issue is fetched from the GitLab API with the given auth token.
+func WebhookItem(tracker Tracker, body []byte, authToken string) (*TrackerItemContent, error) {
+	switch tracker.Type {
+	case ProviderGithub:
+		var payload struct {
+			Issue json.RawMessage `json:"issue"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, errors.NewBadParameterError("body", nil).Expected("Github webhook payload")
+		}
+		return webhookItem(payload.Issue, "url")
+	case ProviderJira:
+		var payload struct {
+			Issue json.RawMessage `json:"issue"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, errors.NewBadParameterError("body", nil).Expected("Jira webhook payload")
+		}
+		return webhookItem(payload.Issue, "key")
+	case ProviderGitLab:
+		var payload struct {
+			ObjectKind string `json:"object_kind"`
+			Issue      *struct {
+				IID       int `json:"iid"`
+				ProjectID int `json:"project_id"`
+			} `json:"issue"`
+			ObjectAttributes struct {
+				IID       int `json:"iid"`
+				ProjectID int `json:"project_id"`
+			} `json:"object_attributes"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, errors.NewBadParameterError("body", nil).Expected("GitLab webhook payload")
+		}
+		projectID, iid := payload.ObjectAttributes.ProjectID, payload.ObjectAttributes.IID
+		switch payload.ObjectKind {
+		case "issue":
+		case "note":
+			// comments on issues carry the issue, others are ignored
+			if payload.Issue == nil {
+				return nil, nil
+			}
+			projectID, iid = payload.Issue.ProjectID, payload.Issue.IID
+		default:
+			return nil, nil
+		}
+		w := NewGitLabWriter(tracker.URL, authToken).(*GitLabWriter)
+		var issue json.RawMessage
+		issueURL := fmt.Sprintf("%s/api/v4/projects/%d/issues/%d", w.url, projectID, iid)
+		if err := w.do(http.MethodGet, issueURL, nil, &issue); err != nil {
+			return nil, errs.Wrapf(err, "failed to fetch the GitLab issue of the webhook")
+		}
+		return webhookItem(issue, "web_url")
+	}
+	return nil, errors.NewBadParameterError("type", tracker.Type)
+}
+
+// webhookItem returns the tracker item of the given issue that is identified
+// by the given attribute
+func webhookItem(issue json.RawMessage, idAttribute string) (*TrackerItemContent, error) {
+	if len(issue) == 0 || string(issue) == "null" {
+		return nil, nil
+	}
+	var attrs map[string]interface{}
+	if err := json.Unmarshal(issue, &attrs); err != nil {
+		return nil, errors.NewBadParameterError("issue", string(issue)).Expected("JSON object")
+	}
+	remoteID, ok := attrs[idAttribute].(string)
+	if !ok || remoteID == "" {
+		return nil, errors.NewBadParameterError("issue."+idAttribute, attrs[idAttribute]).Expected("not empty")
+	}
+	id, _ := json.Marshal(remoteID)
+	return &TrackerItemContent{ID: string(id), Content: issue}, nil
+}
+
+// SyncWebhookItem syncs the given item received through a webhook of the
+// tracker. Only the items that were already imported by a tracker query are
+// synced, into the space of their work item. The other items are left to the
+// scheduled tracker queries, which know the space to import them into.
+// The local changes are pushed to the tracker once the item was synced.
+// It returns true if the item was synced.
+func (s *Scheduler) SyncWebhookItem(ctx context.Context, tracker Tracker, item TrackerItemContent, authToken string) (bool, error) {
+	var synced bool
+	var tq trackerSchedule
+	var push *RemotePush
+	err := models.Transactional(s.db, func(tx *gorm.DB) error {
+		var err error
+		synced, tq, push, err = syncWebhookItem(ctx, tx, tracker, item)
+		return err
+	})
+	if err != nil || push == nil {
+		return synced, err
+	}
+	return synced, Push(ctx, s.db, tq, *push, authToken)
+}
+
+func syncWebhookItem(ctx context.Context, db *gorm.DB, tracker Tracker, item TrackerItemContent) (bool, trackerSchedule, *RemotePush, error) {
+	var ti TrackerItem
+	if db.Where("remote_item_id = ? AND tracker_id = ?", item.ID, tracker.ID).Find(&ti).RecordNotFound() || ti.WorkItemID == nil {
+		log.Info(ctx, map[string]interface{}{
+			"tracker_id": tracker.ID,
+			"remote_id":  item.ID,
+		}, "ignoring webhook for an item that was not imported yet")
+		return false, trackerSchedule{}, nil, nil
+	}
+	wi, err := workitem.NewWorkItemRepository(db).LoadByID(ctx, *ti.WorkItemID)
+	if err != nil {
+		return false, trackerSchedule{}, nil, errs.Wrapf(err, "failed to load the work item of %s", item.ID)
+	}
+	tq := trackerSchedule{
+		TrackerID:      tracker.ID,
+		URL:            tracker.URL,
+		TrackerType:    tracker.Type,
+		SpaceID:        wi.SpaceID,
+		WriteBack:      tracker.WriteBack,
+		ConflictPolicy: tracker.ConflictPolicy,
+	}
+	push, err := Sync(ctx, db, tq, item)
+	if err != nil {
+		return false, tq, nil, errs.WithStack(err)
+	}
+	return true, tq, push, nil
+}
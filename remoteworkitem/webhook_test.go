@@ -0,0 +1,93 @@
+package remoteworkitem
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	errs "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyWebhook(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	body := []byte(`{"action":"edited"}`)
+	mac := hmac.New(sha256.New, []byte("s3cr3t"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	t.Run("github", func(t *testing.T) {
+		tracker := Tracker{Type: ProviderGithub, WebhookSecret: "s3cr3t"}
+		assert.NoError(t, VerifyWebhook(tracker, http.Header{"X-Hub-Signature-256": {signature}}, nil, body))
+		err := VerifyWebhook(tracker, http.Header{"X-Hub-Signature-256": {signature}}, nil, []byte(`{"action":"closed"}`))
+		assert.IsType(t, errors.UnauthorizedError{}, errs.Cause(err))
+		err = VerifyWebhook(tracker, http.Header{}, nil, body)
+		assert.IsType(t, errors.UnauthorizedError{}, errs.Cause(err))
+	})
+	t.Run("gitlab", func(t *testing.T) {
+		tracker := Tracker{Type: ProviderGitLab, WebhookSecret: "s3cr3t"}
+		assert.NoError(t, VerifyWebhook(tracker, http.Header{"X-Gitlab-Token": {"s3cr3t"}}, nil, body))
+		err := VerifyWebhook(tracker, http.Header{"X-Gitlab-Token": {"guess"}}, nil, body)
+		assert.IsType(t, errors.UnauthorizedError{}, errs.Cause(err))
+	})
+	t.Run("jira", func(t *testing.T) {
+		tracker := Tracker{Type: ProviderJira, WebhookSecret: "s3cr3t"}
+		assert.NoError(t, VerifyWebhook(tracker, http.Header{}, url.Values{"secret": {"s3cr3t"}}, body))
+		err := VerifyWebhook(tracker, http.Header{}, url.Values{}, body)
+		assert.IsType(t, errors.UnauthorizedError{}, errs.Cause(err))
+	})
+	t.Run("webhooks disabled", func(t *testing.T) {
+		tracker := Tracker{Type: ProviderGitLab}
+		err := VerifyWebhook(tracker, http.Header{"X-Gitlab-Token": {""}}, nil, body)
+		assert.IsType(t, errors.UnauthorizedError{}, errs.Cause(err))
+	})
+}
+
+func TestWebhookItem(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Run("github", func(t *testing.T) {
+		item, err := WebhookItem(Tracker{Type: ProviderGithub}, []byte(`{"action":"edited","issue":{"url":"https://api.github.com/repos/o/r/issues/1","title":"t"}}`), "")
+		require.NoError(t, err)
+		require.NotNil(t, item)
+		assert.Equal(t, `"https://api.github.com/repos/o/r/issues/1"`, item.ID)
+		assert.Equal(t, `{"url":"https://api.github.com/repos/o/r/issues/1","title":"t"}`, string(item.Content))
+	})
+	t.Run("github ping", func(t *testing.T) {
+		item, err := WebhookItem(Tracker{Type: ProviderGithub}, []byte(`{"zen":"Keep it logically awesome."}`), "")
+		require.NoError(t, err)
+		assert.Nil(t, item)
+	})
+	t.Run("jira", func(t *testing.T) {
+		item, err := WebhookItem(Tracker{Type: ProviderJira}, []byte(`{"webhookEvent":"jira:issue_updated","issue":{"key":"ARQ-1","self":"https://jira.example.com/rest/api/2/issue/10"}}`), "")
+		require.NoError(t, err)
+		require.NotNil(t, item)
+		assert.Equal(t, `"ARQ-1"`, item.ID)
+	})
+	t.Run("gitlab", func(t *testing.T) {
+		var token string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token = r.Header.Get("PRIVATE-TOKEN")
+			require.Equal(t, "/api/v4/projects/42/issues/3", r.URL.Path)
+			fmt.Fprint(w, `{"iid":3,"project_id":42,"web_url":"https://gitlab.example.com/g/p/issues/3"}`)
+		}))
+		defer ts.Close()
+		item, err := WebhookItem(Tracker{Type: ProviderGitLab, URL: ts.URL}, []byte(`{"object_kind":"issue","object_attributes":{"iid":3,"project_id":42}}`), "secret")
+		require.NoError(t, err)
+		require.NotNil(t, item)
+		assert.Equal(t, `"https://gitlab.example.com/g/p/issues/3"`, item.ID)
+		assert.Equal(t, "secret", token)
+	})
+	t.Run("gitlab push", func(t *testing.T) {
+		item, err := WebhookItem(Tracker{Type: ProviderGitLab}, []byte(`{"object_kind":"push"}`), "")
+		require.NoError(t, err)
+		assert.Nil(t, item)
+	})
+}
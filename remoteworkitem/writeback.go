@@ -0,0 +1,288 @@
+package remoteworkitem
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RemoteUpdate holds the local values of a work item that are written back to
+// the linked remote item
+type RemoteUpdate struct {
+	Title string
+	// State is the state of the local work item, e.g. "open" or "closed",
+	// translated back by the enum mapping of the state if the tracker has one
+	State string
+	// Assignees are the logins of the assignees on the tracker
+	Assignees []string
+}
+
+// TrackerWriter writes local changes back to a remote tracker. The given
+// item is the last content fetched from the tracker and is used to locate
+// the remote item.
+type TrackerWriter interface {
+	Update(item AttributeAccessor, update RemoteUpdate) error
+	Comment(item AttributeAccessor, body string) error
+}
+
+// RemoteWriterRegistry contains all the known writers of remote trackers
+var RemoteWriterRegistry = map[string]func(trackerURL, authToken string) TrackerWriter{
+	ProviderGithub: NewGithubWriter,
+	ProviderGitLab: NewGitLabWriter,
+	ProviderJira:   NewJiraWriter,
+}
+
+// trackerClient sends JSON requests to a remote tracker
+type trackerClient struct {
+	client  *http.Client
+	headers map[string]string
+}
+
+func newTrackerClient(headers map[string]string) trackerClient {
+	return trackerClient{
+		client:  &http.Client{Timeout: 30 * time.Second},
+		headers: headers,
+	}
+}
+
+// do sends the given payload as JSON and decodes the response into the
+// given result unless it is nil
+func (c trackerClient) do(method, u string, payload interface{}, result interface{}) error {
+	var body bytes.Buffer
+	if payload != nil {
+		if err := json.NewEncoder(&body).Encode(payload); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	req, err := http.NewRequest(method, u, &body)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to send %s %s", method, u)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("failed to send %s %s: unexpected response status %s", method, u, resp.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return errors.Wrapf(err, "failed to decode the response of %s %s", method, u)
+	}
+	return nil
+}
+
+// remoteString returns the string value of the given attribute or an error
+// if it is missing
+func remoteString(item AttributeAccessor, field AttributeExpression) (string, error) {
+	switch v := item.Get(field).(type) {
+	case string:
+		if v != "" {
+			return v, nil
+		}
+	case float64:
+		return strconv.FormatInt(int64(v), 10), nil
+	}
+	return "", errors.Errorf("remote item has no %q attribute", field)
+}
+
+// GithubWriter writes changes back to Github issues
+type GithubWriter struct {
+	trackerClient
+}
+
+// NewGithubWriter creates a writer for Github issues
+func NewGithubWriter(trackerURL, authToken string) TrackerWriter {
+	headers := map[string]string{}
+	if authToken != "" {
+		headers["Authorization"] = "token " + authToken
+	}
+	return &GithubWriter{newTrackerClient(headers)}
+}
+
+// Update updates the title, state and assignees of the issue
+func (w *GithubWriter) Update(item AttributeAccessor, update RemoteUpdate) error {
+	issueURL, err := remoteString(item, GithubID)
+	if err != nil {
+		return err
+	}
+	state := "open"
+	if update.State == "closed" {
+		state = "closed"
+	}
+	assignees := update.Assignees
+	if assignees == nil {
+		assignees = []string{}
+	}
+	return w.do(http.MethodPatch, issueURL, map[string]interface{}{
+		"title":     update.Title,
+		"state":     state,
+		"assignees": assignees,
+	}, nil)
+}
+
+// Comment adds a comment to the issue
+func (w *GithubWriter) Comment(item AttributeAccessor, body string) error {
+	issueURL, err := remoteString(item, GithubID)
+	if err != nil {
+		return err
+	}
+	return w.do(http.MethodPost, issueURL+"/comments", map[string]interface{}{"body": body}, nil)
+}
+
+// GitLabWriter writes changes back to GitLab issues
+type GitLabWriter struct {
+	trackerClient
+	url string
+}
+
+// NewGitLabWriter creates a writer for the issues of the given GitLab
+// instance
+func NewGitLabWriter(trackerURL, authToken string) TrackerWriter {
+	headers := map[string]string{}
+	if authToken != "" {
+		headers["PRIVATE-TOKEN"] = authToken
+	}
+	return &GitLabWriter{trackerClient: newTrackerClient(headers), url: strings.TrimSuffix(trackerURL, "/")}
+}
+
+// issueURL returns the API URL of the given issue
+func (w *GitLabWriter) issueURL(item AttributeAccessor) (string, error) {
+	projectID, err := remoteString(item, "project_id")
+	if err != nil {
+		return "", err
+	}
+	iid, err := remoteString(item, GitLabIID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/api/v4/projects/%s/issues/%s", w.url, projectID, iid), nil
+}
+
+// userID looks up the ID of the GitLab user with the given username
+func (w *GitLabWriter) userID(username string) (int, error) {
+	var users []struct {
+		ID int `json:"id"`
+	}
+	if err := w.do(http.MethodGet, w.url+"/api/v4/users?username="+url.QueryEscape(username), nil, &users); err != nil {
+		return 0, err
+	}
+	if len(users) == 0 {
+		return 0, errors.Errorf("unknown GitLab user %q", username)
+	}
+	return users[0].ID, nil
+}
+
+// Update updates the title, state and assignees of the issue
+func (w *GitLabWriter) Update(item AttributeAccessor, update RemoteUpdate) error {
+	issueURL, err := w.issueURL(item)
+	if err != nil {
+		return err
+	}
+	assigneeIDs := make([]int, 0, len(update.Assignees))
+	for _, username := range update.Assignees {
+		id, err := w.userID(username)
+		if err != nil {
+			return err
+		}
+		assigneeIDs = append(assigneeIDs, id)
+	}
+	stateEvent := "reopen"
+	if update.State == "closed" {
+		stateEvent = "close"
+	}
+	return w.do(http.MethodPut, issueURL, map[string]interface{}{
+		"title":        update.Title,
+		"state_event":  stateEvent,
+		"assignee_ids": assigneeIDs,
+	}, nil)
+}
+
+// Comment adds a note to the issue
+func (w *GitLabWriter) Comment(item AttributeAccessor, body string) error {
+	issueURL, err := w.issueURL(item)
+	if err != nil {
+		return err
+	}
+	return w.do(http.MethodPost, issueURL+"/notes", map[string]interface{}{"body": body}, nil)
+}
+
+// JiraWriter writes changes back to Jira issues
+type JiraWriter struct {
+	trackerClient
+}
+
+// NewJiraWriter creates a writer for Jira issues
+func NewJiraWriter(trackerURL, authToken string) TrackerWriter {
+	headers := map[string]string{}
+	if authToken != "" {
+		headers["Authorization"] = "Bearer " + authToken
+	}
+	return &JiraWriter{newTrackerClient(headers)}
+}
+
+// Update updates the summary and the assignee of the issue and moves it to
+// the given state through the matching workflow transition
+func (w *JiraWriter) Update(item AttributeAccessor, update RemoteUpdate) error {
+	issueURL, err := remoteString(item, JiraID)
+	if err != nil {
+		return err
+	}
+	fields := map[string]interface{}{"summary": update.Title}
+	// Jira issues have a single assignee
+	if len(update.Assignees) > 0 {
+		fields["assignee"] = map[string]interface{}{"name": update.Assignees[0]}
+	} else {
+		fields["assignee"] = nil
+	}
+	if err := w.do(http.MethodPut, issueURL, map[string]interface{}{"fields": fields}, nil); err != nil {
+		return err
+	}
+	current, _ := item.Get(JiraState).(string)
+	if update.State == "" || strings.EqualFold(current, update.State) {
+		return nil
+	}
+	var transitions struct {
+		Transitions []struct {
+			ID string `json:"id"`
+			To struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := w.do(http.MethodGet, issueURL+"/transitions", nil, &transitions); err != nil {
+		return err
+	}
+	for _, t := range transitions.Transitions {
+		if strings.EqualFold(t.To.Name, update.State) {
+			return w.do(http.MethodPost, issueURL+"/transitions", map[string]interface{}{
+				"transition": map[string]interface{}{"id": t.ID},
+			}, nil)
+		}
+	}
+	return errors.Errorf("no transition of %s leads to state %q", issueURL, update.State)
+}
+
+// Comment adds a comment to the issue
+func (w *JiraWriter) Comment(item AttributeAccessor, body string) error {
+	issueURL, err := remoteString(item, JiraID)
+	if err != nil {
+		return err
+	}
+	return w.do(http.MethodPost, issueURL+"/comment", map[string]interface{}{"body": body}, nil)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"bytes"
+	"regexp"
 	"strings"
 
 	"github.com/russross/blackfriday"
@@ -31,6 +32,42 @@ const (
 		blackfriday.EXTENSION_DEFINITION_LISTS
 )
 
+// AttachmentScheme is the prefix of references to attachments in markup, e.g.
+// "![screenshot](attachment:40bbdd3d-8b5d-4fd6-ac90-7236b669af04)". Such
+// references are rendered as links to the content of the attachment so that
+// images are displayed inline.
+const AttachmentScheme = "attachment:"
+
+// AttachmentContentPath returns the path of the API endpoint that serves the
+// content of the attachment with the given ID. It is set by the package that
+// serves the attachments, so that the path follows the API design. As long as
+// it is nil, attachment references are rendered unchanged.
+var AttachmentContentPath func(attachmentID string) string
+
+// resolveAttachmentLink replaces an attachment reference by the path to the
+// attachment content and returns all other links unchanged
+func resolveAttachmentLink(link []byte) []byte {
+	if AttachmentContentPath == nil || !bytes.HasPrefix(link, []byte(AttachmentScheme)) {
+		return link
+	}
+	return []byte(AttachmentContentPath(string(link[len(AttachmentScheme):])))
+}
+
+// WorkItemKeyPath returns the path of the API endpoint that redirects to the
+// work item with the given human-readable key, e.g. "PLAT-123". It is set by
+// the package that serves the endpoint. As long as it is nil, work item keys
+// are not linked.
+var WorkItemKeyPath func(key string) string
+
+// workItemKeyRegexp matches explicit references to work items by their key in
+// text, e.g. "#PLAT-123". The leading "#" is required so that words that only
+// look like keys (e.g. "UTF-8" or "ISO-9001") aren't linked to spaces that
+// don't exist. The first submatch is the key.
+var workItemKeyRegexp = regexp.MustCompile(`(?:^|[^\w#&])#([A-Z][A-Z0-9]{1,9}-[0-9]+)\b`)
+
+// workItemKeyLinkRegexp matches the links rendered for work item keys
+var workItemKeyLinkRegexp = regexp.MustCompile(`<a class="work-item-key" href="[^"]*">([^<]*)</a>`)
+
 // MarkdownCommonHighlighter uses the blackfriday.MarkdownCommon setup but also includes
 // code-prettify formatting of BlockCode segments
 func MarkdownCommonHighlighter(input []byte) []byte {
@@ -62,6 +99,45 @@ func (h *highlightHTMLRenderer) ListItem(out *bytes.Buffer, text []byte, flags i
 	h.Renderer.ListItem(out, text, flags)
 }
 
+// Image overrides the default Image render to resolve attachment references
+func (h *highlightHTMLRenderer) Image(out *bytes.Buffer, link []byte, title []byte, alt []byte) {
+	h.Renderer.Image(out, resolveAttachmentLink(link), title, alt)
+}
+
+// Link overrides the default Link render to resolve attachment references.
+// Work item keys in the content of the link are not linked themselves.
+func (h *highlightHTMLRenderer) Link(out *bytes.Buffer, link []byte, title []byte, content []byte) {
+	content = workItemKeyLinkRegexp.ReplaceAll(content, []byte("$1"))
+	h.Renderer.Link(out, resolveAttachmentLink(link), title, content)
+}
+
+// NormalText overrides the default NormalText render to link references to
+// work item keys (e.g. "#PLAT-123") to the API endpoint that resolves them
+func (h *highlightHTMLRenderer) NormalText(out *bytes.Buffer, text []byte) {
+	var matches [][]int
+	if WorkItemKeyPath != nil {
+		matches = workItemKeyRegexp.FindAllSubmatchIndex(text, -1)
+	}
+	if matches == nil {
+		h.Renderer.NormalText(out, text)
+		return
+	}
+	start := 0
+	for _, m := range matches {
+		// the reference starts with the "#" in front of the key
+		refStart := m[2] - 1
+		if refStart > start {
+			h.Renderer.NormalText(out, text[start:refStart])
+		}
+		key := string(text[m[2]:m[3]])
+		out.WriteString(fmt.Sprintf(`<a class="work-item-key" href="%s">#%s</a>`, WorkItemKeyPath(key), key))
+		start = m[3]
+	}
+	if start < len(text) {
+		h.Renderer.NormalText(out, text[start:])
+	}
+}
+
 // BlackCode overrides the standard Html Renderer to add support for prettify of source code within block
 // If highlighter fail, normal Html.BlockCode is called
 func (h highlightHTMLRenderer) BlockCode(out *bytes.Buffer, text []byte, lang string) {
@@ -67,6 +67,93 @@ func TestRenderMarkdownContentWithCheckboxItems(t *testing.T) {
 	})
 }
 
+func TestRenderMarkdownContentWithAttachments(t *testing.T) {
+	defer func(contentPath func(string) string) {
+		rendering.AttachmentContentPath = contentPath
+	}(rendering.AttachmentContentPath)
+	rendering.AttachmentContentPath = func(attachmentID string) string {
+		return "/api/attachments/" + attachmentID + "/content"
+	}
+	t.Run("inline image", func(t *testing.T) {
+		content := "See ![screenshot](attachment:40bbdd3d-8b5d-4fd6-ac90-7236b669af04)"
+		result := rendering.RenderMarkupToHTML(content, rendering.SystemMarkupMarkdown)
+		t.Log(result)
+		assert.Contains(t, result, `<img src="/api/attachments/40bbdd3d-8b5d-4fd6-ac90-7236b669af04/content" alt="screenshot"`)
+	})
+	t.Run("link", func(t *testing.T) {
+		content := "See [the log](attachment:40bbdd3d-8b5d-4fd6-ac90-7236b669af04)"
+		result := rendering.RenderMarkupToHTML(content, rendering.SystemMarkupMarkdown)
+		t.Log(result)
+		assert.Contains(t, result, `href="/api/attachments/40bbdd3d-8b5d-4fd6-ac90-7236b669af04/content"`)
+	})
+	t.Run("other images are unchanged", func(t *testing.T) {
+		content := "![logo](https://example.com/logo.png)"
+		result := rendering.RenderMarkupToHTML(content, rendering.SystemMarkupMarkdown)
+		t.Log(result)
+		assert.Contains(t, result, `<img src="https://example.com/logo.png" alt="logo"`)
+	})
+	t.Run("references are not resolved without a content path", func(t *testing.T) {
+		rendering.AttachmentContentPath = nil
+		content := "See ![screenshot](attachment:40bbdd3d-8b5d-4fd6-ac90-7236b669af04)"
+		result := rendering.RenderMarkupToHTML(content, rendering.SystemMarkupMarkdown)
+		t.Log(result)
+		assert.NotContains(t, result, "/content")
+	})
+}
+
+func TestRenderMarkdownContentWithWorkItemKeys(t *testing.T) {
+	defer func(keyPath func(string) string) {
+		rendering.WorkItemKeyPath = keyPath
+	}(rendering.WorkItemKeyPath)
+	rendering.WorkItemKeyPath = func(key string) string {
+		return "/api/workitemkeys/" + key
+	}
+	t.Run("key", func(t *testing.T) {
+		content := "Duplicate of #PLAT-123."
+		result := rendering.RenderMarkupToHTML(content, rendering.SystemMarkupMarkdown)
+		t.Log(result)
+		assert.Contains(t, result, `Duplicate of <a class="work-item-key" href="/api/workitemkeys/PLAT-123">#PLAT-123</a>.`)
+	})
+	t.Run("several keys", func(t *testing.T) {
+		content := "#PLAT-1 blocks #OPS-2"
+		result := rendering.RenderMarkupToHTML(content, rendering.SystemMarkupMarkdown)
+		t.Log(result)
+		assert.Contains(t, result, `<a class="work-item-key" href="/api/workitemkeys/PLAT-1">#PLAT-1</a> blocks <a class="work-item-key" href="/api/workitemkeys/OPS-2">#OPS-2</a>`)
+	})
+	t.Run("keys without a leading # are unchanged", func(t *testing.T) {
+		content := "Convert the file to UTF-8 according to ISO-9001 and PLAT-123"
+		result := rendering.RenderMarkupToHTML(content, rendering.SystemMarkupMarkdown)
+		t.Log(result)
+		assert.NotContains(t, result, "work-item-key")
+	})
+	t.Run("# inside a word is unchanged", func(t *testing.T) {
+		content := "see page#PLAT-123"
+		result := rendering.RenderMarkupToHTML(content, rendering.SystemMarkupMarkdown)
+		t.Log(result)
+		assert.NotContains(t, result, "work-item-key")
+	})
+	t.Run("key in link", func(t *testing.T) {
+		content := "See [#PLAT-123](https://example.com)"
+		result := rendering.RenderMarkupToHTML(content, rendering.SystemMarkupMarkdown)
+		t.Log(result)
+		assert.NotContains(t, result, "work-item-key")
+		assert.Contains(t, result, ">#PLAT-123</a>")
+	})
+	t.Run("key in code", func(t *testing.T) {
+		content := "Run `#PLAT-123`"
+		result := rendering.RenderMarkupToHTML(content, rendering.SystemMarkupMarkdown)
+		t.Log(result)
+		assert.NotContains(t, result, "work-item-key")
+	})
+	t.Run("keys are not linked without a key path", func(t *testing.T) {
+		rendering.WorkItemKeyPath = nil
+		content := "Duplicate of #PLAT-123."
+		result := rendering.RenderMarkupToHTML(content, rendering.SystemMarkupMarkdown)
+		t.Log(result)
+		assert.NotContains(t, result, "work-item-key")
+	})
+}
+
 func TestIsMarkupSupported(t *testing.T) {
 	assert.True(t, rendering.IsMarkupSupported(rendering.SystemMarkupDefault))
 	assert.True(t, rendering.IsMarkupSupported(rendering.SystemMarkupPlainText))
@@ -0,0 +1,44 @@
+package rest
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+// ValidWebhookSignature returns true if the webhook request with the given
+// headers and body carries the given secret. Github requests are signed with
+// an HMAC of the body in the "X-Hub-Signature-256" header, or in the legacy
+// "X-Hub-Signature" header, and GitLab requests carry the secret in the
+// "X-Gitlab-Token" header.
+func ValidWebhookSignature(header http.Header, secret, body []byte) bool {
+	if len(secret) == 0 {
+		return false
+	}
+	switch {
+	case header.Get("X-Hub-Signature-256") != "":
+		return validHMAC(sha256.New, secret, body, strings.TrimPrefix(header.Get("X-Hub-Signature-256"), "sha256="))
+	case header.Get("X-Hub-Signature") != "":
+		return validHMAC(sha1.New, secret, body, strings.TrimPrefix(header.Get("X-Hub-Signature"), "sha1="))
+	case header.Get("X-Gitlab-Token") != "":
+		return subtle.ConstantTimeCompare([]byte(header.Get("X-Gitlab-Token")), secret) == 1
+	}
+	return false
+}
+
+// validHMAC returns true if the given hex encoded signature is the HMAC of
+// the body
+func validHMAC(h func() hash.Hash, secret, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(h, secret)
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
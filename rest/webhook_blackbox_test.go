@@ -0,0 +1,46 @@
+package rest_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidWebhookSignature(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	secret := []byte("s3cr3t")
+	body := []byte(`{"action":"edited"}`)
+	sign := func(h func() hash.Hash, key []byte) string {
+		mac := hmac.New(h, key)
+		mac.Write(body)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	for name, td := range map[string]struct {
+		header   http.Header
+		secret   []byte
+		expected bool
+	}{
+		"github sha256":              {http.Header{"X-Hub-Signature-256": {"sha256=" + sign(sha256.New, secret)}}, secret, true},
+		"github sha1":                {http.Header{"X-Hub-Signature": {"sha1=" + sign(sha1.New, secret)}}, secret, true},
+		"github wrong secret":        {http.Header{"X-Hub-Signature-256": {"sha256=" + sign(sha256.New, []byte("guess"))}}, secret, false},
+		"github invalid signature":   {http.Header{"X-Hub-Signature-256": {"sha256=xyz"}}, secret, false},
+		"gitlab":                     {http.Header{"X-Gitlab-Token": {"s3cr3t"}}, secret, true},
+		"gitlab wrong token":         {http.Header{"X-Gitlab-Token": {"guess"}}, secret, false},
+		"no signature":               {http.Header{}, secret, false},
+		"no secret":                  {http.Header{"X-Gitlab-Token": {""}}, nil, false},
+		"sha256 preferred over sha1": {http.Header{"X-Hub-Signature-256": {"sha256=xyz"}, "X-Hub-Signature": {"sha1=" + sign(sha1.New, secret)}}, secret, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, td.expected, rest.ValidWebhookSignature(td.header, td.secret, body))
+		})
+	}
+}
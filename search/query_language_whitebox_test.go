@@ -416,6 +416,21 @@ func TestGenerateExpression(t *testing.T) {
 		)
 		expectEqualExpr(t, expectedExpr, actualExpr)
 	})
+	t.Run("Equals custom field (top-level)", func(t *testing.T) {
+		t.Parallel()
+		// given
+		wiID := "f7a4a6a6-7e5c-4bc4-9a7c-5c5e5a5e5a5e"
+		q := Query{Name: CustomFieldKeyPrefix + "duplicate_of", Value: &wiID}
+		// when
+		actualExpr, err := q.generateExpression()
+		// then
+		require.NoError(t, err)
+		expectedExpr := c.Or(
+			c.Equals(c.Field("duplicate_of"), c.Literal(wiID)),
+			c.Equals(c.Field("duplicate_of"), c.Literal([]string{wiID})),
+		)
+		expectEqualExpr(t, expectedExpr, actualExpr)
+	})
 	t.Run(AND, func(t *testing.T) {
 		t.Parallel()
 		// given
@@ -28,6 +28,9 @@ import (
 const (
 	HostRegistrationKeyForListWI  = "work-item-list-details"
 	HostRegistrationKeyForBoardWI = "work-item-board-details"
+	// HostRegistrationKeyForWorkItemKey is the known URL of the endpoint that
+	// resolves work item keys. Its pattern must have a "key" group.
+	HostRegistrationKeyForWorkItemKey = "work-item-key"
 
 	EQ     = "$EQ"
 	NE     = "$NE"
@@ -65,6 +68,14 @@ type searchKeyword struct {
 	workItemTypes []uuid.UUID
 	number        []string
 	words         []string
+	workItemKeys  []workItemKey
+}
+
+// workItemKey is a human-readable work item key (e.g. "PLAT-123") found in a
+// search string
+type workItemKey struct {
+	spaceKey string
+	number   int
 }
 
 // KnownURL has a regex string format URL and compiled regex for the same
@@ -192,6 +203,35 @@ func getSearchQueryFromURLPattern(patternName, stringToMatch string) string {
 	return match[0] + ":*"
 }
 
+// workItemKeyFromURL returns the work item key (e.g. "PLAT-123") in the given
+// URL if it matches the known URL of the work item key resolver endpoint
+func workItemKeyFromURL(url string) string {
+	knownURLLock.RLock()
+	defer knownURLLock.RUnlock()
+	known, ok := knownURLs[HostRegistrationKeyForWorkItemKey]
+	if !ok {
+		return ""
+	}
+	match := known.compiledRegex.FindStringSubmatch(url)
+	for i, name := range known.groupNamesInRegex {
+		if name == "key" && i < len(match) {
+			return match[i]
+		}
+	}
+	return ""
+}
+
+// appendWorkItemKey restricts the search to the work item with the given key
+// (e.g. "PLAT-123") and returns false if the key is malformed
+func (res *searchKeyword) appendWorkItemKey(key string) bool {
+	spaceKey, number, err := workitem.ParseKey(key)
+	if err != nil {
+		return false
+	}
+	res.workItemKeys = append(res.workItemKeys, workItemKey{spaceKey: spaceKey, number: number})
+	return true
+}
+
 /*
 getSearchQueryFromURLString gets a url string and checks if that matches with any of known urls.
 Respectively it will return a string that can be directly used in search query
@@ -248,10 +288,19 @@ func parseSearchString(ctx context.Context, rawSearchString string) (searchKeywo
 				return res, errors.NewBadParameterError("failed to parse type ID string as UUID", typeIDStr)
 			}
 			res.workItemTypes = append(res.workItemTypes, typeID)
+		} else if part == strings.ToUpper(part) && workitem.KeyRegexp.MatchString(part) {
+			// work item keys (e.g. "PLAT-123") are only recognized in upper
+			// case to avoid mistaking hyphenated words like "utf-8" for them
+			log.Debug(ctx, map[string]interface{}{"key": part}, "found a work item key in the query string")
+			res.appendWorkItemKey(part)
 		} else if govalidator.IsURL(part) {
 			log.Debug(ctx, map[string]interface{}{"url": part}, "found a URL in the query string")
 			part := strings.ToLower(part)
 			part = trimProtocolFromURLString(part)
+			if key := workItemKeyFromURL(part); key != "" && res.appendWorkItemKey(key) {
+				log.Debug(ctx, map[string]interface{}{"url": part, "key": key}, "found a work item key URL in the query string")
+				continue
+			}
 			searchQueryFromURL := getSearchQueryFromURLString(part)
 			log.Debug(ctx, map[string]interface{}{"url": part, "search_query": searchQueryFromURL}, "found a URL in the query string")
 			res.words = append(res.words, searchQueryFromURL)
@@ -413,6 +462,34 @@ var searchKeyMap = map[string]string{
 	"number":       "Number",
 }
 
+// CustomFieldKeyPrefix allows to filter by any field of a work item that has
+// no dedicated search key, e.g. {"fields.duplicate_of": "<work item ID>"}.
+// The value matches single value fields as well as list fields that contain
+// the value.
+const CustomFieldKeyPrefix = "fields."
+
+// customFieldName returns the name of the work item field referenced by the
+// given search key if it has the CustomFieldKeyPrefix
+func customFieldName(key string) (string, bool) {
+	if !strings.HasPrefix(key, CustomFieldKeyPrefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(key, CustomFieldKeyPrefix)
+	if name == "" {
+		return "", false
+	}
+	return name, true
+}
+
+// customFieldEquals matches work items whose field equals the given value or
+// whose list field contains it
+func customFieldEquals(left criteria.Expression, val string) criteria.Expression {
+	return criteria.Or(
+		criteria.Equals(left, criteria.Literal(val)),
+		criteria.Equals(left, criteria.Literal([]string{val})),
+	)
+}
+
 func (q Query) determineLiteralType(key string, val string) criteria.Expression {
 	switch key {
 	case workitem.SystemAssignees, workitem.SystemLabels, workitem.SystemBoardcolumns, workitem.SystemBoard:
@@ -438,7 +515,10 @@ func (q Query) generateExpression() (criteria.Expression, error) {
 				break
 			}
 		}
-		if !ok && !handledByJoin {
+		fieldName, isCustomField := customFieldName(q.Name)
+		if isCustomField && !ok && !handledByJoin {
+			key = fieldName
+		} else if !ok && !handledByJoin {
 			return nil, errors.NewBadParameterError("key not found", q.Name)
 		}
 		left := criteria.Field(key)
@@ -452,6 +532,8 @@ func (q Query) generateExpression() (criteria.Expression, error) {
 				} else {
 					if q.Child {
 						myexpr = append(myexpr, criteria.Child(left, right))
+					} else if isCustomField {
+						myexpr = append(myexpr, customFieldEquals(left, *q.Value))
 					} else {
 						myexpr = append(myexpr, criteria.Equals(left, right))
 					}
@@ -483,7 +565,10 @@ func (q Query) generateExpression() (criteria.Expression, error) {
 					break
 				}
 			}
-			if !ok && !handledByJoin {
+			fieldName, isCustomField := customFieldName(child.Name)
+			if isCustomField && !ok && !handledByJoin {
+				key = fieldName
+			} else if !ok && !handledByJoin {
 				return nil, errors.NewBadParameterError("key not found", child.Name)
 			}
 			left := criteria.Field(key)
@@ -497,6 +582,8 @@ func (q Query) generateExpression() (criteria.Expression, error) {
 					} else {
 						if child.Child {
 							myexpr = append(myexpr, criteria.Child(left, right))
+						} else if isCustomField {
+							myexpr = append(myexpr, customFieldEquals(left, *child.Value))
 						} else {
 							myexpr = append(myexpr, criteria.Equals(left, right))
 						}
@@ -577,8 +664,13 @@ func generateSQLSearchInfo(keywords searchKeyword) (sqlParameter string) {
 
 // extracted this function from List() in order to close the rows object with "defer" for more readability
 // workaround for https://github.com/lib/pq/issues/81
-func (r *GormSearchRepository) search(ctx context.Context, sqlSearchQueryParameter string, workItemTypes []uuid.UUID, start *int, limit *int, spaceID *string) ([]workitem.WorkItemStorage, int, error) {
-	db := r.db.Model(workitem.WorkItemStorage{}).Where("tsv @@ query")
+func (r *GormSearchRepository) search(ctx context.Context, sqlSearchQueryParameter string, workItemTypes []uuid.UUID, workItemKeys []workItemKey, start *int, limit *int, spaceID *string) ([]workitem.WorkItemStorage, int, error) {
+	// a search for work item keys alone doesn't need the full text search
+	fullText := sqlSearchQueryParameter != "" || len(workItemKeys) == 0
+	db := r.db.Model(workitem.WorkItemStorage{})
+	if fullText {
+		db = db.Where("tsv @@ query")
+	}
 	if start != nil {
 		if *start < 0 {
 			return nil, 0, errors.NewBadParameterError("start", *start)
@@ -601,11 +693,30 @@ func (r *GormSearchRepository) search(ctx context.Context, sqlSearchQueryParamet
 	}
 
 	db = db.Select("count(*) over () as cnt2 , *").Order(workitem.Column(workitem.WorkItemStorage{}.TableName(), "execution_order") + " desc")
-	db = db.Joins(", to_tsquery('english', ?) as query, ts_rank(tsv, query) as rank", sqlSearchQueryParameter)
+	if fullText {
+		db = db.Joins(", to_tsquery('english', ?) as query, ts_rank(tsv, query) as rank", sqlSearchQueryParameter)
+	}
 	if spaceID != nil {
 		db = db.Where("space_id=?", *spaceID)
 	}
-	db = db.Order(fmt.Sprintf("rank desc,%s.updated_at desc", workitem.WorkItemStorage{}.TableName()))
+	if len(workItemKeys) > 0 {
+		// restrict to the work items with the given numbers, each in the space
+		// with the current or former key that belongs to the number
+		conditions := make([]string, len(workItemKeys))
+		args := []interface{}{}
+		for i, key := range workItemKeys {
+			conditions[i] = "(" + workitem.Column(workitem.WorkItemStorage{}.TableName(), "number") + " = ? and space_id in (" +
+				"select id from spaces where key = ? and deleted_at is null " +
+				"union select space_id from space_key_aliases where key = ?))"
+			args = append(args, key.number, key.spaceKey, key.spaceKey)
+		}
+		db = db.Where(strings.Join(conditions, " or "), args...)
+	}
+	if fullText {
+		db = db.Order(fmt.Sprintf("rank desc,%s.updated_at desc", workitem.WorkItemStorage{}.TableName()))
+	} else {
+		db = db.Order(fmt.Sprintf("%s.updated_at desc", workitem.WorkItemStorage{}.TableName()))
+	}
 
 	rows, err := db.Rows()
 	defer closeable.Close(ctx, rows)
@@ -670,7 +781,7 @@ func (r *GormSearchRepository) SearchFullText(ctx context.Context, rawSearchStri
 	sqlSearchQueryParameter := generateSQLSearchInfo(parsedSearchDict)
 	var rows []workitem.WorkItemStorage
 	log.Debug(ctx, map[string]interface{}{"search query": sqlSearchQueryParameter}, "searching for work items")
-	rows, count, err := r.search(ctx, sqlSearchQueryParameter, parsedSearchDict.workItemTypes, start, limit, spaceID)
+	rows, count, err := r.search(ctx, sqlSearchQueryParameter, parsedSearchDict.workItemTypes, parsedSearchDict.workItemKeys, start, limit, spaceID)
 	if err != nil {
 		return nil, 0, errs.WithStack(err)
 	}
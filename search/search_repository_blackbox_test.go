@@ -497,6 +497,44 @@ func (s *searchRepositoryBlackboxTest) TestSearchBoardID() {
 	})
 }
 
+func (s *searchRepositoryBlackboxTest) TestSearchWorkItemKeys() {
+	// given two spaces with two work items each
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.Spaces(2, func(fxt *tf.TestFixture, idx int) error {
+			fxt.Spaces[idx].Key = []string{"SRCHA", "SRCHB"}[idx]
+			return nil
+		}),
+		tf.WorkItems(4, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].SpaceID = fxt.Spaces[idx/2].ID
+			fxt.WorkItems[idx].Fields[workitem.SystemTitle] = "key search"
+			return nil
+		}),
+	)
+	s.T().Run("single key", func(t *testing.T) {
+		// when
+		res, count, err := s.searchRepo.SearchFullText(context.Background(), "SRCHB-1", nil, nil, nil)
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+		assert.Equal(t, fxt.WorkItems[2].ID, res[0].ID)
+	})
+	s.T().Run("each number belongs to its own space key", func(t *testing.T) {
+		// when
+		res, count, err := s.searchRepo.SearchFullText(context.Background(), "SRCHA-1 SRCHB-2", nil, nil, nil)
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+		assert.Condition(t, containsAllWorkItems(res, *fxt.WorkItems[0], *fxt.WorkItems[3]))
+	})
+	s.T().Run("key and words", func(t *testing.T) {
+		// when
+		_, count, err := s.searchRepo.SearchFullText(context.Background(), "SRCHA-2 unknownword", nil, nil, nil)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+}
+
 func (s *searchRepositoryBlackboxTest) TestSearchFullText() {
 
 	s.T().Run("Filter by title", func(t *testing.T) {
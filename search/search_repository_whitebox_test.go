@@ -16,6 +16,7 @@ func init() {
 	// Please do not include trailing slashes because it will be removed before scanning starts
 	RegisterAsKnownURL("test-work-item-list-details", `(?P<domain>demo.openshift.io)(?P<path>/work-item/list/detail/)(?P<id>\d*)`)
 	RegisterAsKnownURL("test-work-item-board-details", `(?P<domain>demo.openshift.io)(?P<path>/work-item/board/detail/)(?P<id>\d*)`)
+	RegisterAsKnownURL(HostRegistrationKeyForWorkItemKey, `(?P<domain>demo.openshift.io)(?P<path>/api/workitemkeys/)(?P<key>[A-Za-z][A-Za-z0-9]{1,9}-\d+)`)
 }
 
 func TestGenerateSQLSearchStringText(t *testing.T) {
@@ -135,6 +136,35 @@ func TestParseSearchStringCombination(t *testing.T) {
 	assert.True(t, assert.ObjectsAreEqualValues(expectedSearchRes, op))
 }
 
+func TestParseSearchStringWorkItemKey(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	inputSet := []searchTestData{{
+		query: "PLAT-123",
+		expected: searchKeyword{
+			workItemKeys: []workItemKey{{spaceKey: "PLAT", number: 123}},
+		},
+	}, {
+		query: "http://demo.openshift.io/api/workitemkeys/PLAT-123",
+		expected: searchKeyword{
+			workItemKeys: []workItemKey{{spaceKey: "PLAT", number: 123}},
+		},
+	}, {
+		query: "PLAT-123 OPS-7",
+		expected: searchKeyword{
+			workItemKeys: []workItemKey{{spaceKey: "PLAT", number: 123}, {spaceKey: "OPS", number: 7}},
+		},
+	}}
+
+	for _, input := range inputSet {
+		op, _ := parseSearchString(context.Background(), input.query)
+		assert.Equal(t, input.expected, op)
+	}
+	// hyphenated words in lower case are not mistaken for keys
+	op, _ := parseSearchString(context.Background(), "utf-8")
+	assert.Empty(t, op.workItemKeys)
+}
+
 func TestRegisterAsKnownURL(t *testing.T) {
 	resource.Require(t, resource.UnitTest)
 	// build 2 fake urls and cross check against RegisterAsKnownURL
@@ -3,6 +3,7 @@ package space
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -26,6 +27,10 @@ var (
 	SpaceType   = "spaces"
 )
 
+// keyRegexp matches valid space keys: an upper case letter followed by 1 to 9
+// upper case letters or digits, e.g. "PLAT"
+var keyRegexp = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
 // Space represents a Space on the domain and db layer
 type Space struct {
 	gormsupport.Lifecycle
@@ -35,6 +40,9 @@ type Space struct {
 	Description     string
 	OwnerID         uuid.UUID `sql:"type:uuid"` // Belongs To Identity
 	SpaceTemplateID uuid.UUID `sql:"type:uuid"`
+	// Key is the optional short key of the space (e.g. "PLAT") that makes its
+	// work items addressable as PLAT-123
+	Key string
 }
 
 // Ensure Fields implements the Equaler interface
@@ -68,6 +76,9 @@ func (p Space) Equal(u convert.Equaler) bool {
 	if !uuid.Equal(p.OwnerID, other.OwnerID) {
 		return false
 	}
+	if p.Key != other.Key {
+		return false
+	}
 	return true
 }
 
@@ -98,6 +109,19 @@ func (p Space) TableName() string {
 	return "spaces"
 }
 
+// keyAlias is a former key of a space
+type keyAlias struct {
+	Key       string    `gorm:"primary_key"`
+	SpaceID   uuid.UUID `sql:"type:uuid"`
+	CreatedAt time.Time
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (a keyAlias) TableName() string {
+	return "space_key_aliases"
+}
+
 // Repository encapsulate storage & retrieval of spaces
 type Repository interface {
 	repository.Exister
@@ -108,6 +132,7 @@ type Repository interface {
 	Delete(ctx context.Context, ID uuid.UUID) error
 	LoadByOwner(ctx context.Context, userID *uuid.UUID, start *int, length *int) ([]Space, int, error)
 	LoadByOwnerAndName(ctx context.Context, userID *uuid.UUID, spaceName *string) (*Space, error)
+	LoadByKey(ctx context.Context, key string) (*Space, error)
 	List(ctx context.Context, start *int, length *int) ([]Space, int, error)
 	Search(ctx context.Context, q *string, start *int, length *int) ([]Space, int, error)
 }
@@ -244,6 +269,14 @@ func (r *GormRepository) Save(ctx context.Context, p *Space) (*Space, error) {
 		}, "unable to find the space by ID")
 		return nil, errors.NewInternalError(ctx, err)
 	}
+	if p.Key != pr.Key {
+		if err := r.checkKey(ctx, p); err != nil {
+			return nil, err
+		}
+		if err := r.renameKey(ctx, p.ID, pr.Key, p.Key); err != nil {
+			return nil, err
+		}
+	}
 	tx = tx.Where("Version = ?", oldVersion).Save(p)
 	if err := tx.Error; err != nil {
 		if gormsupport.IsCheckViolation(tx.Error, "spaces_name_check") {
@@ -252,6 +285,9 @@ func (r *GormRepository) Save(ctx context.Context, p *Space) (*Space, error) {
 		if gormsupport.IsUniqueViolation(tx.Error, "spaces_name_idx") {
 			return nil, errors.NewBadParameterError("Name", p.Name).Expected("unique")
 		}
+		if gormsupport.IsUniqueViolation(tx.Error, "uix_spaces_key") {
+			return nil, errors.NewBadParameterError("Key", p.Key).Expected("unique")
+		}
 		log.Error(ctx, map[string]interface{}{
 			"err":      err,
 			"version":  oldVersion,
@@ -287,6 +323,11 @@ func (r *GormRepository) Create(ctx context.Context, space *Space) (*Space, erro
 	if !templ.CanConstruct {
 		return nil, errors.NewForbiddenError(fmt.Sprintf("space template %q (ID: %s) cannot create spaces", templ.Name, templ.ID))
 	}
+	if space.Key != "" {
+		if err := r.checkKey(ctx, space); err != nil {
+			return nil, err
+		}
+	}
 
 	tx := r.db.Create(space)
 	if err := tx.Error; err != nil {
@@ -300,6 +341,9 @@ func (r *GormRepository) Create(ctx context.Context, space *Space) (*Space, erro
 			}, "unable to create space because a space with the same name already exists for this user")
 			return nil, errors.NewDataConflictError(fmt.Sprintf("space already exists ( for this user ) : %s ", space.Name))
 		}
+		if gormsupport.IsUniqueViolation(tx.Error, "uix_spaces_key") {
+			return nil, errors.NewDataConflictError(fmt.Sprintf("space key already in use: %s", space.Key))
+		}
 		return nil, errors.NewInternalError(ctx, err)
 	}
 	log.Debug(ctx, map[string]interface{}{
@@ -445,3 +489,94 @@ func (r *GormRepository) LoadByOwnerAndName(ctx context.Context, userID *uuid.UU
 	}
 	return &res, nil
 }
+
+// checkKey returns a BadParameterError if the key of the given space is
+// malformed or is the current or former key of another space
+func (r *GormRepository) checkKey(ctx context.Context, s *Space) error {
+	if s.Key == "" {
+		return nil
+	}
+	if !keyRegexp.MatchString(s.Key) {
+		return errors.NewBadParameterError("Key", s.Key).Expected(keyRegexp.String())
+	}
+	var count int
+	err := r.db.Raw(`SELECT count(*) FROM (
+		SELECT id FROM spaces WHERE key = $1 AND id <> $2 AND deleted_at IS NULL
+		UNION ALL
+		SELECT space_id FROM space_key_aliases WHERE key = $1 AND space_id <> $2
+	) AS keys`, s.Key, s.ID).Row().Scan(&count)
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":       err,
+			"space_key": s.Key,
+		}, "unable to check the space key")
+		return errors.NewInternalError(ctx, err)
+	}
+	if count > 0 {
+		return errors.NewBadParameterError("Key", s.Key).Expected("unique")
+	}
+	return nil
+}
+
+// renameKey records the old key of a space as an alias so that the keys of
+// its work items remain resolvable. A space taking back one of its former
+// keys drops the matching alias.
+func (r *GormRepository) renameKey(ctx context.Context, spaceID uuid.UUID, oldKey, newKey string) error {
+	if newKey != "" {
+		db := r.db.Where("key = ? AND space_id = ?", newKey, spaceID).Delete(&keyAlias{})
+		if db.Error != nil {
+			log.Error(ctx, map[string]interface{}{
+				"err":       db.Error,
+				"space_id":  spaceID,
+				"space_key": newKey,
+			}, "unable to delete the space key alias")
+			return errors.NewInternalError(ctx, db.Error)
+		}
+	}
+	if oldKey == "" {
+		return nil
+	}
+	db := r.db.Create(&keyAlias{Key: oldKey, SpaceID: spaceID})
+	if db.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":       db.Error,
+			"space_id":  spaceID,
+			"space_key": oldKey,
+		}, "unable to create the space key alias")
+		return errors.NewInternalError(ctx, db.Error)
+	}
+	return nil
+}
+
+// LoadByKey returns the space with the given key (case insensitive). Spaces
+// are also found by their former keys.
+// returns NotFoundError or InternalError
+func (r *GormRepository) LoadByKey(ctx context.Context, key string) (*Space, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "space", "loadByKey"}, time.Now())
+	key = strings.ToUpper(key)
+	res := Space{}
+	tx := r.db.Where("key = ?", key).First(&res)
+	if tx.RecordNotFound() {
+		alias := keyAlias{}
+		aliasTx := r.db.Where("key = ?", key).First(&alias)
+		if aliasTx.RecordNotFound() {
+			return nil, errors.NewNotFoundError("space", key)
+		}
+		if aliasTx.Error != nil {
+			log.Error(ctx, map[string]interface{}{
+				"err":       aliasTx.Error,
+				"space_key": key,
+			}, "unable to load the space key alias")
+			return nil, errors.NewInternalError(ctx, aliasTx.Error)
+		}
+		return r.Load(ctx, alias.SpaceID)
+	}
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":       tx.Error,
+			"space_key": key,
+		}, "unable to load the space by key")
+		return nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	return &res, nil
+}
@@ -3,6 +3,7 @@ package space_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/space"
 	testsupport "github.com/fabric8-services/fabric8-wit/test"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -292,6 +294,77 @@ func (s *SpaceRepositoryTestSuite) TestLoadByOwnerAndName() {
 	})
 }
 
+// randomKey returns a random valid space key
+func randomKey() string {
+	return "K" + strings.ToUpper(uuid.NewV4().String()[:8])
+}
+
+func (s *SpaceRepositoryTestSuite) TestKey() {
+	s.T().Run("load by key", func(t *testing.T) {
+		// given a space with a key
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		fxt.Spaces[0].Key = randomKey()
+		_, err := s.repo.Save(s.Ctx, fxt.Spaces[0])
+		require.NoError(t, err)
+		// when
+		sp, err := s.repo.LoadByKey(s.Ctx, strings.ToLower(fxt.Spaces[0].Key))
+		// then
+		require.NoError(t, err)
+		require.Equal(t, fxt.Spaces[0].ID, sp.ID)
+	})
+	s.T().Run("load by former key", func(t *testing.T) {
+		// given a space whose key was renamed
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		oldKey := randomKey()
+		fxt.Spaces[0].Key = oldKey
+		sp, err := s.repo.Save(s.Ctx, fxt.Spaces[0])
+		require.NoError(t, err)
+		sp.Key = randomKey()
+		_, err = s.repo.Save(s.Ctx, sp)
+		require.NoError(t, err)
+		// when
+		loaded, err := s.repo.LoadByKey(s.Ctx, oldKey)
+		// then
+		require.NoError(t, err)
+		require.Equal(t, fxt.Spaces[0].ID, loaded.ID)
+		require.Equal(t, sp.Key, loaded.Key)
+	})
+	s.T().Run("fail - former key of another space", func(t *testing.T) {
+		// given a space whose key was renamed
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2))
+		oldKey := randomKey()
+		fxt.Spaces[0].Key = oldKey
+		sp, err := s.repo.Save(s.Ctx, fxt.Spaces[0])
+		require.NoError(t, err)
+		sp.Key = randomKey()
+		_, err = s.repo.Save(s.Ctx, sp)
+		require.NoError(t, err)
+		// when using the former key for another space
+		fxt.Spaces[1].Key = oldKey
+		_, err = s.repo.Save(s.Ctx, fxt.Spaces[1])
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err), "error was %v", err)
+	})
+	s.T().Run("fail - invalid key", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		// when
+		fxt.Spaces[0].Key = "plat-1"
+		_, err := s.repo.Save(s.Ctx, fxt.Spaces[0])
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err), "error was %v", err)
+	})
+	s.T().Run("not found", func(t *testing.T) {
+		// when
+		_, err := s.repo.LoadByKey(s.Ctx, randomKey())
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err), "error was %v", err)
+	})
+}
+
 func (s *SpaceRepositoryTestSuite) TestLoadMany() {
 
 	s.T().Run("ok", func(t *testing.T) {
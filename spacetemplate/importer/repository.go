@@ -145,6 +145,7 @@ func (r *GormRepository) createOrUpdateWITs(ctx context.Context, s *ImportHelper
 			loadedWIT.Description = wit.Description
 			loadedWIT.Icon = wit.Icon
 			loadedWIT.CanConstruct = wit.CanConstruct
+			loadedWIT.Transitions = wit.Transitions
 
 			//------------------------------------------------------------------
 			// Double check all fields from the old work item type are still
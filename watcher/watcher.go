@@ -0,0 +1,70 @@
+// Package watcher contains the operations to manage who is interested in the
+// changes of work items, spaces, areas and iterations and which notification
+// messages each user wants to receive.
+package watcher
+
+import (
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	uuid "github.com/satori/go.uuid"
+)
+
+// TargetType is the type of entity that can be watched
+type TargetType string
+
+// The types of entities that can be watched
+const (
+	TargetWorkItem  TargetType = "workitem"
+	TargetSpace     TargetType = "space"
+	TargetArea      TargetType = "area"
+	TargetIteration TargetType = "iteration"
+)
+
+// tableName returns the name of the table in which the watched entities are
+// stored or an empty string if the target type is unknown
+func (t TargetType) tableName() string {
+	switch t {
+	case TargetWorkItem:
+		return "work_items"
+	case TargetSpace:
+		return "spaces"
+	case TargetArea:
+		return "areas"
+	case TargetIteration:
+		return "iterations"
+	}
+	return ""
+}
+
+// Watcher records that an identity wants to be notified about changes of a
+// work item or of the work items in a space, area or iteration
+type Watcher struct {
+	gormsupport.Lifecycle
+	ID         uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
+	IdentityID uuid.UUID `sql:"type:uuid"`
+	TargetType TargetType
+	TargetID   uuid.UUID `sql:"type:uuid"`
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (w Watcher) TableName() string {
+	return "watchers"
+}
+
+// Preference records if an identity wants to receive notification messages
+// of a given type
+type Preference struct {
+	IdentityID  uuid.UUID `sql:"type:uuid" gorm:"primary_key"`
+	MessageType string    `gorm:"primary_key"`
+	Enabled     bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (p Preference) TableName() string {
+	return "notification_preferences"
+}
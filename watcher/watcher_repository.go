@@ -0,0 +1,212 @@
+package watcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/application/repository"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Repository describes interactions with watchers and notification
+// preferences
+type Repository interface {
+	// Watch makes the given identity watch the given target. Watching a
+	// target that is already watched is not an error.
+	Watch(ctx context.Context, targetType TargetType, targetID uuid.UUID, identityID uuid.UUID) error
+	// Unwatch stops the given identity from watching the given target
+	Unwatch(ctx context.Context, targetType TargetType, targetID uuid.UUID, identityID uuid.UUID) error
+	// List returns the identities watching the given target
+	List(ctx context.Context, targetType TargetType, targetID uuid.UUID) ([]uuid.UUID, error)
+	// Recipients returns the identities that want to receive a notification
+	// message of the given type about the given work item, i.e. those
+	// watching the work item or its space, area or iteration and who haven't
+	// disabled the message type.
+	Recipients(ctx context.Context, workItemID uuid.UUID, messageType string) ([]uuid.UUID, error)
+	// CommentRecipients is like Recipients for the work item of the given
+	// comment
+	CommentRecipients(ctx context.Context, commentID uuid.UUID, messageType string) ([]uuid.UUID, error)
+	// Preferences returns the message types that the given identity has
+	// enabled or disabled explicitly. Message types that are missing are
+	// enabled.
+	Preferences(ctx context.Context, identityID uuid.UUID) (map[string]bool, error)
+	// SetPreferences enables or disables the given message types for the
+	// given identity
+	SetPreferences(ctx context.Context, identityID uuid.UUID, preferences map[string]bool) error
+}
+
+// NewRepository creates a new storage type.
+func NewRepository(db *gorm.DB) Repository {
+	return &GormWatcherRepository{db: db}
+}
+
+// GormWatcherRepository is the implementation of the storage interface for
+// watchers.
+type GormWatcherRepository struct {
+	db *gorm.DB
+}
+
+func (r *GormWatcherRepository) checkTarget(ctx context.Context, targetType TargetType, targetID uuid.UUID) error {
+	tableName := targetType.tableName()
+	if tableName == "" {
+		return errors.NewBadParameterError("target type", targetType).Expected("workitem, space, area or iteration")
+	}
+	return repository.CheckExists(ctx, r.db, tableName, targetID)
+}
+
+// Watch implements Repository
+func (r *GormWatcherRepository) Watch(ctx context.Context, targetType TargetType, targetID uuid.UUID, identityID uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "watcher", "watch"}, time.Now())
+	if err := r.checkTarget(ctx, targetType, targetID); err != nil {
+		return err
+	}
+	if err := repository.CheckExists(ctx, r.db, "identities", identityID); err != nil {
+		return err
+	}
+	// concurrent requests may try to add the same watcher, so the unique
+	// index decides instead of a prior check
+	err := r.db.Exec(`
+		INSERT INTO watchers (id, identity_id, target_type, target_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, now(), now())
+		ON CONFLICT (target_type, target_id, identity_id) WHERE deleted_at IS NULL DO NOTHING`,
+		uuid.NewV4(), identityID, targetType, targetID).Error
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"target_type": targetType,
+			"target_id":   targetID,
+			"identity_id": identityID,
+			"err":         err,
+		}, "unable to create the watcher")
+		return errors.NewInternalError(ctx, errs.Wrap(err, "failed to create watcher"))
+	}
+	return nil
+}
+
+// Unwatch implements Repository
+func (r *GormWatcherRepository) Unwatch(ctx context.Context, targetType TargetType, targetID uuid.UUID, identityID uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "watcher", "unwatch"}, time.Now())
+	tx := r.db.Where("target_type = ? AND target_id = ? AND identity_id = ?", targetType, targetID, identityID).Delete(&Watcher{})
+	if err := tx.Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"target_type": targetType,
+			"target_id":   targetID,
+			"identity_id": identityID,
+			"err":         err,
+		}, "unable to delete the watcher")
+		return errors.NewInternalError(ctx, errs.Wrap(err, "failed to delete watcher"))
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("watcher", identityID.String())
+	}
+	return nil
+}
+
+// List implements Repository
+func (r *GormWatcherRepository) List(ctx context.Context, targetType TargetType, targetID uuid.UUID) ([]uuid.UUID, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "watcher", "list"}, time.Now())
+	if err := r.checkTarget(ctx, targetType, targetID); err != nil {
+		return nil, err
+	}
+	var watchers []Watcher
+	if err := r.db.Where("target_type = ? AND target_id = ?", targetType, targetID).Order("created_at, identity_id").Find(&watchers).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list watchers of %s %s", targetType, targetID))
+	}
+	result := make([]uuid.UUID, len(watchers))
+	for i, w := range watchers {
+		result[i] = w.IdentityID
+	}
+	return result, nil
+}
+
+// recipientsQuery selects the watchers of the work items matching the
+// condition appended to it that haven't disabled the message type given as
+// the first parameter.
+const recipientsQuery = `
+	SELECT DISTINCT w.identity_id
+	FROM watchers w, work_items wi
+	WHERE w.deleted_at IS NULL
+		AND wi.deleted_at IS NULL
+		AND (
+			(w.target_type = 'workitem' AND w.target_id = wi.id)
+			OR (w.target_type = 'space' AND w.target_id = wi.space_id)
+			OR (w.target_type = 'area' AND w.target_id::text = wi.fields->>'system.area')
+			OR (w.target_type = 'iteration' AND w.target_id::text = wi.fields->>'system.iteration')
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM notification_preferences p
+			WHERE p.identity_id = w.identity_id AND p.message_type = $1 AND NOT p.enabled
+		)
+		AND `
+
+// Recipients implements Repository
+func (r *GormWatcherRepository) Recipients(ctx context.Context, workItemID uuid.UUID, messageType string) ([]uuid.UUID, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "watcher", "recipients"}, time.Now())
+	return r.recipients(ctx, recipientsQuery+"wi.id = $2", messageType, workItemID)
+}
+
+// CommentRecipients implements Repository
+func (r *GormWatcherRepository) CommentRecipients(ctx context.Context, commentID uuid.UUID, messageType string) ([]uuid.UUID, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "watcher", "comment_recipients"}, time.Now())
+	return r.recipients(ctx, recipientsQuery+"wi.id = (SELECT parent_id FROM comments WHERE id = $2)", messageType, commentID)
+}
+
+func (r *GormWatcherRepository) recipients(ctx context.Context, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := r.db.Raw(query+" ORDER BY w.identity_id", args...).Rows()
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to resolve the recipients"))
+	}
+	defer rows.Close()
+	result := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to scan the recipients"))
+		}
+		result = append(result, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to resolve the recipients"))
+	}
+	return result, nil
+}
+
+// Preferences implements Repository
+func (r *GormWatcherRepository) Preferences(ctx context.Context, identityID uuid.UUID) (map[string]bool, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "watcher", "preferences"}, time.Now())
+	var preferences []Preference
+	if err := r.db.Where("identity_id = ?", identityID).Find(&preferences).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to load notification preferences of identity %s", identityID))
+	}
+	result := make(map[string]bool, len(preferences))
+	for _, p := range preferences {
+		result[p.MessageType] = p.Enabled
+	}
+	return result, nil
+}
+
+// SetPreferences implements Repository
+func (r *GormWatcherRepository) SetPreferences(ctx context.Context, identityID uuid.UUID, preferences map[string]bool) error {
+	defer goa.MeasureSince([]string{"goa", "db", "watcher", "set_preferences"}, time.Now())
+	for messageType, enabled := range preferences {
+		err := r.db.Exec(`
+			INSERT INTO notification_preferences (identity_id, message_type, enabled, created_at, updated_at)
+			VALUES ($1, $2, $3, now(), now())
+			ON CONFLICT (identity_id, message_type) DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = now()`,
+			identityID, messageType, enabled).Error
+		if err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"identity_id":  identityID,
+				"message_type": messageType,
+				"err":          err,
+			}, "unable to save the notification preference")
+			return errors.NewInternalError(ctx, errs.Wrap(err, "failed to save notification preference"))
+		}
+	}
+	return nil
+}
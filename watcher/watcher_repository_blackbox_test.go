@@ -0,0 +1,172 @@
+package watcher_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/watcher"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type watcherRepositoryBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	repo watcher.Repository
+}
+
+func TestRunWatcherRepositoryBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &watcherRepositoryBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *watcherRepositoryBlackBoxTest) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.repo = watcher.NewRepository(s.DB)
+}
+
+func (s *watcherRepositoryBlackBoxTest) TestWatch() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.Spaces(1))
+		// when
+		err := s.repo.Watch(s.Ctx, watcher.TargetSpace, fxt.Spaces[0].ID, fxt.Identities[1].ID)
+		// then
+		require.NoError(t, err)
+		watchers, err := s.repo.List(s.Ctx, watcher.TargetSpace, fxt.Spaces[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fxt.Identities[1].ID}, watchers)
+	})
+	s.T().Run("watching twice is ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.Areas(1))
+		require.NoError(t, s.repo.Watch(s.Ctx, watcher.TargetArea, fxt.Areas[0].ID, fxt.Identities[1].ID))
+		// when
+		err := s.repo.Watch(s.Ctx, watcher.TargetArea, fxt.Areas[0].ID, fxt.Identities[1].ID)
+		// then
+		require.NoError(t, err)
+		watchers, err := s.repo.List(s.Ctx, watcher.TargetArea, fxt.Areas[0].ID)
+		require.NoError(t, err)
+		assert.Len(t, watchers, 1)
+	})
+	s.T().Run("unknown target", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		// when
+		err := s.repo.Watch(s.Ctx, watcher.TargetIteration, uuid.NewV4(), fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+	s.T().Run("invalid target type", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1), tf.Spaces(1))
+		// when
+		err := s.repo.Watch(s.Ctx, watcher.TargetType("board"), fxt.Spaces[0].ID, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func (s *watcherRepositoryBlackBoxTest) TestUnwatch() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.Spaces(1))
+		require.NoError(t, s.repo.Watch(s.Ctx, watcher.TargetSpace, fxt.Spaces[0].ID, fxt.Identities[1].ID))
+		// when
+		err := s.repo.Unwatch(s.Ctx, watcher.TargetSpace, fxt.Spaces[0].ID, fxt.Identities[1].ID)
+		// then
+		require.NoError(t, err)
+		watchers, err := s.repo.List(s.Ctx, watcher.TargetSpace, fxt.Spaces[0].ID)
+		require.NoError(t, err)
+		assert.Empty(t, watchers)
+	})
+	s.T().Run("watch again", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.Spaces(1))
+		require.NoError(t, s.repo.Watch(s.Ctx, watcher.TargetSpace, fxt.Spaces[0].ID, fxt.Identities[1].ID))
+		require.NoError(t, s.repo.Unwatch(s.Ctx, watcher.TargetSpace, fxt.Spaces[0].ID, fxt.Identities[1].ID))
+		// when
+		err := s.repo.Watch(s.Ctx, watcher.TargetSpace, fxt.Spaces[0].ID, fxt.Identities[1].ID)
+		// then
+		require.NoError(t, err)
+		watchers, err := s.repo.List(s.Ctx, watcher.TargetSpace, fxt.Spaces[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fxt.Identities[1].ID}, watchers)
+	})
+	s.T().Run("not watching", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.Spaces(1))
+		// when
+		err := s.repo.Unwatch(s.Ctx, watcher.TargetSpace, fxt.Spaces[0].ID, fxt.Identities[1].ID)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *watcherRepositoryBlackBoxTest) TestAutomaticWatchers() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.Identities(3),
+		tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields[workitem.SystemAssignees] = []string{fxt.Identities[1].ID.String()}
+			return nil
+		}),
+		tf.Comments(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.Comments[idx].Creator = fxt.Identities[2].ID
+			return nil
+		}),
+	)
+	// when
+	watchers, err := s.repo.List(s.Ctx, watcher.TargetWorkItem, fxt.WorkItems[0].ID)
+	// then the creator, the assignee and the commenter watch the work item
+	require.NoError(s.T(), err)
+	assert.ElementsMatch(s.T(), []uuid.UUID{fxt.Identities[0].ID, fxt.Identities[1].ID, fxt.Identities[2].ID}, watchers)
+}
+
+func (s *watcherRepositoryBlackBoxTest) TestRecipients() {
+	// given a work item created by identity 0, a watcher of its space and a
+	// watcher of its iteration who doesn't want to know about updates
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.Identities(3),
+		tf.Iterations(1),
+		tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[0].ID.String()
+			return nil
+		}),
+		tf.Comments(1),
+	)
+	require.NoError(s.T(), s.repo.Watch(s.Ctx, watcher.TargetSpace, fxt.Spaces[0].ID, fxt.Identities[1].ID))
+	require.NoError(s.T(), s.repo.Watch(s.Ctx, watcher.TargetIteration, fxt.Iterations[0].ID, fxt.Identities[2].ID))
+	require.NoError(s.T(), s.repo.SetPreferences(s.Ctx, fxt.Identities[2].ID, map[string]bool{"workitem.update": false}))
+
+	s.T().Run("work item", func(t *testing.T) {
+		// when
+		recipients, err := s.repo.Recipients(s.Ctx, fxt.WorkItems[0].ID, "workitem.update")
+		// then
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{fxt.Identities[0].ID, fxt.Identities[1].ID}, recipients)
+	})
+	s.T().Run("comment", func(t *testing.T) {
+		// when
+		recipients, err := s.repo.CommentRecipients(s.Ctx, fxt.Comments[0].ID, "comment.create")
+		// then
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{fxt.Identities[0].ID, fxt.Identities[1].ID, fxt.Identities[2].ID}, recipients)
+	})
+	s.T().Run("preferences", func(t *testing.T) {
+		// when
+		preferences, err := s.repo.Preferences(s.Ctx, fxt.Identities[2].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{"workitem.update": false}, preferences)
+	})
+}
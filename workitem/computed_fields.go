@@ -0,0 +1,274 @@
+package workitem
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// SystemWorkItemLinkTypeParentChildID is the ID of the system's parent-child
+// link type. It is defined here rather than in the link package, which
+// imports this package, and exposed there as
+// link.SystemWorkItemLinkTypeParentChildID. Never ever change this UUID!!!
+var SystemWorkItemLinkTypeParentChildID = uuid.FromStringOrNil("25C326A7-6D03-4F5A-B23B-86A9EE4171E9")
+
+// computedFieldsOrder returns the names of all computed fields in the order in
+// which they have to be calculated. A computed field that references another
+// computed field of the same work item comes after the referenced field. An
+// error is returned if the computed fields reference each other in a cycle.
+func (j FieldDefinitions) computedFieldsOrder() ([]string, error) {
+	formulas := map[string]*Formula{}
+	names := []string{}
+	for name, def := range j {
+		computed, ok := def.Type.(ComputedType)
+		if !ok {
+			continue
+		}
+		f, err := computed.Formula()
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to parse formula of computed field %q", name)
+		}
+		formulas[name] = f
+		names = append(names, name)
+	}
+	// sort to get a stable order between independent fields
+	sort.Strings(names)
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	result := make([]string, 0, len(names))
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return errs.Errorf("computed field %q is part of a cycle", name)
+		}
+		state[name] = visiting
+		refs := formulas[name].ReferencedFields()
+		sort.Strings(refs)
+		for _, ref := range refs {
+			if _, ok := formulas[ref]; ok {
+				if err := visit(ref); err != nil {
+					return err
+				}
+			}
+		}
+		state[name] = visited
+		result = append(result, name)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// usesChildren returns true if any computed field in the given field
+// definitions depends on the children of a work item
+func (j FieldDefinitions) usesChildren() bool {
+	for _, def := range j {
+		computed, ok := def.Type.(ComputedType)
+		if !ok {
+			continue
+		}
+		if f, err := computed.Formula(); err == nil && f.UsesChildren() {
+			return true
+		}
+	}
+	return false
+}
+
+// computeFields calculates the values of all computed fields of the given
+// work item and stores them in its fields. The work item must be of the given
+// type.
+func (r *GormWorkItemRepository) computeFields(ctx context.Context, wiType *WorkItemType, wi *WorkItemStorage) error {
+	order, err := wiType.Fields.computedFieldsOrder()
+	if err != nil {
+		return errs.Wrapf(err, "failed to determine order of computed fields of work item type %s", wiType.ID)
+	}
+	if len(order) == 0 {
+		return nil
+	}
+	var children []Fields
+	if wi.ID != uuid.Nil && wiType.Fields.usesChildren() {
+		children, err = r.loadChildrenFields(ctx, wi.ID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+	}
+	if wi.Fields == nil {
+		wi.Fields = Fields{}
+	}
+	for _, name := range order {
+		computed := wiType.Fields[name].Type.(ComputedType)
+		v, err := computed.Compute(wi.Fields, children)
+		if err != nil {
+			return errors.NewBadParameterErrorFromString(fmt.Sprintf("failed to compute field %q: %s", name, err))
+		}
+		wi.Fields[name] = v
+	}
+	return nil
+}
+
+// loadChildrenFields returns the fields of all work items that are children
+// of the given work item
+func (r *GormWorkItemRepository) loadChildrenFields(ctx context.Context, parentID uuid.UUID) ([]Fields, error) {
+	query := fmt.Sprintf(`
+		SELECT wi.fields FROM %[1]s wi
+		JOIN work_item_links l ON l.target_id = wi.id
+		WHERE l.source_id = $1
+			AND l.link_type_id = $2
+			AND l.deleted_at IS NULL
+			AND wi.deleted_at IS NULL`,
+		WorkItemStorage{}.TableName())
+	rows, err := r.db.Raw(query, parentID, SystemWorkItemLinkTypeParentChildID).Rows()
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to load children of work item %s", parentID))
+	}
+	defer rows.Close()
+	result := []Fields{}
+	for rows.Next() {
+		fields := Fields{}
+		if err := rows.Scan(&fields); err != nil {
+			return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to scan fields of child of work item %s", parentID))
+		}
+		result = append(result, fields)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to load children of work item %s", parentID))
+	}
+	return result, nil
+}
+
+// loadParentID returns the ID of the parent of the given work item or nil if
+// the work item has no parent
+func (r *GormWorkItemRepository) loadParentID(ctx context.Context, childID uuid.UUID) (*uuid.UUID, error) {
+	query := `
+		SELECT source_id FROM work_item_links
+		WHERE target_id = $1
+			AND link_type_id = $2
+			AND deleted_at IS NULL`
+	rows, err := r.db.Raw(query, childID, SystemWorkItemLinkTypeParentChildID).Rows()
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to load parent of work item %s", childID))
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	var parentID uuid.UUID
+	if err := rows.Scan(&parentID); err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to scan parent of work item %s", childID))
+	}
+	return &parentID, nil
+}
+
+// RecalculateComputedFields calculates the computed fields of the work item
+// with the given ID and, if they changed, of its ancestors. Updating computed
+// fields neither increases the version of a work item nor creates a revision
+// because no user made a change. Deleted work items are ignored.
+// returns BadParameterError or InternalError
+func (r *GormWorkItemRepository) RecalculateComputedFields(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "recalculate"}, time.Now())
+	visited := map[uuid.UUID]struct{}{}
+	for {
+		if _, ok := visited[id]; ok {
+			return nil
+		}
+		visited[id] = struct{}{}
+		changed, err := r.recalculate(ctx, id)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		if !changed {
+			return nil
+		}
+		parentID, err := r.loadParentID(ctx, id)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		if parentID == nil {
+			return nil
+		}
+		id = *parentID
+	}
+}
+
+// recalculateAncestors calculates the computed fields of the ancestors of the
+// given work item. It is called after a work item was saved.
+func (r *GormWorkItemRepository) recalculateAncestors(ctx context.Context, id uuid.UUID) error {
+	parentID, err := r.loadParentID(ctx, id)
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	if parentID == nil {
+		return nil
+	}
+	return r.RecalculateComputedFields(ctx, *parentID)
+}
+
+// recalculate calculates the computed fields of the given work item and
+// returns true if any of them changed
+func (r *GormWorkItemRepository) recalculate(ctx context.Context, id uuid.UUID) (bool, error) {
+	wiStorage := WorkItemStorage{}
+	tx := r.db.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", id).First(&wiStorage)
+	if tx.RecordNotFound() {
+		return false, nil
+	}
+	if tx.Error != nil {
+		return false, errors.NewInternalError(ctx, tx.Error)
+	}
+	wiType, err := r.witr.Load(ctx, wiStorage.Type)
+	if err != nil {
+		return false, errs.Wrapf(err, "failed to load type of work item %s", id)
+	}
+	oldFields := Fields{}
+	for name, v := range wiStorage.Fields {
+		oldFields[name] = v
+	}
+	if err := r.computeFields(ctx, wiType, &wiStorage); err != nil {
+		return false, errs.WithStack(err)
+	}
+	changed := false
+	for name, def := range wiType.Fields {
+		if def.Type.GetKind() != KindComputed {
+			continue
+		}
+		// values loaded from the database are float64 numbers
+		oldValue, _ := numericFieldValue(name, oldFields[name])
+		newValue, _ := numericFieldValue(name, wiStorage.Fields[name])
+		if oldFields[name] == nil || oldValue != newValue {
+			changed = true
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+	err = r.db.Model(&WorkItemStorage{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"fields":     wiStorage.Fields,
+		"updated_at": time.Now(),
+	}).Error
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id": id,
+			"err":   err,
+		}, "unable to update computed fields of work item")
+		return false, errors.NewInternalError(ctx, err)
+	}
+	log.Debug(ctx, map[string]interface{}{"wi_id": id}, "Recalculated computed fields of work item")
+	return true, nil
+}
@@ -0,0 +1,125 @@
+package workitem
+
+import (
+	"math"
+
+	"github.com/fabric8-services/fabric8-wit/convert"
+	errs "github.com/pkg/errors"
+)
+
+// ComputedType describes a field whose value is not set by the user but
+// calculated from other fields of the work item and its children whenever the
+// work item or one of its descendants is saved. The SimpleType is set to
+// KindComputed and the BaseType is the numeric type of the calculated value
+// (either KindInteger or KindFloat). The Expression is a formula as described
+// in the documentation of the Formula type.
+//
+// A computed value is stored like any other field value and can therefore be
+// used for searching and sorting.
+type ComputedType struct {
+	SimpleType `json:"simple_type"`
+	BaseType   SimpleType `json:"base_type"`
+	Expression string     `json:"expression"`
+}
+
+// Ensure ComputedType implements the FieldType interface
+var _ FieldType = ComputedType{}
+var _ FieldType = (*ComputedType)(nil)
+
+// Ensure ComputedType implements the Equaler interface
+var _ convert.Equaler = ComputedType{}
+var _ convert.Equaler = (*ComputedType)(nil)
+
+// Validate checks that the type of the computed field is "computed", that the
+// base type is a number type and that the expression is a valid formula.
+func (t ComputedType) Validate() error {
+	if t.Kind != KindComputed {
+		return errs.Errorf(`computed type has a base type "%s" but needs "%s"`, t.Kind, KindComputed)
+	}
+	if t.BaseType.Kind != KindInteger && t.BaseType.Kind != KindFloat {
+		return errs.Errorf(`computed type must have a base type of kind "%s" or "%s" and not "%s"`, KindInteger, KindFloat, t.BaseType.Kind)
+	}
+	if _, err := t.Formula(); err != nil {
+		return errs.Wrapf(err, "failed to validate expression of computed type")
+	}
+	return nil
+}
+
+// Formula returns the parsed expression of the computed type
+func (t ComputedType) Formula() (*Formula, error) {
+	return ParseFormula(t.Expression)
+}
+
+// SetDefaultValue implements FieldType. Computed types cannot have a default
+// value.
+func (t ComputedType) SetDefaultValue(v interface{}) (FieldType, error) {
+	if v != nil {
+		return nil, errs.Errorf("computed type cannot have a default value: %+v (%[1]T)", v)
+	}
+	return t, nil
+}
+
+// GetDefaultValue implements FieldType
+func (t ComputedType) GetDefaultValue() interface{} {
+	return nil
+}
+
+// Equal returns true if two ComputedType objects are equal; otherwise false is
+// returned.
+func (t ComputedType) Equal(u convert.Equaler) bool {
+	other, ok := u.(ComputedType)
+	if !ok {
+		return false
+	}
+	if !convert.CascadeEqual(t.SimpleType, other.SimpleType) {
+		return false
+	}
+	if !convert.CascadeEqual(t.BaseType, other.BaseType) {
+		return false
+	}
+	return t.Expression == other.Expression
+}
+
+// EqualValue implements convert.Equaler
+func (t ComputedType) EqualValue(u convert.Equaler) bool {
+	return t.Equal(u)
+}
+
+// ConvertToModel implements FieldType
+func (t ComputedType) ConvertToModel(value interface{}) (interface{}, error) {
+	converted, err := t.BaseType.ConvertToModel(value)
+	if err != nil {
+		return nil, errs.Wrapf(err, "error converting computed value")
+	}
+	return converted, nil
+}
+
+// ConvertFromModel implements FieldType
+func (t ComputedType) ConvertFromModel(value interface{}) (interface{}, error) {
+	converted, err := t.BaseType.ConvertFromModel(value)
+	if err != nil {
+		return nil, errs.Wrapf(err, "error converting computed value")
+	}
+	return converted, nil
+}
+
+// Compute evaluates the expression for a work item with the given fields and
+// the given children fields and returns the result in model representation.
+// Results for an integer base type are rounded to the nearest integer.
+func (t ComputedType) Compute(fields Fields, children []Fields) (interface{}, error) {
+	f, err := t.Formula()
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	v, err := f.Evaluate(fields, children)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to evaluate expression %q", t.Expression)
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil, errs.Errorf("expression %q evaluates to an invalid number: %v", t.Expression, v)
+	}
+	if t.BaseType.Kind == KindInteger {
+		return t.ConvertToModel(math.Floor(v + 0.5))
+	}
+	return t.ConvertToModel(v)
+}
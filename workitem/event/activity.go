@@ -0,0 +1,97 @@
+package event
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeActivities represent the type of an activity
+const APIStringTypeActivities = "activities"
+
+// ActivityKind tells which kind of entity an activity is about
+type ActivityKind string
+
+// Possible kinds of activities
+const (
+	ActivityKindWorkItem  ActivityKind = "workitem"
+	ActivityKindComment   ActivityKind = "comment"
+	ActivityKindLink      ActivityKind = "link"
+	ActivityKindIteration ActivityKind = "iteration"
+)
+
+// ParseActivityKind returns the activity kind for the given string or a
+// BadParameterError if the string is not a known kind.
+func ParseActivityKind(s string) (ActivityKind, error) {
+	switch k := ActivityKind(s); k {
+	case ActivityKindWorkItem, ActivityKindComment, ActivityKindLink, ActivityKindIteration:
+		return k, nil
+	}
+	return "", errors.NewBadParameterError("type", s).Expected("workitem, comment, link or iteration")
+}
+
+// ActivityAction tells what happened to the entity of an activity. The values
+// are shared by the work item, comment and link revision types.
+type ActivityAction int
+
+// Possible actions of an activity
+const (
+	ActivityActionCreate  ActivityAction = 1
+	ActivityActionDelete  ActivityAction = 2
+	ActivityActionUpdate  ActivityAction = 4
+	ActivityActionRestore ActivityAction = 5
+)
+
+// String implements the Stringer interface
+func (a ActivityAction) String() string {
+	switch a {
+	case ActivityActionCreate:
+		return "create"
+	case ActivityActionDelete:
+		return "delete"
+	case ActivityActionUpdate:
+		return "update"
+	case ActivityActionRestore:
+		return "restore"
+	}
+	return strconv.Itoa(int(a))
+}
+
+// Activity is a single entry in the activity stream of a space
+type Activity struct {
+	// ID of the revision (or an ID derived from the iteration ID for
+	// iteration activities)
+	ID        uuid.UUID      `gorm:"column:id"`
+	Kind      ActivityKind   `gorm:"column:kind"`
+	Action    ActivityAction `gorm:"column:action"`
+	Timestamp time.Time      `gorm:"column:timestamp"`
+	// ID of the identity who did the change (nil for iteration activities
+	// for which no author is recorded)
+	ActorID *uuid.UUID `gorm:"column:actor_id"`
+	// ID of the work item affected by the change (nil for iteration
+	// activities)
+	WorkItemID *uuid.UUID `gorm:"column:work_item_id"`
+	// ID of the work item, comment, link or iteration that changed
+	TargetID uuid.UUID `gorm:"column:target_id"`
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (a Activity) GetETagData() []interface{} {
+	return []interface{}{a.ID, a.Kind, a.Action}
+}
+
+// GetLastModified returns the last modification time
+func (a Activity) GetLastModified() time.Time {
+	return a.Timestamp.Truncate(time.Second)
+}
+
+// ActivityFilter restricts the activities returned for a space. All fields
+// are optional.
+type ActivityFilter struct {
+	ActorID *uuid.UUID
+	Kinds   []ActivityKind
+	Since   *time.Time
+	Until   *time.Time
+}
@@ -0,0 +1,157 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/closeable"
+	"github.com/fabric8-services/fabric8-wit/comment"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ActivityRepository encapsulates retrieval of the activities in a space
+type ActivityRepository interface {
+	// List returns the activities in the given space that match the given
+	// filter, most recent first, together with the total number of matching
+	// activities.
+	List(ctx context.Context, spaceID uuid.UUID, filter ActivityFilter, start *int, limit *int) ([]Activity, int, error)
+}
+
+// NewActivityRepository creates an activity repository based on gorm
+func NewActivityRepository(db *gorm.DB) *GormActivityRepository {
+	return &GormActivityRepository{db: db}
+}
+
+// GormActivityRepository implements ActivityRepository using gorm
+type GormActivityRepository struct {
+	db *gorm.DB
+}
+
+// activitiesQuery merges the revisions of work items, comments and links as
+// well as the creation and last update of iterations of a space into one
+// stream. Work items are joined regardless of their deletion state so that
+// the history of deleted work items stays visible.
+//
+// Iterations have no revisions: their history consists of the created_at and
+// updated_at timestamps only, so at most one create and one update activity
+// exist per iteration and intermediate updates are not reported. The IDs of
+// those two activities are derived from the iteration ID (UUID v5) to keep
+// them distinct and stable.
+func activitiesQuery() string {
+	return fmt.Sprintf(`
+		SELECT r.id, '%[6]s' AS kind, r.revision_type AS action, r.revision_time AS timestamp,
+			r.modifier_id AS actor_id, r.work_item_id AS work_item_id, r.work_item_id AS target_id
+		FROM %[1]s r JOIN %[5]s wi ON wi.id = r.work_item_id
+		WHERE wi.space_id = ?
+		UNION ALL
+		SELECT r.id, '%[7]s', r.revision_type, r.revision_time,
+			r.modifier_id, r.comment_parent_id, r.comment_id
+		FROM %[2]s r JOIN %[5]s wi ON wi.id = r.comment_parent_id
+		WHERE wi.space_id = ?
+		UNION ALL
+		SELECT r.id, '%[8]s', r.revision_type, r.revision_time,
+			r.modifier_id, r.work_item_link_source_id, r.work_item_link_id
+		FROM %[3]s r JOIN %[5]s wi ON wi.id = r.work_item_link_source_id
+		WHERE wi.space_id = ?
+		UNION ALL
+		SELECT uuid_generate_v5(i.id, 'created'), '%[9]s', %[10]d, i.created_at,
+			NULL, NULL, i.id
+		FROM %[4]s i
+		WHERE i.space_id = ?
+		UNION ALL
+		SELECT uuid_generate_v5(i.id, 'updated'), '%[9]s', %[11]d, i.updated_at,
+			NULL, NULL, i.id
+		FROM %[4]s i
+		WHERE i.space_id = ? AND i.updated_at > i.created_at`,
+		workitem.Revision{}.TableName(),
+		comment.Revision{}.TableName(),
+		link.Revision{}.TableName(),
+		iteration.Iteration{}.TableName(),
+		workitem.WorkItemStorage{}.TableName(),
+		ActivityKindWorkItem,
+		ActivityKindComment,
+		ActivityKindLink,
+		ActivityKindIteration,
+		ActivityActionCreate,
+		ActivityActionUpdate,
+	)
+}
+
+// List implements ActivityRepository
+func (r *GormActivityRepository) List(ctx context.Context, spaceID uuid.UUID, filter ActivityFilter, start *int, limit *int) ([]Activity, int, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "activity", "list"}, time.Now())
+	parameters := []interface{}{spaceID, spaceID, spaceID, spaceID, spaceID}
+	conditions := []string{"TRUE"}
+	if filter.ActorID != nil {
+		conditions = append(conditions, "a.actor_id = ?")
+		parameters = append(parameters, *filter.ActorID)
+	}
+	if len(filter.Kinds) > 0 {
+		kinds := make([]string, len(filter.Kinds))
+		for i, k := range filter.Kinds {
+			kinds[i] = string(k)
+		}
+		conditions = append(conditions, "a.kind IN (?)")
+		parameters = append(parameters, kinds)
+	}
+	if filter.Since != nil {
+		conditions = append(conditions, "a.timestamp >= ?")
+		parameters = append(parameters, *filter.Since)
+	}
+	if filter.Until != nil {
+		conditions = append(conditions, "a.timestamp < ?")
+		parameters = append(parameters, *filter.Until)
+	}
+	query := fmt.Sprintf(`SELECT count(*) OVER () AS cnt, a.* FROM (%s) a WHERE %s ORDER BY a.timestamp DESC, a.id`,
+		activitiesQuery(), strings.Join(conditions, " AND "))
+	if start != nil {
+		if *start < 0 {
+			return nil, 0, errors.NewBadParameterError("start", *start)
+		}
+		query += fmt.Sprintf(" OFFSET %d", *start)
+	}
+	if limit != nil {
+		if *limit <= 0 {
+			return nil, 0, errors.NewBadParameterError("limit", *limit)
+		}
+		query += fmt.Sprintf(" LIMIT %d", *limit)
+	}
+	rows, err := r.db.Raw(query, parameters...).Rows()
+	defer closeable.Close(ctx, rows)
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"space_id": spaceID,
+			"err":      err,
+		}, "unable to list the activities of the space")
+		return nil, 0, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list activities of space %s", spaceID))
+	}
+	result := []Activity{}
+	var count int
+	for rows.Next() {
+		a := Activity{}
+		if err := rows.Scan(&count, &a.ID, &a.Kind, &a.Action, &a.Timestamp, &a.ActorID, &a.WorkItemID, &a.TargetID); err != nil {
+			return nil, 0, errors.NewInternalError(ctx, errs.Wrap(err, "failed to read activity"))
+		}
+		result = append(result, a)
+	}
+	if len(result) == 0 && start != nil && *start > 0 {
+		// the offset may be outside of the total count, need to count
+		// separately to find out the total
+		countQuery := fmt.Sprintf(`SELECT count(*) FROM (%s) a WHERE %s`, activitiesQuery(), strings.Join(conditions, " AND "))
+		if err := r.db.Raw(countQuery, parameters...).Row().Scan(&count); err != nil {
+			return nil, 0, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to count activities of space %s", spaceID))
+		}
+	}
+	return result, count, nil
+}
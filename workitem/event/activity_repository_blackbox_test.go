@@ -0,0 +1,136 @@
+package event_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem/event"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type activityRepoBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	repo event.ActivityRepository
+}
+
+func TestRunActivityRepoBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &activityRepoBlackBoxTest{
+		DBTestSuite: gormtestsupport.NewDBTestSuite(),
+	})
+}
+
+func (s *activityRepoBlackBoxTest) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.repo = event.NewActivityRepository(s.DB)
+}
+
+func (s *activityRepoBlackBoxTest) TestList() {
+	s.T().Run("empty space", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		activities, count, err := s.repo.List(s.Ctx, fxt.Spaces[0].ID, event.ActivityFilter{}, nil, nil)
+		require.NoError(t, err)
+		// the root iteration of the space is not created by the fixture
+		assert.Empty(t, activities)
+		assert.Equal(t, 0, count)
+	})
+
+	s.T().Run("all kinds", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(2), tf.Comments(1), tf.WorkItemLinks(1), tf.Iterations(1))
+		activities, count, err := s.repo.List(s.Ctx, fxt.Spaces[0].ID, event.ActivityFilter{}, nil, nil)
+		require.NoError(t, err)
+		// 2 work items + 1 comment + 1 link + 1 iteration
+		require.Len(t, activities, 5)
+		assert.Equal(t, 5, count)
+		kinds := map[event.ActivityKind]int{}
+		for i, a := range activities {
+			kinds[a.Kind]++
+			if i > 0 {
+				assert.False(t, a.Timestamp.After(activities[i-1].Timestamp), "activities must be sorted most recent first")
+			}
+		}
+		assert.Equal(t, 2, kinds[event.ActivityKindWorkItem])
+		assert.Equal(t, 1, kinds[event.ActivityKindComment])
+		assert.Equal(t, 1, kinds[event.ActivityKindLink])
+		assert.Equal(t, 1, kinds[event.ActivityKindIteration])
+	})
+
+	s.T().Run("iteration created and updated", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Iterations(1))
+		err := s.DB.Model(&iteration.Iteration{}).Where("id = ?", fxt.Iterations[0].ID).UpdateColumn("updated_at", time.Now().Add(time.Minute)).Error
+		require.NoError(t, err)
+		activities, _, err := s.repo.List(s.Ctx, fxt.Spaces[0].ID, event.ActivityFilter{}, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, activities, 2)
+		assert.Equal(t, event.ActivityActionUpdate, activities[0].Action)
+		assert.Equal(t, event.ActivityActionCreate, activities[1].Action)
+		assert.NotEqual(t, activities[0].ID, activities[1].ID)
+		assert.NotEqual(t, fxt.Iterations[0].ID, activities[0].ID)
+		for _, a := range activities {
+			assert.Equal(t, fxt.Iterations[0].ID, a.TargetID)
+		}
+		// the derived IDs are stable
+		again, _, err := s.repo.List(s.Ctx, fxt.Spaces[0].ID, event.ActivityFilter{}, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, activities[0].ID, again[0].ID)
+	})
+
+	s.T().Run("filtered", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(3), tf.Comments(2))
+		spaceID := fxt.Spaces[0].ID
+		t.Run("by kind", func(t *testing.T) {
+			activities, count, err := s.repo.List(s.Ctx, spaceID, event.ActivityFilter{Kinds: []event.ActivityKind{event.ActivityKindComment}}, nil, nil)
+			require.NoError(t, err)
+			require.Len(t, activities, 2)
+			assert.Equal(t, 2, count)
+			for _, a := range activities {
+				assert.Equal(t, event.ActivityKindComment, a.Kind)
+				assert.Equal(t, event.ActivityActionCreate, a.Action)
+				require.NotNil(t, a.WorkItemID)
+				assert.Equal(t, fxt.WorkItems[0].ID, *a.WorkItemID)
+			}
+		})
+		t.Run("by actor", func(t *testing.T) {
+			activities, _, err := s.repo.List(s.Ctx, spaceID, event.ActivityFilter{ActorID: &fxt.Identities[0].ID}, nil, nil)
+			require.NoError(t, err)
+			assert.Len(t, activities, 5)
+			unknown := uuid.NewV4()
+			activities, count, err := s.repo.List(s.Ctx, spaceID, event.ActivityFilter{ActorID: &unknown}, nil, nil)
+			require.NoError(t, err)
+			assert.Empty(t, activities)
+			assert.Equal(t, 0, count)
+		})
+		t.Run("by time range", func(t *testing.T) {
+			future := time.Now().Add(1 * time.Hour)
+			activities, _, err := s.repo.List(s.Ctx, spaceID, event.ActivityFilter{Since: &future}, nil, nil)
+			require.NoError(t, err)
+			assert.Empty(t, activities)
+			activities, _, err = s.repo.List(s.Ctx, spaceID, event.ActivityFilter{Until: &future}, nil, nil)
+			require.NoError(t, err)
+			assert.Len(t, activities, 5)
+		})
+		t.Run("paged", func(t *testing.T) {
+			activities, count, err := s.repo.List(s.Ctx, spaceID, event.ActivityFilter{}, ptr.Int(1), ptr.Int(2))
+			require.NoError(t, err)
+			assert.Len(t, activities, 2)
+			assert.Equal(t, 5, count)
+			activities, count, err = s.repo.List(s.Ctx, spaceID, event.ActivityFilter{}, ptr.Int(10), ptr.Int(2))
+			require.NoError(t, err)
+			assert.Empty(t, activities)
+			assert.Equal(t, 5, count)
+		})
+	})
+
+	s.T().Run("invalid paging", func(t *testing.T) {
+		_, _, err := s.repo.List(s.Ctx, uuid.NewV4(), event.ActivityFilter{}, ptr.Int(-1), nil)
+		require.Error(t, err)
+	})
+}
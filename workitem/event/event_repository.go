@@ -93,6 +93,11 @@ func (r *GormEventRepository) List(ctx context.Context, wiID uuid.UUID) (List, e
 		}
 
 		for fieldName, fieldDef := range wit.Fields {
+			// The meta-state of types with transitions is derived from the
+			// state and would only repeat the state change event.
+			if fieldName == workitem.SystemMetaState && len(wit.Transitions) > 0 {
+				continue
+			}
 
 			oldVal := oldRev.WorkItemFields[fieldName]
 			newVal := newRev.WorkItemFields[fieldName]
@@ -114,6 +119,10 @@ func (r *GormEventRepository) List(ctx context.Context, wiID uuid.UUID) (List, e
 			if isEnumType {
 				ft = enumType.BaseType
 			}
+			// The same goes for computed fields.
+			if computedType, isComputedType := ft.(workitem.ComputedType); isComputedType {
+				ft = computedType.BaseType
+			}
 
 			switch fieldType := ft.(type) {
 			case workitem.ListType:
@@ -0,0 +1,307 @@
+package workitem
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	errs "github.com/pkg/errors"
+)
+
+// FieldConstraints restrict the values of a field beyond its type. Each
+// constraint is optional and only applies to certain kinds of fields:
+//
+//   - min and max are the inclusive bounds of integer and float fields
+//   - min_length, max_length and pattern apply to string and URL fields. The
+//     pattern is a regular expression that has to match the value.
+//   - min_date and max_date are the inclusive bounds of instant fields. In a
+//     space template they are written in RFC 3339 format (e.g.
+//     "2018-01-01T00:00:00Z").
+//   - max_items limits the number of elements of list fields
+//   - required_when makes a field of any kind required when the state (or any
+//     other field) has one of the given values
+//
+// An example in a space template looks like this:
+//
+//	resolution:
+//	  label: Resolution
+//	  type:
+//	    kind: string
+//	  constraints:
+//	    max_length: 200
+//	    required_when:
+//	      field: system.state
+//	      in: ["resolved", "closed"]
+type FieldConstraints struct {
+	Min          *float64        `json:"min,omitempty"`
+	Max          *float64        `json:"max,omitempty"`
+	MinLength    *int            `json:"min_length,omitempty"`
+	MaxLength    *int            `json:"max_length,omitempty"`
+	Pattern      string          `json:"pattern,omitempty"`
+	MinDate      *time.Time      `json:"min_date,omitempty"`
+	MaxDate      *time.Time      `json:"max_date,omitempty"`
+	MaxItems     *int            `json:"max_items,omitempty"`
+	RequiredWhen *FieldCondition `json:"required_when,omitempty"`
+
+	// pattern is the compiled Pattern. It is set when the constraints are
+	// validated or unmarshalled so that values can be checked without
+	// compiling the pattern again.
+	pattern *regexp.Regexp
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (c *FieldConstraints) UnmarshalJSON(b []byte) error {
+	// the alias type doesn't have this method, which avoids the recursion
+	type constraints FieldConstraints
+	var temp constraints
+	if err := json.Unmarshal(b, &temp); err != nil {
+		return errs.WithStack(err)
+	}
+	*c = FieldConstraints(temp)
+	return c.compilePattern()
+}
+
+// compilePattern compiles the pattern constraint unless it is empty or was
+// compiled already
+func (c *FieldConstraints) compilePattern() error {
+	if c.Pattern == "" {
+		c.pattern = nil
+		return nil
+	}
+	if c.pattern != nil && c.pattern.String() == c.Pattern {
+		return nil
+	}
+	re, err := regexp.Compile(c.Pattern)
+	if err != nil {
+		return errs.Wrapf(err, "invalid pattern constraint %q", c.Pattern)
+	}
+	c.pattern = re
+	return nil
+}
+
+// equalConstraints returns true if the given constraints are both nil or
+// restrict values in the same way
+func equalConstraints(a, b *FieldConstraints) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	x, y := *a, *b
+	x.pattern, y.pattern = nil, nil
+	return reflect.DeepEqual(x, y)
+}
+
+// FieldCondition is true if the field with the given name has one of the
+// given values.
+type FieldCondition struct {
+	Field string        `json:"field"`
+	In    []interface{} `json:"in"`
+}
+
+// Matches returns true if the condition holds for the given fields in storage
+// representation
+func (c FieldCondition) Matches(fields Fields) bool {
+	v, ok := fields[c.Field]
+	if !ok || v == nil {
+		return false
+	}
+	for _, expected := range c.In {
+		if fmt.Sprint(v) == fmt.Sprint(expected) {
+			return true
+		}
+	}
+	return false
+}
+
+// constraintKind returns the kind that determines which constraints can be
+// applied to a field of the given type. For enums this is the base type.
+func constraintKind(t FieldType) Kind {
+	if enumType, ok := t.(EnumType); ok {
+		return enumType.BaseType.GetKind()
+	}
+	return t.GetKind()
+}
+
+// Validate checks that the constraints can be applied to a field of the given
+// type and that they are consistent. A pattern constraint is compiled once
+// here for the later checks of field values.
+func (c *FieldConstraints) Validate(t FieldType) error {
+	kind := constraintKind(t)
+	if c.Min != nil || c.Max != nil {
+		if kind != KindInteger && kind != KindFloat {
+			return errs.Errorf("min and max constraints can only be used for fields of kind %q or %q and not %q", KindInteger, KindFloat, kind)
+		}
+		if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+			return errs.Errorf("min constraint %v is greater than max constraint %v", *c.Min, *c.Max)
+		}
+	}
+	if c.MinLength != nil || c.MaxLength != nil || c.Pattern != "" {
+		if kind != KindString && kind != KindURL {
+			return errs.Errorf("length and pattern constraints can only be used for fields of kind %q or %q and not %q", KindString, KindURL, kind)
+		}
+		if (c.MinLength != nil && *c.MinLength < 0) || (c.MaxLength != nil && *c.MaxLength < 0) {
+			return errs.New("length constraints must not be negative")
+		}
+		if c.MinLength != nil && c.MaxLength != nil && *c.MinLength > *c.MaxLength {
+			return errs.Errorf("min_length constraint %d is greater than max_length constraint %d", *c.MinLength, *c.MaxLength)
+		}
+		if err := c.compilePattern(); err != nil {
+			return errs.WithStack(err)
+		}
+	}
+	if c.MinDate != nil || c.MaxDate != nil {
+		if kind != KindInstant {
+			return errs.Errorf("min_date and max_date constraints can only be used for fields of kind %q and not %q", KindInstant, kind)
+		}
+		if c.MinDate != nil && c.MaxDate != nil && c.MinDate.After(*c.MaxDate) {
+			return errs.Errorf("min_date constraint %s is after max_date constraint %s", c.MinDate, c.MaxDate)
+		}
+	}
+	if c.MaxItems != nil {
+		if kind != KindList {
+			return errs.Errorf("max_items constraint can only be used for fields of kind %q and not %q", KindList, kind)
+		}
+		if *c.MaxItems < 0 {
+			return errs.New("max_items constraint must not be negative")
+		}
+	}
+	if c.RequiredWhen != nil {
+		if strings.TrimSpace(c.RequiredWhen.Field) == "" {
+			return errs.New("required_when constraint needs a field")
+		}
+		if len(c.RequiredWhen.In) == 0 {
+			return errs.Errorf("required_when constraint for field %q needs at least one value", c.RequiredWhen.Field)
+		}
+	}
+	return nil
+}
+
+// Check verifies that the given value in storage representation satisfies the
+// constraints of the field with the given name. Empty values are not checked
+// here; use RequiredWhen for conditional requirements.
+func (c FieldConstraints) Check(name string, kind Kind, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch kind {
+	case KindInteger, KindFloat:
+		v, err := numericFieldValue(name, value)
+		if err != nil {
+			return errors.NewBadParameterError(name, value).Expected("a number")
+		}
+		if c.Min != nil && v < *c.Min {
+			return errors.NewBadParameterError(name, value).Expected(fmt.Sprintf("a number greater than or equal to %v", *c.Min))
+		}
+		if c.Max != nil && v > *c.Max {
+			return errors.NewBadParameterError(name, value).Expected(fmt.Sprintf("a number less than or equal to %v", *c.Max))
+		}
+	case KindString, KindURL:
+		v, ok := value.(string)
+		if !ok {
+			return errors.NewBadParameterError(name, value).Expected("a string")
+		}
+		length := utf8.RuneCountInString(v)
+		if c.MinLength != nil && length < *c.MinLength {
+			return errors.NewBadParameterError(name, value).Expected(fmt.Sprintf("at least %d characters", *c.MinLength))
+		}
+		if c.MaxLength != nil && length > *c.MaxLength {
+			return errors.NewBadParameterError(name, value).Expected(fmt.Sprintf("at most %d characters", *c.MaxLength))
+		}
+		if c.Pattern != "" {
+			re := c.pattern
+			if re == nil || re.String() != c.Pattern {
+				// the constraints were neither validated nor unmarshalled
+				var err error
+				if re, err = regexp.Compile(c.Pattern); err != nil {
+					return errs.Wrapf(err, "invalid pattern constraint %q of field %q", c.Pattern, name)
+				}
+			}
+			if !re.MatchString(v) {
+				return errors.NewBadParameterError(name, value).Expected(fmt.Sprintf("a value matching %q", c.Pattern))
+			}
+		}
+	case KindInstant:
+		nanos, ok := value.(int64)
+		if !ok {
+			return errors.NewBadParameterError(name, value).Expected("an instant")
+		}
+		v := time.Unix(0, nanos)
+		if c.MinDate != nil && v.Before(*c.MinDate) {
+			return errors.NewBadParameterError(name, v.UTC()).Expected(fmt.Sprintf("an instant not before %s", c.MinDate.UTC().Format(time.RFC3339)))
+		}
+		if c.MaxDate != nil && v.After(*c.MaxDate) {
+			return errors.NewBadParameterError(name, v.UTC()).Expected(fmt.Sprintf("an instant not after %s", c.MaxDate.UTC().Format(time.RFC3339)))
+		}
+	case KindList:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return errors.NewBadParameterError(name, value).Expected("a list")
+		}
+		if c.MaxItems != nil && rv.Len() > *c.MaxItems {
+			return errors.NewBadParameterError(name, value).Expected(fmt.Sprintf("at most %d items", *c.MaxItems))
+		}
+	}
+	return nil
+}
+
+// validateConditions checks that every field referenced by a required_when
+// constraint exists. The given field definitions must include the fields
+// inherited from extended types.
+func (j FieldDefinitions) validateConditions() error {
+	for name, def := range j {
+		if def.Constraints == nil || def.Constraints.RequiredWhen == nil {
+			continue
+		}
+		if _, ok := j[def.Constraints.RequiredWhen.Field]; !ok {
+			return errs.Errorf("required_when constraint of field %q references the unknown field %q", name, def.Constraints.RequiredWhen.Field)
+		}
+	}
+	return nil
+}
+
+// checkConditionalRequirements returns a violation for every field that is
+// empty in the given fields although its required_when condition holds
+func (j FieldDefinitions) checkConditionalRequirements(fields Fields) []errors.BadParameterError {
+	violations := []errors.BadParameterError{}
+	for name, def := range j {
+		if def.Constraints == nil || def.Constraints.RequiredWhen == nil {
+			continue
+		}
+		cond := def.Constraints.RequiredWhen
+		if cond.Matches(fields) && isEmptyFieldValue(fields[name]) {
+			violations = append(violations, errors.NewBadParameterError(name, fields[name]).Expected(fmt.Sprintf("a value when %s is %v", cond.Field, cond.In)))
+		}
+	}
+	return violations
+}
+
+// fieldViolation returns the given error of FieldDefinition.ConvertToModel as
+// a BadParameterError for the field with the given name and value
+func fieldViolation(name string, value interface{}, err error) errors.BadParameterError {
+	if badParamErr, ok := errs.Cause(err).(errors.BadParameterError); ok {
+		return badParamErr
+	}
+	return errors.NewBadParameterError(name, value)
+}
+
+// violationsError combines the given violations sorted by field name into one
+// BadParameterError or returns nil if there are none
+func violationsError(violations []errors.BadParameterError) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	sort.Stable(violationsByField(violations))
+	return errors.NewBadParameterErrors(violations...)
+}
+
+// violationsByField sorts violations by the name of the field
+type violationsByField []errors.BadParameterError
+
+func (v violationsByField) Len() int           { return len(v) }
+func (v violationsByField) Less(i, j int) bool { return v[i].Parameter() < v[j].Parameter() }
+func (v violationsByField) Swap(i, j int)      { v[i], v[j] = v[j], v[i] }
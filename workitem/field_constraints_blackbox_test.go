@@ -0,0 +1,162 @@
+package workitem_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldConstraints(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	minDate := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxDate := time.Date(2018, 12, 31, 0, 0, 0, 0, time.UTC)
+	floatType := workitem.SimpleType{Kind: workitem.KindFloat}
+	stringType := workitem.SimpleType{Kind: workitem.KindString}
+	instantType := workitem.SimpleType{Kind: workitem.KindInstant}
+	listType := workitem.ListType{
+		SimpleType:    workitem.SimpleType{Kind: workitem.KindList},
+		ComponentType: workitem.SimpleType{Kind: workitem.KindString},
+	}
+
+	t.Run("validate", func(t *testing.T) {
+		t.Parallel()
+		valid := map[string]struct {
+			constraints workitem.FieldConstraints
+			fieldType   workitem.FieldType
+		}{
+			"min and max":   {workitem.FieldConstraints{Min: ptr.Float64(0), Max: ptr.Float64(10)}, floatType},
+			"string length": {workitem.FieldConstraints{MinLength: ptr.Int(1), MaxLength: ptr.Int(5), Pattern: "^[A-Z]+$"}, stringType},
+			"dates":         {workitem.FieldConstraints{MinDate: &minDate, MaxDate: &maxDate}, instantType},
+			"max items":     {workitem.FieldConstraints{MaxItems: ptr.Int(2)}, listType},
+			"required when": {workitem.FieldConstraints{RequiredWhen: &workitem.FieldCondition{Field: "system.state", In: []interface{}{"closed"}}}, stringType},
+		}
+		for name, test := range valid {
+			t.Run(name, func(t *testing.T) {
+				require.NoError(t, test.constraints.Validate(test.fieldType))
+			})
+		}
+		invalid := map[string]struct {
+			constraints workitem.FieldConstraints
+			fieldType   workitem.FieldType
+		}{
+			"min for string":       {workitem.FieldConstraints{Min: ptr.Float64(0)}, stringType},
+			"min greater than max": {workitem.FieldConstraints{Min: ptr.Float64(2), Max: ptr.Float64(1)}, floatType},
+			"length for float":     {workitem.FieldConstraints{MaxLength: ptr.Int(1)}, floatType},
+			"negative length":      {workitem.FieldConstraints{MinLength: ptr.Int(-1)}, stringType},
+			"invalid pattern":      {workitem.FieldConstraints{Pattern: "("}, stringType},
+			"dates for string":     {workitem.FieldConstraints{MinDate: &minDate}, stringType},
+			"min date after max":   {workitem.FieldConstraints{MinDate: &maxDate, MaxDate: &minDate}, instantType},
+			"max items for string": {workitem.FieldConstraints{MaxItems: ptr.Int(1)}, stringType},
+			"condition w/o values": {workitem.FieldConstraints{RequiredWhen: &workitem.FieldCondition{Field: "system.state"}}, stringType},
+		}
+		for name, test := range invalid {
+			t.Run(name, func(t *testing.T) {
+				require.Error(t, test.constraints.Validate(test.fieldType))
+			})
+		}
+	})
+
+	t.Run("convert to model", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			name        string
+			fieldType   workitem.FieldType
+			constraints workitem.FieldConstraints
+			value       interface{}
+			valid       bool
+		}{
+			{"float in range", floatType, workitem.FieldConstraints{Min: ptr.Float64(0), Max: ptr.Float64(10)}, float64(10), true},
+			{"float too small", floatType, workitem.FieldConstraints{Min: ptr.Float64(0)}, float64(-1), false},
+			{"float too big", floatType, workitem.FieldConstraints{Max: ptr.Float64(10)}, float64(10.5), false},
+			{"empty value", floatType, workitem.FieldConstraints{Min: ptr.Float64(1)}, nil, true},
+			{"string ok", stringType, workitem.FieldConstraints{MinLength: ptr.Int(2), MaxLength: ptr.Int(3), Pattern: "^[A-Z]+$"}, "ABC", true},
+			{"string too short", stringType, workitem.FieldConstraints{MinLength: ptr.Int(2)}, "A", false},
+			{"string too long", stringType, workitem.FieldConstraints{MaxLength: ptr.Int(3)}, "ABCD", false},
+			{"string length in characters", stringType, workitem.FieldConstraints{MaxLength: ptr.Int(3)}, "äöü", true},
+			{"string not matching", stringType, workitem.FieldConstraints{Pattern: "^[A-Z]+$"}, "abc", false},
+			{"instant in range", instantType, workitem.FieldConstraints{MinDate: &minDate, MaxDate: &maxDate}, minDate, true},
+			{"instant too early", instantType, workitem.FieldConstraints{MinDate: &minDate}, minDate.Add(-time.Second), false},
+			{"instant too late", instantType, workitem.FieldConstraints{MaxDate: &maxDate}, maxDate.Add(time.Second), false},
+			{"list ok", listType, workitem.FieldConstraints{MaxItems: ptr.Int(2)}, []interface{}{"a", "b"}, true},
+			{"list too long", listType, workitem.FieldConstraints{MaxItems: ptr.Int(2)}, []interface{}{"a", "b", "c"}, false},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				constraints := test.constraints
+				fd := workitem.FieldDefinition{Label: "Foo", Type: test.fieldType, Constraints: &constraints}
+				_, err := fd.ConvertToModel("foo", test.value)
+				if test.valid {
+					require.NoError(t, err)
+				} else {
+					require.Error(t, err)
+					ok, _ := errors.IsBadParameterError(err)
+					assert.True(t, ok, "expected a bad parameter error but got %+v", errs.Cause(err))
+				}
+			})
+		}
+	})
+
+	t.Run("condition", func(t *testing.T) {
+		t.Parallel()
+		c := workitem.FieldCondition{Field: "system.state", In: []interface{}{"resolved", "closed"}}
+		assert.True(t, c.Matches(workitem.Fields{"system.state": "closed"}))
+		assert.False(t, c.Matches(workitem.Fields{"system.state": "open"}))
+		assert.False(t, c.Matches(workitem.Fields{}))
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		t.Parallel()
+		var fd workitem.FieldDefinition
+		err := json.Unmarshal([]byte(`{
+			"label": "Resolution",
+			"type": {"kind": "string"},
+			"constraints": {
+				"max_length": 200,
+				"required_when": {"field": "system.state", "in": ["closed"]}
+			}
+		}`), &fd)
+		require.NoError(t, err)
+		require.NotNil(t, fd.Constraints)
+		assert.Equal(t, ptr.Int(200), fd.Constraints.MaxLength)
+		assert.Equal(t, &workitem.FieldCondition{Field: "system.state", In: []interface{}{"closed"}}, fd.Constraints.RequiredWhen)
+	})
+
+	t.Run("unmarshal pattern", func(t *testing.T) {
+		t.Parallel()
+		raw := []byte(`{
+			"label": "Code",
+			"type": {"kind": "string"},
+			"constraints": {"pattern": "^[A-Z]+$"}
+		}`)
+		var fd workitem.FieldDefinition
+		require.NoError(t, json.Unmarshal(raw, &fd))
+		_, err := fd.ConvertToModel("code", "ABC")
+		require.NoError(t, err)
+		_, err = fd.ConvertToModel("code", "abc")
+		require.Error(t, err)
+		// the compiled pattern doesn't matter for the equality
+		expected := workitem.FieldDefinition{
+			Label:       "Code",
+			Type:        workitem.SimpleType{Kind: workitem.KindString},
+			Constraints: &workitem.FieldConstraints{Pattern: "^[A-Z]+$"},
+		}
+		assert.True(t, expected.Equal(fd))
+		// an invalid pattern is reported right away
+		err = json.Unmarshal([]byte(`{
+			"label": "Code",
+			"type": {"kind": "string"},
+			"constraints": {"pattern": "("}
+		}`), &fd)
+		require.Error(t, err)
+	})
+}
@@ -28,17 +28,21 @@ const (
 	KindBoardColumn Kind = "boardcolumn"
 	KindArea        Kind = "area"
 	KindCodebase    Kind = "codebase"
+	KindWorkItem    Kind = "workitem"
+	KindRelease     Kind = "release"
 	// composite
-	KindEnum Kind = "enum"
-	KindList Kind = "list"
+	KindEnum     Kind = "enum"
+	KindList     Kind = "list"
+	KindComputed Kind = "computed"
 )
 
 // Kind is the kind of field type
 type Kind string
 
-// IsSimpleType returns 'true' if the kind is simple, i.e., not a list nor an enum
+// IsSimpleType returns 'true' if the kind is simple, i.e., not a list, an enum
+// nor a computed field
 func (k Kind) IsSimpleType() bool {
-	return k != KindEnum && k != KindList
+	return k != KindEnum && k != KindList && k != KindComputed
 }
 
 // IsRelational returns 'true' if the kind must be represented with a
@@ -50,7 +54,9 @@ func (k Kind) IsRelational() bool {
 		KindLabel,
 		KindBoardColumn,
 		KindArea,
-		KindCodebase:
+		KindCodebase,
+		KindWorkItem,
+		KindRelease:
 		return true
 	}
 	return false
@@ -97,11 +103,12 @@ type FieldType interface {
 
 // FieldDefinition describes type & other restrictions of a field
 type FieldDefinition struct {
-	Required    bool      `json:"required"`
-	ReadOnly    bool      `json:"read_only"`
-	Label       string    `json:"label"`
-	Description string    `json:"description"`
-	Type        FieldType `json:"type"`
+	Required    bool              `json:"required"`
+	ReadOnly    bool              `json:"read_only"`
+	Label       string            `json:"label"`
+	Description string            `json:"description"`
+	Type        FieldType         `json:"type"`
+	Constraints *FieldConstraints `json:"constraints,omitempty"`
 }
 
 // Ensure FieldDefinition implements the Equaler interface
@@ -116,7 +123,18 @@ func (f FieldDefinition) Validate() error {
 	if strings.TrimSpace(f.Label) == "" {
 		return errs.Errorf(`field label is empty "%s" when trimmed`, f.Label)
 	}
-	return f.Type.Validate()
+	if f.Type.GetKind() == KindComputed && f.Required {
+		return errs.Errorf("computed field %q cannot be required", f.Label)
+	}
+	if err := f.Type.Validate(); err != nil {
+		return errs.WithStack(err)
+	}
+	if f.Constraints != nil {
+		if err := f.Constraints.Validate(f.Type); err != nil {
+			return errs.Wrapf(err, "invalid constraints of field %q", f.Label)
+		}
+	}
+	return nil
 }
 
 // Equal returns true if two FieldDefinition objects are equal; otherwise false is returned.
@@ -137,6 +155,9 @@ func (f FieldDefinition) Equal(u convert.Equaler) bool {
 	if f.Description != other.Description {
 		return false
 	}
+	if !equalConstraints(f.Constraints, other.Constraints) {
+		return false
+	}
 	return convert.CascadeEqual(f.Type, other.Type)
 }
 
@@ -166,7 +187,16 @@ func (f FieldDefinition) ConvertToModel(name string, value interface{}) (interfa
 			}
 		}
 	}
-	return f.Type.ConvertToModel(value)
+	converted, err := f.Type.ConvertToModel(value)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if f.Constraints != nil {
+		if err := f.Constraints.Check(name, constraintKind(f.Type), converted); err != nil {
+			return nil, errs.WithStack(err)
+		}
+	}
+	return converted, nil
 }
 
 // ConvertFromModel converts a field value for use in the REST API layer
@@ -178,11 +208,12 @@ func (f FieldDefinition) ConvertFromModel(name string, value interface{}) (inter
 }
 
 type rawFieldDef struct {
-	Required    bool             `json:"required"`
-	ReadOnly    bool             `json:"read_only"`
-	Label       string           `json:"label"`
-	Description string           `json:"description"`
-	Type        *json.RawMessage `json:"type"`
+	Required    bool              `json:"required"`
+	ReadOnly    bool              `json:"read_only"`
+	Label       string            `json:"label"`
+	Description string            `json:"description"`
+	Type        *json.RawMessage  `json:"type"`
+	Constraints *FieldConstraints `json:"constraints,omitempty"`
 }
 
 // Ensure rawFieldDef implements the Equaler interface
@@ -210,6 +241,9 @@ func (f rawFieldDef) Equal(u convert.Equaler) bool {
 	if !reflect.DeepEqual(f.Type, other.Type) {
 		return false
 	}
+	if !equalConstraints(f.Constraints, other.Constraints) {
+		return false
+	}
 	return true
 }
 
@@ -255,21 +289,28 @@ func (f *FieldDefinition) UnmarshalJSON(bytes []byte) error {
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		*f = FieldDefinition{Type: theType, Required: temp.Required, ReadOnly: temp.ReadOnly, Label: temp.Label, Description: temp.Description}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, ReadOnly: temp.ReadOnly, Label: temp.Label, Description: temp.Description, Constraints: temp.Constraints}
 	case KindEnum:
 		theType := EnumType{}
 		err = json.Unmarshal(*temp.Type, &theType)
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		*f = FieldDefinition{Type: theType, Required: temp.Required, ReadOnly: temp.ReadOnly, Label: temp.Label, Description: temp.Description}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, ReadOnly: temp.ReadOnly, Label: temp.Label, Description: temp.Description, Constraints: temp.Constraints}
+	case KindComputed:
+		theType := ComputedType{}
+		err = json.Unmarshal(*temp.Type, &theType)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, ReadOnly: temp.ReadOnly, Label: temp.Label, Description: temp.Description, Constraints: temp.Constraints}
 	default:
 		theType := SimpleType{}
 		err = json.Unmarshal(*temp.Type, &theType)
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		*f = FieldDefinition{Type: theType, Required: temp.Required, ReadOnly: temp.ReadOnly, Label: temp.Label, Description: temp.Description}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, ReadOnly: temp.ReadOnly, Label: temp.Label, Description: temp.Description, Constraints: temp.Constraints}
 	}
 	return nil
 }
@@ -286,7 +327,7 @@ func ConvertAnyToKind(any interface{}) (*Kind, error) {
 func ConvertStringToKind(k string) (*Kind, error) {
 	kind := Kind(k)
 	switch kind {
-	case KindString, KindInteger, KindFloat, KindInstant, KindURL, KindUser, KindEnum, KindList, KindIteration, KindMarkup, KindArea, KindCodebase, KindLabel, KindBoardColumn, KindBoolean:
+	case KindString, KindInteger, KindFloat, KindInstant, KindURL, KindUser, KindEnum, KindList, KindIteration, KindMarkup, KindArea, KindCodebase, KindLabel, KindBoardColumn, KindBoolean, KindComputed, KindWorkItem, KindRelease:
 		return &kind, nil
 	}
 	return nil, errs.Errorf("kind '%s' is not a simple type", k)
@@ -85,6 +85,8 @@ func TestFieldDefinition_IsRelational(t *testing.T) {
 	require.True(t, workitem.KindBoardColumn.IsRelational())
 	require.True(t, workitem.KindUser.IsRelational())
 	require.True(t, workitem.KindCodebase.IsRelational())
+	require.True(t, workitem.KindWorkItem.IsRelational())
+	require.True(t, workitem.KindRelease.IsRelational())
 	// composite kinds
 	require.False(t, workitem.KindList.IsRelational())
 	require.False(t, workitem.KindEnum.IsRelational())
@@ -0,0 +1,322 @@
+package workitem
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"strconv"
+	"strings"
+
+	errs "github.com/pkg/errors"
+)
+
+// A Formula is the parsed expression of a computed field. The syntax is a
+// small subset of Go expressions:
+//
+//   - number literals (e.g. 3 or 0.5) and string literals (e.g. "closed")
+//   - references to fields of the work item by their name (e.g. estimate or
+//     system.state). Empty values count as 0.
+//   - the binary operators +, -, *, / and the unary operator -. A division by
+//     zero evaluates to 0.
+//   - parentheses
+//   - the following functions:
+//     sum(field)             sum of the field over the children
+//     avg(field)             average of the field over the children
+//     count()                number of children
+//     count_if(field, value) number of children whose field equals value
+//     percent(a, b)          a in percent of b (0 if b is 0)
+//     min(a, b), max(a, b)   minimum and maximum of two values
+//
+// The children of a work item are the targets of its tree links of the
+// parent-child link type. For example, the remaining effort is "estimate -
+// spent", the total of story points of the children is "sum(storypoints)" and
+// the completion ratio is `percent(count_if(system.state, "closed"), count())`.
+type Formula struct {
+	expr ast.Expr
+	// fields contains the names of all fields of the work item (not the
+	// children) that are referenced by the formula
+	fields map[string]struct{}
+	// usesChildren is true if the formula aggregates values of the children
+	usesChildren bool
+}
+
+// formulaFunction describes a function that can be called in a formula
+type formulaFunction struct {
+	// arity is the number of arguments of the function
+	arity int
+	// aggregate is true if the function aggregates a field of the children
+	// given as first argument
+	aggregate bool
+}
+
+var formulaFunctions = map[string]formulaFunction{
+	"sum":      {arity: 1, aggregate: true},
+	"avg":      {arity: 1, aggregate: true},
+	"count":    {arity: 0, aggregate: true},
+	"count_if": {arity: 2, aggregate: true},
+	"percent":  {arity: 2},
+	"min":      {arity: 2},
+	"max":      {arity: 2},
+}
+
+// ParseFormula parses the given expression and checks that it only uses the
+// supported syntax.
+func ParseFormula(expression string) (*Formula, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, errs.New("formula is empty")
+	}
+	expr, err := parser.ParseExpr(expression)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to parse formula %q", expression)
+	}
+	f := Formula{
+		expr:   expr,
+		fields: map[string]struct{}{},
+	}
+	if err := f.check(expr); err != nil {
+		return nil, errs.Wrapf(err, "invalid formula %q", expression)
+	}
+	return &f, nil
+}
+
+// ReferencedFields returns the names of the fields of the work item that the
+// formula depends on (not including the fields of the children)
+func (f Formula) ReferencedFields() []string {
+	result := make([]string, 0, len(f.fields))
+	for name := range f.fields {
+		result = append(result, name)
+	}
+	return result
+}
+
+// UsesChildren returns true if the formula depends on the children of the
+// work item, in which case it must be recalculated when a child changes
+func (f Formula) UsesChildren() bool {
+	return f.usesChildren
+}
+
+// fieldName returns the field name of the given identifier or selector
+// expression (e.g. "system.state" for the selector "system.state")
+func fieldName(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name, true
+	case *ast.SelectorExpr:
+		prefix, ok := fieldName(e.X)
+		if !ok {
+			return "", false
+		}
+		return prefix + "." + e.Sel.Name, true
+	}
+	return "", false
+}
+
+// check verifies the given node and collects the referenced fields
+func (f *Formula) check(expr ast.Expr) error {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.INT && e.Kind != token.FLOAT && e.Kind != token.STRING {
+			return errs.Errorf("unsupported literal %s", e.Value)
+		}
+		return nil
+	case *ast.Ident, *ast.SelectorExpr:
+		name, ok := fieldName(e)
+		if !ok {
+			return errs.Errorf("unsupported field reference at position %d", e.Pos())
+		}
+		f.fields[name] = struct{}{}
+		return nil
+	case *ast.ParenExpr:
+		return f.check(e.X)
+	case *ast.UnaryExpr:
+		if e.Op != token.SUB && e.Op != token.ADD {
+			return errs.Errorf("unsupported operator %s", e.Op)
+		}
+		return f.check(e.X)
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.ADD, token.SUB, token.MUL, token.QUO:
+		default:
+			return errs.Errorf("unsupported operator %s", e.Op)
+		}
+		if err := f.check(e.X); err != nil {
+			return err
+		}
+		return f.check(e.Y)
+	case *ast.CallExpr:
+		ident, ok := e.Fun.(*ast.Ident)
+		if !ok {
+			return errs.Errorf("unsupported function call at position %d", e.Pos())
+		}
+		fn, ok := formulaFunctions[ident.Name]
+		if !ok {
+			return errs.Errorf("unknown function %s", ident.Name)
+		}
+		if len(e.Args) != fn.arity {
+			return errs.Errorf("function %s expects %d argument(s) but got %d", ident.Name, fn.arity, len(e.Args))
+		}
+		if fn.aggregate {
+			f.usesChildren = true
+			if fn.arity > 0 {
+				if _, ok := fieldName(e.Args[0]); !ok {
+					return errs.Errorf("first argument of function %s must be a field name", ident.Name)
+				}
+			}
+			if ident.Name == "count_if" {
+				if _, ok := e.Args[1].(*ast.BasicLit); !ok {
+					return errs.Errorf("second argument of function %s must be a literal", ident.Name)
+				}
+			}
+			return nil
+		}
+		for _, arg := range e.Args {
+			if err := f.check(arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return errs.Errorf("unsupported expression at position %d", expr.Pos())
+}
+
+// Evaluate computes the value of the formula for a work item with the given
+// fields and the given children fields. All values are in their storage
+// representation.
+func (f Formula) Evaluate(fields Fields, children []Fields) (float64, error) {
+	return f.eval(f.expr, fields, children)
+}
+
+func (f Formula) eval(expr ast.Expr, fields Fields, children []Fields) (float64, error) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind == token.STRING {
+			return 0, errs.Errorf("string literal %s cannot be used as a number", e.Value)
+		}
+		return strconv.ParseFloat(e.Value, 64)
+	case *ast.Ident, *ast.SelectorExpr:
+		name, _ := fieldName(e)
+		return numericFieldValue(name, fields[name])
+	case *ast.ParenExpr:
+		return f.eval(e.X, fields, children)
+	case *ast.UnaryExpr:
+		v, err := f.eval(e.X, fields, children)
+		if err != nil {
+			return 0, err
+		}
+		if e.Op == token.SUB {
+			return -v, nil
+		}
+		return v, nil
+	case *ast.BinaryExpr:
+		x, err := f.eval(e.X, fields, children)
+		if err != nil {
+			return 0, err
+		}
+		y, err := f.eval(e.Y, fields, children)
+		if err != nil {
+			return 0, err
+		}
+		switch e.Op {
+		case token.ADD:
+			return x + y, nil
+		case token.SUB:
+			return x - y, nil
+		case token.MUL:
+			return x * y, nil
+		case token.QUO:
+			if y == 0 {
+				return 0, nil
+			}
+			return x / y, nil
+		}
+	case *ast.CallExpr:
+		return f.call(e, fields, children)
+	}
+	return 0, errs.Errorf("unsupported expression at position %d", expr.Pos())
+}
+
+func (f Formula) call(e *ast.CallExpr, fields Fields, children []Fields) (float64, error) {
+	name := e.Fun.(*ast.Ident).Name
+	switch name {
+	case "count":
+		return float64(len(children)), nil
+	case "count_if":
+		field, _ := fieldName(e.Args[0])
+		lit := e.Args[1].(*ast.BasicLit)
+		expected := lit.Value
+		if lit.Kind == token.STRING {
+			unquoted, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				return 0, errs.Wrapf(err, "failed to unquote %s", lit.Value)
+			}
+			expected = unquoted
+		}
+		var count float64
+		for _, child := range children {
+			if v, ok := child[field]; ok && v != nil && fmt.Sprint(v) == expected {
+				count++
+			}
+		}
+		return count, nil
+	case "sum", "avg":
+		field, _ := fieldName(e.Args[0])
+		var sum float64
+		for _, child := range children {
+			v, err := numericFieldValue(field, child[field])
+			if err != nil {
+				return 0, err
+			}
+			sum += v
+		}
+		if name == "avg" {
+			if len(children) == 0 {
+				return 0, nil
+			}
+			return sum / float64(len(children)), nil
+		}
+		return sum, nil
+	}
+	a, err := f.eval(e.Args[0], fields, children)
+	if err != nil {
+		return 0, err
+	}
+	b, err := f.eval(e.Args[1], fields, children)
+	if err != nil {
+		return 0, err
+	}
+	switch name {
+	case "percent":
+		if b == 0 {
+			return 0, nil
+		}
+		return a * 100 / b, nil
+	case "min":
+		return math.Min(a, b), nil
+	case "max":
+		return math.Max(a, b), nil
+	}
+	return 0, errs.Errorf("unknown function %s", name)
+}
+
+// numericFieldValue returns the given field value in storage representation
+// as a number. Empty values count as 0.
+func numericFieldValue(name string, value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, errs.Errorf("value of field %q is not a number: %+v (%[2]T)", name, value)
+}
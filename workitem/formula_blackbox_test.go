@@ -0,0 +1,120 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormula(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		tests := map[string]struct {
+			fields       []string
+			usesChildren bool
+		}{
+			"1 + 2":                            {fields: []string{}},
+			"estimate - spent":                 {fields: []string{"estimate", "spent"}},
+			"-(a * 2) / b":                     {fields: []string{"a", "b"}},
+			"sum(storypoints)":                 {fields: []string{}, usesChildren: true},
+			"avg(system.order)":                {fields: []string{}, usesChildren: true},
+			"min(estimate, count())":           {fields: []string{"estimate"}, usesChildren: true},
+			"max(estimate, 0.5)":               {fields: []string{"estimate"}},
+			`count_if(system.state, "closed")`: {fields: []string{}, usesChildren: true},
+			`percent(count_if(system.state, "closed"), count())`: {fields: []string{}, usesChildren: true},
+		}
+		for expr, expected := range tests {
+			t.Run(expr, func(t *testing.T) {
+				f, err := workitem.ParseFormula(expr)
+				require.NoError(t, err)
+				assert.ElementsMatch(t, expected.fields, f.ReferencedFields())
+				assert.Equal(t, expected.usesChildren, f.UsesChildren())
+			})
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, expr := range []string{
+			"",
+			"   ",
+			"1 +",
+			"a % b",
+			"a == b",
+			"foo(a)",
+			"sum(a, b)",
+			"sum(1)",
+			`count_if(a, b)`,
+			"a[0]",
+			"'c'",
+			"x.y(1)",
+		} {
+			t.Run(expr, func(t *testing.T) {
+				_, err := workitem.ParseFormula(expr)
+				require.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestFormulaEvaluate(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	fields := workitem.Fields{
+		"estimate":     float64(8),
+		"spent":        3,
+		"system.state": "open",
+	}
+	children := []workitem.Fields{
+		{"storypoints": float64(3), "system.state": "closed"},
+		{"storypoints": float64(5), "system.state": "open"},
+		{"system.state": "closed"},
+		{"storypoints": nil},
+	}
+	tests := map[string]float64{
+		"estimate - spent":                 5,
+		"(estimate + spent) * 2":           22,
+		"-estimate":                        -8,
+		"estimate / 0":                     0,
+		"unknown + 1":                      1,
+		"sum(storypoints)":                 8,
+		"avg(storypoints)":                 2,
+		"count()":                          4,
+		`count_if(system.state, "closed")`: 2,
+		`percent(count_if(system.state, "closed"), count())`: 50,
+		"percent(1, 0)":        0,
+		"min(estimate, spent)": 3,
+		"max(estimate, spent)": 8,
+	}
+	for expr, expected := range tests {
+		t.Run(expr, func(t *testing.T) {
+			f, err := workitem.ParseFormula(expr)
+			require.NoError(t, err)
+			v, err := f.Evaluate(fields, children)
+			require.NoError(t, err)
+			assert.Equal(t, expected, v)
+		})
+	}
+
+	t.Run("no children", func(t *testing.T) {
+		f, err := workitem.ParseFormula("avg(storypoints) + count()")
+		require.NoError(t, err)
+		v, err := f.Evaluate(fields, nil)
+		require.NoError(t, err)
+		assert.Equal(t, float64(0), v)
+	})
+
+	t.Run("not a number", func(t *testing.T) {
+		f, err := workitem.ParseFormula("system.state + 1")
+		require.NoError(t, err)
+		_, err = f.Evaluate(fields, nil)
+		require.Error(t, err)
+	})
+}
@@ -79,9 +79,27 @@ func (j *FieldDefinitions) Validate() error {
 			return errs.Wrapf(err, "failed to validate field %s", name)
 		}
 	}
+	if _, err := j.computedFieldsOrder(); err != nil {
+		return errs.Wrapf(err, "failed to validate computed fields")
+	}
 	return nil
 }
 
+// keepReadOnlyFields copies the stored values of the read-only fields from the
+// old fields to the new fields. Users can't set those fields, so they must
+// survive updates that replace all other fields (e.g. system.time_spent which
+// is maintained by the worklogs). Computed fields are recalculated anyway.
+func (j FieldDefinitions) keepReadOnlyFields(oldFields, newFields Fields) {
+	for name, def := range j {
+		if !def.ReadOnly || def.Type.GetKind() == KindComputed {
+			continue
+		}
+		if value, ok := oldFields[name]; ok {
+			newFields[name] = value
+		}
+	}
+}
+
 func toBytes(j interface{}) (driver.Value, error) {
 	if j == nil {
 		// log.Trace("returning null")
@@ -0,0 +1,36 @@
+package workitem
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+)
+
+// KeyRegexp matches human-readable work item keys made of the key of the
+// space and the number of the work item, e.g. "PLAT-123". Keys are case
+// insensitive.
+var KeyRegexp = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9]{1,9})-([0-9]+)$`)
+
+// ParseKey splits a work item key into the upper case space key and the work
+// item number
+// returns BadParameterError
+func ParseKey(key string) (string, int, error) {
+	m := KeyRegexp.FindStringSubmatch(key)
+	if m == nil {
+		return "", 0, errors.NewBadParameterError("key", key).Expected("<space key>-<number>")
+	}
+	number, err := strconv.Atoi(m[2])
+	if err != nil {
+		return "", 0, errors.NewBadParameterError("key", key).Expected("<space key>-<number>")
+	}
+	return strings.ToUpper(m[1]), number, nil
+}
+
+// FormatKey returns the human-readable key of the work item with the given
+// number in the space with the given key
+func FormatKey(spaceKey string, number int) string {
+	return fmt.Sprintf("%s-%d", spaceKey, number)
+}
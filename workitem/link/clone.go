@@ -0,0 +1,75 @@
+package link
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// CloneTree clones the work item with the given ID and, if requested by the
+// options, all work items below it in tree links. The clones are linked like
+// their originals and each clone is linked to its original with the given
+// link type unless that is nil. It returns the IDs of the clones by the IDs
+// of their originals.
+func (r *GormWorkItemLinkRepository) CloneTree(ctx context.Context, id uuid.UUID, opts workitem.CloneOptions, clonedFromLinkTypeID uuid.UUID, creatorID uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "cloneTree"}, time.Now())
+	type node struct {
+		id           uuid.UUID
+		parentID     uuid.UUID
+		treeLinkType uuid.UUID
+	}
+	clones := map[uuid.UUID]uuid.UUID{}
+	topologies := map[uuid.UUID]Topology{}
+	queue := []node{{id: id}}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if _, ok := clones[n.id]; ok {
+			continue
+		}
+		clone, _, err := r.workItemRepo.Clone(ctx, n.id, opts, creatorID)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		clones[n.id] = clone.ID
+		if n.parentID != uuid.Nil {
+			if _, err := r.Create(ctx, clones[n.parentID], clone.ID, n.treeLinkType, creatorID); err != nil {
+				return nil, errs.Wrapf(err, "failed to link the clone of work item %s to its parent", n.id)
+			}
+		}
+		if clonedFromLinkTypeID != uuid.Nil {
+			if _, err := r.Create(ctx, clone.ID, n.id, clonedFromLinkTypeID, creatorID); err != nil {
+				return nil, errs.Wrapf(err, "failed to link the clone of work item %s to its original", n.id)
+			}
+		}
+		if !opts.Children {
+			continue
+		}
+		links, err := r.ListByWorkItem(ctx, n.id)
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to list the links of work item %s", n.id)
+		}
+		for _, l := range links {
+			if l.SourceID != n.id {
+				continue
+			}
+			topology, ok := topologies[l.LinkTypeID]
+			if !ok {
+				linkType, err := r.workItemLinkTypeRepo.Load(ctx, l.LinkTypeID)
+				if err != nil {
+					return nil, errs.Wrapf(err, "failed to load work item link type %s", l.LinkTypeID)
+				}
+				topology = linkType.Topology
+				topologies[l.LinkTypeID] = topology
+			}
+			if topology == TopologyTree {
+				queue = append(queue, node{id: l.TargetID, parentID: n.id, treeLinkType: l.LinkTypeID})
+			}
+		}
+	}
+	return clones, nil
+}
@@ -39,12 +39,16 @@ type WorkItemLinkRepository interface {
 	List(ctx context.Context) ([]WorkItemLink, error)
 	ListByWorkItem(ctx context.Context, wiID uuid.UUID) ([]WorkItemLink, error)
 	DeleteRelatedLinks(ctx context.Context, wiID uuid.UUID, suppressorID uuid.UUID) error
+	RestoreRelatedLinks(ctx context.Context, wiID uuid.UUID, deletedSince time.Time, restorerID uuid.UUID) error
 	Delete(ctx context.Context, ID uuid.UUID, suppressorID uuid.UUID) error
 	ListChildLinks(ctx context.Context, linkTypeID uuid.UUID, parentIDs ...uuid.UUID) (WorkItemLinkList, error)
 	ListWorkItemChildren(ctx context.Context, parentID uuid.UUID, start *int, limit *int) ([]workitem.WorkItem, int, error)
 	WorkItemHasChildren(ctx context.Context, parentID uuid.UUID) (bool, error)
 	// GetAncestors returns all ancestors for the given work items.
 	GetAncestors(ctx context.Context, linkTypeID uuid.UUID, upToLevel int, workItemIDs ...uuid.UUID) (ancestors AncestorList, err error)
+	// CloneTree clones a work item and optionally the work items below it in
+	// tree links.
+	CloneTree(ctx context.Context, id uuid.UUID, opts workitem.CloneOptions, clonedFromLinkTypeID uuid.UUID, creatorID uuid.UUID) (map[uuid.UUID]uuid.UUID, error)
 }
 
 // NewWorkItemLinkRepository creates a work item link repository based on gorm
@@ -271,6 +275,9 @@ func (r *GormWorkItemLinkRepository) Create(ctx context.Context, sourceID, targe
 	if err := r.revisionRepo.Create(ctx, creatorID, RevisionTypeCreate, *link); err != nil {
 		return nil, errs.Wrapf(err, "error while creating work item")
 	}
+	if err := r.recalculateParent(ctx, *link); err != nil {
+		return nil, errs.WithStack(err)
+	}
 	return link, nil
 }
 
@@ -381,6 +388,91 @@ func (r *GormWorkItemLinkRepository) DeleteRelatedLinks(ctx context.Context, wiI
 	return nil
 }
 
+// RestoreRelatedLinks restores all links in which the source or target equals
+// the given work item ID and that were deleted at or after the given time.
+// Links are skipped if one of their ends is still deleted, if an equal link
+// was created in the meantime or if restoring them would violate the topology
+// of their link type.
+func (r *GormWorkItemLinkRepository) RestoreRelatedLinks(ctx context.Context, wiID uuid.UUID, deletedSince time.Time, restorerID uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "restoreRelatedLinks"}, time.Now())
+	log.Info(ctx, map[string]interface{}{
+		"wi_id":         wiID,
+		"deleted_since": deletedSince,
+	}, "Restoring the links related to work item")
+	wi, err := r.workItemRepo.LoadFromDB(ctx, wiID)
+	if err != nil {
+		return errs.Wrapf(err, "failed to load work item %s", wiID)
+	}
+	if err := r.acquireLock(wi.SpaceID); err != nil {
+		return errs.Wrap(err, "failed to acquire lock during link restoration")
+	}
+	var workitemLinks = []WorkItemLink{}
+	query := fmt.Sprintf(`
+		? IN (l.source_id, l.target_id)
+		AND l.deleted_at >= ?
+		AND NOT EXISTS (
+			SELECT 1 FROM %[1]s wi
+			WHERE wi.id IN (l.source_id, l.target_id) AND wi.deleted_at IS NOT NULL
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM %[2]s l2
+			WHERE l2.source_id = l.source_id
+				AND l2.target_id = l.target_id
+				AND l2.link_type_id = l.link_type_id
+				AND l2.deleted_at IS NULL
+		)`,
+		workitem.WorkItemStorage{}.TableName(),
+		WorkItemLink{}.TableName())
+	db := r.db.Unscoped().Table(WorkItemLink{}.TableName()+" l").Select("l.*").Where(query, wiID, deletedSince).Order("l.deleted_at ASC").Find(&workitemLinks)
+	if db.Error != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to find deleted links of work item %s", wiID))
+	}
+	// restore one by one to trigger the creation of a new work item link revision
+	for _, workitemLink := range workitemLinks {
+		linkType, err := r.workItemLinkTypeRepo.Load(ctx, workitemLink.LinkTypeID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to load link type %s", workitemLink.LinkTypeID)
+		}
+		if err := r.ValidateTopology(ctx, workitemLink.SourceID, workitemLink.TargetID, *linkType); err != nil {
+			log.Warn(ctx, map[string]interface{}{
+				"wil_id": workitemLink.ID,
+				"err":    err,
+			}, "skipping restoration of work item link")
+			continue
+		}
+		if err := r.restoreLink(ctx, workitemLink, restorerID); err != nil {
+			return errs.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// restoreLink restores the given deleted work item link
+// returns NotFoundError or InternalError
+func (r *GormWorkItemLinkRepository) restoreLink(ctx context.Context, lnk WorkItemLink, restorerID uuid.UUID) error {
+	log.Info(ctx, map[string]interface{}{
+		"wil_id": lnk.ID,
+	}, "Restoring the work item link")
+	lnk.DeletedAt = nil
+	lnk.Version = lnk.Version + 1
+	tx := r.db.Unscoped().Save(&lnk)
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wil_id": lnk.ID,
+			"err":    tx.Error,
+		}, "unable to restore work item link")
+		return errors.NewInternalError(ctx, tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("work item link", lnk.ID.String())
+	}
+	// save a revision of the restored work item link
+	if err := r.revisionRepo.Create(ctx, restorerID, RevisionTypeRestore, lnk); err != nil {
+		return errs.Wrapf(err, "error while restoring work item link")
+	}
+	return r.recalculateParent(ctx, lnk)
+}
+
 // Delete deletes the work item link with the given id
 // returns NotFoundError or InternalError
 func (r *GormWorkItemLinkRepository) deleteLink(ctx context.Context, lnk WorkItemLink, suppressorID uuid.UUID) error {
@@ -403,6 +495,19 @@ func (r *GormWorkItemLinkRepository) deleteLink(ctx context.Context, lnk WorkIte
 	if err := r.revisionRepo.Create(ctx, suppressorID, RevisionTypeDelete, lnk); err != nil {
 		return errs.Wrapf(err, "error while deleting work item")
 	}
+	return r.recalculateParent(ctx, lnk)
+}
+
+// recalculateParent updates the computed fields of the source of the given
+// link if it is a parent-child link, because the children of the source
+// changed.
+func (r *GormWorkItemLinkRepository) recalculateParent(ctx context.Context, lnk WorkItemLink) error {
+	if lnk.LinkTypeID != SystemWorkItemLinkTypeParentChildID {
+		return nil
+	}
+	if err := r.workItemRepo.RecalculateComputedFields(ctx, lnk.SourceID); err != nil {
+		return errs.Wrapf(err, "failed to recalculate computed fields of work item %s", lnk.SourceID)
+	}
 	return nil
 }
 
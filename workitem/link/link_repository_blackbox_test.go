@@ -5,6 +5,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
@@ -892,3 +893,164 @@ func (s *linkRepoBlackBoxTest) TestDeleteLinkAndListChildren() {
 		require.Len(t, childrenList, 0)
 	})
 }
+
+func (s *linkRepoBlackBoxTest) TestCloneTree() {
+	// given a parent with a child and a grandchild and a link type to link
+	// clones to their originals
+	setup := func(t *testing.T) *tf.TestFixture {
+		return tf.NewTestFixture(t, s.DB,
+			tf.WorkItems(3, tf.SetWorkItemTitles("A", "B", "C")),
+			tf.WorkItemLinkTypes(2,
+				tf.SetTopologies(link.TopologyTree, link.TopologyNetwork),
+				tf.SetWorkItemLinkTypeNames("tree-type", "cloned-from")),
+			tf.WorkItemLinksCustom(2, tf.BuildLinks(tf.LinkChain("A", "B", "C")...)),
+		)
+	}
+	s.T().Run("with children", func(t *testing.T) {
+		fxt := setup(t)
+		clonedFrom := fxt.WorkItemLinkTypeByName("cloned-from").ID
+		// when
+		clones, err := s.workitemLinkRepo.CloneTree(s.Ctx, fxt.WorkItemByTitle("A").ID, workitem.CloneOptions{Children: true, Labels: true}, clonedFrom, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		require.Len(t, clones, 3)
+		for _, title := range []string{"A", "B", "C"} {
+			original := fxt.WorkItemByTitle(title)
+			clone, err := s.workitemRepo.LoadByID(s.Ctx, clones[original.ID])
+			require.NoError(t, err)
+			assert.Equal(t, title, clone.Fields[workitem.SystemTitle])
+			assert.NotEqual(t, original.Number, clone.Number)
+			links, err := s.workitemLinkRepo.ListByWorkItem(s.Ctx, clone.ID)
+			require.NoError(t, err)
+			found := false
+			for _, l := range links {
+				if l.SourceID == clone.ID && l.TargetID == original.ID && l.LinkTypeID == clonedFrom {
+					found = true
+				}
+			}
+			assert.True(t, found, "missing link from clone of %s to its original", title)
+		}
+		// the clones are linked like their originals
+		links, err := s.workitemLinkRepo.ListByWorkItem(s.Ctx, clones[fxt.WorkItemByTitle("B").ID])
+		require.NoError(t, err)
+		found := false
+		for _, l := range links {
+			if l.SourceID == clones[fxt.WorkItemByTitle("B").ID] && l.TargetID == clones[fxt.WorkItemByTitle("C").ID] && l.LinkTypeID == fxt.WorkItemLinkTypeByName("tree-type").ID {
+				found = true
+			}
+		}
+		assert.True(t, found, "missing tree link between the clones of B and C")
+	})
+	s.T().Run("without children and link", func(t *testing.T) {
+		fxt := setup(t)
+		// when
+		clones, err := s.workitemLinkRepo.CloneTree(s.Ctx, fxt.WorkItemByTitle("A").ID, workitem.CloneOptions{}, uuid.Nil, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		require.Len(t, clones, 1)
+		links, err := s.workitemLinkRepo.ListByWorkItem(s.Ctx, clones[fxt.WorkItemByTitle("A").ID])
+		require.NoError(t, err)
+		assert.Empty(t, links)
+	})
+	s.T().Run("unknown work item", func(t *testing.T) {
+		fxt := setup(t)
+		// when
+		_, err := s.workitemLinkRepo.CloneTree(s.Ctx, uuid.NewV4(), workitem.CloneOptions{}, uuid.Nil, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+	})
+}
+
+func (s *linkRepoBlackBoxTest) TestRestoreRelatedLinks() {
+	// given a parent with two children
+	setup := func(t *testing.T) *tf.TestFixture {
+		return tf.NewTestFixture(t, s.DB,
+			tf.WorkItems(3, tf.SetWorkItemTitles("parent", "child1", "child2")),
+			tf.WorkItemLinksCustom(2, func(fxt *tf.TestFixture, idx int) error {
+				l := fxt.WorkItemLinks[idx]
+				l.LinkTypeID = link.SystemWorkItemLinkTypeParentChildID
+				l.SourceID = fxt.WorkItems[0].ID
+				l.TargetID = fxt.WorkItems[idx+1].ID
+				return nil
+			}),
+		)
+	}
+	// deleteWorkItem deletes the work item along with its links the same way
+	// the work item controller does and returns the deletion time
+	deleteWorkItem := func(t *testing.T, fxt *tf.TestFixture, id uuid.UUID) time.Time {
+		require.NoError(t, s.workitemRepo.Delete(s.Ctx, id, fxt.Identities[0].ID))
+		require.NoError(t, s.workitemLinkRepo.DeleteRelatedLinks(s.Ctx, id, fxt.Identities[0].ID))
+		deletedAt, err := s.workitemRepo.DeletedAt(s.Ctx, id)
+		require.NoError(t, err)
+		require.NotNil(t, deletedAt)
+		return *deletedAt
+	}
+	childIDs := func(t *testing.T, parentID uuid.UUID) []uuid.UUID {
+		links, err := s.workitemLinkRepo.ListChildLinks(s.Ctx, link.SystemWorkItemLinkTypeParentChildID, parentID)
+		require.NoError(t, err)
+		ids := []uuid.UUID{}
+		for _, l := range links {
+			ids = append(ids, l.TargetID)
+		}
+		return ids
+	}
+
+	s.T().Run("ok - links deleted with the work item are restored", func(t *testing.T) {
+		fxt := setup(t)
+		child := fxt.WorkItemByTitle("child1")
+		deletedAt := deleteWorkItem(t, fxt, child.ID)
+		require.Equal(t, []uuid.UUID{fxt.WorkItemByTitle("child2").ID}, childIDs(t, fxt.WorkItemByTitle("parent").ID))
+		// when
+		_, _, err := s.workitemRepo.Restore(s.Ctx, child.ID, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		err = s.workitemLinkRepo.RestoreRelatedLinks(s.Ctx, child.ID, deletedAt, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{child.ID, fxt.WorkItemByTitle("child2").ID}, childIDs(t, fxt.WorkItemByTitle("parent").ID))
+	})
+
+	s.T().Run("ok - links deleted before the work item stay deleted", func(t *testing.T) {
+		fxt := setup(t)
+		child := fxt.WorkItemByTitle("child1")
+		require.NoError(t, s.workitemLinkRepo.Delete(s.Ctx, fxt.WorkItemLinks[0].ID, fxt.Identities[0].ID))
+		deletedAt := deleteWorkItem(t, fxt, child.ID)
+		// when
+		_, _, err := s.workitemRepo.Restore(s.Ctx, child.ID, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		err = s.workitemLinkRepo.RestoreRelatedLinks(s.Ctx, child.ID, deletedAt, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fxt.WorkItemByTitle("child2").ID}, childIDs(t, fxt.WorkItemByTitle("parent").ID))
+	})
+
+	s.T().Run("ok - links to a deleted work item stay deleted", func(t *testing.T) {
+		fxt := setup(t)
+		child := fxt.WorkItemByTitle("child1")
+		deletedAt := deleteWorkItem(t, fxt, child.ID)
+		deleteWorkItem(t, fxt, fxt.WorkItemByTitle("parent").ID)
+		// when
+		_, _, err := s.workitemRepo.Restore(s.Ctx, child.ID, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		err = s.workitemLinkRepo.RestoreRelatedLinks(s.Ctx, child.ID, deletedAt, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, childIDs(t, fxt.WorkItemByTitle("parent").ID))
+	})
+
+	s.T().Run("ok - links violating the topology are skipped", func(t *testing.T) {
+		fxt := setup(t)
+		child := fxt.WorkItemByTitle("child1")
+		deletedAt := deleteWorkItem(t, fxt, child.ID)
+		_, _, err := s.workitemRepo.Restore(s.Ctx, child.ID, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		// child1 got a new parent in the meantime
+		_, err = s.workitemLinkRepo.Create(s.Ctx, fxt.WorkItemByTitle("child2").ID, child.ID, link.SystemWorkItemLinkTypeParentChildID, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		// when
+		err = s.workitemLinkRepo.RestoreRelatedLinks(s.Ctx, child.ID, deletedAt, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fxt.WorkItemByTitle("child2").ID}, childIDs(t, fxt.WorkItemByTitle("parent").ID))
+		assert.Equal(t, []uuid.UUID{child.ID}, childIDs(t, fxt.WorkItemByTitle("child2").ID))
+	})
+}
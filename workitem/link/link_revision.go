@@ -19,7 +19,8 @@ const (
 	// RevisionTypeUpdate a work item link update
 	// TODO(kwk): can we remove this "update" revsion type? We no longer support updating a link.
 	RevisionTypeUpdate // 4
-
+	// RevisionTypeRestore a work item link restoration after a deletion
+	RevisionTypeRestore // 5
 )
 
 // Revision represents a version of a work item link
@@ -7,6 +7,7 @@ import (
 	convert "github.com/fabric8-services/fabric8-wit/convert"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	"github.com/fabric8-services/fabric8-wit/workitem"
 	errs "github.com/pkg/errors"
 
 	uuid "github.com/satori/go.uuid"
@@ -16,7 +17,7 @@ import (
 var (
 	SystemWorkItemLinkTypeBugBlockerID     = uuid.FromStringOrNil("2CEA3C79-3B79-423B-90F4-1E59174C8F43")
 	SystemWorkItemLinkPlannerItemRelatedID = uuid.FromStringOrNil("9B631885-83B1-4ABB-A340-3A9EDE8493FA")
-	SystemWorkItemLinkTypeParentChildID    = uuid.FromStringOrNil("25C326A7-6D03-4F5A-B23B-86A9EE4171E9")
+	SystemWorkItemLinkTypeParentChildID    = workitem.SystemWorkItemLinkTypeParentChildID
 )
 
 // WorkItemLinkType represents the type of a work item link as it is stored in
@@ -0,0 +1,67 @@
+// Package recurrence contains the definitions of work items that are created
+// over and over again on a schedule, e.g. a release checklist every sprint.
+package recurrence
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Recurrence defines a work item that is created on a cron schedule
+type Recurrence struct {
+	gormsupport.Lifecycle
+	ID      uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
+	SpaceID uuid.UUID `sql:"type:uuid"`
+	// TypeID is the type of the work items to create
+	TypeID    uuid.UUID `sql:"type:uuid"`
+	CreatorID uuid.UUID `sql:"type:uuid"`
+	Name      string
+	// Fields are the field values of the work items to create
+	Fields workitem.Fields `sql:"type:jsonb"`
+	// Schedule is a cron expression as understood by github.com/robfig/cron,
+	// e.g. "0 0 9 * * MON" or "@weekly"
+	Schedule string
+	// CurrentIteration puts the work items into the active iteration of the
+	// space instead of the iteration given in the fields
+	CurrentIteration bool
+	// SkipIfOpen skips an occurrence as long as the work item of the previous
+	// occurrence isn't closed
+	SkipIfOpen bool
+	Enabled    bool
+	// LastRunAt is when the schedule was last due
+	LastRunAt *time.Time
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (r Recurrence) TableName() string {
+	return "work_item_recurrences"
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (r Recurrence) GetETagData() []interface{} {
+	return []interface{}{r.ID, strconv.FormatInt(r.UpdatedAt.Unix(), 10)}
+}
+
+// GetLastModified returns the last modification time
+func (r Recurrence) GetLastModified() time.Time {
+	return r.UpdatedAt.Truncate(time.Second)
+}
+
+// Occurrence records a work item that was created for a recurrence
+type Occurrence struct {
+	ID           uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
+	CreatedAt    time.Time
+	RecurrenceID uuid.UUID `sql:"type:uuid"`
+	WorkItemID   uuid.UUID `sql:"type:uuid"`
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (o Occurrence) TableName() string {
+	return "work_item_occurrences"
+}
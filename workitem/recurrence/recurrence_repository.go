@@ -0,0 +1,318 @@
+package recurrence
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/application/repository"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	"github.com/robfig/cron"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Repository describes interactions with recurrences
+type Repository interface {
+	repository.Exister
+	Create(ctx context.Context, recurrence *Recurrence) error
+	Save(ctx context.Context, recurrence *Recurrence) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Load(ctx context.Context, id uuid.UUID) (*Recurrence, error)
+	// List returns the recurrences of the given space
+	List(ctx context.Context, spaceID uuid.UUID) ([]Recurrence, error)
+	// ListEnabled returns the enabled recurrences of all spaces
+	ListEnabled(ctx context.Context) ([]Recurrence, error)
+	// Occurrences returns the work items created for the given recurrence,
+	// latest first
+	Occurrences(ctx context.Context, recurrenceID uuid.UUID) ([]Occurrence, error)
+	// Run creates the work item of the given recurrence that was due at the
+	// given time and records it as an occurrence. It returns nil if the
+	// occurrence was skipped because the recurrence already ran for that time,
+	// e.g. on another instance, or because the work item of the previous
+	// occurrence is still open.
+	Run(ctx context.Context, id uuid.UUID, due time.Time) (*Occurrence, error)
+}
+
+// NewRepository creates a new storage type.
+func NewRepository(db *gorm.DB) Repository {
+	return &GormRecurrenceRepository{
+		db:            db,
+		workItemRepo:  workitem.NewWorkItemRepository(db),
+		witRepo:       workitem.NewWorkItemTypeRepository(db),
+		iterationRepo: iteration.NewIterationRepository(db),
+	}
+}
+
+// GormRecurrenceRepository is the implementation of the storage interface for
+// recurrences.
+type GormRecurrenceRepository struct {
+	db            *gorm.DB
+	workItemRepo  *workitem.GormWorkItemRepository
+	witRepo       *workitem.GormWorkItemTypeRepository
+	iterationRepo iteration.Repository
+}
+
+func (r *GormRecurrenceRepository) validate(ctx context.Context, recurrence Recurrence) error {
+	if recurrence.Name == "" {
+		return errors.NewBadParameterError("name", recurrence.Name).Expected("not empty")
+	}
+	if _, err := cron.Parse(recurrence.Schedule); err != nil {
+		return errors.NewBadParameterError("schedule", recurrence.Schedule).Expected("a cron expression")
+	}
+	if title, ok := recurrence.Fields[workitem.SystemTitle].(string); !ok || title == "" {
+		return errors.NewBadParameterError("fields", recurrence.Fields).Expected("a title")
+	}
+	wit, err := r.witRepo.Load(ctx, recurrence.TypeID)
+	if err != nil {
+		return errs.Wrapf(err, "failed to load work item type %s", recurrence.TypeID)
+	}
+	if _, err := r.workItemRepo.CheckTypeAndSpaceShareTemplate(ctx, wit, recurrence.SpaceID); err != nil {
+		return errs.WithStack(err)
+	}
+	// convert the fields the same way the work item repository does when an
+	// occurrence is created so that bad values don't only show up in the
+	// scheduler
+	for fieldName, fieldDef := range wit.Fields {
+		if fieldDef.ReadOnly || fieldDef.Type.GetKind() == workitem.KindComputed {
+			continue
+		}
+		if fieldName == workitem.SystemIteration && recurrence.CurrentIteration {
+			continue
+		}
+		fieldValue := recurrence.Fields[fieldName]
+		if _, err := fieldDef.ConvertToModel(fieldName, fieldValue); err != nil {
+			if badParamErr, ok := errs.Cause(err).(errors.BadParameterError); ok {
+				return badParamErr
+			}
+			return errors.NewBadParameterError(fieldName, fieldValue)
+		}
+	}
+	return nil
+}
+
+// Create implements Repository
+func (r *GormRecurrenceRepository) Create(ctx context.Context, recurrence *Recurrence) error {
+	defer goa.MeasureSince([]string{"goa", "db", "recurrence", "create"}, time.Now())
+	if err := r.validate(ctx, *recurrence); err != nil {
+		return err
+	}
+	recurrence.ID = uuid.NewV4()
+	recurrence.LastRunAt = nil
+	if err := r.db.Create(recurrence).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"space_id": recurrence.SpaceID,
+			"err":      err,
+		}, "unable to create the recurrence")
+		return errors.NewInternalError(ctx, errs.Wrap(err, "failed to create recurrence"))
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"recurrence_id": recurrence.ID,
+	}, "Recurrence created!")
+	return nil
+}
+
+// Save implements Repository
+func (r *GormRecurrenceRepository) Save(ctx context.Context, recurrence *Recurrence) error {
+	defer goa.MeasureSince([]string{"goa", "db", "recurrence", "save"}, time.Now())
+	existing, err := r.Load(ctx, recurrence.ID)
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	// the space and the creator of a recurrence can't be changed
+	recurrence.SpaceID = existing.SpaceID
+	recurrence.CreatorID = existing.CreatorID
+	recurrence.CreatedAt = existing.CreatedAt
+	recurrence.LastRunAt = existing.LastRunAt
+	if err := r.validate(ctx, *recurrence); err != nil {
+		return err
+	}
+	if err := r.db.Save(recurrence).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"recurrence_id": recurrence.ID,
+			"err":           err,
+		}, "unable to save the recurrence")
+		return errors.NewInternalError(ctx, errs.Wrap(err, "failed to save recurrence"))
+	}
+	return nil
+}
+
+// Delete implements Repository
+func (r *GormRecurrenceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "recurrence", "delete"}, time.Now())
+	tx := r.db.Delete(&Recurrence{ID: id})
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"recurrence_id": id,
+			"err":           tx.Error,
+		}, "unable to delete the recurrence")
+		return errors.NewInternalError(ctx, errs.Wrap(tx.Error, "failed to delete recurrence"))
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("recurrence", id.String())
+	}
+	return nil
+}
+
+// Load implements Repository
+func (r *GormRecurrenceRepository) Load(ctx context.Context, id uuid.UUID) (*Recurrence, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "recurrence", "get"}, time.Now())
+	var obj Recurrence
+	tx := r.db.Where("id = ?", id).First(&obj)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("recurrence", id.String())
+	}
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"recurrence_id": id,
+			"err":           tx.Error,
+		}, "unable to load the recurrence")
+		return nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	return &obj, nil
+}
+
+// List implements Repository
+func (r *GormRecurrenceRepository) List(ctx context.Context, spaceID uuid.UUID) ([]Recurrence, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "recurrence", "list"}, time.Now())
+	result := []Recurrence{}
+	if err := r.db.Where("space_id = ?", spaceID).Order("name").Find(&result).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list recurrences of space %s", spaceID))
+	}
+	return result, nil
+}
+
+// ListEnabled implements Repository
+func (r *GormRecurrenceRepository) ListEnabled(ctx context.Context) ([]Recurrence, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "recurrence", "listEnabled"}, time.Now())
+	result := []Recurrence{}
+	if err := r.db.Where("enabled = ?", true).Find(&result).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to list enabled recurrences"))
+	}
+	return result, nil
+}
+
+// Occurrences implements Repository
+func (r *GormRecurrenceRepository) Occurrences(ctx context.Context, recurrenceID uuid.UUID) ([]Occurrence, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "recurrence", "occurrences"}, time.Now())
+	if err := r.CheckExists(ctx, recurrenceID); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	result := []Occurrence{}
+	if err := r.db.Where("recurrence_id = ?", recurrenceID).Order("created_at desc").Find(&result).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list occurrences of recurrence %s", recurrenceID))
+	}
+	return result, nil
+}
+
+// Run implements Repository
+func (r *GormRecurrenceRepository) Run(ctx context.Context, id uuid.UUID, due time.Time) (*Occurrence, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "recurrence", "run"}, time.Now())
+	// lock the recurrence so that concurrent runs don't create two work items
+	var recurrence Recurrence
+	tx := r.db.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", id).First(&recurrence)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("recurrence", id.String())
+	}
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	if recurrence.LastRunAt != nil && !recurrence.LastRunAt.Before(due) {
+		log.Info(ctx, map[string]interface{}{
+			"recurrence_id": id,
+			"due":           due,
+			"last_run_at":   *recurrence.LastRunAt,
+		}, "skipping the occurrence because the recurrence already ran")
+		return nil, nil
+	}
+	if err := r.db.Model(&recurrence).UpdateColumn("last_run_at", due).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to update recurrence %s", id))
+	}
+	if recurrence.SkipIfOpen {
+		open, err := r.previousIsOpen(ctx, recurrence.ID)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		if open {
+			log.Info(ctx, map[string]interface{}{
+				"recurrence_id": id,
+			}, "skipping the occurrence because the previous one is still open")
+			return nil, nil
+		}
+	}
+	fields := make(map[string]interface{}, len(recurrence.Fields))
+	for name, value := range recurrence.Fields {
+		fields[name] = value
+	}
+	if recurrence.CurrentIteration {
+		itr, err := r.activeIteration(ctx, recurrence.SpaceID)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		if itr != nil {
+			fields[workitem.SystemIteration] = itr.ID.String()
+		}
+	}
+	wi, _, err := r.workItemRepo.Create(ctx, recurrence.SpaceID, recurrence.TypeID, fields, recurrence.CreatorID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to create the work item of recurrence %s", id)
+	}
+	occurrence := Occurrence{
+		ID:           uuid.NewV4(),
+		RecurrenceID: recurrence.ID,
+		WorkItemID:   wi.ID,
+	}
+	if err := r.db.Create(&occurrence).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to record the occurrence of recurrence %s", id))
+	}
+	return &occurrence, nil
+}
+
+// previousIsOpen returns true if the work item of the latest occurrence of the
+// given recurrence exists and isn't closed
+func (r *GormRecurrenceRepository) previousIsOpen(ctx context.Context, recurrenceID uuid.UUID) (bool, error) {
+	var previous workitem.WorkItemStorage
+	tx := r.db.Table(workitem.WorkItemStorage{}.TableName()).
+		Select("work_items.*").
+		Joins("JOIN work_item_occurrences o ON o.work_item_id = work_items.id").
+		Where("o.recurrence_id = ? AND work_items.deleted_at IS NULL", recurrenceID).
+		Order("o.created_at desc").
+		First(&previous)
+	if tx.RecordNotFound() {
+		return false, nil
+	}
+	if tx.Error != nil {
+		return false, errors.NewInternalError(ctx, errs.Wrapf(tx.Error, "failed to load the previous occurrence of recurrence %s", recurrenceID))
+	}
+	return !workitem.IsClosed(previous.Fields), nil
+}
+
+// activeIteration returns the most specific active iteration of the given
+// space or nil if none is active
+func (r *GormRecurrenceRepository) activeIteration(ctx context.Context, spaceID uuid.UUID) (*iteration.Iteration, error) {
+	iterations, err := r.iterationRepo.List(ctx, spaceID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to list iterations of space %s", spaceID)
+	}
+	var active *iteration.Iteration
+	for i := range iterations {
+		itr := iterations[i]
+		if itr.IsRoot(spaceID) || !itr.IsActive() {
+			continue
+		}
+		if active == nil || len(itr.Path) > len(active.Path) {
+			active = &itr
+		}
+	}
+	return active, nil
+}
+
+// CheckExists returns nil if the given ID exists otherwise returns an error
+func (r *GormRecurrenceRepository) CheckExists(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "recurrence", "exists"}, time.Now())
+	return repository.CheckExists(ctx, r.db, Recurrence{}.TableName(), id)
+}
@@ -0,0 +1,236 @@
+package recurrence_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/recurrence"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type recurrenceRepositoryBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	repo recurrence.Repository
+}
+
+func TestRunRecurrenceRepositoryBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &recurrenceRepositoryBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *recurrenceRepositoryBlackBoxTest) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.repo = recurrence.NewRepository(s.DB)
+}
+
+func newRecurrence(fxt *tf.TestFixture) *recurrence.Recurrence {
+	return &recurrence.Recurrence{
+		SpaceID:   fxt.Spaces[0].ID,
+		TypeID:    fxt.WorkItemTypes[0].ID,
+		CreatorID: fxt.Identities[0].ID,
+		Name:      "release checklist",
+		Fields:    workitem.Fields{workitem.SystemTitle: "Release checklist"},
+		Schedule:  "@weekly",
+		Enabled:   true,
+	}
+}
+
+func (s *recurrenceRepositoryBlackBoxTest) TestCreate() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment())
+		r := newRecurrence(fxt)
+		// when
+		err := s.repo.Create(s.Ctx, r)
+		// then
+		require.NoError(t, err)
+		loaded, err := s.repo.Load(s.Ctx, r.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "@weekly", loaded.Schedule)
+		assert.Equal(t, "Release checklist", loaded.Fields[workitem.SystemTitle])
+		recurrences, err := s.repo.List(s.Ctx, fxt.Spaces[0].ID)
+		require.NoError(t, err)
+		require.Len(t, recurrences, 1)
+		assert.Equal(t, r.ID, recurrences[0].ID)
+	})
+	s.T().Run("invalid schedule", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment())
+		r := newRecurrence(fxt)
+		r.Schedule = "every monday"
+		// when
+		err := s.repo.Create(s.Ctx, r)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("missing title", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment())
+		r := newRecurrence(fxt)
+		r.Fields = workitem.Fields{}
+		// when
+		err := s.repo.Create(s.Ctx, r)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("invalid field value", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment())
+		r := newRecurrence(fxt)
+		r.Fields[workitem.SystemState] = "foo"
+		// when
+		err := s.repo.Create(s.Ctx, r)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("invalid field value on save", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment())
+		r := newRecurrence(fxt)
+		require.NoError(t, s.repo.Create(s.Ctx, r))
+		r.Fields[workitem.SystemState] = "foo"
+		// when
+		err := s.repo.Save(s.Ctx, r)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("unknown type", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment())
+		r := newRecurrence(fxt)
+		r.TypeID = uuid.NewV4()
+		// when
+		err := s.repo.Create(s.Ctx, r)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *recurrenceRepositoryBlackBoxTest) TestRun() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment())
+		r := newRecurrence(fxt)
+		require.NoError(t, s.repo.Create(s.Ctx, r))
+		// when
+		occurrence, err := s.repo.Run(s.Ctx, r.ID, time.Now())
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, occurrence)
+		wi, err := workitem.NewWorkItemRepository(s.DB).LoadByID(s.Ctx, occurrence.WorkItemID)
+		require.NoError(t, err)
+		assert.Equal(t, "Release checklist", wi.Fields[workitem.SystemTitle])
+		assert.Equal(t, fxt.Identities[0].ID.String(), wi.Fields[workitem.SystemCreator])
+		occurrences, err := s.repo.Occurrences(s.Ctx, r.ID)
+		require.NoError(t, err)
+		require.Len(t, occurrences, 1)
+		assert.Equal(t, wi.ID, occurrences[0].WorkItemID)
+		loaded, err := s.repo.Load(s.Ctx, r.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, loaded.LastRunAt)
+	})
+	s.T().Run("current iteration", func(t *testing.T) {
+		// given a root iteration and an active child iteration
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.CreateWorkItemEnvironment(),
+			tf.Iterations(2, tf.PlaceIterationUnderRootIteration(), func(fxt *tf.TestFixture, idx int) error {
+				fxt.Iterations[idx].UserActive = idx == 1
+				return nil
+			}),
+		)
+		r := newRecurrence(fxt)
+		r.CurrentIteration = true
+		require.NoError(t, s.repo.Create(s.Ctx, r))
+		// when
+		occurrence, err := s.repo.Run(s.Ctx, r.ID, time.Now())
+		// then
+		require.NoError(t, err)
+		wi, err := workitem.NewWorkItemRepository(s.DB).LoadByID(s.Ctx, occurrence.WorkItemID)
+		require.NoError(t, err)
+		assert.Equal(t, fxt.Iterations[1].ID.String(), wi.Fields[workitem.SystemIteration])
+	})
+	s.T().Run("skip if open", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment())
+		r := newRecurrence(fxt)
+		r.SkipIfOpen = true
+		require.NoError(t, s.repo.Create(s.Ctx, r))
+		first, err := s.repo.Run(s.Ctx, r.ID, time.Now())
+		require.NoError(t, err)
+		require.NotNil(t, first)
+		t.Run("previous is open", func(t *testing.T) {
+			// when
+			occurrence, err := s.repo.Run(s.Ctx, r.ID, time.Now())
+			// then
+			require.NoError(t, err)
+			assert.Nil(t, occurrence)
+		})
+		t.Run("previous is closed", func(t *testing.T) {
+			// given
+			wiRepo := workitem.NewWorkItemRepository(s.DB)
+			wi, err := wiRepo.LoadByID(s.Ctx, first.WorkItemID)
+			require.NoError(t, err)
+			wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+			_, _, err = wiRepo.Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[0].ID)
+			require.NoError(t, err)
+			// when
+			occurrence, err := s.repo.Run(s.Ctx, r.ID, time.Now())
+			// then
+			require.NoError(t, err)
+			require.NotNil(t, occurrence)
+			occurrences, err := s.repo.Occurrences(s.Ctx, r.ID)
+			require.NoError(t, err)
+			assert.Len(t, occurrences, 2)
+		})
+	})
+	s.T().Run("already ran for the due time", func(t *testing.T) {
+		// given a recurrence that ran on another instance
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment())
+		r := newRecurrence(fxt)
+		require.NoError(t, s.repo.Create(s.Ctx, r))
+		due := time.Date(2018, 3, 4, 9, 0, 0, 0, time.UTC)
+		first, err := s.repo.Run(s.Ctx, r.ID, due)
+		require.NoError(t, err)
+		require.NotNil(t, first)
+		// when
+		occurrence, err := s.repo.Run(s.Ctx, r.ID, due)
+		// then
+		require.NoError(t, err)
+		assert.Nil(t, occurrence)
+		occurrences, err := s.repo.Occurrences(s.Ctx, r.ID)
+		require.NoError(t, err)
+		assert.Len(t, occurrences, 1)
+		t.Run("next due time", func(t *testing.T) {
+			// when
+			occurrence, err := s.repo.Run(s.Ctx, r.ID, due.Add(7*24*time.Hour))
+			// then
+			require.NoError(t, err)
+			require.NotNil(t, occurrence)
+			loaded, err := s.repo.Load(s.Ctx, r.ID)
+			require.NoError(t, err)
+			require.NotNil(t, loaded.LastRunAt)
+			assert.True(t, due.Add(7*24*time.Hour).Equal(*loaded.LastRunAt))
+		})
+	})
+	s.T().Run("unknown recurrence", func(t *testing.T) {
+		// when
+		_, err := s.repo.Run(s.Ctx, uuid.NewV4(), time.Now())
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
@@ -0,0 +1,167 @@
+package recurrence
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/models"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron"
+	uuid "github.com/satori/go.uuid"
+)
+
+// dueWindow is how long before the start of a run the schedule is searched
+// for the time it was due. Jobs start right when they are due, so this only
+// needs to cover delays of the cron runner.
+const dueWindow = time.Minute
+
+// Scheduler creates the work items of all enabled recurrences on their
+// schedules
+type Scheduler struct {
+	db   *gorm.DB
+	lock sync.Mutex
+	cr   *cron.Cron
+	// schedules are the currently scheduled cron expressions by recurrence
+	schedules map[uuid.UUID]string
+	stop      chan struct{}
+}
+
+// NewScheduler creates a new Scheduler
+func NewScheduler(db *gorm.DB) *Scheduler {
+	return &Scheduler{db: db, cr: cron.New()}
+}
+
+// Start schedules all enabled recurrences and reloads them in the given
+// interval to pick up the changes made through other instances
+func (s *Scheduler) Start(ctx context.Context, reloadInterval time.Duration) {
+	s.ScheduleAll(ctx)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.stop != nil || reloadInterval <= 0 {
+		return
+	}
+	stop := make(chan struct{})
+	s.stop = stop
+	go func() {
+		ticker := time.NewTicker(reloadInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.ScheduleAll(ctx)
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop scheduler
+// This should be called only from main
+func (s *Scheduler) Stop() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+	s.cr.Stop()
+}
+
+// ScheduleAll (re)schedules all enabled recurrences. Call it whenever a
+// recurrence was created, changed or deleted. The given context must outlive
+// the scheduler because the runs use it. The scheduled runs are kept if none
+// of the schedules changed.
+func (s *Scheduler) ScheduleAll(ctx context.Context) {
+	recurrences, err := NewRepository(s.db).ListEnabled(ctx)
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err": err,
+		}, "fetch operation failed for recurrences")
+		return
+	}
+	schedules := make(map[uuid.UUID]string, len(recurrences))
+	for _, r := range recurrences {
+		schedules[r.ID] = r.Schedule
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if sameSchedules(s.schedules, schedules) {
+		return
+	}
+	s.cr.Stop()
+	s.cr = cron.New()
+	for _, r := range recurrences {
+		recurrenceID := r.ID
+		schedule, err := cron.Parse(r.Schedule)
+		if err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"recurrence_id": recurrenceID,
+				"schedule":      r.Schedule,
+				"err":           err,
+			}, "unable to schedule the recurrence")
+			continue
+		}
+		s.cr.Schedule(schedule, cron.FuncJob(func() {
+			s.run(ctx, recurrenceID, dueTime(schedule, time.Now()))
+		}))
+	}
+	s.schedules = schedules
+	s.cr.Start()
+}
+
+// sameSchedules returns true if both maps hold the same schedules for the
+// same recurrences
+func sameSchedules(a, b map[uuid.UUID]string) bool {
+	if a == nil || len(a) != len(b) {
+		return false
+	}
+	for id, schedule := range a {
+		if other, ok := b[id]; !ok || other != schedule {
+			return false
+		}
+	}
+	return true
+}
+
+// dueTime returns the latest time at or before now at which the given
+// schedule was due. All instances that run the same schedule at about the
+// same time get the same due time, which allows to run it only once.
+func dueTime(schedule cron.Schedule, now time.Time) time.Time {
+	var due time.Time
+	for t := schedule.Next(now.Add(-dueWindow)); !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+		due = t
+	}
+	if due.IsZero() {
+		return now.Truncate(time.Second)
+	}
+	return due
+}
+
+// run creates the occurrence of the given recurrence that was due at the
+// given time
+func (s *Scheduler) run(ctx context.Context, recurrenceID uuid.UUID, due time.Time) {
+	err := models.Transactional(s.db, func(tx *gorm.DB) error {
+		occurrence, err := NewRepository(tx).Run(ctx, recurrenceID, due)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if occurrence != nil {
+			log.Info(ctx, map[string]interface{}{
+				"recurrence_id": recurrenceID,
+				"wi_id":         occurrence.WorkItemID,
+			}, "created the work item of the recurrence")
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"recurrence_id": recurrenceID,
+			"err":           err,
+		}, "unable to create the work item of the recurrence")
+	}
+}
@@ -0,0 +1,47 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/robfig/cron"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDueTime(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	schedule, err := cron.Parse("0 0 9 * * MON")
+	require.NoError(t, err)
+	due := time.Date(2018, 3, 5, 9, 0, 0, 0, time.Local)
+
+	t.Run("run on time", func(t *testing.T) {
+		assert.Equal(t, due, dueTime(schedule, due))
+	})
+	t.Run("run late", func(t *testing.T) {
+		assert.Equal(t, due, dueTime(schedule, due.Add(1500*time.Millisecond)))
+	})
+	t.Run("all instances get the same due time", func(t *testing.T) {
+		assert.Equal(t, dueTime(schedule, due.Add(10*time.Millisecond)), dueTime(schedule, due.Add(2*time.Second)))
+	})
+	t.Run("frequent schedule", func(t *testing.T) {
+		everySecond, err := cron.Parse("* * * * * *")
+		require.NoError(t, err)
+		assert.Equal(t, due.Add(2*time.Second), dueTime(everySecond, due.Add(2500*time.Millisecond)))
+	})
+}
+
+func TestSameSchedules(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	id1, id2 := uuid.NewV4(), uuid.NewV4()
+	current := map[uuid.UUID]string{id1: "@weekly", id2: "@daily"}
+
+	assert.True(t, sameSchedules(current, map[uuid.UUID]string{id1: "@weekly", id2: "@daily"}))
+	assert.True(t, sameSchedules(map[uuid.UUID]string{}, map[uuid.UUID]string{}))
+	assert.False(t, sameSchedules(nil, map[uuid.UUID]string{}), "nothing was scheduled yet")
+	assert.False(t, sameSchedules(current, map[uuid.UUID]string{id1: "@weekly"}), "recurrence deleted or disabled")
+	assert.False(t, sameSchedules(current, map[uuid.UUID]string{id1: "@weekly", id2: "@hourly"}), "schedule changed")
+	assert.False(t, sameSchedules(current, map[uuid.UUID]string{id1: "@weekly", uuid.NewV4(): "@daily"}), "recurrence replaced")
+}
@@ -0,0 +1,141 @@
+package workitem
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// IsWorkItemReference returns true if a field of the given type references
+// other work items, i.e. if it is of kind KindWorkItem or a list of it.
+func IsWorkItemReference(t FieldType) bool {
+	switch fieldType := t.(type) {
+	case SimpleType:
+		return fieldType.Kind == KindWorkItem
+	case ListType:
+		return fieldType.ComponentType.Kind == KindWorkItem
+	}
+	return false
+}
+
+// IsReleaseReference returns true if a field of the given type references
+// releases, i.e. if it is of kind KindRelease or a list of it.
+func IsReleaseReference(t FieldType) bool {
+	switch fieldType := t.(type) {
+	case SimpleType:
+		return fieldType.Kind == KindRelease
+	case ListType:
+		return fieldType.ComponentType.Kind == KindRelease
+	}
+	return false
+}
+
+// ReferencedWorkItemIDs returns the IDs of the work items referenced by the
+// given fields (in storage representation) grouped by field name. Values that
+// are not valid IDs are ignored.
+func (j FieldDefinitions) ReferencedWorkItemIDs(fields Fields) map[string][]uuid.UUID {
+	return j.referencedIDs(fields, IsWorkItemReference)
+}
+
+// referencedIDs returns the IDs held by the given fields whose type matches
+// the given predicate, grouped by field name
+func (j FieldDefinitions) referencedIDs(fields Fields, isReference func(FieldType) bool) map[string][]uuid.UUID {
+	result := map[string][]uuid.UUID{}
+	for name, def := range j {
+		if !isReference(def.Type) {
+			continue
+		}
+		var values []interface{}
+		switch v := fields[name].(type) {
+		case string:
+			values = []interface{}{v}
+		case []interface{}:
+			values = v
+		}
+		for _, v := range values {
+			s, ok := v.(string)
+			if !ok {
+				continue
+			}
+			id, err := uuid.FromString(s)
+			if err != nil {
+				continue
+			}
+			result[name] = append(result[name], id)
+		}
+	}
+	return result
+}
+
+// checkWorkItemReferences returns a violation for every field of the given
+// work item that references a work item which doesn't exist in the same
+// space or the work item itself.
+// returns InternalError
+func (r *GormWorkItemRepository) checkWorkItemReferences(ctx context.Context, wiType *WorkItemType, wi WorkItemStorage) ([]errors.BadParameterError, error) {
+	refs := wiType.Fields.ReferencedWorkItemIDs(wi.Fields)
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	ids := []uuid.UUID{}
+	for _, fieldIDs := range refs {
+		ids = append(ids, fieldIDs...)
+	}
+	var found []WorkItemStorage
+	err := r.db.Select("id").Where("id IN (?) AND space_id = ?", ids, wi.SpaceID).Find(&found).Error
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	existing := map[uuid.UUID]struct{}{}
+	for _, f := range found {
+		existing[f.ID] = struct{}{}
+	}
+	violations := []errors.BadParameterError{}
+	for name, fieldIDs := range refs {
+		for _, id := range fieldIDs {
+			if wi.ID != uuid.Nil && id == wi.ID {
+				violations = append(violations, errors.NewBadParameterError(name, id).Expected("a reference to another work item"))
+				break
+			}
+			if _, ok := existing[id]; !ok {
+				violations = append(violations, errors.NewBadParameterError(name, id).Expected(fmt.Sprintf("a work item in space %s", wi.SpaceID)))
+				break
+			}
+		}
+	}
+	return violations, nil
+}
+
+// checkReleaseReferences returns a violation for every field of the given
+// work item that references a release which doesn't exist in the same space.
+// returns InternalError
+func (r *GormWorkItemRepository) checkReleaseReferences(ctx context.Context, wiType *WorkItemType, wi WorkItemStorage) ([]errors.BadParameterError, error) {
+	refs := wiType.Fields.referencedIDs(wi.Fields, IsReleaseReference)
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	ids := []uuid.UUID{}
+	for _, fieldIDs := range refs {
+		ids = append(ids, fieldIDs...)
+	}
+	var found []uuid.UUID
+	err := r.db.Table("releases").Where("id IN (?) AND space_id = ? AND deleted_at IS NULL", ids, wi.SpaceID).Pluck("id", &found).Error
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	existing := map[uuid.UUID]struct{}{}
+	for _, id := range found {
+		existing[id] = struct{}{}
+	}
+	violations := []errors.BadParameterError{}
+	for name, fieldIDs := range refs {
+		for _, id := range fieldIDs {
+			if _, ok := existing[id]; !ok {
+				violations = append(violations, errors.NewBadParameterError(name, id).Expected(fmt.Sprintf("a release in space %s", wi.SpaceID)))
+				break
+			}
+		}
+	}
+	return violations, nil
+}
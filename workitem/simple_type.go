@@ -10,6 +10,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/convert"
 	"github.com/fabric8-services/fabric8-wit/rendering"
 	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 )
 
 // SimpleType is an unstructured FieldType
@@ -93,6 +94,22 @@ func (t SimpleType) ConvertToModel(value interface{}) (interface{}, error) {
 			return nil, errs.Errorf("value %v (%[1]T) should be %s, but is %s", value, "string", valueType.Name())
 		}
 		return value, nil
+	case KindWorkItem:
+		if valueType.Kind() != reflect.String {
+			return nil, errs.Errorf("value %v (%[1]T) should be %s, but is %s", value, "string", valueType.Name())
+		}
+		if _, err := uuid.FromString(value.(string)); err != nil {
+			return nil, errs.Wrapf(err, "value %q is not a work item ID", value)
+		}
+		return value, nil
+	case KindRelease:
+		if valueType.Kind() != reflect.String {
+			return nil, errs.Errorf("value %v (%[1]T) should be %s, but is %s", value, "string", valueType.Name())
+		}
+		if _, err := uuid.FromString(value.(string)); err != nil {
+			return nil, errs.Wrapf(err, "value %q is not a release ID", value)
+		}
+		return value, nil
 	case KindURL:
 		if valueType.Kind() == reflect.String && govalidator.IsURL(value.(string)) {
 			return value, nil
@@ -183,7 +200,7 @@ func (t SimpleType) ConvertFromModel(value interface{}) (interface{}, error) {
 	}
 	valueType := reflect.TypeOf(value)
 	switch t.GetKind() {
-	case KindString, KindURL, KindUser, KindInteger, KindFloat, KindIteration, KindArea, KindLabel, KindBoardColumn, KindBoolean:
+	case KindString, KindURL, KindUser, KindInteger, KindFloat, KindIteration, KindArea, KindLabel, KindBoardColumn, KindBoolean, KindWorkItem, KindRelease:
 		return value, nil
 	case KindInstant:
 		switch valueType.Kind() {
@@ -0,0 +1,258 @@
+package workitem
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	errs "github.com/pkg/errors"
+)
+
+// TransitionFromAny can be used as the source state of a transition to allow
+// the transition from every state.
+const TransitionFromAny = "*"
+
+// TransitionRole restricts who is allowed to perform a transition
+type TransitionRole string
+
+// The roles that a transition can require
+const (
+	// TransitionRoleCreator is the creator of the work item
+	TransitionRoleCreator TransitionRole = "creator"
+	// TransitionRoleAssignee is any of the assignees of the work item
+	TransitionRoleAssignee TransitionRole = "assignee"
+	// TransitionRoleSpaceOwner is the owner of the space of the work item
+	TransitionRoleSpaceOwner TransitionRole = "space_owner"
+)
+
+// IsValid returns true if the role is one of the known roles
+func (r TransitionRole) IsValid() bool {
+	switch r {
+	case TransitionRoleCreator, TransitionRoleAssignee, TransitionRoleSpaceOwner:
+		return true
+	}
+	return false
+}
+
+// A Transition allows to change the state of a work item from one value of the
+// system.state field to another. The transition can require fields to be set
+// and restrict the transition to users with a given role.
+type Transition struct {
+	// From is the state before the transition or TransitionFromAny
+	From string `json:"from"`
+	// To is the state after the transition
+	To string `json:"to"`
+	// RequiredFields contains the names of the fields that must not be empty
+	// when the work item is moved to the target state
+	RequiredFields []string `json:"required_fields,omitempty"`
+	// Role is optional and restricts the transition to users with that role
+	Role TransitionRole `json:"role,omitempty"`
+}
+
+// Transitions contains the allowed state transitions of a work item type. If
+// it is empty, the state of a work item can be changed to any allowed value of
+// its state field.
+type Transitions []Transition
+
+// Ensure Transitions implements the Scanner and Valuer interfaces
+var _ sql.Scanner = (*Transitions)(nil)
+var _ driver.Valuer = (*Transitions)(nil)
+
+// Value implements the https://golang.org/pkg/database/sql/driver/#Valuer interface
+func (t Transitions) Value() (driver.Value, error) {
+	if t == nil {
+		return nil, nil
+	}
+	return toBytes(t)
+}
+
+// Scan implements the https://golang.org/pkg/database/sql/#Scanner interface
+func (t *Transitions) Scan(src interface{}) error {
+	return fromBytes(src, t)
+}
+
+// Validate checks that all transitions use values of the state field of the
+// given field definitions, that the required fields exist and that the roles
+// are known. When a work item type extends another type, the given field
+// definitions may not contain the inherited fields yet. In that case only the
+// roles and the states themselves are checked.
+func (t Transitions) Validate(fields FieldDefinitions) error {
+	if len(t) == 0 {
+		return nil
+	}
+	_, hasState := fields[SystemState]
+	var states []interface{}
+	if hasState {
+		var err error
+		states, err = stateValues(fields)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+	}
+	for i, tr := range t {
+		if strings.TrimSpace(tr.From) == "" || strings.TrimSpace(tr.To) == "" {
+			return errs.Errorf("transition %d must have a source and a target state", i)
+		}
+		if tr.From == tr.To {
+			return errs.Errorf("transition %d has the same source and target state %q", i, tr.To)
+		}
+		if tr.Role != "" && !tr.Role.IsValid() {
+			return errs.Errorf("transition %d has an unknown role %q", i, tr.Role)
+		}
+		if !hasState {
+			continue
+		}
+		if tr.From != TransitionFromAny && !contains(states, tr.From) {
+			return errs.Errorf("transition %d has an unknown source state %q", i, tr.From)
+		}
+		if !contains(states, tr.To) {
+			return errs.Errorf("transition %d has an unknown target state %q", i, tr.To)
+		}
+		for _, name := range tr.RequiredFields {
+			if _, ok := fields[name]; !ok {
+				return errs.Errorf("transition %d requires the unknown field %q", i, name)
+			}
+		}
+	}
+	return nil
+}
+
+// stateValues returns the allowed values of the state field
+func stateValues(fields FieldDefinitions) ([]interface{}, error) {
+	def, ok := fields[SystemState]
+	if !ok {
+		return nil, errs.Errorf("transitions require a %q field", SystemState)
+	}
+	enumType, ok := def.Type.(EnumType)
+	if !ok {
+		return nil, errs.Errorf("transitions require the %q field to be an enum", SystemState)
+	}
+	return enumType.Values, nil
+}
+
+// setMetaState derives the system.metastate field of the given fields from
+// their system.state field if the type declares transitions, the meta-state
+// of other types is left as it is. The values of the meta-state enum map to
+// the values of the state enum by their position, so the meta-state is only
+// set if both enums have the same number of values. A meta-state that was set
+// before is always discarded because it can't be set by users.
+func (wit WorkItemType) setMetaState(fields Fields) {
+	if len(wit.Transitions) == 0 {
+		return
+	}
+	f := wit.Fields
+	def, ok := f[SystemMetaState]
+	if !ok {
+		return
+	}
+	delete(fields, SystemMetaState)
+	metaStates, ok := def.Type.(EnumType)
+	if !ok {
+		return
+	}
+	states, err := stateValues(f)
+	if err != nil || len(states) != len(metaStates.Values) {
+		return
+	}
+	for i, s := range states {
+		if s == fields[SystemState] {
+			fields[SystemMetaState] = metaStates.Values[i]
+			return
+		}
+	}
+}
+
+// IsClosed returns true if the given fields are the ones of a closed work
+// item, i.e. its state is "closed" or its meta-state is mClosed. See also
+// ClosedCondition.
+func IsClosed(fields Fields) bool {
+	if metaState, ok := fields[SystemMetaState].(string); ok && metaState == SystemMetaStateClosed {
+		return true
+	}
+	state, _ := fields[SystemState].(string)
+	return strings.EqualFold(state, SystemStateClosed)
+}
+
+// ClosedCondition returns the SQL condition that matches the closed work
+// items like IsClosed. The given alias is the one of the work items table.
+func ClosedCondition(alias string) string {
+	return fmt.Sprintf(`(%[1]s.fields->>'%[2]s' = '%[3]s' OR %[1]s.fields->>'%[4]s' ILIKE '%[5]s')`,
+		alias, SystemMetaState, SystemMetaStateClosed, SystemState, SystemStateClosed)
+}
+
+// From returns all transitions that start in the given state
+func (t Transitions) From(state string) Transitions {
+	result := Transitions{}
+	for _, tr := range t {
+		if tr.To != state && (tr.From == state || tr.From == TransitionFromAny) {
+			result = append(result, tr)
+		}
+	}
+	return result
+}
+
+// To returns all transitions that end in the given state
+func (t Transitions) To(state string) Transitions {
+	result := Transitions{}
+	for _, tr := range t {
+		if tr.To == state {
+			result = append(result, tr)
+		}
+	}
+	return result
+}
+
+// AllowedFor returns the transitions that can be performed by a user with the
+// given roles
+func (t Transitions) AllowedFor(roles map[TransitionRole]bool) Transitions {
+	result := Transitions{}
+	for _, tr := range t {
+		if tr.Role == "" || roles[tr.Role] {
+			result = append(result, tr)
+		}
+	}
+	return result
+}
+
+// MissingFields returns the names of the required fields of the transition
+// that are empty in the given fields
+func (tr Transition) MissingFields(fields Fields) []string {
+	missing := []string{}
+	for _, name := range tr.RequiredFields {
+		if isEmptyFieldValue(fields[name]) {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// isEmptyFieldValue returns true if the given value in storage representation
+// is nil, a blank string or an empty list
+func isEmptyFieldValue(v interface{}) bool {
+	switch value := v.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(value) == ""
+	case []interface{}:
+		return len(value) == 0
+	case []string:
+		return len(value) == 0
+	}
+	return false
+}
+
+// targetStates returns the distinct target states of the given transitions as
+// a comma separated list
+func (t Transitions) targetStates() string {
+	seen := map[string]struct{}{}
+	states := []string{}
+	for _, tr := range t {
+		if _, ok := seen[tr.To]; !ok {
+			seen[tr.To] = struct{}{}
+			states = append(states, fmt.Sprintf("%q", tr.To))
+		}
+	}
+	return strings.Join(states, ", ")
+}
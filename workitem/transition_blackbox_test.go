@@ -0,0 +1,108 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransitions(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	fields := workitem.FieldDefinitions{
+		workitem.SystemState: {
+			Label: "State",
+			Type: workitem.EnumType{
+				SimpleType: workitem.SimpleType{Kind: workitem.KindEnum},
+				BaseType:   workitem.SimpleType{Kind: workitem.KindString},
+				Values:     []interface{}{"new", "open", "closed"},
+			},
+		},
+		"resolution": {
+			Label: "Resolution",
+			Type:  workitem.SimpleType{Kind: workitem.KindString},
+		},
+	}
+	transitions := workitem.Transitions{
+		{From: "new", To: "open"},
+		{From: "open", To: "closed", RequiredFields: []string{"resolution"}},
+		{From: workitem.TransitionFromAny, To: "new", Role: workitem.TransitionRoleSpaceOwner},
+	}
+
+	t.Run("validate", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, transitions.Validate(fields))
+		invalid := map[string]workitem.Transitions{
+			"unknown source state": {{From: "foo", To: "open"}},
+			"unknown target state": {{From: "new", To: "foo"}},
+			"same states":          {{From: "new", To: "new"}},
+			"empty state":          {{From: "new", To: ""}},
+			"unknown field":        {{From: "new", To: "open", RequiredFields: []string{"foo"}}},
+			"unknown role":         {{From: "new", To: "open", Role: "foo"}},
+		}
+		for name, tr := range invalid {
+			t.Run(name, func(t *testing.T) {
+				require.Error(t, tr.Validate(fields))
+			})
+		}
+	})
+
+	t.Run("from", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, workitem.Transitions{transitions[0]}, transitions.From("new"))
+		assert.Equal(t, workitem.Transitions{transitions[1], transitions[2]}, transitions.From("open"))
+		assert.Equal(t, workitem.Transitions{transitions[2]}, transitions.From("closed"))
+	})
+
+	t.Run("allowed for", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, workitem.Transitions{transitions[1]}, transitions.From("open").AllowedFor(nil))
+		owner := map[workitem.TransitionRole]bool{workitem.TransitionRoleSpaceOwner: true}
+		assert.Equal(t, workitem.Transitions{transitions[1], transitions[2]}, transitions.From("open").AllowedFor(owner))
+	})
+
+	t.Run("missing fields", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"resolution"}, transitions[1].MissingFields(workitem.Fields{"resolution": " "}))
+		assert.Empty(t, transitions[1].MissingFields(workitem.Fields{"resolution": "fixed"}))
+	})
+
+	t.Run("next transitions without workflow", func(t *testing.T) {
+		t.Parallel()
+		wit := workitem.WorkItemType{Fields: fields}
+		assert.Equal(t, workitem.Transitions{
+			{From: "open", To: "new"},
+			{From: "open", To: "closed"},
+		}, wit.NextTransitions("open"))
+	})
+}
+
+func TestIsClosed(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	for name, td := range map[string]struct {
+		fields   workitem.Fields
+		expected bool
+	}{
+		"closed state":       {workitem.Fields{workitem.SystemState: workitem.SystemStateClosed}, true},
+		"closed state, case": {workitem.Fields{workitem.SystemState: "Closed"}, true},
+		"closed meta-state":  {workitem.Fields{workitem.SystemState: "done", workitem.SystemMetaState: workitem.SystemMetaStateClosed}, true},
+		"open state":         {workitem.Fields{workitem.SystemState: workitem.SystemStateOpen}, false},
+		"open meta-state":    {workitem.Fields{workitem.SystemState: "doing", workitem.SystemMetaState: "mInprogress"}, false},
+		"no state":           {workitem.Fields{}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, td.expected, workitem.IsClosed(td.fields))
+		})
+	}
+}
+
+func TestClosedCondition(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, `(wi.fields->>'system.metastate' = 'mClosed' OR wi.fields->>'system.state' ILIKE 'closed')`, workitem.ClosedCondition("wi"))
+}
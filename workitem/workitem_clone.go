@@ -0,0 +1,64 @@
+package workitem
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// CloneOptions controls which parts of a work item are copied to its clone
+type CloneOptions struct {
+	// Children also clones the work items below the work item in tree links
+	Children bool
+	// Labels copies the labels of the work item
+	Labels bool
+	// ResetState starts the clone in the default state of its type
+	ResetState bool
+	// ResetAssignees leaves the clone unassigned
+	ResetAssignees bool
+	// ResetIteration puts the clone into the root iteration of the space
+	ResetIteration bool
+}
+
+// Clone creates a copy of the work item with the given ID in the same space.
+// The clone gets a new number and is created by the given identity.
+// returns NotFoundError, BadParameterError or InternalError
+func (r *GormWorkItemRepository) Clone(ctx context.Context, id uuid.UUID, opts CloneOptions, creatorID uuid.UUID) (*WorkItem, *Revision, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "clone"}, time.Now())
+	wi, err := r.LoadByID(ctx, id)
+	if err != nil {
+		return nil, nil, errs.Wrapf(err, "failed to load work item %s", id)
+	}
+	fields := make(map[string]interface{}, len(wi.Fields))
+	for name, value := range wi.Fields {
+		fields[name] = value
+	}
+	// the clone is a new work item in the board columns of its type
+	delete(fields, SystemBoardcolumns)
+	if !opts.Labels {
+		delete(fields, SystemLabels)
+	}
+	if opts.ResetState {
+		delete(fields, SystemState)
+		delete(fields, SystemMetaState)
+	}
+	if opts.ResetAssignees {
+		delete(fields, SystemAssignees)
+	}
+	if opts.ResetIteration {
+		root, err := iteration.NewIterationRepository(r.db).Root(ctx, wi.SpaceID)
+		if err != nil {
+			return nil, nil, errs.Wrapf(err, "failed to load the root iteration of space %s", wi.SpaceID)
+		}
+		fields[SystemIteration] = root.ID.String()
+	}
+	clone, rev, err := r.Create(ctx, wi.SpaceID, wi.Type, fields, creatorID)
+	if err != nil {
+		return nil, nil, errs.Wrapf(err, "failed to clone work item %s", id)
+	}
+	return clone, rev, nil
+}
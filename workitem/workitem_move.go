@@ -0,0 +1,439 @@
+package workitem
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/area"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/label"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// MoveMapping maps the space specific values of a work item into the target
+// space of a move. Values that are not mapped are replaced by defaults.
+type MoveMapping struct {
+	// Type is the work item type to use in the target space. By default the
+	// current type is kept if the target space shares it, otherwise the type
+	// with the same name in the space template of the target space is used.
+	Type *uuid.UUID
+	// Iteration defaults to the root iteration of the target space.
+	Iteration *uuid.UUID
+	// Area defaults to the root area of the target space.
+	Area *uuid.UUID
+	// Labels maps the labels of the work item to labels of the target space.
+	// Labels that are not mapped are replaced by the label with the same name
+	// in the target space, which is created if necessary.
+	Labels map[uuid.UUID]uuid.UUID
+	// Releases maps the releases referenced by fields of the work item to
+	// releases of the target space. Releases that are not mapped are replaced
+	// by the release with the same name in the target space.
+	Releases map[uuid.UUID]uuid.UUID
+}
+
+// workItemAlias is a former space and number of a work item that was moved
+// to another space
+type workItemAlias struct {
+	SpaceID    uuid.UUID `sql:"type:uuid" gorm:"primary_key"`
+	Number     int       `gorm:"primary_key"`
+	WorkItemID uuid.UUID `sql:"type:uuid"`
+	CreatedAt  time.Time
+}
+
+// TableName implements gorm.tabler
+func (a workItemAlias) TableName() string {
+	return "work_item_aliases"
+}
+
+// Move moves the work item with the given ID to the target space. The work
+// item gets a new number in the target space and its type, iteration, area,
+// labels and referenced releases are mapped into the target space. Links and
+// references by fields can't cross spaces, so a work item that has links or
+// references other work items or is referenced by them can't be moved; they
+// have to be removed first. Comments, attachments and revisions are kept, and
+// the former space and number of the work item remain resolvable.
+// returns NotFoundError, BadParameterError, ForbiddenError, VersionConflictError or InternalError
+func (r *GormWorkItemRepository) Move(ctx context.Context, id uuid.UUID, version int, targetSpaceID uuid.UUID, mapping MoveMapping, modifierID uuid.UUID) (*WorkItem, *Revision, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "move"}, time.Now())
+	wiStorage := &WorkItemStorage{}
+	tx := r.db.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", id).First(wiStorage)
+	if tx.RecordNotFound() {
+		return nil, nil, errors.NewNotFoundError("work item", id.String())
+	}
+	if tx.Error != nil {
+		return nil, nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	if wiStorage.Version != version {
+		return nil, nil, errors.NewVersionConflictError("version conflict")
+	}
+	if wiStorage.SpaceID == targetSpaceID {
+		return nil, nil, errors.NewBadParameterError("space", targetSpaceID).Expected("a space other than the current one")
+	}
+	targetSpace, err := r.space.Load(ctx, targetSpaceID)
+	if err != nil {
+		return nil, nil, errs.Wrapf(err, "failed to load target space %s", targetSpaceID)
+	}
+	oldType, err := r.witr.Load(ctx, wiStorage.Type)
+	if err != nil {
+		return nil, nil, errors.NewInternalError(ctx, err)
+	}
+	if err := r.checkMoveRelations(ctx, oldType, *wiStorage); err != nil {
+		return nil, nil, err
+	}
+	newType, err := r.moveType(ctx, oldType, *targetSpace, mapping.Type)
+	if err != nil {
+		return nil, nil, err
+	}
+	oldSpaceID, oldNumber := wiStorage.SpaceID, wiStorage.Number
+	oldState := wiStorage.Fields[SystemState]
+
+	// map the space specific fields before the type is changed so that they
+	// are converted along with the other fields
+	if err := r.moveIteration(ctx, wiStorage, targetSpaceID, mapping.Iteration); err != nil {
+		return nil, nil, err
+	}
+	if err := r.moveArea(ctx, wiStorage, targetSpaceID, mapping.Area); err != nil {
+		return nil, nil, err
+	}
+	if err := r.moveLabels(ctx, wiStorage, targetSpaceID, mapping.Labels); err != nil {
+		return nil, nil, err
+	}
+	if err := r.moveReleases(ctx, oldType, wiStorage, targetSpaceID, mapping.Releases); err != nil {
+		return nil, nil, err
+	}
+	// board columns belong to the boards of the space template
+	delete(wiStorage.Fields, SystemBoardcolumns)
+	if newType.ID != oldType.ID {
+		if err := r.ChangeWorkItemType(ctx, wiStorage, oldType, newType, targetSpaceID); err != nil {
+			return nil, nil, errs.Wrapf(err, "unable to change workitem type from %s (ID: %s) to %s (ID: %s)", oldType.Name, oldType.ID, newType.Name, newType.ID)
+		}
+		wiStorage.Type = newType.ID
+	}
+
+	pos, err := r.LoadHighestOrder(ctx, targetSpaceID)
+	if err != nil {
+		return nil, nil, errors.NewInternalError(ctx, err)
+	}
+	number, err := r.winr.NextVal(ctx, targetSpaceID)
+	if err != nil {
+		return nil, nil, errors.NewInternalError(ctx, err)
+	}
+	wiStorage.SpaceID = targetSpaceID
+	wiStorage.Number = *number
+	wiStorage.ExecutionOrder = pos + orderValue
+	wiStorage.Version = wiStorage.Version + 1
+	// the state may have been reset by a change of the type, which must be
+	// allowed in the target space like any other state change
+	if err := r.checkTransition(ctx, newType, *wiStorage, oldState, modifierID); err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
+	newType.setMetaState(wiStorage.Fields)
+	if err := r.computeFields(ctx, newType, wiStorage); err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
+	tx = r.db.Where("Version = ?", version).Save(wiStorage)
+	if err := tx.Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id":    id,
+			"space_id": targetSpaceID,
+			"version":  version,
+			"err":      err,
+		}, "unable to move the work item")
+		return nil, nil, errors.NewInternalError(ctx, err)
+	}
+	if tx.RowsAffected == 0 {
+		return nil, nil, errors.NewVersionConflictError("version conflict")
+	}
+	alias := workItemAlias{SpaceID: oldSpaceID, Number: oldNumber, WorkItemID: id}
+	if err := r.db.Create(&alias).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id":     id,
+			"space_id":  oldSpaceID,
+			"wi_number": oldNumber,
+			"err":       err,
+		}, "unable to record the former number of the moved work item")
+		return nil, nil, errors.NewInternalError(ctx, err)
+	}
+	rev, err := r.wirr.Create(context.Background(), modifierID, RevisionTypeUpdate, *wiStorage)
+	if err != nil {
+		return nil, nil, errs.Wrapf(err, "error while moving work item")
+	}
+	log.Info(ctx, map[string]interface{}{
+		"wi_id":           id,
+		"space_id":        targetSpaceID,
+		"former_space_id": oldSpaceID,
+		"former_number":   oldNumber,
+	}, "Moved work item")
+	w, err := ConvertWorkItemStorageToModel(newType, wiStorage)
+	if err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
+	return w, &rev, nil
+}
+
+// checkMoveRelations returns a BadParameterError if the given work item has
+// links, references other work items by its fields or is referenced by other
+// work items of its space, because none of them can cross spaces.
+func (r *GormWorkItemRepository) checkMoveRelations(ctx context.Context, wiType *WorkItemType, wi WorkItemStorage) error {
+	var links int
+	err := r.db.Table("work_item_links").Where("? IN (source_id, target_id) AND deleted_at IS NULL", wi.ID).Count(&links).Error
+	if err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to count the links of work item %s", wi.ID))
+	}
+	if links > 0 {
+		return errors.NewBadParameterError("links", links).Expected("a work item without links; remove them to move the work item")
+	}
+	if refs := wiType.Fields.ReferencedWorkItemIDs(wi.Fields); len(refs) > 0 {
+		return errors.NewBadParameterErrorFromString(fmt.Sprintf(
+			"work item %s references other work items by its fields; remove the references to move the work item", wi.ID))
+	}
+	// the fields of the candidates mention the ID somewhere, the type tells
+	// whether it is a reference
+	var candidates []WorkItemStorage
+	err = r.db.Where("space_id = ? AND id <> ? AND fields::text LIKE ?", wi.SpaceID, wi.ID, "%"+wi.ID.String()+"%").Find(&candidates).Error
+	if err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to look up the work items referencing work item %s", wi.ID))
+	}
+	for _, c := range candidates {
+		cType, err := r.witr.Load(ctx, c.Type)
+		if err != nil {
+			return errs.Wrapf(err, "failed to load type of work item %s", c.ID)
+		}
+		for name, ids := range cType.Fields.ReferencedWorkItemIDs(c.Fields) {
+			for _, id := range ids {
+				if id == wi.ID {
+					return errors.NewBadParameterErrorFromString(fmt.Sprintf(
+						"work item %s is referenced by field %q of work item %s; remove the reference to move the work item",
+						wi.ID, name, c.ID))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// moveType returns the type of a work item moved to the given space
+// returns BadParameterError if the type can't be mapped into the space
+// template of the target space
+func (r *GormWorkItemRepository) moveType(ctx context.Context, oldType *WorkItemType, targetSpace space.Space, typeID *uuid.UUID) (*WorkItemType, error) {
+	if typeID != nil {
+		newType, err := r.witr.Load(ctx, *typeID)
+		if err != nil {
+			return nil, errors.NewBadParameterError("type", *typeID)
+		}
+		if _, err := r.CheckTypeAndSpaceShareTemplate(ctx, newType, targetSpace.ID); err != nil {
+			return nil, err
+		}
+		return newType, nil
+	}
+	if oldType.SpaceTemplateID == targetSpace.SpaceTemplateID {
+		return oldType, nil
+	}
+	types, err := r.witr.List(ctx, targetSpace.SpaceTemplateID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to list the work item types of space template %s", targetSpace.SpaceTemplateID)
+	}
+	for _, t := range types {
+		if t.Name == oldType.Name && t.CanConstruct {
+			newType := t
+			return &newType, nil
+		}
+	}
+	return nil, errors.NewBadParameterErrorFromString(fmt.Sprintf(
+		"work item type %q (ID: %s) has no counterpart in the space template of space %s; a type mapping is required",
+		oldType.Name, oldType.ID, targetSpace.ID))
+}
+
+// moveIteration sets the iteration of a work item moved to the given space
+func (r *GormWorkItemRepository) moveIteration(ctx context.Context, wi *WorkItemStorage, targetSpaceID uuid.UUID, iterationID *uuid.UUID) error {
+	repo := iteration.NewIterationRepository(r.db)
+	if iterationID == nil {
+		root, err := repo.Root(ctx, targetSpaceID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to load the root iteration of space %s", targetSpaceID)
+		}
+		wi.Fields[SystemIteration] = root.ID.String()
+		return nil
+	}
+	itr, err := repo.Load(ctx, *iterationID)
+	if err != nil || itr.SpaceID != targetSpaceID {
+		return errors.NewBadParameterError("iteration", *iterationID).Expected(fmt.Sprintf("an iteration of space %s", targetSpaceID))
+	}
+	wi.Fields[SystemIteration] = itr.ID.String()
+	return nil
+}
+
+// moveArea sets the area of a work item moved to the given space
+func (r *GormWorkItemRepository) moveArea(ctx context.Context, wi *WorkItemStorage, targetSpaceID uuid.UUID, areaID *uuid.UUID) error {
+	repo := area.NewAreaRepository(r.db)
+	if areaID == nil {
+		root, err := repo.Root(ctx, targetSpaceID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to load the root area of space %s", targetSpaceID)
+		}
+		wi.Fields[SystemArea] = root.ID.String()
+		return nil
+	}
+	a, err := repo.Load(ctx, *areaID)
+	if err != nil || a.SpaceID != targetSpaceID {
+		return errors.NewBadParameterError("area", *areaID).Expected(fmt.Sprintf("an area of space %s", targetSpaceID))
+	}
+	wi.Fields[SystemArea] = a.ID.String()
+	return nil
+}
+
+// moveLabels replaces the labels of a work item moved to the given space by
+// labels of the target space
+func (r *GormWorkItemRepository) moveLabels(ctx context.Context, wi *WorkItemStorage, targetSpaceID uuid.UUID, mapping map[uuid.UUID]uuid.UUID) error {
+	labelIDs, ok := wi.Fields[SystemLabels].([]interface{})
+	if !ok || len(labelIDs) == 0 {
+		return nil
+	}
+	repo := label.NewLabelRepository(r.db)
+	targetLabels, err := repo.List(ctx, targetSpaceID)
+	if err != nil {
+		return errs.Wrapf(err, "failed to list the labels of space %s", targetSpaceID)
+	}
+	result := make([]interface{}, 0, len(labelIDs))
+	for _, v := range labelIDs {
+		labelID, err := uuid.FromString(fmt.Sprint(v))
+		if err != nil {
+			return errors.NewInternalError(ctx, errs.Wrapf(err, "invalid label ID %v", v))
+		}
+		if newID, ok := mapping[labelID]; ok {
+			l, err := repo.Load(ctx, newID)
+			if err != nil || l.SpaceID != targetSpaceID {
+				return errors.NewBadParameterError("labels", newID).Expected(fmt.Sprintf("a label of space %s", targetSpaceID))
+			}
+			result = append(result, newID.String())
+			continue
+		}
+		l, err := repo.Load(ctx, labelID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to load label %s", labelID)
+		}
+		newID := uuid.Nil
+		for _, tl := range targetLabels {
+			if tl.Name == l.Name {
+				newID = tl.ID
+				break
+			}
+		}
+		if newID == uuid.Nil {
+			newLabel := label.Label{
+				SpaceID:         targetSpaceID,
+				Name:            l.Name,
+				TextColor:       l.TextColor,
+				BackgroundColor: l.BackgroundColor,
+				BorderColor:     l.BorderColor,
+			}
+			if err := repo.Create(ctx, &newLabel); err != nil {
+				return errs.Wrapf(err, "failed to create label %q in space %s", l.Name, targetSpaceID)
+			}
+			targetLabels = append(targetLabels, newLabel)
+			newID = newLabel.ID
+		}
+		result = append(result, newID.String())
+	}
+	wi.Fields[SystemLabels] = result
+	return nil
+}
+
+// moveReleases replaces the releases referenced by fields of a work item
+// moved to the given space by releases of the target space
+func (r *GormWorkItemRepository) moveReleases(ctx context.Context, wiType *WorkItemType, wi *WorkItemStorage, targetSpaceID uuid.UUID, mapping map[uuid.UUID]uuid.UUID) error {
+	refs := wiType.Fields.referencedIDs(wi.Fields, IsReleaseReference)
+	if len(refs) == 0 {
+		return nil
+	}
+	type namedRelease struct {
+		ID   uuid.UUID
+		Name string
+	}
+	var targetReleases []namedRelease
+	err := r.db.Table("releases").Select("id, name").Where("space_id = ? AND deleted_at IS NULL", targetSpaceID).Scan(&targetReleases).Error
+	if err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list the releases of space %s", targetSpaceID))
+	}
+	ids := []uuid.UUID{}
+	for _, fieldIDs := range refs {
+		ids = append(ids, fieldIDs...)
+	}
+	var oldReleases []namedRelease
+	err = r.db.Table("releases").Select("id, name").Where("id IN (?)", ids).Scan(&oldReleases).Error
+	if err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to load the releases %s", ids))
+	}
+	names := map[uuid.UUID]string{}
+	for _, rel := range oldReleases {
+		names[rel.ID] = rel.Name
+	}
+	mapRelease := func(field string, id uuid.UUID) (string, error) {
+		if newID, ok := mapping[id]; ok {
+			for _, rel := range targetReleases {
+				if rel.ID == newID {
+					return newID.String(), nil
+				}
+			}
+			return "", errors.NewBadParameterError("releases", newID).Expected(fmt.Sprintf("a release of space %s", targetSpaceID))
+		}
+		for _, rel := range targetReleases {
+			if name, ok := names[id]; ok && rel.Name == name {
+				return rel.ID.String(), nil
+			}
+		}
+		return "", errors.NewBadParameterError(field, id).Expected(fmt.Sprintf("a release with a counterpart in space %s; a release mapping is required", targetSpaceID))
+	}
+	for name := range refs {
+		switch v := wi.Fields[name].(type) {
+		case string:
+			id, _ := uuid.FromString(v)
+			newID, err := mapRelease(name, id)
+			if err != nil {
+				return err
+			}
+			wi.Fields[name] = newID
+		case []interface{}:
+			result := make([]interface{}, 0, len(v))
+			for _, elem := range v {
+				id, err := uuid.FromString(fmt.Sprint(elem))
+				if err != nil {
+					continue
+				}
+				newID, err := mapRelease(name, id)
+				if err != nil {
+					return err
+				}
+				result = append(result, newID)
+			}
+			wi.Fields[name] = result
+		}
+	}
+	return nil
+}
+
+// lookupAlias returns the ID of the work item that was moved away from the
+// given space where it had the given number
+// returns NotFoundError or InternalError
+func (r *GormWorkItemRepository) lookupAlias(ctx context.Context, spaceID uuid.UUID, wiNumber int) (*uuid.UUID, *uuid.UUID, error) {
+	var result struct {
+		WorkItemID uuid.UUID
+		SpaceID    uuid.UUID
+	}
+	db := r.db.Raw(`SELECT a.work_item_id, wi.space_id FROM work_item_aliases a
+		JOIN work_items wi ON wi.id = a.work_item_id AND wi.deleted_at IS NULL
+		WHERE a.space_id = ? AND a.number = ?`, spaceID, wiNumber).Scan(&result)
+	if db.RecordNotFound() {
+		return nil, nil, errors.NewNotFoundError("work item", fmt.Sprintf("%s/%d", spaceID, wiNumber))
+	}
+	if db.Error != nil {
+		return nil, nil, errors.NewInternalError(ctx, errs.Wrap(db.Error, "error while looking up a moved work item"))
+	}
+	return &result.WorkItemID, &result.SpaceID, nil
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/log"
 	"github.com/fabric8-services/fabric8-wit/rendering"
 	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/fabric8-services/fabric8-wit/watcher"
 	"github.com/fabric8-services/fabric8-wit/workitem/number_sequence"
 	"github.com/goadesign/goa"
 	"github.com/jinzhu/gorm"
@@ -94,9 +95,15 @@ type WorkItemRepository interface {
 	LoadBatchByID(ctx context.Context, ids []uuid.UUID) ([]*WorkItem, error)
 	LoadByIteration(ctx context.Context, id uuid.UUID) ([]*WorkItem, error)
 	LookupIDByNamedSpaceAndNumber(ctx context.Context, ownerName, spaceName string, wiNumber int) (*uuid.UUID, *uuid.UUID, error)
+	LookupIDByKey(ctx context.Context, key string) (*uuid.UUID, *uuid.UUID, error)
+	Move(ctx context.Context, id uuid.UUID, version int, targetSpaceID uuid.UUID, mapping MoveMapping, modifierID uuid.UUID) (*WorkItem, *Revision, error)
+	Clone(ctx context.Context, id uuid.UUID, opts CloneOptions, creatorID uuid.UUID) (*WorkItem, *Revision, error)
 	Save(ctx context.Context, spaceID uuid.UUID, wi WorkItem, modifierID uuid.UUID) (*WorkItem, *Revision, error)
 	Reorder(ctx context.Context, spaceID uuid.UUID, direction DirectionType, targetID *uuid.UUID, wi WorkItem, modifierID uuid.UUID) (*WorkItem, error)
 	Delete(ctx context.Context, id uuid.UUID, suppressorID uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID, modifierID uuid.UUID) (*WorkItem, *Revision, error)
+	DeletedAt(ctx context.Context, id uuid.UUID) (*time.Time, error)
+	RevertToRevision(ctx context.Context, id uuid.UUID, revisionID uuid.UUID, version int, modifierID uuid.UUID) (*WorkItem, *Revision, error)
 	Create(ctx context.Context, spaceID uuid.UUID, typeID uuid.UUID, fields map[string]interface{}, creatorID uuid.UUID) (*WorkItem, *Revision, error)
 	List(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, start *int, length *int, sort SortWorkItemsBy) ([]WorkItem, int, error)
 	Fetch(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression) (*WorkItem, error)
@@ -104,27 +111,31 @@ type WorkItemRepository interface {
 	GetCountsForIteration(ctx context.Context, itr *iteration.Iteration) (map[string]WICountsPerIteration, error)
 	Count(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression) (int, error)
 	ChangeWorkItemType(ctx context.Context, wiStorage *WorkItemStorage, oldWIType *WorkItemType, newWIType *WorkItemType, spaceID uuid.UUID) error
+	RecalculateComputedFields(ctx context.Context, id uuid.UUID) error
+	ListTransitions(ctx context.Context, id uuid.UUID, identityID uuid.UUID) (Transitions, error)
 }
 
 // NewWorkItemRepository creates a GormWorkItemRepository
 func NewWorkItemRepository(db *gorm.DB) *GormWorkItemRepository {
 	repository := &GormWorkItemRepository{
-		db:    db,
-		winr:  numbersequence.NewWorkItemNumberSequenceRepository(db),
-		witr:  &GormWorkItemTypeRepository{db},
-		wirr:  &GormRevisionRepository{db},
-		space: space.NewRepository(db),
+		db:       db,
+		winr:     numbersequence.NewWorkItemNumberSequenceRepository(db),
+		witr:     &GormWorkItemTypeRepository{db},
+		wirr:     &GormRevisionRepository{db},
+		space:    space.NewRepository(db),
+		watchers: watcher.NewRepository(db),
 	}
 	return repository
 }
 
 // GormWorkItemRepository implements WorkItemRepository using gorm
 type GormWorkItemRepository struct {
-	db    *gorm.DB
-	winr  *numbersequence.GormWorkItemNumberSequenceRepository
-	witr  *GormWorkItemTypeRepository
-	wirr  *GormRevisionRepository
-	space *space.GormRepository
+	db       *gorm.DB
+	winr     *numbersequence.GormWorkItemNumberSequenceRepository
+	witr     *GormWorkItemTypeRepository
+	wirr     *GormRevisionRepository
+	space    *space.GormRepository
+	watchers watcher.Repository
 }
 
 // ************************************************
@@ -247,6 +258,21 @@ func (r *GormWorkItemRepository) LookupIDByNamedSpaceAndNumber(ctx context.Conte
 	var result Result
 	db := r.db.Raw(query, ownerName, spaceName, wiNumber).Scan(&result)
 	if db.RecordNotFound() {
+		// the work item may have been moved to another space
+		aliasQuery := fmt.Sprintf(`select wi.id, wi.space_id from %[1]s wi
+			join %[2]s a on a.work_item_id = wi.id
+			join %[3]s s on a.space_id = s.id
+			join %[4]s i on s.owner_id = i.id
+			where lower(i.username) = lower(?) and
+			lower(s.name) = lower(?) and
+			a.number = ? and
+			wi.deleted_at IS NULL and
+			s.deleted_at IS NULL
+			and i.deleted_at IS NULL`,
+			WorkItemStorage{}.TableName(), workItemAlias{}.TableName(), space.Space{}.TableName(), account.Identity{}.TableName())
+		if aliasDB := r.db.Raw(aliasQuery, ownerName, spaceName, wiNumber).Scan(&result); aliasDB.Error == nil {
+			return &result.WiID, &result.SpaceID, nil
+		}
 		log.Error(nil, map[string]interface{}{
 			"wi_number":  wiNumber,
 			"space_name": spaceName,
@@ -265,6 +291,46 @@ func (r *GormWorkItemRepository) LookupIDByNamedSpaceAndNumber(ctx context.Conte
 	return &result.WiID, &result.SpaceID, nil
 }
 
+// LookupIDByKey returns the work item's ID and space ID for the given
+// human-readable key (e.g. "PLAT-123"). Keys using a former key of the space
+// are resolved as well.
+// returns NotFoundError, BadParameterError or InternalError
+func (r *GormWorkItemRepository) LookupIDByKey(ctx context.Context, key string) (*uuid.UUID, *uuid.UUID, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "lookupIDByKey"}, time.Now())
+	spaceKey, wiNumber, err := ParseKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	sp, err := r.space.LoadByKey(ctx, spaceKey)
+	if err != nil {
+		if ok, _ := errors.IsNotFoundError(err); ok {
+			return nil, nil, errors.NewNotFoundError("work item", key)
+		}
+		return nil, nil, err
+	}
+	res := WorkItemStorage{}
+	db := r.db.Select("id").Where("space_id = ? AND number = ?", sp.ID, wiNumber).First(&res)
+	if db.RecordNotFound() {
+		// the work item may have been moved to another space
+		wiID, spaceID, err := r.lookupAlias(ctx, sp.ID, wiNumber)
+		if err != nil {
+			if ok, _ := errors.IsNotFoundError(err); ok {
+				return nil, nil, errors.NewNotFoundError("work item", key)
+			}
+			return nil, nil, err
+		}
+		return wiID, spaceID, nil
+	}
+	if db.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err": db.Error,
+			"key": key,
+		}, "unable to look up the work item by key")
+		return nil, nil, errors.NewInternalError(ctx, errs.Wrap(db.Error, "error while looking up a work item ID"))
+	}
+	return &res.ID, &sp.ID, nil
+}
+
 // CheckExists returns nil if the given ID exists otherwise returns an error
 func (r *GormWorkItemRepository) CheckExists(ctx context.Context, workitemID uuid.UUID) error {
 	defer goa.MeasureSince([]string{"goa", "db", "workitem", "exists"}, time.Now())
@@ -381,6 +447,192 @@ func (r *GormWorkItemRepository) Delete(ctx context.Context, workitemID uuid.UUI
 	return nil
 }
 
+// DeletedAt returns the time when the work item with the given ID was
+// deleted or nil if the work item was not deleted.
+// returns NotFoundError or InternalError
+func (r *GormWorkItemRepository) DeletedAt(ctx context.Context, workitemID uuid.UUID) (*time.Time, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "deletedAt"}, time.Now())
+	wiStorage := WorkItemStorage{}
+	tx := r.db.Unscoped().Select("id, deleted_at").Where("id = ?", workitemID).First(&wiStorage)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("work item", workitemID.String())
+	}
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	return wiStorage.DeletedAt, nil
+}
+
+// Restore brings back the deleted work item with the given id and stores a
+// revision of the restored work item. The type of the work item must still
+// belong to the template of the work item's space.
+// returns NotFoundError, BadParameterError, ForbiddenError or InternalError
+func (r *GormWorkItemRepository) Restore(ctx context.Context, workitemID uuid.UUID, modifierID uuid.UUID) (*WorkItem, *Revision, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "restore"}, time.Now())
+	wiStorage := WorkItemStorage{}
+	tx := r.db.Unscoped().Set("gorm:query_option", "FOR UPDATE").Where("id = ? AND deleted_at IS NOT NULL", workitemID).First(&wiStorage)
+	if tx.RecordNotFound() {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id": workitemID,
+		}, "deleted work item not found")
+		return nil, nil, errors.NewNotFoundError("deleted work item", workitemID.String())
+	}
+	if tx.Error != nil {
+		return nil, nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	if err := r.space.CheckExists(ctx, wiStorage.SpaceID); err != nil {
+		return nil, nil, errs.Wrapf(err, "failed to find space of work item %s", workitemID)
+	}
+	wiType, err := r.witr.Load(ctx, wiStorage.Type)
+	if err != nil {
+		return nil, nil, errors.NewBadParameterError("typeID", wiStorage.Type)
+	}
+	if _, err := r.CheckTypeAndSpaceShareTemplate(ctx, wiType, wiStorage.SpaceID); err != nil {
+		return nil, nil, errs.Wrapf(err, "unable to restore work item %s", workitemID)
+	}
+	wiStorage.DeletedAt = nil
+	wiStorage.Version = wiStorage.Version + 1
+	if err := r.db.Unscoped().Save(&wiStorage).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id": workitemID,
+			"err":   err,
+		}, "unable to restore the work item")
+		return nil, nil, errors.NewInternalError(ctx, err)
+	}
+	// store a revision of the restored work item
+	rev, err := r.wirr.Create(context.Background(), modifierID, RevisionTypeRestore, wiStorage)
+	if err != nil {
+		return nil, nil, errs.Wrapf(err, "error while restoring work item")
+	}
+	log.Debug(ctx, map[string]interface{}{"wi_id": workitemID}, "Work item restored successfully!")
+	w, err := ConvertWorkItemStorageToModel(wiType, &wiStorage)
+	if err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
+	return w, &rev, nil
+}
+
+// RevertToRevision sets the fields of the work item with the given id back
+// to the values they had in the given revision and stores the result as a
+// new update revision. Version must be the same as the one in the stored
+// version. Read-only fields keep their stored values. Reverting to a deletion
+// or to a revision that was made with another work item type is not allowed
+// and so is a change of the state that the transitions of the type don't
+// allow.
+// returns NotFoundError, VersionConflictError, BadParameterError, ForbiddenError or InternalError
+func (r *GormWorkItemRepository) RevertToRevision(ctx context.Context, workitemID uuid.UUID, revisionID uuid.UUID, version int, modifierID uuid.UUID) (*WorkItem, *Revision, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "revert"}, time.Now())
+	targetRev, err := r.wirr.Load(ctx, revisionID)
+	if err != nil {
+		return nil, nil, errs.Wrapf(err, "failed to load revision %s", revisionID)
+	}
+	if targetRev.WorkItemID != workitemID {
+		return nil, nil, errors.NewBadParameterError("revision", revisionID).Expected(fmt.Sprintf("revision of work item %s", workitemID))
+	}
+	if targetRev.Type == RevisionTypeDelete {
+		return nil, nil, errors.NewBadParameterError("revision", revisionID).Expected("revision that is not a deletion")
+	}
+	wiStorage := WorkItemStorage{}
+	tx := r.db.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", workitemID).First(&wiStorage)
+	if tx.RecordNotFound() {
+		return nil, nil, errors.NewNotFoundError("work item", workitemID.String())
+	}
+	if tx.Error != nil {
+		return nil, nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	if wiStorage.Version != version {
+		return nil, nil, errors.NewVersionConflictError("version conflict")
+	}
+	if targetRev.WorkItemTypeID != wiStorage.Type {
+		return nil, nil, errors.NewBadParameterErrorFromString(
+			fmt.Sprintf("revision %s was made with work item type %s but the work item is now of type %s", revisionID, targetRev.WorkItemTypeID, wiStorage.Type),
+		)
+	}
+	wiType, err := r.witr.Load(ctx, wiStorage.Type)
+	if err != nil {
+		return nil, nil, errors.NewInternalError(ctx, err)
+	}
+	oldState := wiStorage.Fields[SystemState]
+	oldFields := wiStorage.Fields
+	wiStorage.Version = wiStorage.Version + 1
+	wiStorage.Fields = Fields{}
+	wiType.Fields.keepReadOnlyFields(oldFields, wiStorage.Fields)
+	for fieldName, fieldDef := range wiType.Fields {
+		if fieldDef.ReadOnly || fieldDef.Type.GetKind() == KindComputed {
+			continue
+		}
+		oldValue := targetRev.WorkItemFields[fieldName]
+		if fieldName == SystemAssignees || fieldName == SystemLabels || fieldName == SystemBoardcolumns {
+			if l, ok := oldValue.([]interface{}); oldValue == nil || (ok && len(l) == 0) {
+				continue
+			}
+		}
+		// round-trip the stored value to make sure it is still valid for the
+		// current field definition
+		fieldValue, err := fieldDef.ConvertFromModel(fieldName, oldValue)
+		if err != nil {
+			return nil, nil, errors.NewBadParameterError(fieldName, oldValue)
+		}
+		wiStorage.Fields[fieldName], err = fieldDef.ConvertToModel(fieldName, fieldValue)
+		if err != nil {
+			return nil, nil, errors.NewBadParameterError(fieldName, fieldValue)
+		}
+	}
+	// the reverted fields must still be valid in combination and may not
+	// reference work items or releases that are gone by now
+	violations := wiType.Fields.checkConditionalRequirements(wiStorage.Fields)
+	refViolations, err := r.checkWorkItemReferences(ctx, wiType, wiStorage)
+	if err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
+	violations = append(violations, refViolations...)
+	releaseViolations, err := r.checkReleaseReferences(ctx, wiType, wiStorage)
+	if err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
+	violations = append(violations, releaseViolations...)
+	if err := violationsError(violations); err != nil {
+		return nil, nil, err
+	}
+	// reverting the state is a state change like any other
+	if err := r.checkTransition(ctx, wiType, wiStorage, oldState, modifierID); err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
+	wiType.setMetaState(wiStorage.Fields)
+	if err := r.computeFields(ctx, wiType, &wiStorage); err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
+	tx = r.db.Where("Version = ?", version).Save(&wiStorage)
+	if err := tx.Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id":       workitemID,
+			"revision_id": revisionID,
+			"err":         err,
+		}, "unable to revert the work item")
+		return nil, nil, errors.NewInternalError(ctx, err)
+	}
+	if tx.RowsAffected == 0 {
+		return nil, nil, errors.NewVersionConflictError("version conflict")
+	}
+	// store a revision of the reverted work item
+	rev, err := r.wirr.Create(context.Background(), modifierID, RevisionTypeUpdate, wiStorage)
+	if err != nil {
+		return nil, nil, errs.Wrapf(err, "error while reverting work item")
+	}
+	if err := r.recalculateAncestors(ctx, workitemID); err != nil {
+		return nil, nil, errs.Wrapf(err, "failed to recalculate computed fields of ancestors of work item %s", workitemID)
+	}
+	log.Info(ctx, map[string]interface{}{
+		"wi_id":       workitemID,
+		"revision_id": revisionID,
+	}, "Reverted work item to revision")
+	w, err := ConvertWorkItemStorageToModel(wiType, &wiStorage)
+	if err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
+	return w, &rev, nil
+}
+
 // CalculateOrder calculates the order of the reorder workitem
 func (r *GormWorkItemRepository) CalculateOrder(above, below *float64) float64 {
 	return (*above + *below) / 2
@@ -546,12 +798,14 @@ func (r *GormWorkItemRepository) Reorder(ctx context.Context, spaceID uuid.UUID,
 	}
 	res.Version = res.Version + 1
 	res.Type = wi.Type
+	oldFields := res.Fields
 	res.Fields = Fields{}
+	wiType.Fields.keepReadOnlyFields(oldFields, res.Fields)
 
 	res.ExecutionOrder = order
 
 	for fieldName, fieldDef := range wiType.Fields {
-		if fieldDef.ReadOnly {
+		if fieldDef.ReadOnly || fieldDef.Type.GetKind() == KindComputed {
 			continue
 		}
 		fieldValue := wi.Fields[fieldName]
@@ -561,6 +815,9 @@ func (r *GormWorkItemRepository) Reorder(ctx context.Context, spaceID uuid.UUID,
 			return nil, errors.NewBadParameterError(fieldName, fieldValue)
 		}
 	}
+	if err := r.computeFields(ctx, wiType, &res); err != nil {
+		return nil, errs.WithStack(err)
+	}
 	tx = tx.Where("Version = ?", wi.Version).Save(&res)
 	if err := tx.Error; err != nil {
 		return nil, errors.NewInternalError(ctx, err)
@@ -587,10 +844,15 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 	if wiStorage.Version != updatedWorkItem.Version {
 		return nil, nil, errors.NewVersionConflictError("version conflict")
 	}
+	oldState := wiStorage.Fields[SystemState]
+	oldFields := wiStorage.Fields
 	wiStorage.Version = wiStorage.Version + 1
 	wiStorage.Fields = Fields{}
+	wiType.Fields.keepReadOnlyFields(oldFields, wiStorage.Fields)
+	// collect the errors of all fields in order to report them at once
+	violations := []errors.BadParameterError{}
 	for fieldName, fieldDef := range wiType.Fields {
-		if fieldDef.ReadOnly {
+		if fieldDef.ReadOnly || fieldDef.Type.GetKind() == KindComputed {
 			continue
 		}
 		fieldValue := updatedWorkItem.Fields[fieldName]
@@ -611,11 +873,32 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 		}
 		wiStorage.Fields[fieldName], err = fieldDef.ConvertToModel(fieldName, fieldValue)
 		if err != nil {
-			return nil, nil, errors.NewBadParameterError(fieldName, fieldValue)
+			violations = append(violations, fieldViolation(fieldName, fieldValue, err))
 		}
 	}
-	// Change of Work Item Type
-	if wiStorage.Type != updatedWorkItem.Type {
+	if wiStorage.Type == updatedWorkItem.Type {
+		violations = append(violations, wiType.Fields.checkConditionalRequirements(wiStorage.Fields)...)
+		refViolations, err := r.checkWorkItemReferences(ctx, wiType, *wiStorage)
+		if err != nil {
+			return nil, nil, errs.WithStack(err)
+		}
+		violations = append(violations, refViolations...)
+		releaseViolations, err := r.checkReleaseReferences(ctx, wiType, *wiStorage)
+		if err != nil {
+			return nil, nil, errs.WithStack(err)
+		}
+		violations = append(violations, releaseViolations...)
+	}
+	if err := violationsError(violations); err != nil {
+		return nil, nil, err
+	}
+	// Enforce the workflow of the work item type unless the type is changed
+	if wiStorage.Type == updatedWorkItem.Type {
+		if err := r.checkTransition(ctx, wiType, *wiStorage, oldState, modifierID); err != nil {
+			return nil, nil, errs.WithStack(err)
+		}
+	} else {
+		// Change of Work Item Type
 		newWiType, err := r.witr.Load(ctx, updatedWorkItem.Type)
 		if err != nil {
 			return nil, nil, errs.Wrapf(err, "failed to load workitemtype: %s ", updatedWorkItem.Type)
@@ -626,6 +909,10 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 		// This will be used by the ConvertWorkItemStorageToModel function
 		wiType = newWiType
 	}
+	wiType.setMetaState(wiStorage.Fields)
+	if err := r.computeFields(ctx, wiType, wiStorage); err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
 	tx := r.db.Where("Version = ?", updatedWorkItem.Version).Save(&wiStorage)
 	if err := tx.Error; err != nil {
 		log.Error(ctx, map[string]interface{}{
@@ -644,6 +931,13 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 	if err != nil {
 		return nil, nil, errs.Wrapf(err, "error while saving work item")
 	}
+	// new assignees watch the work item
+	if err := r.watch(ctx, *wiStorage); err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
+	if err := r.recalculateAncestors(ctx, wiStorage.ID); err != nil {
+		return nil, nil, errs.Wrapf(err, "failed to recalculate computed fields of ancestors of work item %s", wiStorage.ID)
+	}
 	log.Info(ctx, map[string]interface{}{
 		"wi_id":    updatedWorkItem.ID,
 		"space_id": spaceID,
@@ -655,6 +949,127 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 	return w, &rev, nil
 }
 
+// checkTransition verifies that the given identity is allowed to change the
+// state of the given work item from the old state to the state that is set in
+// its fields.
+// returns BadParameterError, ForbiddenError or InternalError
+func (r *GormWorkItemRepository) checkTransition(ctx context.Context, wiType *WorkItemType, wi WorkItemStorage, oldState interface{}, identityID uuid.UUID) error {
+	if len(wiType.Transitions) == 0 {
+		return nil
+	}
+	from, ok := oldState.(string)
+	if !ok {
+		// the work item had no state before
+		return nil
+	}
+	to, ok := wi.Fields[SystemState].(string)
+	if !ok || from == to {
+		return nil
+	}
+	candidates := wiType.Transitions.From(from).To(to)
+	if len(candidates) == 0 {
+		return errors.NewBadParameterError(SystemState, to).Expected(fmt.Sprintf("one of the states reachable from %q: %s", from, wiType.Transitions.From(from).targetStates()))
+	}
+	roles, err := r.transitionRoles(ctx, wi, identityID)
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	allowed := candidates.AllowedFor(roles)
+	if len(allowed) == 0 {
+		return errors.NewForbiddenError(fmt.Sprintf("the state can only be changed from %q to %q by the %s of the work item", from, to, candidates[0].Role))
+	}
+	var missing []string
+	for _, tr := range allowed {
+		missing = tr.MissingFields(wi.Fields)
+		if len(missing) == 0 {
+			return nil
+		}
+	}
+	return errors.NewBadParameterErrorFromString(fmt.Sprintf("the state can only be changed from %q to %q if these fields are set: %s", from, to, strings.Join(missing, ", ")))
+}
+
+// transitionRoles returns the roles that the given identity has for the given
+// work item
+func (r *GormWorkItemRepository) transitionRoles(ctx context.Context, wi WorkItemStorage, identityID uuid.UUID) (map[TransitionRole]bool, error) {
+	roles := map[TransitionRole]bool{}
+	id := identityID.String()
+	if creator, ok := wi.Fields[SystemCreator].(string); ok && creator == id {
+		roles[TransitionRoleCreator] = true
+	}
+	for _, a := range assignees(wi.Fields) {
+		if a == id {
+			roles[TransitionRoleAssignee] = true
+		}
+	}
+	s, err := r.space.Load(ctx, wi.SpaceID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to load space %s", wi.SpaceID)
+	}
+	if s.OwnerID == identityID {
+		roles[TransitionRoleSpaceOwner] = true
+	}
+	return roles, nil
+}
+
+// assignees returns the IDs of the identities assigned to a work item with
+// the given fields
+func assignees(fields Fields) []string {
+	var result []string
+	switch values := fields[SystemAssignees].(type) {
+	case []interface{}:
+		for _, v := range values {
+			if a, ok := v.(string); ok {
+				result = append(result, a)
+			}
+		}
+	case []string:
+		result = values
+	}
+	return result
+}
+
+// watch makes the given identities and the assignees of the given work item
+// watch the work item
+func (r *GormWorkItemRepository) watch(ctx context.Context, wi WorkItemStorage, identityIDs ...uuid.UUID) error {
+	for _, a := range assignees(wi.Fields) {
+		if id, err := uuid.FromString(a); err == nil {
+			identityIDs = append(identityIDs, id)
+		}
+	}
+	for _, id := range identityIDs {
+		err := r.watchers.Watch(ctx, watcher.TargetWorkItem, wi.ID, id)
+		if ok, _ := errors.IsNotFoundError(err); ok {
+			// e.g. an assignee imported from a remote tracker
+			continue
+		}
+		if err != nil {
+			return errs.Wrapf(err, "failed to add watcher %s to work item %s", id, wi.ID)
+		}
+	}
+	return nil
+}
+
+// ListTransitions returns the transitions that the given identity can perform
+// from the current state of the work item with the given ID.
+// returns NotFoundError or InternalError
+func (r *GormWorkItemRepository) ListTransitions(ctx context.Context, id uuid.UUID, identityID uuid.UUID) (Transitions, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "listTransitions"}, time.Now())
+	wiStorage, err := r.LoadFromDB(ctx, id)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	wiType, err := r.witr.Load(ctx, wiStorage.Type)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to load type of work item %s", id)
+	}
+	state, _ := wiStorage.Fields[SystemState].(string)
+	roles, err := r.transitionRoles(ctx, *wiStorage, identityID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	return wiType.NextTransitions(state).AllowedFor(roles), nil
+}
+
 // CheckTypeAndSpaceShareTemplate returns true if the given workitem type (wit)
 // belongs to the same space template as the space (spaceID); otherwise false is
 // returned
@@ -688,7 +1103,9 @@ func (r *GormWorkItemRepository) CheckTypeAndSpaceShareTemplate(ctx context.Cont
 	return true, nil
 }
 
-// Create creates a new work item in the repository
+// Create creates a new work item in the repository. The work item can be
+// created in any state of its type, the transitions of the type only apply to
+// the later changes of the state.
 // returns BadParameterError, ConversionError or InternalError
 func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID, typeID uuid.UUID, fields map[string]interface{}, creatorID uuid.UUID) (*WorkItem, *Revision, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "workitem", "create"}, time.Now())
@@ -725,15 +1142,18 @@ func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID,
 		Number:         *number,
 	}
 	fields[SystemCreator] = creatorID.String()
+	// collect the errors of all fields in order to report them at once
+	violations := []errors.BadParameterError{}
 	for fieldName, fieldDef := range wiType.Fields {
-		if fieldDef.ReadOnly {
+		if fieldDef.ReadOnly || fieldDef.Type.GetKind() == KindComputed {
 			continue
 		}
 		fieldValue := fields[fieldName]
 		var err error
 		wi.Fields[fieldName], err = fieldDef.ConvertToModel(fieldName, fieldValue)
 		if err != nil {
-			return nil, nil, errors.NewBadParameterError(fieldName, fieldValue) // TODO(kwk): Change errors pkg to consume the original error as well
+			violations = append(violations, fieldViolation(fieldName, fieldValue, err))
+			continue
 		}
 		if (fieldName == SystemAssignees || fieldName == SystemLabels || fieldName == SystemBoardcolumns) && fieldValue == nil {
 			delete(wi.Fields, fieldName)
@@ -741,10 +1161,28 @@ func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID,
 		if fieldName == SystemDescription && wi.Fields[fieldName] != nil {
 			description := rendering.NewMarkupContentFromMap(wi.Fields[fieldName].(map[string]interface{}))
 			if !rendering.IsMarkupSupported(description.Markup) {
-				return nil, nil, errors.NewBadParameterError(fieldName, fieldValue)
+				violations = append(violations, errors.NewBadParameterError(fieldName, fieldValue))
 			}
 		}
 	}
+	violations = append(violations, wiType.Fields.checkConditionalRequirements(wi.Fields)...)
+	refViolations, err := r.checkWorkItemReferences(ctx, wiType, wi)
+	if err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
+	violations = append(violations, refViolations...)
+	releaseViolations, err := r.checkReleaseReferences(ctx, wiType, wi)
+	if err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
+	violations = append(violations, releaseViolations...)
+	if err := violationsError(violations); err != nil {
+		return nil, nil, err
+	}
+	wiType.setMetaState(wi.Fields)
+	if err := r.computeFields(ctx, wiType, &wi); err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
 	if err := r.db.Create(&wi).Error; err != nil {
 		return nil, nil, errs.Wrapf(err, "failed to create work item")
 	}
@@ -758,6 +1196,9 @@ func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID,
 	if err != nil {
 		return nil, nil, errs.Wrapf(err, "error while creating work item")
 	}
+	if err := r.watch(ctx, wi, creatorID); err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
 	log.Debug(ctx, map[string]interface{}{"pkg": "workitem", "wi_id": wi.ID, "number": wi.Number}, "Work item created successfully!")
 	return witem, &rev, nil
 }
@@ -1200,6 +1641,12 @@ func (r *GormWorkItemRepository) ChangeWorkItemType(ctx context.Context, wiStora
 		if oldFieldName == SystemMetaState {
 			continue
 		}
+		// Computed values are recalculated for the new type and therefore
+		// never show up in the field diff either.
+		if oldFieldDef.Type.GetKind() == KindComputed {
+			delete(wiStorage.Fields, oldFieldName)
+			continue
+		}
 		// The field exists in old type and new type
 		if newField, ok := newWIType.Fields[oldFieldName]; ok {
 			newVal, err := oldFieldDef.Type.ConvertToModelWithType(newField.Type, wiStorage.Fields[oldFieldName])
@@ -1381,6 +1828,22 @@ func getValueOfRelationalKind(db *gorm.DB, val interface{}, kind Kind) (string,
 		}
 		result = codebase.URL // TODO(ibrahim): Figure out what we should be here. Codebase does not have a name.
 
+	case KindWorkItem:
+		var wi WorkItemStorage
+		tx := db.Model(wi.TableName()).Where("id = ?", val).First(&wi)
+		if tx.Error != nil {
+			return result, errs.Wrap(tx.Error, "failed to find work item")
+		}
+		result = fmt.Sprintf("#%d %s", wi.Number, wi.Fields[SystemTitle])
+	case KindRelease:
+		var release struct {
+			Name string
+		}
+		tx := db.Table("releases").Select("name").Where("id = ?", val).Scan(&release)
+		if tx.Error != nil {
+			return result, errs.Wrap(tx.Error, "failed to find release")
+		}
+		result = release.Name
 	case KindLabel:
 		var label label.Label
 		tx := db.Model(label.TableName()).Where("id = ?", val).First(&label)
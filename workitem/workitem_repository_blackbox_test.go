@@ -13,13 +13,16 @@ import (
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
 	"github.com/fabric8-services/fabric8-wit/id"
+	"github.com/fabric8-services/fabric8-wit/label"
 	"github.com/fabric8-services/fabric8-wit/ptr"
 	query "github.com/fabric8-services/fabric8-wit/query/simple"
+	"github.com/fabric8-services/fabric8-wit/release"
 	"github.com/fabric8-services/fabric8-wit/rendering"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	"github.com/fabric8-services/fabric8-wit/space"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
 	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
@@ -461,6 +464,122 @@ func (s *workItemRepoBlackBoxTest) TestCheckExists() {
 	})
 }
 
+func (s *workItemRepoBlackBoxTest) TestRestore() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		wi := fxt.WorkItems[0]
+		err := s.repo.Delete(s.Ctx, wi.ID, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		deletedAt, err := s.repo.DeletedAt(s.Ctx, wi.ID)
+		require.NoError(t, err)
+		require.NotNil(t, deletedAt)
+		// when
+		restored, rev, err := s.repo.Restore(s.Ctx, wi.ID, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, rev)
+		assert.Equal(t, workitem.RevisionTypeRestore, rev.Type)
+		assert.Equal(t, wi.Version+1, restored.Version)
+		assert.Equal(t, wi.Fields[workitem.SystemTitle], restored.Fields[workitem.SystemTitle])
+		require.NoError(t, s.repo.CheckExists(s.Ctx, wi.ID))
+		deletedAt, err = s.repo.DeletedAt(s.Ctx, wi.ID)
+		require.NoError(t, err)
+		require.Nil(t, deletedAt)
+	})
+
+	s.T().Run("fail - work item not deleted", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		// when
+		_, _, err := s.repo.Restore(s.Ctx, fxt.WorkItems[0].ID, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+
+	s.T().Run("fail - unknown work item", func(t *testing.T) {
+		// when
+		_, err := s.repo.DeletedAt(s.Ctx, uuid.NewV4())
+		// then
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestRevertToRevision() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields[workitem.SystemTitle] = "original title"
+			return nil
+		}))
+		revisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, revisions)
+		fxt.WorkItems[0].Fields[workitem.SystemTitle] = "overwritten title"
+		updated, _, err := s.repo.Save(s.Ctx, fxt.WorkItems[0].SpaceID, *fxt.WorkItems[0], fxt.Identities[0].ID)
+		require.NoError(t, err)
+		// when
+		reverted, rev, err := s.repo.RevertToRevision(s.Ctx, updated.ID, revisions[0].ID, updated.Version, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, rev)
+		assert.Equal(t, workitem.RevisionTypeUpdate, rev.Type)
+		assert.Equal(t, "original title", reverted.Fields[workitem.SystemTitle])
+		assert.Equal(t, updated.Version+1, reverted.Version)
+	})
+
+	s.T().Run("keep the stored values of read-only fields", func(t *testing.T) {
+		// given a read-only field that was set by the system after the
+		// revision was made
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemTypes[idx].Fields["counter"] = workitem.FieldDefinition{
+					Label:    "Counter",
+					Type:     workitem.SimpleType{Kind: workitem.KindInteger},
+					ReadOnly: true,
+				}
+				return nil
+			}),
+			tf.WorkItems(1),
+		)
+		revisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, revisions)
+		err = s.DB.Exec(`UPDATE work_items SET fields = fields || '{"counter": 5}' WHERE id = ?`, fxt.WorkItems[0].ID).Error
+		require.NoError(t, err)
+		// when
+		reverted, _, err := s.repo.RevertToRevision(s.Ctx, fxt.WorkItems[0].ID, revisions[0].ID, fxt.WorkItems[0].Version, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, float64(5), reverted.Fields["counter"])
+	})
+
+	s.T().Run("fail - version conflict", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		revisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, revisions)
+		// when
+		_, _, err = s.repo.RevertToRevision(s.Ctx, fxt.WorkItems[0].ID, revisions[0].ID, fxt.WorkItems[0].Version+1, fxt.Identities[0].ID)
+		// then
+		require.IsType(t, errors.VersionConflictError{}, errs.Cause(err))
+	})
+
+	s.T().Run("fail - revision of another work item", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(2))
+		revisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, fxt.WorkItems[1].ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, revisions)
+		// when
+		_, _, err = s.repo.RevertToRevision(s.Ctx, fxt.WorkItems[0].ID, revisions[0].ID, fxt.WorkItems[0].Version, fxt.Identities[0].ID)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
 func (s *workItemRepoBlackBoxTest) TestGetCountsPerIteration() {
 	s.T().Run("ok", func(t *testing.T) {
 		// given
@@ -514,6 +633,242 @@ func (s *workItemRepoBlackBoxTest) TestLookupIDByNamedSpaceAndNumber() {
 	})
 }
 
+func (s *workItemRepoBlackBoxTest) TestLookupIDByKey() {
+	// given a work item in a space with a key
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItems(1))
+	spaceKey := "K" + strings.ToUpper(uuid.NewV4().String()[:8])
+	fxt.Spaces[0].Key = spaceKey
+	_, err := space.NewRepository(s.DB).Save(s.Ctx, fxt.Spaces[0])
+	require.NoError(s.T(), err)
+	s.T().Run("ok", func(t *testing.T) {
+		// when
+		wiID, spaceID, err := s.repo.LookupIDByKey(s.Ctx, fmt.Sprintf("%s-%d", spaceKey, fxt.WorkItems[0].Number))
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, wiID)
+		assert.Equal(t, fxt.WorkItems[0].ID, *wiID)
+		require.NotNil(t, spaceID)
+		assert.Equal(t, fxt.Spaces[0].ID, *spaceID)
+	})
+	s.T().Run("not found", func(t *testing.T) {
+		// when
+		_, _, err := s.repo.LookupIDByKey(s.Ctx, fmt.Sprintf("%s-%d", spaceKey, fxt.WorkItems[0].Number+1))
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+	s.T().Run("malformed key", func(t *testing.T) {
+		// when
+		_, _, err := s.repo.LookupIDByKey(s.Ctx, spaceKey)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestMove() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given a work item with an iteration, an area and a label in the
+		// first of two spaces
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.Spaces(2),
+			tf.Iterations(2, func(fxt *tf.TestFixture, idx int) error {
+				fxt.Iterations[idx].SpaceID = fxt.Spaces[idx].ID
+				return nil
+			}),
+			tf.Areas(2, func(fxt *tf.TestFixture, idx int) error {
+				fxt.Areas[idx].SpaceID = fxt.Spaces[idx].ID
+				return nil
+			}),
+			tf.Labels(1),
+			tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[0].ID.String()
+				fxt.WorkItems[idx].Fields[workitem.SystemArea] = fxt.Areas[0].ID.String()
+				fxt.WorkItems[idx].Fields[workitem.SystemLabels] = []string{fxt.Labels[0].ID.String()}
+				return nil
+			}),
+		)
+		oldNumber := fxt.WorkItems[0].Number
+		// when
+		wi, rev, err := s.repo.Move(s.Ctx, fxt.WorkItems[0].ID, fxt.WorkItems[0].Version, fxt.Spaces[1].ID, workitem.MoveMapping{}, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, rev)
+		assert.Equal(t, fxt.WorkItems[0].ID, wi.ID)
+		assert.Equal(t, fxt.Spaces[1].ID, wi.SpaceID)
+		assert.Equal(t, fxt.WorkItems[0].Type, wi.Type)
+		assert.Equal(t, fxt.Iterations[1].ID.String(), wi.Fields[workitem.SystemIteration])
+		assert.Equal(t, fxt.Areas[1].ID.String(), wi.Fields[workitem.SystemArea])
+		labels, ok := wi.Fields[workitem.SystemLabels].([]interface{})
+		require.True(t, ok)
+		require.Len(t, labels, 1)
+		l, err := label.NewLabelRepository(s.DB).Load(s.Ctx, uuid.FromStringOrNil(labels[0].(string)))
+		require.NoError(t, err)
+		assert.Equal(t, fxt.Spaces[1].ID, l.SpaceID)
+		assert.Equal(t, fxt.Labels[0].Name, l.Name)
+		t.Run("former number is resolved", func(t *testing.T) {
+			wiID, spaceID, err := s.repo.LookupIDByNamedSpaceAndNumber(s.Ctx, fxt.Identities[0].Username, fxt.Spaces[0].Name, oldNumber)
+			require.NoError(t, err)
+			assert.Equal(t, fxt.WorkItems[0].ID, *wiID)
+			assert.Equal(t, fxt.Spaces[1].ID, *spaceID)
+		})
+	})
+	s.T().Run("fail - no matching type in target space template", func(t *testing.T) {
+		// given two spaces with different templates
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.SpaceTemplates(2),
+			tf.Spaces(2, func(fxt *tf.TestFixture, idx int) error {
+				fxt.Spaces[idx].SpaceTemplateID = fxt.SpaceTemplates[idx].ID
+				return nil
+			}),
+			tf.WorkItems(1),
+		)
+		// when
+		_, _, err := s.repo.Move(s.Ctx, fxt.WorkItems[0].ID, fxt.WorkItems[0].Version, fxt.Spaces[1].ID, workitem.MoveMapping{}, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - version conflict", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItems(1))
+		// when
+		_, _, err := s.repo.Move(s.Ctx, fxt.WorkItems[0].ID, fxt.WorkItems[0].Version+1, fxt.Spaces[1].ID, workitem.MoveMapping{}, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.VersionConflictError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - work item has links", func(t *testing.T) {
+		// given a parent with a child in the first space
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.Spaces(2),
+			tf.WorkItems(2, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].SpaceID = fxt.Spaces[0].ID
+				return nil
+			}),
+			tf.WorkItemLinksCustom(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemLinks[idx].LinkTypeID = link.SystemWorkItemLinkTypeParentChildID
+				fxt.WorkItemLinks[idx].SourceID = fxt.WorkItems[0].ID
+				fxt.WorkItemLinks[idx].TargetID = fxt.WorkItems[1].ID
+				return nil
+			}),
+		)
+		// when
+		_, _, err := s.repo.Move(s.Ctx, fxt.WorkItems[1].ID, fxt.WorkItems[1].Version, fxt.Spaces[1].ID, workitem.MoveMapping{}, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		t.Run("ok after the links were removed", func(t *testing.T) {
+			// when
+			err := link.NewWorkItemLinkRepository(s.DB).DeleteRelatedLinks(s.Ctx, fxt.WorkItems[1].ID, fxt.Identities[0].ID)
+			require.NoError(t, err)
+			wi, _, err := s.repo.Move(s.Ctx, fxt.WorkItems[1].ID, fxt.WorkItems[1].Version, fxt.Spaces[1].ID, workitem.MoveMapping{}, fxt.Identities[0].ID)
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, fxt.Spaces[1].ID, wi.SpaceID)
+		})
+	})
+	s.T().Run("comments are kept", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItems(1), tf.Comments(2))
+		// when
+		_, _, err := s.repo.Move(s.Ctx, fxt.WorkItems[0].ID, fxt.WorkItems[0].Version, fxt.Spaces[1].ID, workitem.MoveMapping{}, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		var count int
+		err = s.DB.Table("comments").Where("parent_id = ? AND deleted_at IS NULL", fxt.WorkItems[0].ID).Count(&count).Error
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	// given a type with fields referencing a work item and releases
+	referenceType := func(fxt *tf.TestFixture, idx int) error {
+		fxt.WorkItemTypes[idx].Fields["duplicate_of"] = workitem.FieldDefinition{
+			Label: "Duplicate of",
+			Type:  workitem.SimpleType{Kind: workitem.KindWorkItem},
+		}
+		fxt.WorkItemTypes[idx].Fields["fixed_in"] = workitem.FieldDefinition{
+			Label: "Fixed in",
+			Type: workitem.ListType{
+				SimpleType:    workitem.SimpleType{Kind: workitem.KindList},
+				ComponentType: workitem.SimpleType{Kind: workitem.KindRelease},
+			},
+		}
+		return nil
+	}
+	newReferenceFixture := func(t *testing.T) *tf.TestFixture {
+		return tf.NewTestFixture(t, s.DB,
+			tf.Spaces(2),
+			tf.WorkItemTypes(1, referenceType),
+			tf.WorkItems(2, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].SpaceID = fxt.Spaces[0].ID
+				return nil
+			}),
+		)
+	}
+	s.T().Run("fail - work item references another work item", func(t *testing.T) {
+		// given
+		fxt := newReferenceFixture(t)
+		wi := *fxt.WorkItems[0]
+		wi.Fields["duplicate_of"] = fxt.WorkItems[1].ID.String()
+		saved, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		// when
+		_, _, err = s.repo.Move(s.Ctx, saved.ID, saved.Version, fxt.Spaces[1].ID, workitem.MoveMapping{}, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - work item is referenced by another work item", func(t *testing.T) {
+		// given
+		fxt := newReferenceFixture(t)
+		wi := *fxt.WorkItems[0]
+		wi.Fields["duplicate_of"] = fxt.WorkItems[1].ID.String()
+		_, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		// when
+		_, _, err = s.repo.Move(s.Ctx, fxt.WorkItems[1].ID, fxt.WorkItems[1].Version, fxt.Spaces[1].ID, workitem.MoveMapping{}, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("releases are mapped into the target space", func(t *testing.T) {
+		// given a work item fixed in two releases of the first space, one
+		// with a counterpart of the same name in the second space
+		fxt := newReferenceFixture(t)
+		releaseRepo := release.NewRepository(s.DB)
+		newRelease := func(spaceID uuid.UUID, name string) release.Release {
+			r := release.Release{SpaceID: spaceID, Name: name}
+			require.NoError(t, releaseRepo.Create(s.Ctx, &r))
+			return r
+		}
+		oldByName := newRelease(fxt.Spaces[0].ID, "1.0")
+		oldMapped := newRelease(fxt.Spaces[0].ID, "2.0")
+		newByName := newRelease(fxt.Spaces[1].ID, "1.0")
+		newMapped := newRelease(fxt.Spaces[1].ID, "2.0-beta")
+		wi := *fxt.WorkItems[0]
+		wi.Fields["fixed_in"] = []interface{}{oldByName.ID.String(), oldMapped.ID.String()}
+		saved, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		t.Run("fail - no counterpart", func(t *testing.T) {
+			// when
+			_, _, err := s.repo.Move(s.Ctx, saved.ID, saved.Version, fxt.Spaces[1].ID, workitem.MoveMapping{}, fxt.Identities[0].ID)
+			// then
+			require.Error(t, err)
+			assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		})
+		t.Run("ok", func(t *testing.T) {
+			// when
+			moved, _, err := s.repo.Move(s.Ctx, saved.ID, saved.Version, fxt.Spaces[1].ID, workitem.MoveMapping{
+				Releases: map[uuid.UUID]uuid.UUID{oldMapped.ID: newMapped.ID},
+			}, fxt.Identities[0].ID)
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, []interface{}{newByName.ID.String(), newMapped.ID.String()}, moved.Fields["fixed_in"])
+		})
+	})
+}
+
 // TestLoadBatchByID verifies that repo.LoadBatchByID returns distinct items
 func (s *workItemRepoBlackBoxTest) TestLoadBatchByID() {
 	fixtures := tf.NewTestFixture(s.T(), s.DB, tf.WorkItems(5))
@@ -769,3 +1124,507 @@ func (s *workItemRepoBlackBoxTest) TestList() {
 
 	})
 }
+
+func (s *workItemRepoBlackBoxTest) TestComputedFields() {
+	// given a type with an estimate, the remaining effort of an item and the
+	// total estimate of its children
+	computedFieldsType := func(fxt *tf.TestFixture, idx int) error {
+		fxt.WorkItemTypes[idx].Fields["estimate"] = workitem.FieldDefinition{
+			Label: "Estimate",
+			Type:  workitem.SimpleType{Kind: workitem.KindFloat},
+		}
+		fxt.WorkItemTypes[idx].Fields["spent"] = workitem.FieldDefinition{
+			Label: "Spent",
+			Type:  workitem.SimpleType{Kind: workitem.KindFloat},
+		}
+		fxt.WorkItemTypes[idx].Fields["remaining"] = workitem.FieldDefinition{
+			Label: "Remaining",
+			Type: workitem.ComputedType{
+				SimpleType: workitem.SimpleType{Kind: workitem.KindComputed},
+				BaseType:   workitem.SimpleType{Kind: workitem.KindFloat},
+				Expression: "max(estimate - spent, 0)",
+			},
+		}
+		fxt.WorkItemTypes[idx].Fields["children_estimate"] = workitem.FieldDefinition{
+			Label: "Children estimate",
+			Type: workitem.ComputedType{
+				SimpleType: workitem.SimpleType{Kind: workitem.KindComputed},
+				BaseType:   workitem.SimpleType{Kind: workitem.KindFloat},
+				Expression: "sum(estimate)",
+			},
+		}
+		fxt.WorkItemTypes[idx].Fields["progress"] = workitem.FieldDefinition{
+			Label: "Progress",
+			Type: workitem.ComputedType{
+				SimpleType: workitem.SimpleType{Kind: workitem.KindComputed},
+				BaseType:   workitem.SimpleType{Kind: workitem.KindInteger},
+				Expression: `percent(count_if(system.state, "closed"), count())`,
+			},
+		}
+		return nil
+	}
+	newFixture := func(t *testing.T) *tf.TestFixture {
+		return tf.NewTestFixture(t, s.DB,
+			tf.WorkItemTypes(1, computedFieldsType),
+			tf.WorkItems(3, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Fields["estimate"] = float64(idx + 1)
+				fxt.WorkItems[idx].Fields["spent"] = float64(1)
+				// values given for computed fields are ignored
+				fxt.WorkItems[idx].Fields["remaining"] = float64(42)
+				return nil
+			}),
+			tf.WorkItemLinksCustom(2, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemLinks[idx].LinkTypeID = link.SystemWorkItemLinkTypeParentChildID
+				fxt.WorkItemLinks[idx].SourceID = fxt.WorkItems[0].ID
+				fxt.WorkItemLinks[idx].TargetID = fxt.WorkItems[idx+1].ID
+				return nil
+			}),
+		)
+	}
+
+	s.T().Run("calculated on create and link", func(t *testing.T) {
+		// when
+		fxt := newFixture(t)
+		// then
+		parent, err := s.repo.LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, float64(0), parent.Fields["remaining"])
+		assert.Equal(t, float64(5), parent.Fields["children_estimate"])
+		assert.Equal(t, float64(0), parent.Fields["progress"])
+		child, err := s.repo.LoadByID(s.Ctx, fxt.WorkItems[2].ID)
+		require.NoError(t, err)
+		assert.Equal(t, float64(2), child.Fields["remaining"])
+		assert.Equal(t, float64(0), child.Fields["children_estimate"])
+	})
+
+	s.T().Run("recalculated on save of child", func(t *testing.T) {
+		// given
+		fxt := newFixture(t)
+		parent, err := s.repo.LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		child, err := s.repo.LoadByID(s.Ctx, fxt.WorkItems[1].ID)
+		require.NoError(t, err)
+		// when
+		child.Fields["estimate"] = float64(10)
+		child.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		_, _, err = s.repo.Save(s.Ctx, child.SpaceID, *child, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		// then
+		updatedParent, err := s.repo.LoadByID(s.Ctx, parent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, float64(13), updatedParent.Fields["children_estimate"])
+		assert.Equal(t, float64(50), updatedParent.Fields["progress"])
+		assert.Equal(t, parent.Version, updatedParent.Version, "recalculation must not change the version")
+	})
+
+	s.T().Run("recalculated on link deletion", func(t *testing.T) {
+		// given
+		fxt := newFixture(t)
+		// when
+		err := link.NewWorkItemLinkRepository(s.DB).Delete(s.Ctx, fxt.WorkItemLinks[1].ID, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		// then
+		parent, err := s.repo.LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, float64(2), parent.Fields["children_estimate"])
+	})
+
+	s.T().Run("invalid formula", func(t *testing.T) {
+		wit := workitem.WorkItemType{
+			Name: "invalid",
+			Fields: workitem.FieldDefinitions{
+				"a": {
+					Label: "A",
+					Type: workitem.ComputedType{
+						SimpleType: workitem.SimpleType{Kind: workitem.KindComputed},
+						BaseType:   workitem.SimpleType{Kind: workitem.KindFloat},
+						Expression: "b + 1",
+					},
+				},
+				"b": {
+					Label: "B",
+					Type: workitem.ComputedType{
+						SimpleType: workitem.SimpleType{Kind: workitem.KindComputed},
+						BaseType:   workitem.SimpleType{Kind: workitem.KindFloat},
+						Expression: "a + 1",
+					},
+				},
+			},
+		}
+		require.Error(t, wit.Validate())
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestTransitions() {
+	// given a type that only allows to go from "new" to "open" and from "open"
+	// to "closed" if a resolution is set. Only the space owner can resolve
+	// work items.
+	newFixture := func(t *testing.T) *tf.TestFixture {
+		return tf.NewTestFixture(t, s.DB,
+			tf.Identities(2),
+			tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemTypes[idx].Fields["resolution"] = workitem.FieldDefinition{
+					Label: "Resolution",
+					Type:  workitem.SimpleType{Kind: workitem.KindString},
+				}
+				fxt.WorkItemTypes[idx].Transitions = workitem.Transitions{
+					{From: workitem.SystemStateNew, To: workitem.SystemStateOpen},
+					{From: workitem.SystemStateOpen, To: workitem.SystemStateClosed, RequiredFields: []string{"resolution"}},
+					{From: workitem.TransitionFromAny, To: workitem.SystemStateResolved, Role: workitem.TransitionRoleSpaceOwner},
+				}
+				return nil
+			}),
+			tf.WorkItems(1, tf.SetWorkItemField(workitem.SystemState, workitem.SystemStateNew)),
+		)
+	}
+	targetStates := func(transitions workitem.Transitions) []string {
+		res := make([]string, len(transitions))
+		for i, tr := range transitions {
+			res[i] = tr.To
+		}
+		return res
+	}
+
+	s.T().Run("list", func(t *testing.T) {
+		fxt := newFixture(t)
+		t.Run("space owner", func(t *testing.T) {
+			transitions, err := s.repo.ListTransitions(s.Ctx, fxt.WorkItems[0].ID, fxt.Spaces[0].OwnerID)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{workitem.SystemStateOpen, workitem.SystemStateResolved}, targetStates(transitions))
+		})
+		t.Run("other user", func(t *testing.T) {
+			transitions, err := s.repo.ListTransitions(s.Ctx, fxt.WorkItems[0].ID, uuid.NewV4())
+			require.NoError(t, err)
+			assert.Equal(t, []string{workitem.SystemStateOpen}, targetStates(transitions))
+		})
+		t.Run("not found", func(t *testing.T) {
+			_, err := s.repo.ListTransitions(s.Ctx, uuid.NewV4(), fxt.Identities[0].ID)
+			require.Error(t, err)
+			assert.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+		})
+	})
+
+	s.T().Run("allowed transitions", func(t *testing.T) {
+		// given
+		fxt := newFixture(t)
+		wi := *fxt.WorkItems[0]
+		// when
+		wi.Fields[workitem.SystemState] = workitem.SystemStateOpen
+		opened, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		opened.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		opened.Fields["resolution"] = "done"
+		closed, _, err := s.repo.Save(s.Ctx, opened.SpaceID, *opened, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, workitem.SystemStateClosed, closed.Fields[workitem.SystemState])
+	})
+
+	s.T().Run("illegal transition", func(t *testing.T) {
+		// given
+		fxt := newFixture(t)
+		wi := *fxt.WorkItems[0]
+		// when
+		wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		_, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("missing required field", func(t *testing.T) {
+		// given
+		fxt := newFixture(t)
+		wi := *fxt.WorkItems[0]
+		wi.Fields[workitem.SystemState] = workitem.SystemStateOpen
+		opened, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		// when
+		opened.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		_, _, err = s.repo.Save(s.Ctx, opened.SpaceID, *opened, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("missing role", func(t *testing.T) {
+		// given
+		fxt := newFixture(t)
+		wi := *fxt.WorkItems[0]
+		// when
+		wi.Fields[workitem.SystemState] = workitem.SystemStateResolved
+		_, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, uuid.NewV4())
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.ForbiddenError{}, errs.Cause(err))
+	})
+
+	s.T().Run("meta state follows the state", func(t *testing.T) {
+		// given
+		fxt := newFixture(t)
+		wi := *fxt.WorkItems[0]
+		require.Equal(t, "mNew", wi.Fields[workitem.SystemMetaState])
+		// when
+		wi.Fields[workitem.SystemState] = workitem.SystemStateOpen
+		wi.Fields[workitem.SystemMetaState] = "mClosed"
+		opened, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "mOpen", opened.Fields[workitem.SystemMetaState])
+	})
+
+	s.T().Run("illegal revert", func(t *testing.T) {
+		// given a work item that was opened, which can't be undone
+		fxt := newFixture(t)
+		revisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, revisions)
+		wi := *fxt.WorkItems[0]
+		wi.Fields[workitem.SystemState] = workitem.SystemStateOpen
+		opened, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		// when
+		_, _, err = s.repo.RevertToRevision(s.Ctx, opened.ID, revisions[0].ID, opened.Version, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("create", func(t *testing.T) {
+		fxt := newFixture(t)
+		fields := func(state string) map[string]interface{} {
+			return map[string]interface{}{
+				workitem.SystemTitle: "transitions",
+				workitem.SystemState: state,
+			}
+		}
+		t.Run("state reachable from the default state", func(t *testing.T) {
+			wi, _, err := s.repo.Create(s.Ctx, fxt.Spaces[0].ID, fxt.WorkItemTypes[0].ID, fields(workitem.SystemStateOpen), fxt.Identities[0].ID)
+			require.NoError(t, err)
+			assert.Equal(t, "mOpen", wi.Fields[workitem.SystemMetaState])
+		})
+		t.Run("state not reachable from the default state", func(t *testing.T) {
+			// transitions don't apply to the initial state
+			wi, _, err := s.repo.Create(s.Ctx, fxt.Spaces[0].ID, fxt.WorkItemTypes[0].ID, fields(workitem.SystemStateClosed), fxt.Identities[1].ID)
+			require.NoError(t, err)
+			assert.Equal(t, workitem.SystemStateClosed, wi.Fields[workitem.SystemState])
+			assert.Equal(t, "mClosed", wi.Fields[workitem.SystemMetaState])
+		})
+		t.Run("invalid state", func(t *testing.T) {
+			_, _, err := s.repo.Create(s.Ctx, fxt.Spaces[0].ID, fxt.WorkItemTypes[0].ID, fields("foo"), fxt.Identities[0].ID)
+			require.Error(t, err)
+		})
+		t.Run("clone", func(t *testing.T) {
+			resolved, _, err := s.repo.Create(s.Ctx, fxt.Spaces[0].ID, fxt.WorkItemTypes[0].ID, fields(workitem.SystemStateResolved), fxt.Identities[1].ID)
+			require.NoError(t, err)
+			clone, _, err := s.repo.Clone(s.Ctx, resolved.ID, workitem.CloneOptions{}, fxt.Identities[1].ID)
+			require.NoError(t, err)
+			assert.Equal(t, workitem.SystemStateResolved, clone.Fields[workitem.SystemState])
+			clone, _, err = s.repo.Clone(s.Ctx, resolved.ID, workitem.CloneOptions{ResetState: true}, fxt.Identities[1].ID)
+			require.NoError(t, err)
+			assert.Equal(t, workitem.SystemStateNew, clone.Fields[workitem.SystemState])
+		})
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestFieldConstraints() {
+	// given a type whose estimate must not be negative, whose code is limited
+	// to three upper case letters and whose resolution is required for closed
+	// work items
+	newFixture := func(t *testing.T) *tf.TestFixture {
+		return tf.NewTestFixture(t, s.DB,
+			tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemTypes[idx].Fields["estimate"] = workitem.FieldDefinition{
+					Label:       "Estimate",
+					Type:        workitem.SimpleType{Kind: workitem.KindFloat},
+					Constraints: &workitem.FieldConstraints{Min: ptr.Float64(0)},
+				}
+				fxt.WorkItemTypes[idx].Fields["code"] = workitem.FieldDefinition{
+					Label:       "Code",
+					Type:        workitem.SimpleType{Kind: workitem.KindString},
+					Constraints: &workitem.FieldConstraints{MaxLength: ptr.Int(3), Pattern: "^[A-Z]*$"},
+				}
+				fxt.WorkItemTypes[idx].Fields["resolution"] = workitem.FieldDefinition{
+					Label: "Resolution",
+					Type:  workitem.SimpleType{Kind: workitem.KindString},
+					Constraints: &workitem.FieldConstraints{
+						RequiredWhen: &workitem.FieldCondition{
+							Field: workitem.SystemState,
+							In:    []interface{}{workitem.SystemStateResolved, workitem.SystemStateClosed},
+						},
+					},
+				}
+				return nil
+			}),
+			tf.WorkItems(1, tf.SetWorkItemField(workitem.SystemState, workitem.SystemStateNew)),
+		)
+	}
+
+	s.T().Run("create reports every violated field", func(t *testing.T) {
+		// given
+		fxt := newFixture(t)
+		// when
+		_, _, err := s.repo.Create(s.Ctx, fxt.Spaces[0].ID, fxt.WorkItemTypes[0].ID, map[string]interface{}{
+			workitem.SystemTitle: "some title",
+			workitem.SystemState: workitem.SystemStateClosed,
+			"estimate":           float64(-1),
+			"code":               "abcd",
+		}, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		badParamErr, ok := errs.Cause(err).(errors.BadParameterError)
+		require.True(t, ok, "expected a bad parameter error but got %+v", err)
+		violations := badParamErr.Violations()
+		require.Len(t, violations, 3)
+		assert.Equal(t, "code", violations[0].Parameter())
+		assert.Equal(t, "estimate", violations[1].Parameter())
+		assert.Equal(t, "resolution", violations[2].Parameter())
+	})
+
+	s.T().Run("save with valid values", func(t *testing.T) {
+		// given
+		fxt := newFixture(t)
+		wi := *fxt.WorkItems[0]
+		// when
+		wi.Fields["estimate"] = float64(2)
+		wi.Fields["code"] = "ABC"
+		wi.Fields["resolution"] = "fixed"
+		wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		saved, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "fixed", saved.Fields["resolution"])
+	})
+
+	s.T().Run("save requires resolution when closed", func(t *testing.T) {
+		// given
+		fxt := newFixture(t)
+		wi := *fxt.WorkItems[0]
+		// when
+		wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		_, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		badParamErr, ok := errs.Cause(err).(errors.BadParameterError)
+		require.True(t, ok, "expected a bad parameter error but got %+v", err)
+		require.Len(t, badParamErr.Violations(), 1)
+		assert.Equal(t, "resolution", badParamErr.Violations()[0].Parameter())
+	})
+
+	s.T().Run("revert requires resolution when closed", func(t *testing.T) {
+		// given a revision of a closed work item that was made before the
+		// resolution became required
+		fxt := newFixture(t)
+		wi := *fxt.WorkItems[0]
+		wi.Fields["resolution"] = "fixed"
+		wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		closed, rev, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		fields := workitem.Fields{}
+		for k, v := range rev.WorkItemFields {
+			fields[k] = v
+		}
+		delete(fields, "resolution")
+		require.NoError(t, s.DB.Model(rev).Update("work_item_fields", fields).Error)
+		// when
+		_, _, err = s.repo.RevertToRevision(s.Ctx, closed.ID, rev.ID, closed.Version, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		badParamErr, ok := errs.Cause(err).(errors.BadParameterError)
+		require.True(t, ok, "expected a bad parameter error but got %+v", err)
+		require.Len(t, badParamErr.Violations(), 1)
+		assert.Equal(t, "resolution", badParamErr.Violations()[0].Parameter())
+	})
+
+	s.T().Run("unknown field in condition", func(t *testing.T) {
+		_, err := tf.NewFixture(s.DB, tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].Fields["resolution"] = workitem.FieldDefinition{
+				Label: "Resolution",
+				Type:  workitem.SimpleType{Kind: workitem.KindString},
+				Constraints: &workitem.FieldConstraints{
+					RequiredWhen: &workitem.FieldCondition{Field: "foo", In: []interface{}{"bar"}},
+				},
+			}
+			return nil
+		}))
+		require.Error(t, err)
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestWorkItemReferences() {
+	// given a type with a single and a list field referencing other work items
+	newFixture := func(t *testing.T) *tf.TestFixture {
+		return tf.NewTestFixture(t, s.DB,
+			tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemTypes[idx].Fields["duplicate_of"] = workitem.FieldDefinition{
+					Label: "Duplicate of",
+					Type:  workitem.SimpleType{Kind: workitem.KindWorkItem},
+				}
+				fxt.WorkItemTypes[idx].Fields["related"] = workitem.FieldDefinition{
+					Label: "Related",
+					Type: workitem.ListType{
+						SimpleType:    workitem.SimpleType{Kind: workitem.KindList},
+						ComponentType: workitem.SimpleType{Kind: workitem.KindWorkItem},
+					},
+				}
+				return nil
+			}),
+			tf.WorkItems(3),
+		)
+	}
+
+	s.T().Run("references to work items in the same space", func(t *testing.T) {
+		// given
+		fxt := newFixture(t)
+		wi := *fxt.WorkItems[0]
+		// when
+		wi.Fields["duplicate_of"] = fxt.WorkItems[1].ID.String()
+		wi.Fields["related"] = []interface{}{fxt.WorkItems[1].ID.String(), fxt.WorkItems[2].ID.String()}
+		saved, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, fxt.WorkItems[1].ID.String(), saved.Fields["duplicate_of"])
+		assert.Equal(t, []interface{}{fxt.WorkItems[1].ID.String(), fxt.WorkItems[2].ID.String()}, saved.Fields["related"])
+	})
+
+	s.T().Run("reference to unknown work item", func(t *testing.T) {
+		// given
+		fxt := newFixture(t)
+		// when
+		_, _, err := s.repo.Create(s.Ctx, fxt.Spaces[0].ID, fxt.WorkItemTypes[0].ID, map[string]interface{}{
+			workitem.SystemTitle: "some title",
+			workitem.SystemState: workitem.SystemStateNew,
+			"duplicate_of":       uuid.NewV4().String(),
+		}, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("reference to work item in another space", func(t *testing.T) {
+		// given
+		fxt := newFixture(t)
+		otherFxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		wi := *fxt.WorkItems[0]
+		// when
+		wi.Fields["related"] = []interface{}{fxt.WorkItems[1].ID.String(), otherFxt.WorkItems[0].ID.String()}
+		_, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		badParamErr, ok := errs.Cause(err).(errors.BadParameterError)
+		require.True(t, ok, "expected a bad parameter error but got %+v", err)
+		assert.Equal(t, "related", badParamErr.Parameter())
+	})
+
+	s.T().Run("reference to itself", func(t *testing.T) {
+		// given
+		fxt := newFixture(t)
+		wi := *fxt.WorkItems[0]
+		// when
+		wi.Fields["duplicate_of"] = wi.ID.String()
+		_, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
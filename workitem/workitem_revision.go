@@ -18,6 +18,8 @@ const (
 	_                  // ignore 3rd value
 	// RevisionTypeUpdate a work item update
 	RevisionTypeUpdate // 4
+	// RevisionTypeRestore a work item restoration after a deletion
+	RevisionTypeRestore // 5
 )
 
 // Revision represents a version of a work item
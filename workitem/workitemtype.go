@@ -39,6 +39,12 @@ const (
 	SystemBoardcolumns        = "system.boardcolumns"
 	SystemMetaState           = "system.metastate"
 
+	// time tracking fields (in minutes) that are maintained by worklogs if a
+	// work item type defines them
+	SystemOriginalEstimate  = "system.original_estimate"
+	SystemRemainingEstimate = "system.remaining_estimate"
+	SystemTimeSpent         = "system.time_spent"
+
 	SystemBoard = "Board"
 
 	SystemStateOpen       = "open"
@@ -46,6 +52,9 @@ const (
 	SystemStateInProgress = "in progress"
 	SystemStateResolved   = "resolved"
 	SystemStateClosed     = "closed"
+
+	// SystemMetaStateClosed is the meta-state of closed work items
+	SystemMetaStateClosed = "mClosed"
 )
 
 // Never ever change these UUIDs!!!
@@ -111,6 +120,11 @@ type WorkItemType struct {
 	// type of this work item. This field is filled upon loading the work item
 	// type from the DB.
 	ChildTypeIDs []uuid.UUID `gorm:"-" json:"child_types,omitempty"`
+
+	// Transitions contains the allowed changes of the state of work items of
+	// this type. If empty, the state can be changed to any value of the state
+	// field.
+	Transitions Transitions `sql:"type:jsonb" json:"transitions,omitempty"`
 }
 
 // Validate runs some checks on the work item type to ensure the field
@@ -122,9 +136,32 @@ func (wit WorkItemType) Validate() error {
 	if err := wit.Fields.Validate(); err != nil {
 		return errs.Wrapf(err, "failed to validate work item type's fields")
 	}
+	if err := wit.Transitions.Validate(wit.Fields); err != nil {
+		return errs.Wrapf(err, "failed to validate work item type's transitions")
+	}
 	return nil
 }
 
+// NextTransitions returns the transitions that start in the given state. If
+// the work item type has no transitions, a transition to every other value of
+// the state field is returned.
+func (wit WorkItemType) NextTransitions(state string) Transitions {
+	if len(wit.Transitions) > 0 {
+		return wit.Transitions.From(state)
+	}
+	result := Transitions{}
+	values, err := stateValues(wit.Fields)
+	if err != nil {
+		return result
+	}
+	for _, v := range values {
+		if to, ok := v.(string); ok && to != state {
+			result = append(result, Transition{From: state, To: to})
+		}
+	}
+	return result
+}
+
 // GetTypePathSeparator returns the work item type's path separator "."
 func GetTypePathSeparator() string {
 	return pathSep
@@ -223,6 +260,9 @@ func (wit WorkItemType) Equal(u convert.Equaler) bool {
 	if wit.SpaceTemplateID != other.SpaceTemplateID {
 		return false
 	}
+	if !reflect.DeepEqual(wit.Transitions, other.Transitions) {
+		return false
+	}
 	return true
 }
 
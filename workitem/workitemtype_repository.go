@@ -149,6 +149,13 @@ func (r *GormWorkItemTypeRepository) CreateFromModel(ctx context.Context, model
 		allFields[field] = definition
 	}
 
+	if err := model.Transitions.Validate(allFields); err != nil {
+		return nil, errors.NewBadParameterErrorFromString(err.Error())
+	}
+	if err := allFields.validateConditions(); err != nil {
+		return nil, errors.NewBadParameterErrorFromString(err.Error())
+	}
+
 	model.Version = 0
 	model.Path = path
 	model.Fields = allFields
@@ -0,0 +1,55 @@
+// Package worklog contains the operations to track the time spent on work
+// items.
+package worklog
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Worklog records the time an identity spent on a work item
+type Worklog struct {
+	gormsupport.Lifecycle
+	ID         uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
+	WorkItemID uuid.UUID `sql:"type:uuid"`
+	IdentityID uuid.UUID `sql:"type:uuid"`
+	// Duration is the time spent in minutes
+	Duration int
+	// WorkDate is when the work was done
+	WorkDate time.Time
+	Comment  string
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (w Worklog) TableName() string {
+	return "worklogs"
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (w Worklog) GetETagData() []interface{} {
+	return []interface{}{w.ID, strconv.FormatInt(w.UpdatedAt.Unix(), 10)}
+}
+
+// GetLastModified returns the last modification time
+func (w Worklog) GetLastModified() time.Time {
+	return w.UpdatedAt.Truncate(time.Second)
+}
+
+// Totals sums up the time in minutes logged on a work item
+type Totals struct {
+	// TimeSpent is the time logged on the work item itself
+	TimeSpent int
+	// TimeSpentTotal is the time logged on the work item and all of its
+	// descendants in the tree of parent-child links
+	TimeSpentTotal int
+}
+
+// IdentityTotal is the time in minutes logged by an identity
+type IdentityTotal struct {
+	IdentityID uuid.UUID
+	TimeSpent  int
+}
@@ -0,0 +1,343 @@
+package worklog
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/application/repository"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Repository describes interactions with worklogs
+type Repository interface {
+	repository.Exister
+	// Create logs work on a work item. The remaining estimate of the work
+	// item is reduced by the duration of the worklog unless a new remaining
+	// estimate is given.
+	Create(ctx context.Context, worklog *Worklog, remainingEstimate *int) error
+	// Save updates a worklog. The remaining estimate of the work item is
+	// adjusted by the change of the duration unless a new remaining estimate
+	// is given.
+	Save(ctx context.Context, worklog *Worklog, remainingEstimate *int) error
+	// Delete deletes a worklog and adds its duration back to the remaining
+	// estimate of the work item
+	Delete(ctx context.Context, id uuid.UUID) error
+	Load(ctx context.Context, id uuid.UUID) (*Worklog, error)
+	// List returns a page of the worklogs of the given work item, latest work
+	// first, together with the total number of worklogs
+	List(ctx context.Context, workItemID uuid.UUID, start *int, limit *int) ([]Worklog, int, error)
+	// Totals returns the time logged on the given work item and its
+	// descendants
+	Totals(ctx context.Context, workItemID uuid.UUID) (*Totals, error)
+	// TotalsByIteration returns the time logged per identity on the work
+	// items of the given iteration and its child iterations
+	TotalsByIteration(ctx context.Context, itr iteration.Iteration) ([]IdentityTotal, error)
+}
+
+// NewRepository creates a new storage type.
+func NewRepository(db *gorm.DB) Repository {
+	return &GormWorklogRepository{
+		db:           db,
+		workItemRepo: workitem.NewWorkItemRepository(db),
+		witRepo:      workitem.NewWorkItemTypeRepository(db),
+	}
+}
+
+// GormWorklogRepository is the implementation of the storage interface for
+// worklogs.
+type GormWorklogRepository struct {
+	db           *gorm.DB
+	workItemRepo *workitem.GormWorkItemRepository
+	witRepo      *workitem.GormWorkItemTypeRepository
+}
+
+func validate(worklog Worklog, remainingEstimate *int) error {
+	if worklog.Duration <= 0 {
+		return errors.NewBadParameterError("duration", worklog.Duration).Expected("a positive number of minutes")
+	}
+	if remainingEstimate != nil && *remainingEstimate < 0 {
+		return errors.NewBadParameterError("remaining estimate", *remainingEstimate).Expected("a non-negative number of minutes")
+	}
+	return nil
+}
+
+// Create implements Repository
+func (r *GormWorklogRepository) Create(ctx context.Context, worklog *Worklog, remainingEstimate *int) error {
+	defer goa.MeasureSince([]string{"goa", "db", "worklog", "create"}, time.Now())
+	if err := validate(*worklog, remainingEstimate); err != nil {
+		return err
+	}
+	if err := r.workItemRepo.CheckExists(ctx, worklog.WorkItemID); err != nil {
+		return errs.WithStack(err)
+	}
+	worklog.ID = uuid.NewV4()
+	if worklog.WorkDate.IsZero() {
+		worklog.WorkDate = time.Now()
+	}
+	if err := r.db.Create(worklog).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id": worklog.WorkItemID,
+			"err":   err,
+		}, "unable to create the worklog")
+		return errors.NewInternalError(ctx, errs.Wrap(err, "failed to create worklog"))
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"worklog_id": worklog.ID,
+	}, "Worklog created!")
+	return r.updateTimeTracking(ctx, worklog.WorkItemID, worklog.Duration, remainingEstimate)
+}
+
+// Save implements Repository
+func (r *GormWorklogRepository) Save(ctx context.Context, worklog *Worklog, remainingEstimate *int) error {
+	defer goa.MeasureSince([]string{"goa", "db", "worklog", "save"}, time.Now())
+	if err := validate(*worklog, remainingEstimate); err != nil {
+		return err
+	}
+	existing, err := r.Load(ctx, worklog.ID)
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	// the work item and the author of a worklog can't be changed
+	worklog.WorkItemID = existing.WorkItemID
+	worklog.IdentityID = existing.IdentityID
+	worklog.CreatedAt = existing.CreatedAt
+	if worklog.WorkDate.IsZero() {
+		worklog.WorkDate = existing.WorkDate
+	}
+	if err := r.db.Save(worklog).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"worklog_id": worklog.ID,
+			"err":        err,
+		}, "unable to save the worklog")
+		return errors.NewInternalError(ctx, errs.Wrap(err, "failed to save worklog"))
+	}
+	return r.updateTimeTracking(ctx, worklog.WorkItemID, worklog.Duration-existing.Duration, remainingEstimate)
+}
+
+// Delete implements Repository
+func (r *GormWorklogRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "worklog", "delete"}, time.Now())
+	existing, err := r.Load(ctx, id)
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	if err := r.db.Delete(existing).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"worklog_id": id,
+			"err":        err,
+		}, "unable to delete the worklog")
+		return errors.NewInternalError(ctx, errs.Wrap(err, "failed to delete worklog"))
+	}
+	return r.updateTimeTracking(ctx, existing.WorkItemID, -existing.Duration, nil)
+}
+
+// updateTimeTracking updates the time tracking fields of the given work item
+// if its type defines them:
+//
+//   - the time spent is set to the sum of all worklogs of the work item
+//   - the remaining estimate is set to the given value or otherwise reduced
+//     by the given number of minutes (but not below zero). A negative number
+//     increases the remaining estimate.
+//   - the original estimate is initialized with the remaining estimate when
+//     it was not set before.
+//
+// Like computed fields, these fields are updated without increasing the
+// version of the work item. Afterwards the computed fields of the work item
+// and its ancestors are recalculated so that totals roll up the tree.
+func (r *GormWorklogRepository) updateTimeTracking(ctx context.Context, workItemID uuid.UUID, consumed int, remainingEstimate *int) error {
+	wi := workitem.WorkItemStorage{}
+	tx := r.db.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", workItemID).First(&wi)
+	if tx.RecordNotFound() {
+		return errors.NewNotFoundError("work item", workItemID.String())
+	}
+	if tx.Error != nil {
+		return errors.NewInternalError(ctx, tx.Error)
+	}
+	wit, err := r.witRepo.Load(ctx, wi.Type)
+	if err != nil {
+		return errs.Wrapf(err, "failed to load type of work item %s", workItemID)
+	}
+	changed := false
+	if _, ok := wit.Fields[workitem.SystemTimeSpent]; ok {
+		var spent struct{ Total int }
+		err := r.db.Raw(`SELECT COALESCE(SUM(duration), 0) AS total FROM worklogs WHERE work_item_id = ? AND deleted_at IS NULL`, workItemID).Scan(&spent).Error
+		if err != nil {
+			return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to sum up worklogs of work item %s", workItemID))
+		}
+		wi.Fields[workitem.SystemTimeSpent] = spent.Total
+		changed = true
+	}
+	if _, ok := wit.Fields[workitem.SystemRemainingEstimate]; ok {
+		current, hasCurrent := intValue(wi.Fields[workitem.SystemRemainingEstimate])
+		if _, ok := wit.Fields[workitem.SystemOriginalEstimate]; ok && hasCurrent {
+			if _, hasOriginal := intValue(wi.Fields[workitem.SystemOriginalEstimate]); !hasOriginal {
+				wi.Fields[workitem.SystemOriginalEstimate] = current
+			}
+		}
+		if remainingEstimate != nil {
+			wi.Fields[workitem.SystemRemainingEstimate] = *remainingEstimate
+		} else if hasCurrent {
+			remaining := current - consumed
+			if remaining < 0 {
+				remaining = 0
+			}
+			wi.Fields[workitem.SystemRemainingEstimate] = remaining
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	err = r.db.Model(&workitem.WorkItemStorage{}).Where("id = ?", workItemID).UpdateColumns(map[string]interface{}{
+		"fields":     wi.Fields,
+		"updated_at": time.Now(),
+	}).Error
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id": workItemID,
+			"err":   err,
+		}, "unable to update time tracking fields of work item")
+		return errors.NewInternalError(ctx, err)
+	}
+	return r.workItemRepo.RecalculateComputedFields(ctx, workItemID)
+}
+
+// intValue returns the given field value in storage representation as an int
+// and false if it is empty
+func intValue(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		// numbers loaded from the database are float64
+		return int(n), true
+	}
+	return 0, false
+}
+
+// Load implements Repository
+func (r *GormWorklogRepository) Load(ctx context.Context, id uuid.UUID) (*Worklog, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "worklog", "get"}, time.Now())
+	var obj Worklog
+	tx := r.db.Where("id = ?", id).First(&obj)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("worklog", id.String())
+	}
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"worklog_id": id,
+			"err":        tx.Error,
+		}, "unable to load the worklog")
+		return nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	return &obj, nil
+}
+
+// List implements Repository
+func (r *GormWorklogRepository) List(ctx context.Context, workItemID uuid.UUID, start *int, limit *int) ([]Worklog, int, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "worklog", "list"}, time.Now())
+	db := r.db.Model(&Worklog{}).Where("work_item_id = ?", workItemID)
+	var count int
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to count worklogs of work item %s", workItemID))
+	}
+	if start != nil {
+		if *start < 0 {
+			return nil, 0, errors.NewBadParameterError("start", *start)
+		}
+		db = db.Offset(*start)
+	}
+	if limit != nil {
+		if *limit <= 0 {
+			return nil, 0, errors.NewBadParameterError("limit", *limit)
+		}
+		db = db.Limit(*limit)
+	}
+	result := []Worklog{}
+	if err := db.Order("work_date desc, created_at desc").Find(&result).Error; err != nil {
+		return nil, 0, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list worklogs of work item %s", workItemID))
+	}
+	return result, count, nil
+}
+
+// Totals implements Repository
+func (r *GormWorklogRepository) Totals(ctx context.Context, workItemID uuid.UUID) (*Totals, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "worklog", "totals"}, time.Now())
+	if err := r.workItemRepo.CheckExists(ctx, workItemID); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	// UNION (instead of UNION ALL) stops the recursion on cycles
+	query := `
+		WITH RECURSIVE tree(id) AS (
+			SELECT $1::uuid
+			UNION
+			SELECT l.target_id FROM work_item_links l
+			JOIN tree t ON l.source_id = t.id
+			WHERE l.link_type_id = $2 AND l.deleted_at IS NULL
+		)
+		SELECT
+			COALESCE(SUM(w.duration) FILTER (WHERE w.work_item_id = $1::uuid), 0) AS time_spent,
+			COALESCE(SUM(w.duration), 0) AS time_spent_total
+		FROM worklogs w
+		JOIN tree ON w.work_item_id = tree.id
+		JOIN work_items wi ON wi.id = w.work_item_id
+		WHERE w.deleted_at IS NULL AND wi.deleted_at IS NULL`
+	var result Totals
+	if err := r.db.Raw(query, workItemID, link.SystemWorkItemLinkTypeParentChildID).Row().Scan(&result.TimeSpent, &result.TimeSpentTotal); err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to sum up worklogs of work item %s", workItemID))
+	}
+	return &result, nil
+}
+
+// TotalsByIteration implements Repository
+func (r *GormWorklogRepository) TotalsByIteration(ctx context.Context, itr iteration.Iteration) ([]IdentityTotal, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "worklog", "totals_by_iteration"}, time.Now())
+	return r.totalsByIdentity(ctx, `wi.space_id = ? AND wi.fields->>'system.iteration' IN (
+			SELECT id::text FROM iterations WHERE path <@ ? AND space_id = ? AND deleted_at IS NULL
+		)`, itr.SpaceID, itr.Path.Convert(), itr.SpaceID)
+}
+
+// totalsByIdentity sums up the worklogs per identity on the work items
+// matching the given condition
+func (r *GormWorklogRepository) totalsByIdentity(ctx context.Context, workItemCondition string, args ...interface{}) ([]IdentityTotal, error) {
+	rows, err := r.db.Raw(`
+		SELECT w.identity_id, SUM(w.duration)
+		FROM worklogs w
+		JOIN work_items wi ON wi.id = w.work_item_id
+		WHERE w.deleted_at IS NULL AND wi.deleted_at IS NULL AND `+workItemCondition+`
+		GROUP BY w.identity_id
+		ORDER BY w.identity_id`, args...).Rows()
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to sum up worklogs"))
+	}
+	defer rows.Close()
+	result := []IdentityTotal{}
+	for rows.Next() {
+		var t IdentityTotal
+		if err := rows.Scan(&t.IdentityID, &t.TimeSpent); err != nil {
+			return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to scan worklog totals"))
+		}
+		result = append(result, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to sum up worklogs"))
+	}
+	return result, nil
+}
+
+// CheckExists returns nil if the given ID exists otherwise returns an error
+func (r *GormWorklogRepository) CheckExists(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "worklog", "exists"}, time.Now())
+	return repository.CheckExists(ctx, r.db, Worklog{}.TableName(), id)
+}
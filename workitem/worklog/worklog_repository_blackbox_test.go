@@ -0,0 +1,250 @@
+package worklog_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/fabric8-services/fabric8-wit/workitem/worklog"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type worklogRepositoryBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	repo worklog.Repository
+}
+
+func TestRunWorklogRepositoryBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &worklogRepositoryBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *worklogRepositoryBlackBoxTest) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.repo = worklog.NewRepository(s.DB)
+}
+
+// newFixture creates a parent work item with two children, all of them in
+// the same iteration and of a type with time tracking fields. The parent has
+// a remaining estimate of 8 hours.
+func (s *worklogRepositoryBlackBoxTest) newFixture(t *testing.T) *tf.TestFixture {
+	return tf.NewTestFixture(t, s.DB,
+		tf.Identities(2),
+		tf.Iterations(1),
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			intField := workitem.FieldDefinition{Type: workitem.SimpleType{Kind: workitem.KindInteger}}
+			fxt.WorkItemTypes[idx].Fields[workitem.SystemOriginalEstimate] = intField
+			fxt.WorkItemTypes[idx].Fields[workitem.SystemRemainingEstimate] = intField
+			fxt.WorkItemTypes[idx].Fields[workitem.SystemTimeSpent] = workitem.FieldDefinition{
+				Type:     workitem.SimpleType{Kind: workitem.KindInteger},
+				ReadOnly: true,
+			}
+			return nil
+		}),
+		tf.WorkItems(3, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[0].ID.String()
+			if idx == 0 {
+				fxt.WorkItems[idx].Fields[workitem.SystemRemainingEstimate] = 480
+			}
+			return nil
+		}),
+		tf.WorkItemLinksCustom(2, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemLinks[idx].LinkTypeID = link.SystemWorkItemLinkTypeParentChildID
+			fxt.WorkItemLinks[idx].SourceID = fxt.WorkItems[0].ID
+			fxt.WorkItemLinks[idx].TargetID = fxt.WorkItems[idx+1].ID
+			return nil
+		}),
+	)
+}
+
+func (s *worklogRepositoryBlackBoxTest) loadFields(t *testing.T, id uuid.UUID) workitem.Fields {
+	wi, err := workitem.NewWorkItemRepository(s.DB).LoadByID(s.Ctx, id)
+	require.NoError(t, err)
+	return wi.Fields
+}
+
+func (s *worklogRepositoryBlackBoxTest) TestCreate() {
+	s.T().Run("reduces remaining estimate", func(t *testing.T) {
+		// given
+		fxt := s.newFixture(t)
+		w := worklog.Worklog{WorkItemID: fxt.WorkItems[0].ID, IdentityID: fxt.Identities[0].ID, Duration: 90, Comment: "bug hunting"}
+		// when
+		err := s.repo.Create(s.Ctx, &w, nil)
+		// then
+		require.NoError(t, err)
+		assert.False(t, w.WorkDate.IsZero())
+		fields := s.loadFields(t, fxt.WorkItems[0].ID)
+		assert.Equal(t, float64(390), fields[workitem.SystemRemainingEstimate])
+		assert.Equal(t, float64(480), fields[workitem.SystemOriginalEstimate])
+		assert.Equal(t, float64(90), fields[workitem.SystemTimeSpent])
+	})
+	s.T().Run("time spent survives updates of the work item", func(t *testing.T) {
+		// given
+		fxt := s.newFixture(t)
+		w := worklog.Worklog{WorkItemID: fxt.WorkItems[0].ID, IdentityID: fxt.Identities[0].ID, Duration: 90}
+		require.NoError(t, s.repo.Create(s.Ctx, &w, nil))
+		wiRepo := workitem.NewWorkItemRepository(s.DB)
+		wi, err := wiRepo.LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		// when
+		wi.Fields[workitem.SystemTitle] = "updated title"
+		delete(wi.Fields, workitem.SystemTimeSpent)
+		_, _, err = wiRepo.Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		fields := s.loadFields(t, fxt.WorkItems[0].ID)
+		assert.Equal(t, "updated title", fields[workitem.SystemTitle])
+		assert.Equal(t, float64(90), fields[workitem.SystemTimeSpent])
+	})
+	s.T().Run("time spent survives reordering the work item", func(t *testing.T) {
+		// given
+		fxt := s.newFixture(t)
+		w := worklog.Worklog{WorkItemID: fxt.WorkItems[1].ID, IdentityID: fxt.Identities[0].ID, Duration: 90}
+		require.NoError(t, s.repo.Create(s.Ctx, &w, nil))
+		wiRepo := workitem.NewWorkItemRepository(s.DB)
+		wi, err := wiRepo.LoadByID(s.Ctx, fxt.WorkItems[1].ID)
+		require.NoError(t, err)
+		// when
+		delete(wi.Fields, workitem.SystemTimeSpent)
+		_, err = wiRepo.Reorder(s.Ctx, wi.SpaceID, workitem.DirectionTop, nil, *wi, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		fields := s.loadFields(t, fxt.WorkItems[1].ID)
+		assert.Equal(t, float64(90), fields[workitem.SystemTimeSpent])
+	})
+	s.T().Run("sets remaining estimate", func(t *testing.T) {
+		// given
+		fxt := s.newFixture(t)
+		w := worklog.Worklog{WorkItemID: fxt.WorkItems[0].ID, IdentityID: fxt.Identities[0].ID, Duration: 90}
+		// when
+		err := s.repo.Create(s.Ctx, &w, ptr.Int(600))
+		// then
+		require.NoError(t, err)
+		fields := s.loadFields(t, fxt.WorkItems[0].ID)
+		assert.Equal(t, float64(600), fields[workitem.SystemRemainingEstimate])
+		assert.Equal(t, float64(480), fields[workitem.SystemOriginalEstimate])
+	})
+	s.T().Run("remaining estimate doesn't go below zero", func(t *testing.T) {
+		// given
+		fxt := s.newFixture(t)
+		w := worklog.Worklog{WorkItemID: fxt.WorkItems[0].ID, IdentityID: fxt.Identities[0].ID, Duration: 600}
+		// when
+		err := s.repo.Create(s.Ctx, &w, nil)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, float64(0), s.loadFields(t, fxt.WorkItems[0].ID)[workitem.SystemRemainingEstimate])
+	})
+	s.T().Run("invalid duration", func(t *testing.T) {
+		// given
+		fxt := s.newFixture(t)
+		w := worklog.Worklog{WorkItemID: fxt.WorkItems[0].ID, IdentityID: fxt.Identities[0].ID, Duration: 0}
+		// when
+		err := s.repo.Create(s.Ctx, &w, nil)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("unknown work item", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		w := worklog.Worklog{WorkItemID: uuid.NewV4(), IdentityID: fxt.Identities[0].ID, Duration: 30}
+		// when
+		err := s.repo.Create(s.Ctx, &w, nil)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *worklogRepositoryBlackBoxTest) TestSaveAndDelete() {
+	// given
+	fxt := s.newFixture(s.T())
+	w := worklog.Worklog{WorkItemID: fxt.WorkItems[0].ID, IdentityID: fxt.Identities[0].ID, Duration: 60}
+	require.NoError(s.T(), s.repo.Create(s.Ctx, &w, nil))
+
+	s.T().Run("save adjusts remaining estimate by the difference", func(t *testing.T) {
+		// when
+		w.Duration = 120
+		err := s.repo.Save(s.Ctx, &w, nil)
+		// then
+		require.NoError(t, err)
+		fields := s.loadFields(t, fxt.WorkItems[0].ID)
+		assert.Equal(t, float64(360), fields[workitem.SystemRemainingEstimate])
+		assert.Equal(t, float64(120), fields[workitem.SystemTimeSpent])
+	})
+	s.T().Run("delete adds the time back", func(t *testing.T) {
+		// when
+		err := s.repo.Delete(s.Ctx, w.ID)
+		// then
+		require.NoError(t, err)
+		fields := s.loadFields(t, fxt.WorkItems[0].ID)
+		assert.Equal(t, float64(480), fields[workitem.SystemRemainingEstimate])
+		assert.Equal(t, float64(0), fields[workitem.SystemTimeSpent])
+		_, err = s.repo.Load(s.Ctx, w.ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *worklogRepositoryBlackBoxTest) TestList() {
+	// given
+	fxt := s.newFixture(s.T())
+	for i := 1; i <= 3; i++ {
+		w := worklog.Worklog{WorkItemID: fxt.WorkItems[0].ID, IdentityID: fxt.Identities[0].ID, Duration: i * 10}
+		require.NoError(s.T(), s.repo.Create(s.Ctx, &w, nil))
+	}
+	// when
+	worklogs, count, err := s.repo.List(s.Ctx, fxt.WorkItems[0].ID, ptr.Int(1), ptr.Int(1))
+	// then
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 3, count)
+	require.Len(s.T(), worklogs, 1)
+	assert.Equal(s.T(), 20, worklogs[0].Duration)
+}
+
+func (s *worklogRepositoryBlackBoxTest) TestTotals() {
+	// given
+	fxt := s.newFixture(s.T())
+	logs := []worklog.Worklog{
+		{WorkItemID: fxt.WorkItems[0].ID, IdentityID: fxt.Identities[0].ID, Duration: 30},
+		{WorkItemID: fxt.WorkItems[1].ID, IdentityID: fxt.Identities[0].ID, Duration: 60},
+		{WorkItemID: fxt.WorkItems[2].ID, IdentityID: fxt.Identities[1].ID, Duration: 120},
+	}
+	for i := range logs {
+		require.NoError(s.T(), s.repo.Create(s.Ctx, &logs[i], nil))
+	}
+
+	s.T().Run("rolled up through parent-child links", func(t *testing.T) {
+		// when
+		totals, err := s.repo.Totals(s.Ctx, fxt.WorkItems[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, worklog.Totals{TimeSpent: 30, TimeSpentTotal: 210}, *totals)
+		childTotals, err := s.repo.Totals(s.Ctx, fxt.WorkItems[1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, worklog.Totals{TimeSpent: 60, TimeSpentTotal: 60}, *childTotals)
+	})
+	s.T().Run("per identity in iteration", func(t *testing.T) {
+		// when
+		totals, err := s.repo.TotalsByIteration(s.Ctx, *fxt.Iterations[0])
+		// then
+		require.NoError(t, err)
+		expected := map[uuid.UUID]int{
+			fxt.Identities[0].ID: 90,
+			fxt.Identities[1].ID: 120,
+		}
+		actual := map[uuid.UUID]int{}
+		for _, total := range totals {
+			actual[total.IdentityID] = total.TimeSpent
+		}
+		assert.Equal(t, expected, actual)
+	})
+}